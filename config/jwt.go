@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+)
+
+const (
+	JWT_ALGORITHM_HS256 = "HS256"
+	JWT_ALGORITHM_RS256 = "RS256"
+	JWT_ALGORITHM_EDDSA = "EdDSA"
+
+	devJWTSecret = "Template"
+)
+
+type JWTConfig struct {
+	Algorithm string
+	// Secret is the HMAC key used with HS256.
+	Secret string
+	// KeySecret encrypts the RS256/EdDSA private keys stored in the database.
+	KeySecret        string
+	RotationInterval time.Duration
+	Issuer           string
+}
+
+// NewJWTConfig reads the token signing settings. Outside production missing
+// secrets fall back to a development value with a warning; in production they
+// are a startup error.
+func NewJWTConfig() (JWTConfig, error) {
+	cfg := JWTConfig{
+		Algorithm: getEnv("JWT_ALGORITHM", JWT_ALGORITHM_RS256),
+		Secret:    os.Getenv("JWT_SECRET"),
+		KeySecret: os.Getenv("JWT_KEY_SECRET"),
+		Issuer:    getEnv("JWT_ISSUER", "Template"),
+	}
+
+	interval, err := time.ParseDuration(getEnv("JWT_ROTATION_INTERVAL", "720h"))
+	if err != nil || interval < time.Hour {
+		return JWTConfig{}, fmt.Errorf("JWT_ROTATION_INTERVAL must be a duration of at least 1h")
+	}
+	cfg.RotationInterval = interval
+
+	production := os.Getenv("APP_ENV") == constants.ENUM_RUN_PRODUCTION
+
+	switch cfg.Algorithm {
+	case JWT_ALGORITHM_HS256:
+		if cfg.Secret == "" {
+			if production {
+				return JWTConfig{}, fmt.Errorf("JWT_SECRET is required for %s in production", cfg.Algorithm)
+			}
+			log.Println("JWT_SECRET is not set, using an insecure development secret")
+			cfg.Secret = devJWTSecret
+		}
+	case JWT_ALGORITHM_RS256, JWT_ALGORITHM_EDDSA:
+		if cfg.KeySecret == "" {
+			if production {
+				return JWTConfig{}, fmt.Errorf("JWT_KEY_SECRET is required for %s in production", cfg.Algorithm)
+			}
+			log.Println("JWT_KEY_SECRET is not set, using an insecure development secret")
+			cfg.KeySecret = devJWTSecret
+		}
+	default:
+		return JWTConfig{}, fmt.Errorf("unsupported JWT_ALGORITHM %q", cfg.Algorithm)
+	}
+
+	return cfg, nil
+}
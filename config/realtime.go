@@ -0,0 +1,13 @@
+package config
+
+type RealtimeConfig struct {
+	// Broker selects how task events reach the streams of other app
+	// instances. Only "memory" (this process only) is built in.
+	Broker string
+}
+
+func NewRealtimeConfig() RealtimeConfig {
+	return RealtimeConfig{
+		Broker: getEnv("REALTIME_BROKER", "memory"),
+	}
+}
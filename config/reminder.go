@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type ReminderConfig struct {
+	// Interval is how often the due-date scanner runs.
+	Interval time.Duration
+	// DueSoonWindow is how far ahead of its due date a task counts as due soon.
+	DueSoonWindow time.Duration
+}
+
+func NewReminderConfig() (ReminderConfig, error) {
+	interval, err := time.ParseDuration(getEnv("REMINDER_SCAN_INTERVAL", "15m"))
+	if err != nil || interval < time.Minute {
+		return ReminderConfig{}, fmt.Errorf("REMINDER_SCAN_INTERVAL must be a duration of at least 1m")
+	}
+
+	window, err := time.ParseDuration(getEnv("REMINDER_DUE_SOON_WINDOW", "24h"))
+	if err != nil || window <= 0 {
+		return ReminderConfig{}, fmt.Errorf("REMINDER_DUE_SOON_WINDOW must be a positive duration")
+	}
+
+	return ReminderConfig{
+		Interval:      interval,
+		DueSoonWindow: window,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+type StorageConfig struct {
+	Driver      string
+	LocalPath   string
+	LocalURL    string
+	LocalSecret string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool
+}
+
+// NewStorageConfig reads the storage settings from the environment, which
+// SetUpDatabaseConnection has already populated from .env outside production.
+func NewStorageConfig() StorageConfig {
+	pathStyle, err := strconv.ParseBool(getEnv("S3_USE_PATH_STYLE", "true"))
+	if err != nil {
+		pathStyle = true
+	}
+
+	return StorageConfig{
+		Driver:      getEnv("STORAGE_DRIVER", "local"),
+		LocalPath:   getEnv("STORAGE_LOCAL_PATH", "assets"),
+		LocalURL:    getEnv("STORAGE_LOCAL_URL", "/assets"),
+		LocalSecret: os.Getenv("STORAGE_LOCAL_SECRET"),
+		S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:    os.Getenv("S3_BUCKET"),
+		S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+		S3PathStyle: pathStyle,
+	}
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,21 @@
+package config
+
+import "strconv"
+
+type WebhookConfig struct {
+	// AllowPrivateNetworks lets webhooks reach loopback, private and
+	// link-local addresses. Only turn it on when every team is trusted, such
+	// as in local development.
+	AllowPrivateNetworks bool
+}
+
+func NewWebhookConfig() WebhookConfig {
+	allowPrivate, err := strconv.ParseBool(getEnv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", "false"))
+	if err != nil {
+		allowPrivate = false
+	}
+
+	return WebhookConfig{
+		AllowPrivateNetworks: allowPrivate,
+	}
+}
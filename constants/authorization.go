@@ -0,0 +1,76 @@
+package constants
+
+const (
+	// User
+	ACTION_USER_LIST = "user:list"
+
+	// Team
+	ACTION_TEAM_CREATE = "team:create"
+	ACTION_TEAM_READ   = "team:read"
+	ACTION_TEAM_UPDATE = "team:update"
+	ACTION_TEAM_DELETE = "team:delete"
+	ACTION_TEAM_STATS  = "team:stats"
+
+	// Team membership
+	ACTION_TEAM_MEMBER_READ   = "team_member:read"
+	ACTION_TEAM_MEMBER_ADD    = "team_member:add"
+	ACTION_TEAM_MEMBER_REMOVE = "team_member:remove"
+	ACTION_TEAM_MEMBER_ROLE   = "team_member:role"
+
+	// Workflow
+	ACTION_WORKFLOW_READ   = "workflow:read"
+	ACTION_WORKFLOW_MANAGE = "workflow:manage"
+
+	// Label
+	ACTION_LABEL_READ   = "label:read"
+	ACTION_LABEL_MANAGE = "label:manage"
+
+	// Sprint
+	ACTION_SPRINT_READ   = "sprint:read"
+	ACTION_SPRINT_PLAN   = "sprint:plan"
+	ACTION_SPRINT_MANAGE = "sprint:manage"
+
+	// Worklog
+	ACTION_WORKLOG_REPORT = "worklog:report"
+
+	// Webhook
+	ACTION_WEBHOOK_MANAGE = "webhook:manage"
+
+	// Task
+	ACTION_TASK_CREATE    = "task:create"
+	ACTION_TASK_LIST      = "task:list"
+	ACTION_TASK_READ      = "task:read"
+	ACTION_TASK_HISTORY   = "task:history"
+	ACTION_TASK_UPDATE    = "task:update"
+	ACTION_TASK_DELETE    = "task:delete"
+	ACTION_TASK_ASSIGN    = "task:assign"
+	ACTION_TASK_REASSIGN  = "task:reassign"
+	ACTION_TASK_LIST_TEAM = "task:list_team"
+	ACTION_TASK_LIST_USER = "task:list_user"
+
+	// Task comment
+	ACTION_TASK_COMMENT_READ  = "task_comment:read"
+	ACTION_TASK_COMMENT_WRITE = "task_comment:write"
+
+	// Task attachment
+	ACTION_TASK_ATTACHMENT_READ   = "task_attachment:read"
+	ACTION_TASK_ATTACHMENT_WRITE  = "task_attachment:write"
+	ACTION_TASK_ATTACHMENT_MANAGE = "task_attachment:manage"
+
+	// Task checklist
+	ACTION_TASK_CHECKLIST_READ  = "task_checklist:read"
+	ACTION_TASK_CHECKLIST_WRITE = "task_checklist:write"
+
+	// Task link
+	ACTION_TASK_LINK_READ  = "task_link:read"
+	ACTION_TASK_LINK_WRITE = "task_link:write"
+
+	// Task label
+	ACTION_TASK_LABEL_READ  = "task_label:read"
+	ACTION_TASK_LABEL_WRITE = "task_label:write"
+
+	// Task worklog
+	ACTION_TASK_WORKLOG_READ   = "task_worklog:read"
+	ACTION_TASK_WORKLOG_WRITE  = "task_worklog:write"
+	ACTION_TASK_WORKLOG_MANAGE = "task_worklog:manage"
+)
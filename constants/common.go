@@ -4,6 +4,67 @@ const (
 	ENUM_ROLE_ADMIN = "admin"
 	ENUM_ROLE_USER = "user"
 
+	ENUM_TEAM_ROLE_OWNER = "owner"
+	ENUM_TEAM_ROLE_MAINTAINER = "maintainer"
+	ENUM_TEAM_ROLE_MEMBER = "member"
+	ENUM_TEAM_ROLE_VIEWER = "viewer"
+
+	ENUM_STATUS_CATEGORY_TODO = "todo"
+	ENUM_STATUS_CATEGORY_IN_PROGRESS = "in_progress"
+	ENUM_STATUS_CATEGORY_DONE = "done"
+
+	ENUM_TASK_EVENT_CREATED = "created"
+	ENUM_TASK_EVENT_UPDATED = "updated"
+	ENUM_TASK_EVENT_ASSIGNED = "assigned"
+	ENUM_TASK_EVENT_UNASSIGNED = "unassigned"
+	ENUM_TASK_EVENT_DELETED = "deleted"
+
+	ENUM_TASK_ROLE_ASSIGNEE = "assignee"
+	ENUM_TASK_ROLE_REVIEWER = "reviewer"
+	ENUM_TASK_ROLE_WATCHER = "watcher"
+
+	ENUM_TASK_PRIORITY_LOW = "low"
+	ENUM_TASK_PRIORITY_MEDIUM = "medium"
+	ENUM_TASK_PRIORITY_HIGH = "high"
+	ENUM_TASK_PRIORITY_URGENT = "urgent"
+
+	ENUM_SPRINT_STATE_PLANNED = "planned"
+	ENUM_SPRINT_STATE_ACTIVE = "active"
+	ENUM_SPRINT_STATE_CLOSED = "closed"
+
+	// Only blocks, relates_to and duplicates are stored; blocked_by and
+	// duplicated_by name the same links as seen from the other task.
+	ENUM_TASK_LINK_BLOCKS = "blocks"
+	ENUM_TASK_LINK_BLOCKED_BY = "blocked_by"
+	ENUM_TASK_LINK_RELATES_TO = "relates_to"
+	ENUM_TASK_LINK_DUPLICATES = "duplicates"
+	ENUM_TASK_LINK_DUPLICATED_BY = "duplicated_by"
+
+	ENUM_NOTIFICATION_TASK_ASSIGNED = "task_assigned"
+	ENUM_NOTIFICATION_TASK_UNASSIGNED = "task_unassigned"
+	ENUM_NOTIFICATION_TASK_STATUS_CHANGED = "task_status_changed"
+	ENUM_NOTIFICATION_TASK_COMMENT = "task_comment"
+	ENUM_NOTIFICATION_TASK_DUE_SOON = "task_due_soon"
+	ENUM_NOTIFICATION_TASK_OVERDUE = "task_overdue"
+
+	ENUM_ATTACHMENT_MAX_SIZE = 10 << 20
+
+	ENUM_EMAIL_STATUS_PENDING = "pending"
+	ENUM_EMAIL_STATUS_SENT = "sent"
+	ENUM_EMAIL_STATUS_FAILED = "failed"
+
+	ENUM_WEBHOOK_EVENT_TASK_CREATED = "task.created"
+	ENUM_WEBHOOK_EVENT_TASK_UPDATED = "task.updated"
+	ENUM_WEBHOOK_EVENT_TASK_ASSIGNED = "task.assigned"
+	ENUM_WEBHOOK_EVENT_TASK_UNASSIGNED = "task.unassigned"
+	ENUM_WEBHOOK_EVENT_TASK_DELETED = "task.deleted"
+	ENUM_WEBHOOK_EVENT_TEAM_UPDATED = "team.updated"
+	ENUM_WEBHOOK_EVENT_TEAM_DELETED = "team.deleted"
+
+	ENUM_WEBHOOK_DELIVERY_PENDING = "pending"
+	ENUM_WEBHOOK_DELIVERY_DELIVERED = "delivered"
+	ENUM_WEBHOOK_DELIVERY_FAILED = "failed"
+
 	ENUM_RUN_PRODUCTION = "production"
 	ENUM_RUN_TESTING = "testing"
 
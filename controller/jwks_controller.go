@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	JWKSController interface {
+		GetJWKS(ctx *gin.Context)
+	}
+
+	jwksController struct {
+		signingKeyService service.SigningKeyService
+	}
+)
+
+func NewJWKSController(sks service.SigningKeyService) JWKSController {
+	return &jwksController{
+		signingKeyService: sks,
+	}
+}
+
+// GetJWKS answers in plain RFC 7517 form rather than the usual response
+// envelope so standard JWT libraries can consume it directly.
+func (c *jwksController) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.signingKeyService.JWKS())
+}
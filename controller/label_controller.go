@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	LabelController interface {
+		Create(ctx *gin.Context)
+		GetLabelsByTeamId(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+	}
+
+	labelController struct {
+		labelService service.LabelService
+	}
+)
+
+func NewLabelController(ls service.LabelService) LabelController {
+	return &labelController{
+		labelService: ls,
+	}
+}
+
+func (c *labelController) Create(ctx *gin.Context) {
+	var req dto.LabelCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_LABEL, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.labelService.Create(ctx.Request.Context(), teamId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_LABEL, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_LABEL, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *labelController) GetLabelsByTeamId(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_LABEL, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.labelService.GetLabelsByTeamId(ctx.Request.Context(), teamId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_LABEL, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_LABEL, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *labelController) Update(ctx *gin.Context) {
+	var req dto.LabelUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_LABEL, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	labelId, err := strconv.Atoi(ctx.Param("labelId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_LABEL, dto.ErrLabelNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.labelService.Update(ctx.Request.Context(), teamId, labelId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_LABEL, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_LABEL, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *labelController) Delete(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_LABEL, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	labelId, err := strconv.Atoi(ctx.Param("labelId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_LABEL, dto.ErrLabelNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.labelService.Delete(ctx.Request.Context(), teamId, labelId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_LABEL, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_LABEL, nil)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	NotificationController interface {
+		GetNotifications(ctx *gin.Context)
+		CountUnread(ctx *gin.Context)
+		MarkAsRead(ctx *gin.Context)
+		MarkAllAsRead(ctx *gin.Context)
+		GetPreferences(ctx *gin.Context)
+		UpdatePreferences(ctx *gin.Context)
+	}
+
+	notificationController struct {
+		notificationService service.NotificationService
+	}
+)
+
+func NewNotificationController(ns service.NotificationService) NotificationController {
+	return &notificationController{
+		notificationService: ns,
+	}
+}
+
+func (c *notificationController) GetNotifications(ctx *gin.Context) {
+	var req dto.NotificationListRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.notificationService.GetNotifications(ctx.Request.Context(), userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_NOTIFICATION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_LIST_NOTIFICATION,
+		Data:    result.Data,
+		Meta:    result.PaginationResponse,
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *notificationController) CountUnread(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.notificationService.CountUnread(ctx.Request.Context(), userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_UNREAD_NOTIFICATION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_UNREAD_NOTIFICATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *notificationController) MarkAsRead(ctx *gin.Context) {
+	notificationId, err := strconv.Atoi(ctx.Param("notificationId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_READ_NOTIFICATION, dto.ErrNotificationNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+
+	if err := c.notificationService.MarkAsRead(ctx.Request.Context(), userId, notificationId); err != nil {
+		status := http.StatusBadRequest
+		if err == dto.ErrNotificationNotFound {
+			status = http.StatusNotFound
+		}
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_READ_NOTIFICATION, err.Error(), nil)
+		ctx.JSON(status, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_READ_NOTIFICATION, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *notificationController) MarkAllAsRead(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.notificationService.MarkAllAsRead(ctx.Request.Context(), userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_READ_NOTIFICATION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_READ_NOTIFICATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *notificationController) GetPreferences(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.notificationService.GetPreferences(ctx.Request.Context(), userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_NOTIFICATION_PREFERENCE, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_NOTIFICATION_PREFERENCE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *notificationController) UpdatePreferences(ctx *gin.Context) {
+	var req dto.NotificationPreferenceUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.notificationService.UpdatePreferences(ctx.Request.Context(), userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_NOTIFICATION_PREFERENCE, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_NOTIFICATION_PREFERENCE, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SprintController interface {
+		Create(ctx *gin.Context)
+		GetSprintsByTeamId(ctx *gin.Context)
+		GetSprint(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+		Start(ctx *gin.Context)
+		Close(ctx *gin.Context)
+		AddTask(ctx *gin.Context)
+		RemoveTask(ctx *gin.Context)
+	}
+
+	sprintController struct {
+		sprintService service.SprintService
+	}
+)
+
+func NewSprintController(ss service.SprintService) SprintController {
+	return &sprintController{
+		sprintService: ss,
+	}
+}
+
+func (c *sprintController) Create(ctx *gin.Context) {
+	var req dto.SprintCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_SPRINT, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.sprintService.Create(ctx.Request.Context(), teamId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_SPRINT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_SPRINT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sprintController) GetSprintsByTeamId(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_SPRINT, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.sprintService.GetSprintsByTeamId(ctx.Request.Context(), teamId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_SPRINT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_SPRINT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sprintController) GetSprint(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_SPRINT, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	sprintId, err := strconv.Atoi(ctx.Param("sprintId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_SPRINT, dto.ErrSprintNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.sprintService.GetSprint(ctx.Request.Context(), teamId, sprintId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_SPRINT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_SPRINT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sprintController) Update(ctx *gin.Context) {
+	var req dto.SprintUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_SPRINT, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	sprintId, err := strconv.Atoi(ctx.Param("sprintId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_SPRINT, dto.ErrSprintNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.sprintService.Update(ctx.Request.Context(), teamId, sprintId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_SPRINT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_SPRINT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sprintController) Delete(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_SPRINT, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	sprintId, err := strconv.Atoi(ctx.Param("sprintId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_SPRINT, dto.ErrSprintNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.sprintService.Delete(ctx.Request.Context(), teamId, sprintId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_SPRINT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_SPRINT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sprintController) Start(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_START_SPRINT, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	sprintId, err := strconv.Atoi(ctx.Param("sprintId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_START_SPRINT, dto.ErrSprintNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.sprintService.Start(ctx.Request.Context(), teamId, sprintId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_START_SPRINT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_START_SPRINT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sprintController) Close(ctx *gin.Context) {
+	var req dto.SprintCloseRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CLOSE_SPRINT, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	sprintId, err := strconv.Atoi(ctx.Param("sprintId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CLOSE_SPRINT, dto.ErrSprintNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.sprintService.Close(ctx.Request.Context(), teamId, sprintId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CLOSE_SPRINT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CLOSE_SPRINT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sprintController) AddTask(ctx *gin.Context) {
+	var req dto.SprintTaskRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ADD_SPRINT_TASK, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	sprintId, err := strconv.Atoi(ctx.Param("sprintId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ADD_SPRINT_TASK, dto.ErrSprintNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.sprintService.AddTask(ctx.Request.Context(), teamId, sprintId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ADD_SPRINT_TASK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_ADD_SPRINT_TASK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *sprintController) RemoveTask(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REMOVE_SPRINT_TASK, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	sprintId, err := strconv.Atoi(ctx.Param("sprintId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REMOVE_SPRINT_TASK, dto.ErrSprintNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId, err := strconv.Atoi(ctx.Param("taskId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REMOVE_SPRINT_TASK, dto.ErrTaskNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.sprintService.RemoveTask(ctx.Request.Context(), teamId, sprintId, taskId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REMOVE_SPRINT_TASK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REMOVE_SPRINT_TASK, nil)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/realtime"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// STREAM_HEARTBEAT keeps idle streams from being cut by proxies. The
+	// caller's access is checked again on every heartbeat.
+	STREAM_HEARTBEAT = 25 * time.Second
+
+	STREAM_EVENT_READY  = "ready"
+	STREAM_EVENT_CLOSED = "closed"
+)
+
+type (
+	StreamController interface {
+		StreamTeam(ctx *gin.Context)
+	}
+
+	streamController struct {
+		hub                  *realtime.Hub
+		jwtService           service.JWTService
+		authorizationService service.AuthorizationService
+		heartbeat            time.Duration
+	}
+)
+
+func NewStreamController(hub *realtime.Hub, jwtService service.JWTService, authorizationService service.AuthorizationService, heartbeat time.Duration) StreamController {
+	return &streamController{
+		hub:                  hub,
+		jwtService:           jwtService,
+		authorizationService: authorizationService,
+		heartbeat:            heartbeat,
+	}
+}
+
+// StreamTeam pushes the task events of a team as Server-Sent Events until
+// the client disconnects. Each SSE event is named after the change (e.g.
+// task.updated) and carries a realtime.Event as JSON.
+//
+// The stream ends with a "closed" event once the caller may no longer see
+// the team: when their access token expires, or when a heartbeat finds their
+// session revoked or their membership gone.
+func (c *streamController) StreamTeam(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_STREAM_TEAM, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	token := ctx.MustGet("token").(string)
+	claims := ctx.MustGet("claims").(dto.TokenClaims)
+
+	subscription := c.hub.Subscribe(teamId)
+	defer subscription.Close()
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+
+	var expired <-chan time.Time
+	if !claims.ExpiresAt.IsZero() {
+		expiry := time.NewTimer(time.Until(claims.ExpiresAt))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	ctx.SSEvent(STREAM_EVENT_READY, gin.H{"team_id": teamId})
+	ctx.Writer.Flush()
+
+	closed := func(reason error) bool {
+		ctx.SSEvent(STREAM_EVENT_CLOSED, gin.H{"reason": reason.Error()})
+		return false
+	}
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event)
+			return true
+		case <-expired:
+			return closed(dto.ErrTokenExpired)
+		case <-heartbeat.C:
+			if err := c.checkAccess(ctx, token); err != nil {
+				return closed(err)
+			}
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
+
+// checkAccess repeats what Authenticate and Authorize checked when the
+// stream was opened.
+func (c *streamController) checkAccess(ctx *gin.Context, token string) error {
+	claims, err := c.jwtService.ParseToken(ctx.Request.Context(), token)
+	if err != nil {
+		return err
+	}
+
+	return c.authorizationService.Authorize(ctx.Request.Context(), dto.AuthorizeRequest{
+		UserID: claims.UserID,
+		Role:   claims.Role,
+		Action: constants.ACTION_TASK_LIST_TEAM,
+		TeamID: ctx.Param("teamId"),
+	})
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	TaskAttachmentController interface {
+		Upload(ctx *gin.Context)
+		GetAttachmentsByTaskId(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+		Download(ctx *gin.Context)
+	}
+
+	taskAttachmentController struct {
+		taskAttachmentService service.TaskAttachmentService
+	}
+)
+
+func NewTaskAttachmentController(tas service.TaskAttachmentService) TaskAttachmentController {
+	return &taskAttachmentController{
+		taskAttachmentService: tas,
+	}
+}
+
+func (c *taskAttachmentController) Upload(ctx *gin.Context) {
+	var req dto.AttachmentUploadRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.taskAttachmentService.Upload(ctx.Request.Context(), taskId, userId, req.File)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == dto.ErrAttachmentTooLarge {
+			status = http.StatusRequestEntityTooLarge
+		} else if err == dto.ErrAttachmentTypeNotAllowed {
+			status = http.StatusUnsupportedMediaType
+		}
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPLOAD_ATTACHMENT, err.Error(), nil)
+		ctx.JSON(status, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPLOAD_ATTACHMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskAttachmentController) GetAttachmentsByTaskId(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskAttachmentService.GetAttachmentsByTaskId(ctx.Request.Context(), taskId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_ATTACHMENT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_ATTACHMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskAttachmentController) Delete(ctx *gin.Context) {
+	attachmentId, err := strconv.Atoi(ctx.Param("attachmentId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_ATTACHMENT, dto.ErrAttachmentNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	if err := c.taskAttachmentService.Delete(ctx.Request.Context(), taskId, attachmentId, userId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_ATTACHMENT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_ATTACHMENT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Download always sends the file as a download with the type sniffed at
+// upload, so the browser never renders it as a page of the API origin.
+func (c *taskAttachmentController) Download(ctx *gin.Context) {
+	attachmentId, err := strconv.Atoi(ctx.Param("attachmentId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DOWNLOAD_ATTACHMENT, dto.ErrAttachmentNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	attachment, body, err := c.taskAttachmentService.Download(ctx.Request.Context(), taskId, attachmentId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DOWNLOAD_ATTACHMENT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+	defer body.Close()
+
+	ctx.DataFromReader(http.StatusOK, attachment.Size, attachment.MimeType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	TaskChecklistController interface {
+		Create(ctx *gin.Context)
+		GetItemsByTaskId(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+		Reorder(ctx *gin.Context)
+	}
+
+	taskChecklistController struct {
+		taskChecklistService service.TaskChecklistService
+	}
+)
+
+func NewTaskChecklistController(tcs service.TaskChecklistService) TaskChecklistController {
+	return &taskChecklistController{
+		taskChecklistService: tcs,
+	}
+}
+
+func (c *taskChecklistController) Create(ctx *gin.Context) {
+	var req dto.ChecklistItemCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskChecklistService.Create(ctx.Request.Context(), taskId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_CHECKLIST_ITEM, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_CHECKLIST_ITEM, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskChecklistController) GetItemsByTaskId(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskChecklistService.GetItemsByTaskId(ctx.Request.Context(), taskId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_CHECKLIST, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_CHECKLIST, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskChecklistController) Update(ctx *gin.Context) {
+	var req dto.ChecklistItemUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	itemId, err := strconv.Atoi(ctx.Param("itemId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_CHECKLIST_ITEM, dto.ErrChecklistItemNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.taskChecklistService.Update(ctx.Request.Context(), taskId, itemId, userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_CHECKLIST_ITEM, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_CHECKLIST_ITEM, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskChecklistController) Delete(ctx *gin.Context) {
+	itemId, err := strconv.Atoi(ctx.Param("itemId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_CHECKLIST_ITEM, dto.ErrChecklistItemNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	if err := c.taskChecklistService.Delete(ctx.Request.Context(), taskId, itemId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_CHECKLIST_ITEM, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_CHECKLIST_ITEM, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskChecklistController) Reorder(ctx *gin.Context) {
+	var req dto.ChecklistReorderRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskChecklistService.Reorder(ctx.Request.Context(), taskId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REORDER_CHECKLIST_ITEMS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REORDER_CHECKLIST_ITEMS, result)
+	ctx.JSON(http.StatusOK, res)
+}
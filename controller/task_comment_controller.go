@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	TaskCommentController interface {
+		Create(ctx *gin.Context)
+		GetCommentsByTaskId(ctx *gin.Context)
+		Update(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+	}
+
+	taskCommentController struct {
+		taskCommentService service.TaskCommentService
+	}
+)
+
+func NewTaskCommentController(tcs service.TaskCommentService) TaskCommentController {
+	return &taskCommentController{
+		taskCommentService: tcs,
+	}
+}
+
+func (c *taskCommentController) Create(ctx *gin.Context) {
+	var req dto.CommentCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.taskCommentService.Create(ctx.Request.Context(), taskId, userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_COMMENT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_COMMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskCommentController) GetCommentsByTaskId(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskCommentService.GetCommentsByTaskId(ctx.Request.Context(), taskId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_COMMENT, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_COMMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskCommentController) Update(ctx *gin.Context) {
+	var req dto.CommentUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	commentId, err := strconv.Atoi(ctx.Param("commentId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_COMMENT, dto.ErrCommentNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.taskCommentService.Update(ctx.Request.Context(), taskId, commentId, userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_COMMENT, err.Error(), nil)
+		ctx.JSON(commentErrorStatus(err), res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_COMMENT, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskCommentController) Delete(ctx *gin.Context) {
+	commentId, err := strconv.Atoi(ctx.Param("commentId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_COMMENT, dto.ErrCommentNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	if err := c.taskCommentService.Delete(ctx.Request.Context(), taskId, commentId, userId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_COMMENT, err.Error(), nil)
+		ctx.AbortWithStatusJSON(commentErrorStatus(err), res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_COMMENT, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func commentErrorStatus(err error) int {
+	if err == dto.ErrNotCommentAuthor {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -15,6 +16,7 @@ type (
 		Register(ctx *gin.Context)
 		Task(ctx *gin.Context)
 		GetAllTask(ctx *gin.Context)
+		GetOverdueTasks(ctx *gin.Context)
 		GetTaskById(ctx *gin.Context)
 		GetTasksByTeamID(ctx *gin.Context) 
 		Update(ctx *gin.Context)
@@ -23,6 +25,15 @@ type (
 		RemoveUser(ctx *gin.Context)
 		GetAssignedUser(ctx *gin.Context)
 		GetTasksByUserID(ctx *gin.Context)
+		GetTaskHistory(ctx *gin.Context)
+		GetSubtasks(ctx *gin.Context)
+		MoveTask(ctx *gin.Context)
+		ReorderSubtasks(ctx *gin.Context)
+		GetAssignees(ctx *gin.Context)
+		AddAssignee(ctx *gin.Context)
+		RemoveAssignee(ctx *gin.Context)
+		WatchTask(ctx *gin.Context)
+		UnwatchTask(ctx *gin.Context)
 	}
 
 	taskController struct {
@@ -44,7 +55,8 @@ func (c *taskController) Register(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.taskService.Register(ctx.Request.Context(), task)
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.taskService.Register(ctx.Request.Context(), task, userId)
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REGISTER_TASK, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -56,14 +68,41 @@ func (c *taskController) Register(ctx *gin.Context) {
 }
 
 func (c *taskController) GetAllTask(ctx *gin.Context) {
-	var req dto.PaginationRequest
+	var req dto.TaskListRequest
 	if err := ctx.ShouldBind(&req); err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
 
-	result, err := c.taskService.GetAllTaskWithPagination(ctx.Request.Context(), req)
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.taskService.GetAllTaskWithPagination(ctx.Request.Context(), req, userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_TASK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_LIST_TASK,
+		Data:    result.Data,
+		Meta:    result.PaginationResponse,
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *taskController) GetOverdueTasks(ctx *gin.Context) {
+	var req dto.TaskListRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.taskService.GetOverdueTasks(ctx.Request.Context(), req, userId)
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_TASK, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -117,10 +156,18 @@ func (c *taskController) GetTasksByTeamID(ctx *gin.Context) {
         return
     }
 
-    tasks, err := c.taskService.GetTasksByTeamID(ctx.Request.Context(), teamIDInt)
+    var req dto.TaskListRequest
+    if err := ctx.ShouldBindQuery(&req); err != nil {
+        res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+        ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+        return
+    }
+
+    userId := ctx.MustGet("user_id").(string)
+    tasks, err := c.taskService.GetTasksByTeamID(ctx.Request.Context(), teamIDInt, req, userId)
     if err != nil {
         res := utils.BuildResponseFailed("Failed to get tasks", err.Error(), nil)
-        ctx.JSON(http.StatusInternalServerError, res)
+        ctx.JSON(http.StatusBadRequest, res)
         return
     }
 
@@ -138,8 +185,9 @@ func (c *taskController) Update(ctx *gin.Context) {
 	}
 
 	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
 
-	result, err := c.taskService.Update(ctx.Request.Context(), req, taskId)
+	result, err := c.taskService.Update(ctx.Request.Context(), req, taskId, userId)
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_TASK, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -152,8 +200,9 @@ func (c *taskController) Update(ctx *gin.Context) {
 
 func (c *taskController) Delete(ctx *gin.Context) {
 	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
 
-	if err := c.taskService.Delete(ctx.Request.Context(), taskId); err != nil {
+	if err := c.taskService.Delete(ctx.Request.Context(), taskId, userId); err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_TASK, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
@@ -173,7 +222,8 @@ func (c *taskController) AssignUser(ctx *gin.Context) {
         return
     }
 
-    err := c.taskService.AssignUserToTask(ctx.Request.Context(), taskId, &req.UserID)
+    actorId := ctx.MustGet("user_id").(string)
+    err := c.taskService.AssignUserToTask(ctx.Request.Context(), taskId, &req.UserID, actorId)
     if err != nil {
         res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ASSIGN_USER, err.Error(), nil)
         ctx.JSON(http.StatusBadRequest, res)
@@ -188,7 +238,8 @@ func (c *taskController) AssignUser(ctx *gin.Context) {
 func (c *taskController) RemoveUser(ctx *gin.Context) {
     taskId := ctx.Param("taskId")
 
-    err := c.taskService.RemoveUserFromTask(ctx.Request.Context(), taskId)
+    actorId := ctx.MustGet("user_id").(string)
+    err := c.taskService.RemoveUserFromTask(ctx.Request.Context(), taskId, actorId)
     if err != nil {
         res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REMOVE_USER, err.Error(), nil)
         ctx.JSON(http.StatusBadRequest, res)
@@ -216,10 +267,14 @@ func (c *taskController) GetAssignedUser(ctx *gin.Context) {
 func (c *taskController) GetTasksByUserID(ctx *gin.Context) {
     userID := ctx.Param("userId") 
 
-    tasks, err := c.taskService.GetTasksByUserID(ctx.Request.Context(), userID) 
+    tasks, err := c.taskService.GetTasksByUserID(ctx.Request.Context(), userID, ctx.MustGet("user_id").(string))
     if err != nil {
+        status := http.StatusInternalServerError
+        if errors.Is(err, dto.ErrPermissionDenied) {
+            status = http.StatusForbidden
+        }
         res := utils.BuildResponseFailed("Failed to get tasks", err.Error(), nil)
-        ctx.JSON(http.StatusInternalServerError, res)
+        ctx.JSON(status, res)
         return
     }
 
@@ -227,3 +282,165 @@ func (c *taskController) GetTasksByUserID(ctx *gin.Context) {
     ctx.JSON(http.StatusOK, res)
 }
 
+func (c *taskController) GetTaskHistory(ctx *gin.Context) {
+	var req dto.PaginationRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskService.GetTaskHistory(ctx.Request.Context(), taskId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_TASK_HISTORY, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_TASK_HISTORY,
+		Data:    result.Data,
+		Meta:    result.PaginationResponse,
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *taskController) GetSubtasks(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskService.GetSubtasks(ctx.Request.Context(), taskId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_SUBTASKS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_SUBTASKS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskController) MoveTask(ctx *gin.Context) {
+	var req dto.TaskMoveRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.taskService.MoveTask(ctx.Request.Context(), taskId, req, userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_MOVE_TASK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_MOVE_TASK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskController) ReorderSubtasks(ctx *gin.Context) {
+	var req dto.TaskReorderRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskService.ReorderSubtasks(ctx.Request.Context(), taskId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REORDER_TASKS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REORDER_TASKS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskController) GetAssignees(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskService.GetAssignees(ctx.Request.Context(), taskId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_ASSIGNEE, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_ASSIGNEE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskController) AddAssignee(ctx *gin.Context) {
+	var req dto.TaskAssigneeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	actorId := ctx.MustGet("user_id").(string)
+
+	result, err := c.taskService.AddAssignee(ctx.Request.Context(), taskId, req, actorId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ADD_ASSIGNEE, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_ADD_ASSIGNEE, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskController) RemoveAssignee(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+	actorId := ctx.MustGet("user_id").(string)
+
+	err := c.taskService.RemoveAssignee(ctx.Request.Context(), taskId, ctx.Param("userId"), actorId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REMOVE_ASSIGNEE, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REMOVE_ASSIGNEE, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskController) WatchTask(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	if err := c.taskService.WatchTask(ctx.Request.Context(), taskId, userId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_WATCH_TASK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_WATCH_TASK, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskController) UnwatchTask(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	if err := c.taskService.UnwatchTask(ctx.Request.Context(), taskId, userId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UNWATCH_TASK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UNWATCH_TASK, nil)
+	ctx.JSON(http.StatusOK, res)
+}
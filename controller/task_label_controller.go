@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	TaskLabelController interface {
+		GetTaskLabels(ctx *gin.Context)
+		AddTaskLabel(ctx *gin.Context)
+		RemoveTaskLabel(ctx *gin.Context)
+	}
+
+	taskLabelController struct {
+		labelService service.LabelService
+	}
+)
+
+func NewTaskLabelController(ls service.LabelService) TaskLabelController {
+	return &taskLabelController{
+		labelService: ls,
+	}
+}
+
+func (c *taskLabelController) GetTaskLabels(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+
+	result, err := c.labelService.GetTaskLabels(ctx.Request.Context(), taskId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_TASK_LABEL, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_TASK_LABEL, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskLabelController) AddTaskLabel(ctx *gin.Context) {
+	var req dto.TaskLabelRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	result, err := c.labelService.AddTaskLabel(ctx.Request.Context(), taskId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_ADD_TASK_LABEL, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_ADD_TASK_LABEL, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskLabelController) RemoveTaskLabel(ctx *gin.Context) {
+	labelId, err := strconv.Atoi(ctx.Param("labelId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REMOVE_TASK_LABEL, dto.ErrTaskLabelNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	if err := c.labelService.RemoveTaskLabel(ctx.Request.Context(), taskId, labelId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REMOVE_TASK_LABEL, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REMOVE_TASK_LABEL, nil)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	TaskLinkController interface {
+		Create(ctx *gin.Context)
+		GetLinksByTaskId(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+	}
+
+	taskLinkController struct {
+		taskLinkService service.TaskLinkService
+	}
+)
+
+func NewTaskLinkController(tls service.TaskLinkService) TaskLinkController {
+	return &taskLinkController{
+		taskLinkService: tls,
+	}
+}
+
+func (c *taskLinkController) Create(ctx *gin.Context) {
+	var req dto.TaskLinkCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.taskLinkService.Create(ctx.Request.Context(), taskId, userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_TASK_LINK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_TASK_LINK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskLinkController) GetLinksByTaskId(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+
+	result, err := c.taskLinkService.GetLinksByTaskId(ctx.Request.Context(), taskId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_TASK_LINK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_TASK_LINK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskLinkController) Delete(ctx *gin.Context) {
+	linkId, err := strconv.Atoi(ctx.Param("linkId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_TASK_LINK, dto.ErrTaskLinkNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	if err := c.taskLinkService.Delete(ctx.Request.Context(), taskId, linkId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_TASK_LINK, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_TASK_LINK, nil)
+	ctx.JSON(http.StatusOK, res)
+}
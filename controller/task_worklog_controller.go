@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	TaskWorklogController interface {
+		Create(ctx *gin.Context)
+		GetWorklogsByTaskId(ctx *gin.Context)
+		Delete(ctx *gin.Context)
+		StartTimer(ctx *gin.Context)
+		StopTimer(ctx *gin.Context)
+		GetSummary(ctx *gin.Context)
+	}
+
+	taskWorklogController struct {
+		worklogService service.WorklogService
+	}
+)
+
+func NewTaskWorklogController(ws service.WorklogService) TaskWorklogController {
+	return &taskWorklogController{
+		worklogService: ws,
+	}
+}
+
+func (c *taskWorklogController) Create(ctx *gin.Context) {
+	var req dto.WorklogCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.worklogService.Create(ctx.Request.Context(), taskId, userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_WORKLOG, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_WORKLOG, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskWorklogController) GetWorklogsByTaskId(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+
+	result, err := c.worklogService.GetWorklogsByTaskId(ctx.Request.Context(), taskId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_WORKLOG, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_WORKLOG, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskWorklogController) Delete(ctx *gin.Context) {
+	worklogId, err := strconv.Atoi(ctx.Param("worklogId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_WORKLOG, dto.ErrWorklogNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	if err := c.worklogService.Delete(ctx.Request.Context(), taskId, worklogId, userId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_WORKLOG, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_WORKLOG, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskWorklogController) StartTimer(ctx *gin.Context) {
+	var req dto.WorklogTimerRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.worklogService.StartTimer(ctx.Request.Context(), taskId, userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_START_TIMER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_START_TIMER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskWorklogController) StopTimer(ctx *gin.Context) {
+	taskId := ctx.Param("taskId")
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.worklogService.StopTimer(ctx.Request.Context(), taskId, userId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_STOP_TIMER, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_STOP_TIMER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *taskWorklogController) GetSummary(ctx *gin.Context) {
+	var req dto.WorklogSummaryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	taskId := ctx.Param("taskId")
+
+	result, err := c.worklogService.GetTaskSummary(ctx.Request.Context(), taskId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WORKLOG_SUMMARY, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_WORKLOG_SUMMARY, result)
+	ctx.JSON(http.StatusOK, res)
+}
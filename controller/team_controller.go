@@ -17,6 +17,7 @@ type (
 		GetTeamById(ctx *gin.Context)
 		Update(ctx *gin.Context)
 		Delete(ctx *gin.Context)
+		GetStatistics(ctx *gin.Context)
 	}
 
 	teamController struct {
@@ -38,7 +39,8 @@ func (c *teamController) Register(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.teamService.Register(ctx.Request.Context(), team)
+	userId := ctx.MustGet("user_id").(string)
+	result, err := c.teamService.Register(ctx.Request.Context(), team, userId)
 	if err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REGISTER_TEAM, err.Error(), nil)
 		ctx.JSON(http.StatusBadRequest, res)
@@ -127,8 +129,9 @@ func (c *teamController) Update(ctx *gin.Context) {
 
 func (c *teamController) Delete(ctx *gin.Context) {
 	teamId := ctx.Param("teamId")
+	userId := ctx.MustGet("user_id").(string)
 
-	if err := c.teamService.Delete(ctx.Request.Context(), teamId); err != nil {
+	if err := c.teamService.Delete(ctx.Request.Context(), teamId, userId); err != nil {
 		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_TEAM, err.Error(), nil)
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
@@ -138,3 +141,16 @@ func (c *teamController) Delete(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
+func (c *teamController) GetStatistics(ctx *gin.Context) {
+	teamId := ctx.Param("teamId")
+
+	result, err := c.teamService.GetStatistics(ctx.Request.Context(), teamId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_TEAM_STATISTICS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_TEAM_STATISTICS, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -71,7 +72,19 @@ func (c *UserTeamsController) RemoveUserFromTeam(ctx *gin.Context) {
         return
     }
 
-    if err := c.userTeamsService.RemoveUserFromTeam(userUuid, uint(teamID)); err != nil {
+    var reassignTo *uuid.UUID
+    if raw := ctx.Query("reassign_to"); raw != "" {
+        target, err := uuid.Parse(raw)
+        if err != nil {
+            log.Printf("Invalid reassign_to: %v", err)
+            ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid reassign_to user id"})
+            return
+        }
+        reassignTo = &target
+    }
+
+    actorId := ctx.MustGet("user_id").(string)
+    if err := c.userTeamsService.RemoveUserFromTeam(userUuid, uint(teamID), actorId, reassignTo); err != nil {
         log.Printf("Failed to remove user from team: %v", err)
         ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
         return
@@ -100,4 +113,37 @@ func (c *UserTeamsController) GetUsersByTeamId(ctx *gin.Context) {
     }
 
     ctx.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+func (c *UserTeamsController) ChangeRole(ctx *gin.Context) {
+    teamId := ctx.Param("teamId")
+    userId := ctx.Param("userId")
+
+    var req dto.ChangeTeamRoleRequest
+    if err := ctx.ShouldBind(&req); err != nil {
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    userUuid, err := uuid.Parse(userId)
+    if err != nil {
+        log.Printf("Invalid userId: %v", err)
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+        return
+    }
+
+    teamID, err := strconv.ParseUint(teamId, 10, 32)
+    if err != nil {
+        log.Printf("Invalid teamId: %v", err)
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid team id"})
+        return
+    }
+
+    if err := c.userTeamsService.ChangeRole(userUuid, uint(teamID), req.Role); err != nil {
+        log.Printf("Failed to change team role: %v", err)
+        ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    ctx.JSON(http.StatusOK, gin.H{"message": "team role changed successfully"})
 }
\ No newline at end of file
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	WebhookController interface {
+		CreateWebhook(ctx *gin.Context)
+		GetWebhooks(ctx *gin.Context)
+		GetWebhookById(ctx *gin.Context)
+		UpdateWebhook(ctx *gin.Context)
+		DeleteWebhook(ctx *gin.Context)
+		GetDeliveries(ctx *gin.Context)
+		Redeliver(ctx *gin.Context)
+	}
+
+	webhookController struct {
+		webhookService service.WebhookService
+	}
+)
+
+func NewWebhookController(ws service.WebhookService) WebhookController {
+	return &webhookController{
+		webhookService: ws,
+	}
+}
+
+func (c *webhookController) CreateWebhook(ctx *gin.Context) {
+	var req dto.WebhookCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_WEBHOOK, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.webhookService.CreateWebhook(ctx.Request.Context(), teamId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_WEBHOOK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_WEBHOOK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) GetWebhooks(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_WEBHOOK, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.webhookService.GetWebhooks(ctx.Request.Context(), teamId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_LIST_WEBHOOK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_WEBHOOK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) GetWebhookById(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WEBHOOK, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	webhookId, err := strconv.Atoi(ctx.Param("webhookId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WEBHOOK, dto.ErrWebhookNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.webhookService.GetWebhookById(ctx.Request.Context(), teamId, webhookId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WEBHOOK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_WEBHOOK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) UpdateWebhook(ctx *gin.Context) {
+	var req dto.WebhookUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_WEBHOOK, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	webhookId, err := strconv.Atoi(ctx.Param("webhookId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_WEBHOOK, dto.ErrWebhookNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.webhookService.UpdateWebhook(ctx.Request.Context(), teamId, webhookId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_WEBHOOK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_WEBHOOK, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) DeleteWebhook(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_WEBHOOK, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	webhookId, err := strconv.Atoi(ctx.Param("webhookId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_WEBHOOK, dto.ErrWebhookNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.webhookService.DeleteWebhook(ctx.Request.Context(), teamId, webhookId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_WEBHOOK, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_WEBHOOK, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) GetDeliveries(ctx *gin.Context) {
+	var req dto.PaginationRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WEBHOOK_DELIVERY, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	webhookId, err := strconv.Atoi(ctx.Param("webhookId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WEBHOOK_DELIVERY, dto.ErrWebhookNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.webhookService.GetDeliveries(ctx.Request.Context(), teamId, webhookId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WEBHOOK_DELIVERY, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	resp := utils.Response{
+		Status:  true,
+		Message: dto.MESSAGE_SUCCESS_GET_WEBHOOK_DELIVERY,
+		Data:    result.Data,
+		Meta:    result.PaginationResponse,
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (c *webhookController) Redeliver(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REDELIVER_WEBHOOK, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	webhookId, err := strconv.Atoi(ctx.Param("webhookId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REDELIVER_WEBHOOK, dto.ErrWebhookNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	deliveryId, err := strconv.Atoi(ctx.Param("deliveryId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REDELIVER_WEBHOOK, dto.ErrWebhookDeliveryNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.webhookService.Redeliver(ctx.Request.Context(), teamId, webhookId, deliveryId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_REDELIVER_WEBHOOK, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REDELIVER_WEBHOOK, result)
+	ctx.JSON(http.StatusOK, res)
+}
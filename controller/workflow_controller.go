@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	WorkflowController interface {
+		GetWorkflow(ctx *gin.Context)
+		CreateStatus(ctx *gin.Context)
+		UpdateStatus(ctx *gin.Context)
+		DeleteStatus(ctx *gin.Context)
+		CreateTransition(ctx *gin.Context)
+		DeleteTransition(ctx *gin.Context)
+	}
+
+	workflowController struct {
+		workflowService service.WorkflowService
+	}
+)
+
+func NewWorkflowController(ws service.WorkflowService) WorkflowController {
+	return &workflowController{
+		workflowService: ws,
+	}
+}
+
+func (c *workflowController) GetWorkflow(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WORKFLOW, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.workflowService.GetWorkflow(ctx.Request.Context(), teamId)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WORKFLOW, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_WORKFLOW, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *workflowController) CreateStatus(ctx *gin.Context) {
+	var req dto.WorkflowStatusCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_STATUS, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.workflowService.CreateStatus(ctx.Request.Context(), teamId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_STATUS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_STATUS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *workflowController) UpdateStatus(ctx *gin.Context) {
+	var req dto.WorkflowStatusUpdateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_STATUS, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	statusId, err := strconv.Atoi(ctx.Param("statusId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_STATUS, dto.ErrWorkflowStatusNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.workflowService.UpdateStatus(ctx.Request.Context(), teamId, statusId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_STATUS, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_STATUS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *workflowController) DeleteStatus(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_STATUS, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	statusId, err := strconv.Atoi(ctx.Param("statusId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_STATUS, dto.ErrWorkflowStatusNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.workflowService.DeleteStatus(ctx.Request.Context(), teamId, statusId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_STATUS, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_STATUS, nil)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *workflowController) CreateTransition(ctx *gin.Context) {
+	var req dto.WorkflowTransitionCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_TRANSITION, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.workflowService.CreateTransition(ctx.Request.Context(), teamId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_CREATE_TRANSITION, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_TRANSITION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *workflowController) DeleteTransition(ctx *gin.Context) {
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_TRANSITION, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	transitionId, err := strconv.Atoi(ctx.Param("transitionId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_TRANSITION, dto.ErrTransitionNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	if err := c.workflowService.DeleteTransition(ctx.Request.Context(), teamId, transitionId); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DELETE_TRANSITION, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_TRANSITION, nil)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	WorklogController interface {
+		GetTeamSummary(ctx *gin.Context)
+		GetMySummary(ctx *gin.Context)
+	}
+
+	worklogController struct {
+		worklogService service.WorklogService
+	}
+)
+
+func NewWorklogController(ws service.WorklogService) WorklogController {
+	return &worklogController{
+		worklogService: ws,
+	}
+}
+
+func (c *worklogController) GetTeamSummary(ctx *gin.Context) {
+	var req dto.WorklogSummaryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	teamId, err := strconv.Atoi(ctx.Param("teamId"))
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WORKLOG_SUMMARY, dto.ErrTeamNotFound.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	result, err := c.worklogService.GetTeamSummary(ctx.Request.Context(), teamId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WORKLOG_SUMMARY, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_WORKLOG_SUMMARY, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *worklogController) GetMySummary(ctx *gin.Context) {
+	var req dto.WorklogSummaryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.worklogService.GetUserSummary(ctx.Request.Context(), userId, req)
+	if err != nil {
+		res := utils.BuildResponseFailed(dto.MESSAGE_FAILED_GET_WORKLOG_SUMMARY, err.Error(), nil)
+		ctx.JSON(http.StatusBadRequest, res)
+		return
+	}
+
+	res := utils.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_WORKLOG_SUMMARY, result)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "errors"
+
+const (
+	// Failed
+	MESSAGE_FAILED_AUTHORIZE = "failed authorize request"
+)
+
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrUnknownAction    = errors.New("unknown action")
+	ErrNotTeamMember    = errors.New("user is not a member of this team")
+)
+
+type (
+	AuthorizeRequest struct {
+		UserID string
+		Role   string
+		Action string
+		TeamID string
+		TaskID string
+	}
+)
@@ -0,0 +1,27 @@
+package dto
+
+import "errors"
+
+var (
+	ErrSigningKeyNotFound = errors.New("no signing key available")
+	ErrUnknownKeyID       = errors.New("unknown key id")
+)
+
+type (
+	// JWK is a public key in RFC 7517 format. RSA keys fill N and E, Ed25519
+	// keys fill Crv and X.
+	JWK struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+	}
+
+	JWKSResponse struct {
+		Keys []JWK `json:"keys"`
+	}
+)
@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_LABEL        = "failed create label"
+	MESSAGE_FAILED_GET_LIST_LABEL      = "failed get list label"
+	MESSAGE_FAILED_UPDATE_LABEL        = "failed update label"
+	MESSAGE_FAILED_DELETE_LABEL        = "failed delete label"
+	MESSAGE_FAILED_GET_LIST_TASK_LABEL = "failed get list task label"
+	MESSAGE_FAILED_ADD_TASK_LABEL      = "failed add task label"
+	MESSAGE_FAILED_REMOVE_TASK_LABEL   = "failed remove task label"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_LABEL        = "success create label"
+	MESSAGE_SUCCESS_GET_LIST_LABEL      = "success get list label"
+	MESSAGE_SUCCESS_UPDATE_LABEL        = "success update label"
+	MESSAGE_SUCCESS_DELETE_LABEL        = "success delete label"
+	MESSAGE_SUCCESS_GET_LIST_TASK_LABEL = "success get list task label"
+	MESSAGE_SUCCESS_ADD_TASK_LABEL      = "success add task label"
+	MESSAGE_SUCCESS_REMOVE_TASK_LABEL   = "success remove task label"
+)
+
+var (
+	ErrCreateLabel       = errors.New("failed to create label")
+	ErrGetAllLabel       = errors.New("failed to get all label")
+	ErrUpdateLabel       = errors.New("failed to update label")
+	ErrDeleteLabel       = errors.New("failed to delete label")
+	ErrLabelNotFound     = errors.New("label not found")
+	ErrLabelExists       = errors.New("label already exists")
+	ErrGetAllTaskLabel   = errors.New("failed to get all task label")
+	ErrAddTaskLabel      = errors.New("failed to add task label")
+	ErrRemoveTaskLabel   = errors.New("failed to remove task label")
+	ErrTaskLabelNotFound = errors.New("task does not have this label")
+)
+
+type (
+	// LabelCreateRequest adds a label to a team's catalog. Color is a
+	// #RRGGBB hex color.
+	LabelCreateRequest struct {
+		Name  string `json:"name" form:"name" binding:"required,max=50"`
+		Color string `json:"color" form:"color" binding:"required,len=7,hexcolor"`
+	}
+
+	LabelUpdateRequest struct {
+		Name  string `json:"name" form:"name" binding:"required,max=50"`
+		Color string `json:"color" form:"color" binding:"required,len=7,hexcolor"`
+	}
+
+	LabelResponse struct {
+		ID        int       `json:"id"`
+		TeamsID   int       `json:"teams_id"`
+		Name      string    `json:"name"`
+		Color     string    `json:"color"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	TaskLabelRequest struct {
+		LabelID int `json:"label_id" form:"label_id" binding:"required"`
+	}
+
+	// LabelCount is how many of a team's tasks carry a label.
+	LabelCount struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+		Count int64  `json:"count"`
+	}
+)
@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_LIST_NOTIFICATION          = "failed get list notification"
+	MESSAGE_FAILED_GET_UNREAD_NOTIFICATION        = "failed get unread notification count"
+	MESSAGE_FAILED_READ_NOTIFICATION              = "failed mark notification as read"
+	MESSAGE_FAILED_GET_NOTIFICATION_PREFERENCE    = "failed get notification preference"
+	MESSAGE_FAILED_UPDATE_NOTIFICATION_PREFERENCE = "failed update notification preference"
+
+	// Success
+	MESSAGE_SUCCESS_GET_LIST_NOTIFICATION          = "success get list notification"
+	MESSAGE_SUCCESS_GET_UNREAD_NOTIFICATION        = "success get unread notification count"
+	MESSAGE_SUCCESS_READ_NOTIFICATION              = "success mark notification as read"
+	MESSAGE_SUCCESS_GET_NOTIFICATION_PREFERENCE    = "success get notification preference"
+	MESSAGE_SUCCESS_UPDATE_NOTIFICATION_PREFERENCE = "success update notification preference"
+)
+
+var (
+	ErrGetAllNotification           = errors.New("failed to get all notification")
+	ErrNotificationNotFound         = errors.New("notification not found")
+	ErrReadNotification             = errors.New("failed to mark notification as read")
+	ErrGetNotificationPreference    = errors.New("failed to get notification preference")
+	ErrUpdateNotificationPreference = errors.New("failed to update notification preference")
+	ErrSendNotification             = errors.New("failed to send notification")
+)
+
+type (
+	NotificationListRequest struct {
+		PaginationRequest
+		Unread bool `form:"unread"`
+	}
+
+	NotificationResponse struct {
+		ID        int        `json:"id"`
+		Type      string     `json:"type"`
+		TaskID    *int       `json:"task_id,omitempty"`
+		ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+		Title     string     `json:"title"`
+		Body      string     `json:"body"`
+		IsRead    bool       `json:"is_read"`
+		ReadAt    *time.Time `json:"read_at,omitempty"`
+		CreatedAt time.Time  `json:"created_at"`
+	}
+
+	NotificationPaginationResponse struct {
+		Data []NotificationResponse `json:"data"`
+		PaginationResponse
+	}
+
+	GetAllNotificationRepositoryResponse struct {
+		Notifications []entity.Notification
+		PaginationResponse
+	}
+
+	NotificationUnreadResponse struct {
+		Unread int64 `json:"unread"`
+	}
+
+	NotificationReadAllResponse struct {
+		Updated int64 `json:"updated"`
+	}
+
+	NotificationPreferenceResponse struct {
+		Type  string `json:"type"`
+		InApp bool   `json:"in_app"`
+		Email bool   `json:"email"`
+	}
+
+	NotificationPreferenceItem struct {
+		Type  string `json:"type" binding:"required,oneof=task_assigned task_unassigned task_status_changed task_comment task_due_soon task_overdue"`
+		InApp bool   `json:"in_app"`
+		Email bool   `json:"email"`
+	}
+
+	NotificationPreferenceUpdateRequest struct {
+		Preferences []NotificationPreferenceItem `json:"preferences" binding:"required,dive"`
+	}
+)
@@ -1,20 +1,38 @@
 package dto
 
+import "errors"
+
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+)
+
 type (
+	// PaginationRequest pages by offset by default. Setting keyset=true (or
+	// passing a cursor) switches to keyset pagination, which skips the
+	// COUNT(*) query unless with_count=true.
 	PaginationRequest struct {
-		Search  string `form:"search"`
-		Page    int    `form:"page"`
-		PerPage int    `form:"per_page"`
+		Search    string `form:"search"`
+		Page      int    `form:"page"`
+		PerPage   int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+		Keyset    bool   `form:"keyset"`
+		Cursor    string `form:"cursor"`
+		WithCount bool   `form:"with_count"`
 	}
 
 	PaginationResponse struct {
-		Page    int   `json:"page"`
-		PerPage int   `json:"per_page"`
-		MaxPage int64 `json:"max_page"`
-		Count   int64 `json:"count"`
+		Page       int    `json:"page"`
+		PerPage    int    `json:"per_page"`
+		MaxPage    int64  `json:"max_page"`
+		Count      int64  `json:"count"`
+		NextCursor string `json:"next_cursor,omitempty"`
+		PrevCursor string `json:"prev_cursor,omitempty"`
 	}
 )
 
+func (p *PaginationRequest) IsKeyset() bool {
+	return p.Keyset || p.Cursor != ""
+}
+
 func (p *PaginationRequest) GetOffset() int {
 	return (p.Page - 1) * p.PerPage
 }
@@ -0,0 +1,133 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_SPRINT      = "failed create sprint"
+	MESSAGE_FAILED_GET_LIST_SPRINT    = "failed get list sprint"
+	MESSAGE_FAILED_GET_SPRINT         = "failed get sprint"
+	MESSAGE_FAILED_UPDATE_SPRINT      = "failed update sprint"
+	MESSAGE_FAILED_DELETE_SPRINT      = "failed delete sprint"
+	MESSAGE_FAILED_START_SPRINT       = "failed start sprint"
+	MESSAGE_FAILED_CLOSE_SPRINT       = "failed close sprint"
+	MESSAGE_FAILED_ADD_SPRINT_TASK    = "failed add sprint task"
+	MESSAGE_FAILED_REMOVE_SPRINT_TASK = "failed remove sprint task"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_SPRINT      = "success create sprint"
+	MESSAGE_SUCCESS_GET_LIST_SPRINT    = "success get list sprint"
+	MESSAGE_SUCCESS_GET_SPRINT         = "success get sprint"
+	MESSAGE_SUCCESS_UPDATE_SPRINT      = "success update sprint"
+	MESSAGE_SUCCESS_DELETE_SPRINT      = "success delete sprint"
+	MESSAGE_SUCCESS_START_SPRINT       = "success start sprint"
+	MESSAGE_SUCCESS_CLOSE_SPRINT       = "success close sprint"
+	MESSAGE_SUCCESS_ADD_SPRINT_TASK    = "success add sprint task"
+	MESSAGE_SUCCESS_REMOVE_SPRINT_TASK = "success remove sprint task"
+)
+
+var (
+	ErrCreateSprint        = errors.New("failed to create sprint")
+	ErrGetAllSprint        = errors.New("failed to get all sprint")
+	ErrGetSprint           = errors.New("failed to get sprint")
+	ErrUpdateSprint        = errors.New("failed to update sprint")
+	ErrDeleteSprint        = errors.New("failed to delete sprint")
+	ErrStartSprint         = errors.New("failed to start sprint")
+	ErrCloseSprint         = errors.New("failed to close sprint")
+	ErrAddSprintTask       = errors.New("failed to add task to sprint")
+	ErrRemoveSprintTask    = errors.New("failed to remove task from sprint")
+	ErrSprintNotFound      = errors.New("sprint not found")
+	ErrInvalidSprintDates  = errors.New("sprint dates must be RFC3339 or YYYY-MM-DD and end after the start")
+	ErrSprintAlreadyActive = errors.New("team already has an active sprint")
+	ErrSprintNotPlanned    = errors.New("sprint has already started")
+	ErrSprintNotActive     = errors.New("sprint is not active")
+	ErrSprintClosed        = errors.New("sprint is closed")
+	ErrInvalidNextSprint   = errors.New("next sprint must be a planned sprint of the same team")
+	ErrSprintTaskOtherTeam = errors.New("task belongs to another team")
+	ErrTaskNotInSprint     = errors.New("task is not in this sprint")
+)
+
+type (
+	// SprintCreateRequest plans a sprint. Dates are RFC3339 or YYYY-MM-DD.
+	SprintCreateRequest struct {
+		Name      string `json:"name" form:"name" binding:"required,max=100"`
+		Goal      string `json:"goal" form:"goal" binding:"max=2000"`
+		StartDate string `json:"start_date" form:"start_date" binding:"required"`
+		EndDate   string `json:"end_date" form:"end_date" binding:"required"`
+	}
+
+	SprintUpdateRequest struct {
+		Name      string `json:"name" form:"name" binding:"required,max=100"`
+		Goal      string `json:"goal" form:"goal" binding:"max=2000"`
+		StartDate string `json:"start_date" form:"start_date" binding:"required"`
+		EndDate   string `json:"end_date" form:"end_date" binding:"required"`
+	}
+
+	// SprintCloseRequest names the sprint that receives the unfinished
+	// tasks. When it is omitted they go to the team's next planned sprint,
+	// or back to the backlog if there is none.
+	SprintCloseRequest struct {
+		NextSprintID *int `json:"next_sprint_id" form:"next_sprint_id"`
+	}
+
+	SprintTaskRequest struct {
+		TaskID int `json:"task_id" form:"task_id" binding:"required"`
+	}
+
+	SprintResponse struct {
+		ID        int        `json:"id"`
+		TeamsID   int        `json:"teams_id"`
+		Name      string     `json:"name"`
+		Goal      string     `json:"goal"`
+		StartDate time.Time  `json:"start_date"`
+		EndDate   time.Time  `json:"end_date"`
+		State     string     `json:"state"`
+		ClosedAt  *time.Time `json:"closed_at,omitempty"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt time.Time  `json:"updated_at"`
+	}
+
+	SprintDetailResponse struct {
+		SprintResponse
+		Summary SprintSummary       `json:"summary"`
+		Tasks   []SprintTaskSummary `json:"tasks"`
+	}
+
+	// SprintSummary totals the sprint's tasks. A task is done once its
+	// status is in the done category of the team's workflow.
+	SprintSummary struct {
+		TaskCount       int `json:"task_count"`
+		DoneCount       int `json:"done_count"`
+		Estimate        int `json:"estimate"`
+		DoneEstimate    int `json:"done_estimate"`
+		RemainingEffort int `json:"remaining_effort"`
+	}
+
+	// SprintTaskSummary is one task of a sprint. On a closed sprint it is
+	// the task as it stood at closing, and CarriedOver marks the unfinished
+	// ones that moved on.
+	SprintTaskSummary struct {
+		ID              int        `json:"id"`
+		Title           string     `json:"title"`
+		Status          string     `json:"status"`
+		IsDone          bool       `json:"is_done"`
+		CarriedOver     bool       `json:"carried_over"`
+		UserID          *uuid.UUID `json:"user_id,omitempty"`
+		Priority        string     `json:"priority"`
+		Estimate        int        `json:"estimate"`
+		RemainingEffort int        `json:"remaining_effort"`
+	}
+
+	// SprintCloseResponse reports where the unfinished tasks went.
+	// NextSprintID is null when they went back to the backlog.
+	SprintCloseResponse struct {
+		Sprint       SprintResponse `json:"sprint"`
+		CarriedOver  []int          `json:"carried_over"`
+		NextSprintID *int           `json:"next_sprint_id"`
+	}
+)
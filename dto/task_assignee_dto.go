@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_ADD_ASSIGNEE      = "failed add assignee"
+	MESSAGE_FAILED_GET_LIST_ASSIGNEE = "failed get list assignee"
+	MESSAGE_FAILED_REMOVE_ASSIGNEE   = "failed remove assignee"
+	MESSAGE_FAILED_WATCH_TASK        = "failed watch task"
+	MESSAGE_FAILED_UNWATCH_TASK      = "failed unwatch task"
+
+	// Success
+	MESSAGE_SUCCESS_ADD_ASSIGNEE      = "success add assignee"
+	MESSAGE_SUCCESS_GET_LIST_ASSIGNEE = "success get list assignee"
+	MESSAGE_SUCCESS_REMOVE_ASSIGNEE   = "success remove assignee"
+	MESSAGE_SUCCESS_WATCH_TASK        = "success watch task"
+	MESSAGE_SUCCESS_UNWATCH_TASK      = "success unwatch task"
+)
+
+var (
+	ErrAddAssignee         = errors.New("failed to add assignee")
+	ErrGetAllAssignee      = errors.New("failed to get all assignee")
+	ErrRemoveAssignee      = errors.New("failed to remove assignee")
+	ErrAssigneeNotFound    = errors.New("user is not on this task")
+	ErrInvalidAssigneeRole = errors.New("invalid assignee role")
+	ErrNotWatching         = errors.New("you are not watching this task")
+)
+
+type (
+	// TaskAssigneeRequest puts a user on a task, or changes their role if
+	// they are already on it. Role defaults to assignee.
+	TaskAssigneeRequest struct {
+		UserID uuid.UUID `json:"user_id" form:"user_id" binding:"required"`
+		Role   string    `json:"role" form:"role"`
+	}
+
+	TaskAssigneeResponse struct {
+		User      UserResponse `json:"user"`
+		Role      string       `json:"role"`
+		CreatedAt time.Time    `json:"created_at"`
+	}
+)
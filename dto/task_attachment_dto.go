@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"errors"
+	"mime/multipart"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_UPLOAD_ATTACHMENT   = "failed upload attachment"
+	MESSAGE_FAILED_GET_LIST_ATTACHMENT = "failed get list attachment"
+	MESSAGE_FAILED_DELETE_ATTACHMENT   = "failed delete attachment"
+	MESSAGE_FAILED_DOWNLOAD_ATTACHMENT = "failed download attachment"
+
+	// Success
+	MESSAGE_SUCCESS_UPLOAD_ATTACHMENT   = "success upload attachment"
+	MESSAGE_SUCCESS_GET_LIST_ATTACHMENT = "success get list attachment"
+	MESSAGE_SUCCESS_DELETE_ATTACHMENT   = "success delete attachment"
+)
+
+var (
+	ErrUploadAttachment         = errors.New("failed to upload attachment")
+	ErrGetAllAttachment         = errors.New("failed to get all attachment")
+	ErrDeleteAttachment         = errors.New("failed to delete attachment")
+	ErrDownloadAttachment       = errors.New("failed to download attachment")
+	ErrAttachmentNotFound       = errors.New("attachment not found")
+	ErrAttachmentTooLarge       = errors.New("attachment exceeds the maximum size")
+	ErrAttachmentTypeNotAllowed = errors.New("attachment type is not allowed")
+)
+
+type (
+	AttachmentUploadRequest struct {
+		File *multipart.FileHeader `form:"file" binding:"required"`
+	}
+
+	// AttachmentResponse.Url points at the download endpoint, which needs
+	// the same authorization as reading the task.
+	AttachmentResponse struct {
+		ID        int          `json:"id"`
+		TaskID    int          `json:"task_id"`
+		FileName  string       `json:"file_name"`
+		Url       string       `json:"url"`
+		MimeType  string       `json:"mime_type"`
+		Size      int64        `json:"size"`
+		Checksum  string       `json:"checksum"`
+		Uploader  UserResponse `json:"uploader"`
+		CreatedAt time.Time    `json:"created_at"`
+	}
+)
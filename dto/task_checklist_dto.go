@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_CHECKLIST_ITEM   = "failed create checklist item"
+	MESSAGE_FAILED_GET_CHECKLIST           = "failed get checklist"
+	MESSAGE_FAILED_UPDATE_CHECKLIST_ITEM   = "failed update checklist item"
+	MESSAGE_FAILED_DELETE_CHECKLIST_ITEM   = "failed delete checklist item"
+	MESSAGE_FAILED_REORDER_CHECKLIST_ITEMS = "failed reorder checklist items"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_CHECKLIST_ITEM   = "success create checklist item"
+	MESSAGE_SUCCESS_GET_CHECKLIST           = "success get checklist"
+	MESSAGE_SUCCESS_UPDATE_CHECKLIST_ITEM   = "success update checklist item"
+	MESSAGE_SUCCESS_DELETE_CHECKLIST_ITEM   = "success delete checklist item"
+	MESSAGE_SUCCESS_REORDER_CHECKLIST_ITEMS = "success reorder checklist items"
+)
+
+var (
+	ErrCreateChecklistItem   = errors.New("failed to create checklist item")
+	ErrGetChecklist          = errors.New("failed to get checklist")
+	ErrUpdateChecklistItem   = errors.New("failed to update checklist item")
+	ErrDeleteChecklistItem   = errors.New("failed to delete checklist item")
+	ErrReorderChecklistItems = errors.New("failed to reorder checklist items")
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrInvalidChecklistOrder = errors.New("order must list every checklist item exactly once")
+)
+
+type (
+	ChecklistItemCreateRequest struct {
+		Title string `json:"title" form:"title" binding:"required,max=255"`
+	}
+
+	// ChecklistItemUpdateRequest only changes the fields that are sent.
+	ChecklistItemUpdateRequest struct {
+		Title  *string `json:"title" form:"title" binding:"omitempty,min=1,max=255"`
+		IsDone *bool   `json:"is_done" form:"is_done"`
+	}
+
+	ChecklistReorderRequest struct {
+		ItemIDs []int `json:"item_ids" binding:"required"`
+	}
+
+	ChecklistItemResponse struct {
+		ID          int        `json:"id"`
+		TaskID      int        `json:"task_id"`
+		Title       string     `json:"title"`
+		IsDone      bool       `json:"is_done"`
+		Position    int        `json:"position"`
+		CompletedBy *uuid.UUID `json:"completed_by,omitempty"`
+		CompletedAt *time.Time `json:"completed_at,omitempty"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   time.Time  `json:"updated_at"`
+	}
+
+	// ChecklistCount is the number of checklist items of a task and how
+	// many of them are done.
+	ChecklistCount struct {
+		TaskID    int
+		Total     int64
+		Completed int64
+	}
+)
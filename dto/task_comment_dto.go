@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_COMMENT   = "failed create comment"
+	MESSAGE_FAILED_GET_LIST_COMMENT = "failed get list comment"
+	MESSAGE_FAILED_UPDATE_COMMENT   = "failed update comment"
+	MESSAGE_FAILED_DELETE_COMMENT   = "failed delete comment"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_COMMENT   = "success create comment"
+	MESSAGE_SUCCESS_GET_LIST_COMMENT = "success get list comment"
+	MESSAGE_SUCCESS_UPDATE_COMMENT   = "success update comment"
+	MESSAGE_SUCCESS_DELETE_COMMENT   = "success delete comment"
+)
+
+var (
+	ErrCreateComment         = errors.New("failed to create comment")
+	ErrGetAllComment         = errors.New("failed to get all comment")
+	ErrUpdateComment         = errors.New("failed to update comment")
+	ErrDeleteComment         = errors.New("failed to delete comment")
+	ErrCommentNotFound       = errors.New("comment not found")
+	ErrParentCommentNotFound = errors.New("parent comment not found on this task")
+	ErrNotCommentAuthor      = errors.New("only the author can change this comment")
+)
+
+type (
+	CommentCreateRequest struct {
+		Body     string `json:"body" form:"body" binding:"required"`
+		ParentID *int   `json:"parent_id" form:"parent_id"`
+	}
+
+	CommentUpdateRequest struct {
+		Body string `json:"body" form:"body" binding:"required"`
+	}
+
+	CommentResponse struct {
+		ID        int               `json:"id"`
+		TaskID    int               `json:"task_id"`
+		ParentID  *int              `json:"parent_id,omitempty"`
+		Body      string            `json:"body"`
+		IsDeleted bool              `json:"is_deleted"`
+		Author    UserResponse      `json:"author"`
+		Mentions  []UserResponse    `json:"mentions"`
+		Replies   []CommentResponse `json:"replies"`
+		CreatedAt time.Time         `json:"created_at"`
+		UpdatedAt time.Time         `json:"updated_at"`
+	}
+)
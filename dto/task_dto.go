@@ -17,6 +17,9 @@ const (
 	MESSAGE_FAILED_DELETE_TASK   = "failed delete task"
 	MESSAGE_FAILED_ASSIGN_USER   = "failed to assign user to task"
 	MESSAGE_FAILED_REMOVE_USER   = "failed to remove user from task"
+	MESSAGE_FAILED_GET_SUBTASKS  = "failed get subtasks"
+	MESSAGE_FAILED_MOVE_TASK     = "failed move task"
+	MESSAGE_FAILED_REORDER_TASKS = "failed reorder subtasks"
 
 	// Success
 	MESSAGE_SUCCESS_REGISTER_TASK = "success create task"
@@ -26,6 +29,9 @@ const (
 	MESSAGE_SUCCESS_DELETE_TASK   = "success delete task"
 	MESSAGE_SUCCESS_ASSIGN_USER   = "successfully assigned user to task"
 	MESSAGE_SUCCESS_REMOVE_USER   = "successfully removed user from task"
+	MESSAGE_SUCCESS_GET_SUBTASKS  = "success get subtasks"
+	MESSAGE_SUCCESS_MOVE_TASK     = "success move task"
+	MESSAGE_SUCCESS_REORDER_TASKS = "success reorder subtasks"
 )
 
 var (
@@ -37,6 +43,21 @@ var (
 	ErrDeleteTask   = errors.New("failed to delete task")
 	ErrAssignUser   = errors.New("failed to assign user to task")
 	ErrRemoveUser   = errors.New("failed to remove user from task")
+
+	ErrAssigneeNotTeamMember = errors.New("assignee is not a member of the task's team")
+	ErrReassignTasks         = errors.New("failed to reassign tasks")
+	ErrInvalidTaskFilter     = errors.New("invalid task filter")
+	ErrInvalidTaskPriority   = errors.New("priority must be one of low, medium, high or urgent")
+	ErrInvalidTaskEstimate   = errors.New("estimate and remaining effort cannot be negative")
+
+	ErrGetSubtasks         = errors.New("failed to get subtasks")
+	ErrMoveTask            = errors.New("failed to move task")
+	ErrReorderTasks        = errors.New("failed to reorder subtasks")
+	ErrParentTaskNotFound  = errors.New("parent task not found")
+	ErrParentTaskOtherTeam = errors.New("parent task belongs to another team")
+	ErrTaskCycle           = errors.New("a task cannot be nested under itself or one of its subtasks")
+	ErrTaskTooDeep         = errors.New("subtasks are nested too deeply")
+	ErrInvalidTaskOrder    = errors.New("order must list every subtask exactly once")
 )
 
 type (
@@ -47,6 +68,11 @@ type (
 		DueDate     string     `json:"due_date" form:"due_date" binding:"required"`
 		TeamsID     int        `json:"teams_id" form:"teams_id" binding:"required"`
 		UserID      *uuid.UUID `json:"user_id" form:"user_id"`
+		ParentID    *int       `json:"parent_id" form:"parent_id"`
+		// Priority defaults to medium and RemainingEffort to Estimate.
+		Priority        string `json:"priority" form:"priority" binding:"omitempty,oneof=low medium high urgent"`
+		Estimate        *int   `json:"estimate" form:"estimate" binding:"omitempty,min=0"`
+		RemainingEffort *int   `json:"remaining_effort" form:"remaining_effort" binding:"omitempty,min=0"`
 	}
 
 	TaskResponse struct {
@@ -55,13 +81,99 @@ type (
 		Description string    `json:"description"`
 		Status      string    `json:"status"`
 		DueDate     time.Time `json:"due_date"`
+		IsOverdue   bool      `json:"is_overdue"`
 		TeamsID     int       `json:"teams_id"`
 		UserID      *uuid.UUID `json:"user_id,omitempty"`
 		User        UserResponse `json:"user,omitempty"`
+		ParentID    *int          `json:"parent_id,omitempty"`
+		SprintID    *int          `json:"sprint_id,omitempty"`
+		Priority        string `json:"priority"`
+		PriorityRank    int    `json:"priority_rank"`
+		Estimate        int    `json:"estimate"`
+		RemainingEffort int    `json:"remaining_effort"`
+		Progress    *TaskProgress `json:"progress,omitempty"`
+		Assignees   []TaskAssigneeResponse `json:"assignees,omitempty"`
+		Labels      []LabelResponse        `json:"labels,omitempty"`
 		CreatedAt   time.Time `json:"created_at"`
 		UpdatedAt   time.Time `json:"updated_at"`
 	}
 
+	// TaskProgress rolls up the direct subtasks and checklist items of a
+	// task. A subtask counts as completed once its status is in the done
+	// category of the team's workflow.
+	TaskProgress struct {
+		Completed int64 `json:"completed"`
+		Total     int64 `json:"total"`
+	}
+
+	// TaskChildStatusCount is one row of the per-status subtask count used
+	// to build TaskProgress.
+	TaskChildStatusCount struct {
+		ParentID int
+		Status   string
+		Count    int64
+	}
+
+	// TaskMoveRequest nests a task under ParentID, or makes it a top-level
+	// task again when ParentID is null.
+	TaskMoveRequest struct {
+		ParentID *int `json:"parent_id"`
+	}
+
+	TaskReorderRequest struct {
+		TaskIDs []int `json:"task_ids" binding:"required"`
+	}
+
+	// TaskListRequest is the query string accepted by GET /api/tasks, e.g.
+	// ?status=todo,doing&assignee=me&label=3,5&priority=high,urgent&max_remaining=3&due_before=2024-06-01&sort=-priority,due_date
+	TaskListRequest struct {
+		PaginationRequest
+		Status       string `form:"status"`
+		TeamID       int    `form:"team_id"`
+		Assignee     string `form:"assignee"`
+		Label        string `form:"label"`
+		Priority     string `form:"priority"`
+		MinEstimate  string `form:"min_estimate"`
+		MaxEstimate  string `form:"max_estimate"`
+		MinRemaining string `form:"min_remaining"`
+		MaxRemaining string `form:"max_remaining"`
+		DueBefore    string `form:"due_before"`
+		DueAfter     string `form:"due_after"`
+		Sort         string `form:"sort"`
+	}
+
+	// TaskFilter is the validated form of TaskListRequest handed to the
+	// repository; zero values mean "no constraint".
+	TaskFilter struct {
+		Statuses []string
+		TeamID   int
+		// MemberID limits the list to the teams the user belongs to. It is
+		// nil for admins, who see every team.
+		MemberID   *uuid.UUID
+		AssigneeID *uuid.UUID
+		Unassigned bool
+		// LabelIDs keeps tasks that carry every one of the labels.
+		LabelIDs  []int
+		DueBefore *time.Time
+		DueAfter  *time.Time
+		Sort      []TaskSort
+		// Priorities holds priority ranks; the effort bounds are inclusive.
+		Priorities   []int
+		MinEstimate  *int
+		MaxEstimate  *int
+		MinRemaining *int
+		MaxRemaining *int
+		// OpenOnly drops tasks in a done status. DefaultDoneStatuses are the
+		// done statuses of teams that have no workflow of their own.
+		OpenOnly            bool
+		DefaultDoneStatuses []string
+	}
+
+	TaskSort struct {
+		Field string
+		Desc  bool
+	}
+
 	TaskPaginationResponse struct {
 		Data []TaskResponse `json:"data"`
 		PaginationResponse
@@ -78,16 +190,23 @@ type (
 		Status      string     `json:"status" form:"status" binding:"required"`
 		DueDate     string     `json:"due_date" form:"due_date" binding:"required"`
 		UserID      *uuid.UUID `json:"user_id"` 
+		// Omitted planning fields keep their current value.
+		Priority        string `json:"priority" form:"priority" binding:"omitempty,oneof=low medium high urgent"`
+		Estimate        *int   `json:"estimate" form:"estimate" binding:"omitempty,min=0"`
+		RemainingEffort *int   `json:"remaining_effort" form:"remaining_effort" binding:"omitempty,min=0"`
 	}
 
 	TaskUpdateResponse struct {
-		ID          int        `json:"id"`
-		Title       string     `json:"title"`
-		Description string     `json:"description"`
-		Status      string     `json:"status"`
-		DueDate     time.Time  `json:"due_date"`
-		UserID      *uuid.UUID `json:"user_id,omitempty"`
-		UpdatedAt   time.Time  `json:"updated_at"`
+		ID              int        `json:"id"`
+		Title           string     `json:"title"`
+		Description     string     `json:"description"`
+		Status          string     `json:"status"`
+		DueDate         time.Time  `json:"due_date"`
+		UserID          *uuid.UUID `json:"user_id,omitempty"`
+		Priority        string     `json:"priority"`
+		Estimate        int        `json:"estimate"`
+		RemainingEffort int        `json:"remaining_effort"`
+		UpdatedAt       time.Time  `json:"updated_at"`
 	}
 
 	AssignUserRequest struct {
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_TASK_HISTORY = "failed get task history"
+
+	// Success
+	MESSAGE_SUCCESS_GET_TASK_HISTORY = "success get task history"
+)
+
+var (
+	ErrGetTaskHistory    = errors.New("failed to get task history")
+	ErrRecordTaskHistory = errors.New("failed to record task history")
+)
+
+type (
+	TaskEventResponse struct {
+		ID        int        `json:"id"`
+		TaskID    int        `json:"task_id"`
+		UserID    *uuid.UUID `json:"user_id,omitempty"`
+		Type      string     `json:"type"`
+		Field     string     `json:"field,omitempty"`
+		OldValue  string     `json:"old_value,omitempty"`
+		NewValue  string     `json:"new_value,omitempty"`
+		CreatedAt time.Time  `json:"created_at"`
+	}
+
+	TaskEventPaginationResponse struct {
+		Data []TaskEventResponse `json:"data"`
+		PaginationResponse
+	}
+
+	GetAllTaskEventRepositoryResponse struct {
+		Events []entity.TaskEvent
+		PaginationResponse
+	}
+)
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_TASK_LINK   = "failed create task link"
+	MESSAGE_FAILED_GET_LIST_TASK_LINK = "failed get list task link"
+	MESSAGE_FAILED_DELETE_TASK_LINK   = "failed delete task link"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_TASK_LINK   = "success create task link"
+	MESSAGE_SUCCESS_GET_LIST_TASK_LINK = "success get list task link"
+	MESSAGE_SUCCESS_DELETE_TASK_LINK   = "success delete task link"
+)
+
+var (
+	ErrCreateTaskLink      = errors.New("failed to create task link")
+	ErrGetAllTaskLink      = errors.New("failed to get all task link")
+	ErrDeleteTaskLink      = errors.New("failed to delete task link")
+	ErrTaskLinkNotFound    = errors.New("task link not found")
+	ErrInvalidTaskLinkType = errors.New("invalid task link type")
+	ErrLinkedTaskNotFound  = errors.New("linked task not found")
+	ErrLinkedTaskOtherTeam = errors.New("linked task belongs to another team")
+	ErrTaskLinkSelf        = errors.New("a task cannot be linked to itself")
+	ErrTaskLinkExists      = errors.New("tasks are already linked this way")
+	ErrTaskLinkCycle       = errors.New("blocking link would create a cycle")
+	ErrTaskBlocked         = errors.New("task is blocked by open tasks")
+	ErrCheckTaskBlockers   = errors.New("failed to check task blockers")
+)
+
+type (
+	// TaskLinkCreateRequest links the task in the URL to TaskID. Type is
+	// read from the URL task's side, so "blocked_by" makes TaskID a blocker
+	// of it.
+	TaskLinkCreateRequest struct {
+		Type   string `json:"type" form:"type" binding:"required"`
+		TaskID int    `json:"task_id" form:"task_id" binding:"required"`
+	}
+
+	TaskLinkResponse struct {
+		ID        int          `json:"id"`
+		Type      string       `json:"type"`
+		Task      TaskLinkTask `json:"task"`
+		CreatedBy *uuid.UUID   `json:"created_by,omitempty"`
+		CreatedAt time.Time    `json:"created_at"`
+	}
+
+	// TaskLinkTask is the task at the other end of a link.
+	TaskLinkTask struct {
+		ID     int    `json:"id"`
+		Title  string `json:"title"`
+		Status string `json:"status"`
+		IsDone bool   `json:"is_done"`
+	}
+)
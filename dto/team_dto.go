@@ -14,6 +14,8 @@ const (
 	MESSAGE_FAILED_GET_TEAM                = "failed get team"
 	MESSAGE_FAILED_UPDATE_TEAM             = "failed update team"
 	MESSAGE_FAILED_DELETE_TEAM             = "failed delete team"
+	MESSAGE_FAILED_STREAM_TEAM             = "failed stream team events"
+	MESSAGE_FAILED_GET_TEAM_STATISTICS     = "failed get team statistics"
 
 	// Success
 	MESSAGE_SUCCESS_REGISTER_TEAM           = "success create team"
@@ -21,6 +23,7 @@ const (
 	MESSAGE_SUCCESS_GET_TEAM                = "success get team"
 	MESSAGE_SUCCESS_UPDATE_TEAM             = "success update team"
 	MESSAGE_SUCCESS_DELETE_TEAM             = "success delete team"
+	MESSAGE_SUCCESS_GET_TEAM_STATISTICS     = "success get team statistics"
 )
 
 var (
@@ -30,6 +33,7 @@ var (
 	ErrUpdateTeam             = errors.New("failed to update team")
 	ErrTeamNotFound           = errors.New("team not found")
 	ErrDeleteTeam             = errors.New("failed to delete team")
+	ErrGetTeamStatistics      = errors.New("failed to get team statistics")
 )
 
 type (
@@ -67,4 +71,17 @@ type (
 		Description string `json:"description"`
 		UpdatedAt   time.Time `json:"updated_at"`
 	}
+
+	// TeamStatisticsResponse breaks a team's tasks down by status and by
+	// label. A task with several labels is counted under each of them.
+	TeamStatisticsResponse struct {
+		TotalTasks int64             `json:"total_tasks"`
+		Statuses   []TaskStatusCount `json:"statuses"`
+		Labels     []LabelCount      `json:"labels"`
+	}
+
+	TaskStatusCount struct {
+		Status string `json:"status"`
+		Count  int64  `json:"count"`
+	}
 )
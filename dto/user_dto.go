@@ -3,6 +3,7 @@ package dto
 import (
 	"errors"
 	"mime/multipart"
+	"time"
 
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 )
@@ -23,6 +24,10 @@ const (
 	MESSAGE_FAILED_PROSES_REQUEST          = "failed proses request"
 	MESSAGE_FAILED_DENIED_ACCESS           = "denied access"
 	MESSAGE_FAILED_VERIFY_EMAIL            = "failed verify email"
+	MESSAGE_FAILED_REFRESH_TOKEN           = "failed refresh token"
+	MESSAGE_FAILED_LOGOUT                  = "failed logout"
+	MESSAGE_FAILED_RESET_PASSWORD          = "failed reset password"
+	MESSAGE_FAILED_CHANGE_PASSWORD         = "failed change password"
 
 	// Success
 	MESSAGE_SUCCESS_REGISTER_USER           = "success create user"
@@ -33,6 +38,12 @@ const (
 	MESSAGE_SUCCESS_DELETE_USER             = "success delete user"
 	MESSAGE_SEND_VERIFICATION_EMAIL_SUCCESS = "success send verification email"
 	MESSAGE_SUCCESS_VERIFY_EMAIL            = "success verify email"
+	MESSAGE_SUCCESS_REFRESH_TOKEN           = "success refresh token"
+	MESSAGE_SUCCESS_LOGOUT                  = "success logout"
+	MESSAGE_SUCCESS_LOGOUT_ALL              = "success logout from all devices"
+	MESSAGE_SUCCESS_FORGOT_PASSWORD         = "if the email is registered, a reset link has been sent"
+	MESSAGE_SUCCESS_RESET_PASSWORD          = "success reset password"
+	MESSAGE_SUCCESS_CHANGE_PASSWORD         = "success change password"
 )
 
 var (
@@ -52,6 +63,11 @@ var (
 	ErrTokenInvalid           = errors.New("token invalid")
 	ErrTokenExpired           = errors.New("token expired")
 	ErrAccountAlreadyVerified = errors.New("account already verified")
+	ErrCreateSession          = errors.New("failed to create session")
+	ErrGenerateToken          = errors.New("failed to generate token")
+	ErrSessionRevoked         = errors.New("session has been revoked")
+	ErrRefreshTokenInvalid    = errors.New("refresh token invalid")
+	ErrRefreshTokenReused     = errors.New("refresh token already used, session revoked")
 )
 
 type (
@@ -112,13 +128,44 @@ type (
 	}
 
 	UserLoginRequest struct {
-		Email    string `json:"email" form:"email" binding:"required"`
-		Password string `json:"password" form:"password" binding:"required"`
+		Email     string `json:"email" form:"email" binding:"required"`
+		Password  string `json:"password" form:"password" binding:"required"`
+		UserAgent string `json:"-" form:"-"`
+		IPAddress string `json:"-" form:"-"`
 	}
 
 	UserLoginResponse struct {
-		Token string `json:"token"`
-		Role  string `json:"role"`
+		Token        string    `json:"token"`
+		RefreshToken string    `json:"refresh_token"`
+		ExpiresAt    time.Time `json:"expires_at"`
+		Role         string    `json:"role"`
+	}
+
+	// TokenClaims are the claims of a verified access token. Authenticate
+	// keeps them in the request context under "claims".
+	TokenClaims struct {
+		UserID    string
+		Role      string
+		SessionID string
+		ExpiresAt time.Time
+	}
+
+	ForgotPasswordRequest struct {
+		Email string `json:"email" form:"email" binding:"required,email"`
+	}
+
+	ResetPasswordRequest struct {
+		Token    string `json:"token" form:"token" binding:"required"`
+		Password string `json:"password" form:"password" binding:"required,min=8"`
+	}
+
+	ChangePasswordRequest struct {
+		OldPassword string `json:"old_password" form:"old_password" binding:"required"`
+		NewPassword string `json:"new_password" form:"new_password" binding:"required,min=8"`
+	}
+
+	RefreshTokenRequest struct {
+		RefreshToken string `json:"refresh_token" form:"refresh_token" binding:"required"`
 	}
 
 	UpdateStatusIsVerifiedRequest struct {
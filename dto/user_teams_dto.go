@@ -0,0 +1,15 @@
+package dto
+
+import "errors"
+
+var (
+	ErrMembershipNotFound = errors.New("user is not a member of this team")
+	ErrLastTeamOwner      = errors.New("team must keep at least one owner")
+	ErrChangeTeamRole     = errors.New("failed to change team role")
+)
+
+type (
+	ChangeTeamRoleRequest struct {
+		Role string `json:"role" form:"role" binding:"required,oneof=owner maintainer member viewer"`
+	}
+)
@@ -0,0 +1,139 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_WEBHOOK       = "failed create webhook"
+	MESSAGE_FAILED_GET_LIST_WEBHOOK     = "failed get list webhook"
+	MESSAGE_FAILED_GET_WEBHOOK          = "failed get webhook"
+	MESSAGE_FAILED_UPDATE_WEBHOOK       = "failed update webhook"
+	MESSAGE_FAILED_DELETE_WEBHOOK       = "failed delete webhook"
+	MESSAGE_FAILED_GET_WEBHOOK_DELIVERY = "failed get webhook deliveries"
+	MESSAGE_FAILED_REDELIVER_WEBHOOK    = "failed redeliver webhook"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_WEBHOOK       = "success create webhook"
+	MESSAGE_SUCCESS_GET_LIST_WEBHOOK     = "success get list webhook"
+	MESSAGE_SUCCESS_GET_WEBHOOK          = "success get webhook"
+	MESSAGE_SUCCESS_UPDATE_WEBHOOK       = "success update webhook"
+	MESSAGE_SUCCESS_DELETE_WEBHOOK       = "success delete webhook"
+	MESSAGE_SUCCESS_GET_WEBHOOK_DELIVERY = "success get webhook deliveries"
+	MESSAGE_SUCCESS_REDELIVER_WEBHOOK    = "success redeliver webhook"
+)
+
+var (
+	ErrCreateWebhook           = errors.New("failed to create webhook")
+	ErrGetWebhook              = errors.New("failed to get webhook")
+	ErrUpdateWebhook           = errors.New("failed to update webhook")
+	ErrDeleteWebhook           = errors.New("failed to delete webhook")
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrInvalidWebhookURL       = errors.New("webhook url must be an absolute http or https url")
+	ErrWebhookAddressBlocked   = errors.New("webhook url must resolve to a public address")
+	ErrGetWebhookDelivery      = errors.New("failed to get webhook deliveries")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrRedeliverWebhook        = errors.New("failed to redeliver webhook")
+	ErrDispatchWebhook         = errors.New("failed to dispatch webhook")
+)
+
+type (
+	WebhookCreateRequest struct {
+		URL    string   `json:"url" form:"url" binding:"required,url,max=2048"`
+		Secret string   `json:"secret" form:"secret" binding:"omitempty,min=16,max=255"`
+		Events []string `json:"events" form:"events" binding:"dive,oneof=task.created task.updated task.assigned task.unassigned task.deleted team.updated team.deleted"`
+	}
+
+	// WebhookUpdateRequest only changes the fields that are present. Sending
+	// an empty events list subscribes the webhook to every event.
+	WebhookUpdateRequest struct {
+		URL          *string  `json:"url" form:"url" binding:"omitempty,url,max=2048"`
+		Events       []string `json:"events" form:"events" binding:"dive,oneof=task.created task.updated task.assigned task.unassigned task.deleted team.updated team.deleted"`
+		IsActive     *bool    `json:"is_active" form:"is_active"`
+		RotateSecret bool     `json:"rotate_secret" form:"rotate_secret"`
+	}
+
+	// WebhookResponse carries the signing secret only when it was just
+	// created or rotated.
+	WebhookResponse struct {
+		ID        int       `json:"id"`
+		TeamsID   int       `json:"teams_id"`
+		URL       string    `json:"url"`
+		Events    []string  `json:"events"`
+		IsActive  bool      `json:"is_active"`
+		Secret    string    `json:"secret,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	WebhookDeliveryResponse struct {
+		ID             int        `json:"id"`
+		WebhookID      int        `json:"webhook_id"`
+		Event          string     `json:"event"`
+		Payload        string     `json:"payload"`
+		Status         string     `json:"status"`
+		Attempts       int        `json:"attempts"`
+		NextAttemptAt  time.Time  `json:"next_attempt_at"`
+		ResponseStatus int        `json:"response_status"`
+		LastError      string     `json:"last_error"`
+		DeliveredAt    *time.Time `json:"delivered_at"`
+		CreatedAt      time.Time  `json:"created_at"`
+	}
+
+	WebhookDeliveryPaginationResponse struct {
+		Data []WebhookDeliveryResponse `json:"data"`
+		PaginationResponse
+	}
+
+	GetAllWebhookDeliveryRepositoryResponse struct {
+		Deliveries []entity.WebhookDelivery
+		PaginationResponse
+	}
+
+	// WebhookPayload is the JSON body POSTed to a webhook. Data is a
+	// WebhookTaskData or a WebhookTeamData depending on the event.
+	WebhookPayload struct {
+		Event      string      `json:"event"`
+		TeamID     int         `json:"team_id"`
+		ActorID    *uuid.UUID  `json:"actor_id,omitempty"`
+		OccurredAt time.Time   `json:"occurred_at"`
+		Data       interface{} `json:"data"`
+	}
+
+	WebhookTaskData struct {
+		Task    WebhookTask     `json:"task"`
+		Changes []WebhookChange `json:"changes,omitempty"`
+	}
+
+	WebhookTask struct {
+		ID          int        `json:"id"`
+		Title       string     `json:"title"`
+		Description string     `json:"description"`
+		Status      string     `json:"status"`
+		DueDate     time.Time  `json:"due_date"`
+		TeamsID     int        `json:"teams_id"`
+		UserID      *uuid.UUID `json:"user_id"`
+		ParentID    *int       `json:"parent_id"`
+	}
+
+	WebhookChange struct {
+		Field    string `json:"field"`
+		OldValue string `json:"old_value"`
+		NewValue string `json:"new_value"`
+	}
+
+	WebhookTeamData struct {
+		Team WebhookTeam `json:"team"`
+	}
+
+	WebhookTeam struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+)
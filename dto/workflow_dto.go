@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"errors"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_WORKFLOW      = "failed get workflow"
+	MESSAGE_FAILED_CREATE_STATUS     = "failed create workflow status"
+	MESSAGE_FAILED_UPDATE_STATUS     = "failed update workflow status"
+	MESSAGE_FAILED_DELETE_STATUS     = "failed delete workflow status"
+	MESSAGE_FAILED_CREATE_TRANSITION = "failed create workflow transition"
+	MESSAGE_FAILED_DELETE_TRANSITION = "failed delete workflow transition"
+
+	// Success
+	MESSAGE_SUCCESS_GET_WORKFLOW      = "success get workflow"
+	MESSAGE_SUCCESS_CREATE_STATUS     = "success create workflow status"
+	MESSAGE_SUCCESS_UPDATE_STATUS     = "success update workflow status"
+	MESSAGE_SUCCESS_DELETE_STATUS     = "success delete workflow status"
+	MESSAGE_SUCCESS_CREATE_TRANSITION = "success create workflow transition"
+	MESSAGE_SUCCESS_DELETE_TRANSITION = "success delete workflow transition"
+)
+
+var (
+	ErrGetWorkflow              = errors.New("failed to get workflow")
+	ErrCreateWorkflowStatus     = errors.New("failed to create workflow status")
+	ErrUpdateWorkflowStatus     = errors.New("failed to update workflow status")
+	ErrDeleteWorkflowStatus     = errors.New("failed to delete workflow status")
+	ErrWorkflowStatusNotFound   = errors.New("workflow status not found")
+	ErrWorkflowStatusExists     = errors.New("workflow status already exists")
+	ErrWorkflowStatusInUse      = errors.New("workflow status is still used by tasks")
+	ErrCreateWorkflowTransition = errors.New("failed to create workflow transition")
+	ErrDeleteWorkflowTransition = errors.New("failed to delete workflow transition")
+	ErrWorkflowTransitionExists = errors.New("workflow transition already exists")
+	ErrTransitionNotFound       = errors.New("workflow transition not found")
+	ErrInvalidStatus            = errors.New("status is not defined in the team workflow")
+	ErrTransitionNotAllowed     = errors.New("status transition is not allowed by the team workflow")
+)
+
+type (
+	WorkflowStatusCreateRequest struct {
+		Name     string `json:"name" form:"name" binding:"required,max=50"`
+		Position int    `json:"position" form:"position"`
+		Category string `json:"category" form:"category" binding:"required,oneof=todo in_progress done"`
+	}
+
+	WorkflowStatusUpdateRequest struct {
+		Name     string `json:"name" form:"name" binding:"required,max=50"`
+		Position int    `json:"position" form:"position"`
+		Category string `json:"category" form:"category" binding:"required,oneof=todo in_progress done"`
+	}
+
+	WorkflowStatusResponse struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`
+		Position int    `json:"position"`
+		Category string `json:"category"`
+	}
+
+	WorkflowTransitionCreateRequest struct {
+		FromStatusID int `json:"from_status_id" form:"from_status_id" binding:"required"`
+		ToStatusID   int `json:"to_status_id" form:"to_status_id" binding:"required"`
+	}
+
+	WorkflowTransitionResponse struct {
+		ID           int `json:"id"`
+		FromStatusID int `json:"from_status_id"`
+		ToStatusID   int `json:"to_status_id"`
+	}
+
+	WorkflowResponse struct {
+		TeamsID     int                          `json:"teams_id"`
+		IsDefault   bool                         `json:"is_default"`
+		Statuses    []WorkflowStatusResponse     `json:"statuses"`
+		Transitions []WorkflowTransitionResponse `json:"transitions"`
+	}
+)
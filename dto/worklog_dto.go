@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_CREATE_WORKLOG      = "failed create worklog"
+	MESSAGE_FAILED_GET_LIST_WORKLOG    = "failed get list worklog"
+	MESSAGE_FAILED_DELETE_WORKLOG      = "failed delete worklog"
+	MESSAGE_FAILED_START_TIMER         = "failed start timer"
+	MESSAGE_FAILED_STOP_TIMER          = "failed stop timer"
+	MESSAGE_FAILED_GET_WORKLOG_SUMMARY = "failed get worklog summary"
+
+	// Success
+	MESSAGE_SUCCESS_CREATE_WORKLOG      = "success create worklog"
+	MESSAGE_SUCCESS_GET_LIST_WORKLOG    = "success get list worklog"
+	MESSAGE_SUCCESS_DELETE_WORKLOG      = "success delete worklog"
+	MESSAGE_SUCCESS_START_TIMER         = "success start timer"
+	MESSAGE_SUCCESS_STOP_TIMER          = "success stop timer"
+	MESSAGE_SUCCESS_GET_WORKLOG_SUMMARY = "success get worklog summary"
+)
+
+var (
+	ErrCreateWorklog      = errors.New("failed to create worklog")
+	ErrGetAllWorklog      = errors.New("failed to get all worklog")
+	ErrDeleteWorklog      = errors.New("failed to delete worklog")
+	ErrWorklogNotFound    = errors.New("worklog not found")
+	ErrStartTimer         = errors.New("failed to start timer")
+	ErrStopTimer          = errors.New("failed to stop timer")
+	ErrTimerRunning       = errors.New("a timer is already running")
+	ErrNoRunningTimer     = errors.New("no timer is running on this task")
+	ErrInvalidWorklog     = errors.New("started_at must be RFC3339 and the worklog cannot end in the future")
+	ErrGetWorklogSummary  = errors.New("failed to get worklog summary")
+	ErrInvalidWorklogDate = errors.New("from and to must be RFC3339 or YYYY-MM-DD, with from before to")
+)
+
+type (
+	// WorklogCreateRequest logs time after the fact. Duration is in seconds.
+	WorklogCreateRequest struct {
+		StartedAt string `json:"started_at" form:"started_at" binding:"required"`
+		Duration  int    `json:"duration" form:"duration" binding:"required,min=1"`
+		Note      string `json:"note" form:"note" binding:"max=1000"`
+	}
+
+	WorklogTimerRequest struct {
+		Note string `json:"note" form:"note" binding:"max=1000"`
+	}
+
+	WorklogResponse struct {
+		ID        int          `json:"id"`
+		TaskID    int          `json:"task_id"`
+		User      UserResponse `json:"user"`
+		StartedAt time.Time    `json:"started_at"`
+		Duration  int          `json:"duration"`
+		Note      string       `json:"note"`
+		IsRunning bool         `json:"is_running"`
+		CreatedAt time.Time    `json:"created_at"`
+	}
+
+	// WorklogSummaryRequest is the query string of the summary endpoints,
+	// e.g. ?from=2024-06-01&to=2024-07-01. The range is half-open and either
+	// end may be left out. UserID narrows a team summary to one member.
+	WorklogSummaryRequest struct {
+		From   string `form:"from"`
+		To     string `form:"to"`
+		UserID string `form:"user_id"`
+	}
+
+	// WorklogSummaryResponse totals the finished worklogs in the range, in
+	// seconds, broken down by user and/or by task depending on the scope.
+	WorklogSummaryResponse struct {
+		From          *time.Time         `json:"from,omitempty"`
+		To            *time.Time         `json:"to,omitempty"`
+		TotalDuration int64              `json:"total_duration"`
+		Users         []WorklogUserTotal `json:"users,omitempty"`
+		Tasks         []WorklogTaskTotal `json:"tasks,omitempty"`
+	}
+
+	WorklogUserTotal struct {
+		UserID   uuid.UUID `json:"user_id"`
+		Name     string    `json:"name"`
+		Duration int64     `json:"duration"`
+	}
+
+	WorklogTaskTotal struct {
+		TaskID   int    `json:"task_id"`
+		Title    string `json:"title"`
+		Duration int64  `json:"duration"`
+	}
+
+	// WorklogFilter selects the worklogs a summary adds up; zero values mean
+	// "no constraint".
+	WorklogFilter struct {
+		TaskID int
+		TeamID int
+		UserID *uuid.UUID
+		From   *time.Time
+		To     *time.Time
+	}
+)
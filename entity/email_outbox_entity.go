@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// EmailOutbox is an email waiting to be delivered, or the record of one that
+// was. Rows are claimed by a worker through LockToken/LockedUntil so several
+// app instances can drain the same table without sending a message twice.
+type EmailOutbox struct {
+	ID            int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	ToEmail       string     `gorm:"type:varchar(255);not null" json:"to_email"`
+	Subject       string     `gorm:"type:varchar(255);not null" json:"subject"`
+	Body          string     `gorm:"type:mediumtext;not null" json:"-"`
+	Status        string     `gorm:"type:varchar(20);not null;index:idx_email_outbox_due,priority:1" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"type:datetime;not null;index:idx_email_outbox_due,priority:2" json:"next_attempt_at"`
+	LockToken     string     `gorm:"type:varchar(36);index" json:"-"`
+	LockedUntil   *time.Time `gorm:"type:datetime" json:"-"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	SentAt        *time.Time `gorm:"type:datetime" json:"sent_at"`
+	CreatedAt     time.Time  `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"type:datetime" json:"updated_at"`
+}
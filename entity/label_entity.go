@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Label is a tag from a team's catalog that can be put on the team's tasks.
+type Label struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	TeamsID   int       `gorm:"not null;uniqueIndex:idx_label_team_name" json:"teams_id"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_label_team_name" json:"name"`
+	Color     string    `gorm:"type:varchar(7);not null" json:"color"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Team Team `gorm:"foreignKey:TeamsID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type TaskLabel struct {
+	TaskID    int       `gorm:"primaryKey" json:"task_id"`
+	LabelID   int       `gorm:"primaryKey;index" json:"label_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Task  Task  `gorm:"foreignKey:TaskID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Label Label `gorm:"foreignKey:LabelID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"label"`
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification is an in-app message for one user about something that
+// happened to a task they are involved in.
+type Notification struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:char(36);not null;index:idx_notification_user_read,priority:1" json:"user_id"`
+	Type      string     `gorm:"type:varchar(30);not null" json:"type"`
+	TaskID    *int       `gorm:"index" json:"task_id"`
+	ActorID   *uuid.UUID `gorm:"type:char(36)" json:"actor_id"`
+	Title     string     `gorm:"type:varchar(255);not null" json:"title"`
+	Body      string     `gorm:"type:text" json:"body"`
+	ReadAt    *time.Time `gorm:"type:datetime;index:idx_notification_user_read,priority:2" json:"read_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// NotificationPreference overrides the default channels for one
+// notification type. Users without a row get the defaults.
+type NotificationPreference struct {
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey" json:"user_id"`
+	Type      string    `gorm:"type:varchar(30);primaryKey" json:"type"`
+	InApp     bool      `gorm:"not null" json:"in_app"`
+	Email     bool      `gorm:"not null" json:"email"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session is one signed-in device. Access tokens carry its ID and stop being
+// accepted as soon as it is revoked.
+type Session struct {
+	ID         uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:char(36);not null;index" json:"user_id"`
+	UserAgent  string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IPAddress  string     `gorm:"type:varchar(45)" json:"ip_address"`
+	ExpiresAt  time.Time  `gorm:"type:datetime;not null" json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"type:datetime" json:"revoked_at"`
+	LastUsedAt time.Time  `gorm:"type:datetime" json:"last_used_at"`
+	CreatedAt  time.Time  `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"type:datetime" json:"updated_at"`
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RefreshToken is a single-use refresh token belonging to a session. Only the
+// SHA-256 of the token is stored; a used token is kept so that replaying it
+// can be detected.
+type RefreshToken struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	SessionID uuid.UUID  `gorm:"type:char(36);not null;index" json:"session_id"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	UsedAt    *time.Time `gorm:"type:datetime" json:"used_at"`
+	CreatedAt time.Time  `gorm:"type:datetime" json:"created_at"`
+
+	Session Session `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE" json:"-"`
+}
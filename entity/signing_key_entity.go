@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// SigningKey is an asymmetric key used to sign access tokens. Keys are
+// published (and accepted) before ActivatesAt and after a newer key takes
+// over, until ExpiresAt.
+type SigningKey struct {
+	Kid         string    `gorm:"type:varchar(64);primaryKey" json:"kid"`
+	Algorithm   string    `gorm:"type:varchar(10);not null" json:"algorithm"`
+	PrivateKey  string    `gorm:"type:text;not null" json:"-"`
+	PublicKey   string    `gorm:"type:text;not null" json:"public_key"`
+	ActivatesAt time.Time `gorm:"type:datetime;not null;index" json:"activates_at"`
+	ExpiresAt   time.Time `gorm:"type:datetime;not null;index" json:"expires_at"`
+	CreatedAt   time.Time `gorm:"type:datetime" json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sprint is a time-boxed iteration of a team. Tasks join a sprint through
+// Task.SprintID; tasks without a sprint are in the team's backlog.
+type Sprint struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	TeamsID   int        `gorm:"not null;index" json:"teams_id"`
+	Name      string     `gorm:"type:varchar(100);not null" json:"name"`
+	Goal      string     `gorm:"type:text" json:"goal"`
+	StartDate time.Time  `gorm:"type:datetime;not null" json:"start_date"`
+	EndDate   time.Time  `gorm:"type:datetime;not null" json:"end_date"`
+	State     string     `gorm:"type:varchar(20);not null;default:planned" json:"state"`
+	ClosedAt  *time.Time `gorm:"type:datetime" json:"closed_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Team Team `gorm:"foreignKey:TeamsID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// SprintTask is a task as it stood when its sprint was closed. Unfinished
+// tasks leave the sprint when it closes, so these rows are what a closed
+// sprint shows of the work it was given.
+type SprintTask struct {
+	SprintID        int        `gorm:"primaryKey" json:"sprint_id"`
+	TaskID          int        `gorm:"primaryKey" json:"task_id"`
+	Title           string     `gorm:"type:varchar(255);not null" json:"title"`
+	Status          string     `gorm:"type:varchar(50);not null" json:"status"`
+	IsDone          bool       `gorm:"not null" json:"is_done"`
+	UserID          *uuid.UUID `gorm:"type:char(36)" json:"user_id"`
+	Priority        int        `gorm:"not null" json:"priority"`
+	Estimate        int        `gorm:"not null;default:0" json:"estimate"`
+	RemainingEffort int        `gorm:"not null;default:0" json:"remaining_effort"`
+
+	Sprint Sprint `gorm:"foreignKey:SprintID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskAssignee puts a user on a task as an assignee, a reviewer or a
+// watcher. Task.UserID mirrors the first assignee so that single-assignee
+// clients keep working.
+type TaskAssignee struct {
+	TaskID    int       `gorm:"primaryKey" json:"task_id"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey;index" json:"user_id"`
+	Role      string    `gorm:"type:varchar(20);not null" json:"role"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Task Task `gorm:"foreignKey:TaskID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
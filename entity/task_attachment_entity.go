@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TaskAttachment struct {
+	ID        int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskID    int            `gorm:"not null;index" json:"task_id"`
+	UserID    uuid.UUID      `gorm:"type:char(36);not null" json:"user_id"`
+	FileName  string         `gorm:"type:varchar(255);not null" json:"file_name"`
+	Path      string         `gorm:"type:varchar(255);not null" json:"path"`
+	MimeType  string         `gorm:"type:varchar(100);not null" json:"mime_type"`
+	Size      int64          `gorm:"not null" json:"size"`
+	Checksum  string         `gorm:"type:char(64);not null" json:"checksum"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	Task Task `gorm:"foreignKey:TaskID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
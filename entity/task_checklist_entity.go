@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TaskChecklistItem struct {
+	ID          int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskID      int            `gorm:"not null;index" json:"task_id"`
+	Title       string         `gorm:"type:varchar(255);not null" json:"title"`
+	IsDone      bool           `gorm:"not null;default:false" json:"is_done"`
+	Position    int            `gorm:"not null;default:0" json:"position"`
+	CompletedBy *uuid.UUID     `gorm:"type:char(36)" json:"completed_by"`
+	CompletedAt *time.Time     `gorm:"type:datetime" json:"completed_at"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	Task Task `gorm:"foreignKey:TaskID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
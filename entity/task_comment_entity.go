@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TaskComment struct {
+	ID        int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskID    int            `gorm:"not null;index" json:"task_id"`
+	UserID    uuid.UUID      `gorm:"type:char(36);not null" json:"user_id"`
+	ParentID  *int           `gorm:"index" json:"parent_id"`
+	Body      string         `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	Task     Task                 `gorm:"foreignKey:TaskID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	User     User                 `gorm:"foreignKey:UserID" json:"user"`
+	Mentions []TaskCommentMention `gorm:"foreignKey:CommentID" json:"mentions"`
+}
+
+type TaskCommentMention struct {
+	CommentID int       `gorm:"primaryKey" json:"comment_id"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey" json:"user_id"`
+
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
@@ -15,6 +15,17 @@ type Task struct {
 	DueDate     time.Time      `gorm:"type:datetime" json:"due_date"`
 	TeamsID     int            `gorm:"not null" json:"teams_id"`
     UserID      *uuid.UUID     `gorm:"type:char(36)" json:"user_id"`
+	ParentID    *int           `gorm:"index" json:"parent_id"`
+	SprintID    *int           `gorm:"index" json:"sprint_id"`
+	Position    int            `gorm:"not null;default:0" json:"position"`
+
+	// Priority is the rank of the task's priority, so ordering by it sorts
+	// from low to urgent. Estimate and RemainingEffort are in the team's own
+	// unit (story points or hours); zero means not estimated.
+	Priority        int `gorm:"not null;default:2;index" json:"priority"`
+	Estimate        int `gorm:"not null;default:0" json:"estimate"`
+	RemainingEffort int `gorm:"not null;default:0" json:"remaining_effort"`
+
 	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskEvent is an append-only record of a change made to a task.
+type TaskEvent struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskID    int        `gorm:"not null;index" json:"task_id"`
+	UserID    *uuid.UUID `gorm:"type:char(36)" json:"user_id"`
+	Type      string     `gorm:"type:varchar(30);not null" json:"type"`
+	Field     string     `gorm:"type:varchar(50)" json:"field"`
+	OldValue  string     `gorm:"type:text" json:"old_value"`
+	NewValue  string     `gorm:"type:text" json:"new_value"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskLink relates two tasks. For a blocks link, SourceTask blocks
+// TargetTask; for a duplicates link, SourceTask duplicates TargetTask.
+type TaskLink struct {
+	ID           int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	SourceTaskID int        `gorm:"not null;uniqueIndex:idx_task_link" json:"source_task_id"`
+	TargetTaskID int        `gorm:"not null;uniqueIndex:idx_task_link;index" json:"target_task_id"`
+	Type         string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_task_link" json:"type"`
+	CreatedBy    *uuid.UUID `gorm:"type:char(36)" json:"created_by"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+
+	SourceTask Task `gorm:"foreignKey:SourceTaskID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	TargetTask Task `gorm:"foreignKey:TargetTaskID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskReminder records that a reminder was sent, so each assignee is
+// reminded once per kind and due date. Moving the due date or reassigning
+// the task makes it eligible again.
+type TaskReminder struct {
+	ID      int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskID  int       `gorm:"not null;uniqueIndex:idx_task_reminder,priority:1" json:"task_id"`
+	Kind    string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_task_reminder,priority:2" json:"kind"`
+	UserID  uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_task_reminder,priority:3" json:"user_id"`
+	DueDate time.Time `gorm:"type:datetime;not null;uniqueIndex:idx_task_reminder,priority:4" json:"due_date"`
+	SentAt  time.Time `gorm:"type:datetime;not null" json:"sent_at"`
+}
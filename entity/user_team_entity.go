@@ -9,5 +9,6 @@ import (
 type UserTeams struct {
 	UserID    uuid.UUID `gorm:"type:char(36);primaryKey" json:"user_id"`
 	TeamID    uint      `gorm:"primaryKey" json:"team_id"`
+	Role      string    `gorm:"type:varchar(20);not null;default:member" json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
\ No newline at end of file
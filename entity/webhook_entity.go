@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook is a team's subscription to outgoing event notifications. Events
+// holds a comma-separated list of event names; an empty list subscribes to
+// every event.
+type Webhook struct {
+	ID        int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	TeamsID   int            `gorm:"not null;index" json:"teams_id"`
+	URL       string         `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret    string         `gorm:"type:varchar(255);not null" json:"-"`
+	Events    string         `gorm:"type:text" json:"events"`
+	IsActive  bool           `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	Team Team `gorm:"foreignKey:TeamsID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// WebhookDelivery is one payload sent, or waiting to be sent, to a webhook.
+// Like EmailOutbox, rows are leased through LockToken/LockedUntil so several
+// app instances can work through the same table.
+type WebhookDelivery struct {
+	ID             int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	WebhookID      int        `gorm:"not null;index" json:"webhook_id"`
+	Event          string     `gorm:"type:varchar(50);not null" json:"event"`
+	Payload        string     `gorm:"type:mediumtext;not null" json:"payload"`
+	Status         string     `gorm:"type:varchar(20);not null;index:idx_webhook_delivery_due,priority:1" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"type:datetime;not null;index:idx_webhook_delivery_due,priority:2" json:"next_attempt_at"`
+	LockToken      string     `gorm:"type:varchar(36);index" json:"-"`
+	LockedUntil    *time.Time `gorm:"type:datetime" json:"-"`
+	ResponseStatus int        `gorm:"not null;default:0" json:"response_status"`
+	LastError      string     `gorm:"type:text" json:"last_error"`
+	DeliveredAt    *time.Time `gorm:"type:datetime" json:"delivered_at"`
+	CreatedAt      time.Time  `gorm:"type:datetime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"type:datetime" json:"updated_at"`
+
+	Webhook Webhook `gorm:"foreignKey:WebhookID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowStatus struct {
+	ID        int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	TeamsID   int            `gorm:"not null;index" json:"teams_id"`
+	Name      string         `gorm:"type:varchar(50);not null" json:"name"`
+	Position  int            `gorm:"not null;default:0" json:"position"`
+	Category  string         `gorm:"type:varchar(20);not null" json:"category"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	Team Team `gorm:"foreignKey:TeamsID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type WorkflowTransition struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	TeamsID      int       `gorm:"not null;index" json:"teams_id"`
+	FromStatusID int       `gorm:"not null" json:"from_status_id"`
+	ToStatusID   int       `gorm:"not null" json:"to_status_id"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	FromStatus WorkflowStatus `gorm:"foreignKey:FromStatusID;constraint:onDelete:CASCADE" json:"-"`
+	ToStatus   WorkflowStatus `gorm:"foreignKey:ToStatusID;constraint:onDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Worklog is time a user spent on a task. Duration is in seconds. A running
+// timer is a worklog with IsRunning set whose duration is filled in when it
+// is stopped.
+type Worklog struct {
+	ID        int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	TaskID    int            `gorm:"not null;index" json:"task_id"`
+	UserID    uuid.UUID      `gorm:"type:char(36);not null;index" json:"user_id"`
+	StartedAt time.Time      `gorm:"type:datetime;not null;index" json:"started_at"`
+	Duration  int            `gorm:"not null;default:0" json:"duration"`
+	Note      string         `gorm:"type:text" json:"note"`
+	IsRunning bool           `gorm:"not null;default:false" json:"is_running"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	Task Task `gorm:"foreignKey:TaskID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// a mention is "@" followed by a handle; a handle may itself be an email
+// address. The "@" must start the text or follow whitespace or "(", so plain
+// email addresses in a comment are not read as mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[\s(])@([A-Za-z0-9._+\-]+(?:@[A-Za-z0-9.\-]+[A-Za-z0-9])?)`)
+
+// ParseMentions returns the distinct lower-cased handles mentioned in body,
+// in the order they first appear.
+func ParseMentions(body string) []string {
+	var handles []string
+	seen := map[string]bool{}
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		handle := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+
+	return handles
+}
+
+// MentionHandles lists the handles a user can be mentioned by: the full
+// email, the part of the email before "@", and the name without spaces.
+func MentionHandles(name string, email string) []string {
+	var handles []string
+
+	email = strings.ToLower(email)
+	if email != "" {
+		handles = append(handles, email)
+		if i := strings.Index(email, "@"); i > 0 {
+			handles = append(handles, email[:i])
+		}
+	}
+
+	if compact := strings.ToLower(strings.Join(strings.Fields(name), "")); compact != "" {
+		handles = append(handles, compact)
+	}
+
+	return handles
+}
@@ -1,17 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/Caknoooo/go-gin-clean-starter/command"
 	"github.com/Caknoooo/go-gin-clean-starter/config"
 	"github.com/Caknoooo/go-gin-clean-starter/controller"
-	"github.com/Caknoooo/go-gin-clean-starter/entity"
 	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/migrations"
+	"github.com/Caknoooo/go-gin-clean-starter/realtime"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
 	"github.com/Caknoooo/go-gin-clean-starter/routes"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -26,44 +36,152 @@ func main() {
 		}
 	}
 
-	err := db.AutoMigrate(&entity.User{}, &entity.Team{}, &entity.UserTeams{}, &entity.Task{})
+	err := migrations.Migrate(db)
 	if err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
-	var (
-		jwtService service.JWTService = service.NewJWTService()
+	storageConfig := config.NewStorageConfig()
+	fileStorage, err := storage.NewStorage(storageConfig)
+	if err != nil {
+		log.Fatalf("Failed to set up file storage: %v", err)
+	}
+
+	// Background jobs run until the process is asked to stop.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	jwtConfig, err := config.NewJWTConfig()
+	if err != nil {
+		log.Fatalf("Invalid JWT configuration: %v", err)
+	}
+
+	signingKeyService := service.NewSigningKeyService(jwtConfig, repository.NewSigningKeyRepository(db))
+	if err := signingKeyService.Rotate(ctx); err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	signingKeyService.StartRotation(ctx)
+
+	emailConfig, err := config.NewEmailConfig()
+	if err != nil {
+		log.Fatalf("Failed to load email configuration: %v", err)
+	}
+
+	reminderConfig, err := config.NewReminderConfig()
+	if err != nil {
+		log.Fatalf("Invalid reminder configuration: %v", err)
+	}
 
+	broker, err := realtime.NewBroker(config.NewRealtimeConfig())
+	if err != nil {
+		log.Fatalf("Failed to set up realtime broker: %v", err)
+	}
+	hub := realtime.NewHub(broker)
+	if err := hub.Start(ctx); err != nil {
+		log.Fatalf("Failed to start realtime hub: %v", err)
+	}
+
+	var (
 		// Implementation Dependency Injection
 		// Repository
+		transactor repository.Transactor = repository.NewTransactor(db)
 		userRepository     repository.UserRepository     = repository.NewUserRepository(db)
 		teamRepository     repository.TeamRepository     = repository.NewTeamRepository(db)
 		userTeamsRepository repository.UserTeamsRepository = repository.NewUserTeamsRepository(db)
 		taskRepository     repository.TaskRepository     = repository.NewTaskRepository(db)
+		workflowRepository repository.WorkflowRepository = repository.NewWorkflowRepository(db)
+		taskEventRepository repository.TaskEventRepository = repository.NewTaskEventRepository(db)
+		taskCommentRepository repository.TaskCommentRepository = repository.NewTaskCommentRepository(db)
+		taskAttachmentRepository repository.TaskAttachmentRepository = repository.NewTaskAttachmentRepository(db)
+		taskChecklistRepository repository.TaskChecklistRepository = repository.NewTaskChecklistRepository(db)
+		taskLinkRepository repository.TaskLinkRepository = repository.NewTaskLinkRepository(db)
+		taskAssigneeRepository repository.TaskAssigneeRepository = repository.NewTaskAssigneeRepository(db)
+		labelRepository repository.LabelRepository = repository.NewLabelRepository(db)
+		worklogRepository repository.WorklogRepository = repository.NewWorklogRepository(db)
+		sprintRepository repository.SprintRepository = repository.NewSprintRepository(db)
+		sessionRepository repository.SessionRepository = repository.NewSessionRepository(db)
+		emailOutboxRepository repository.EmailOutboxRepository = repository.NewEmailOutboxRepository(db)
+		notificationRepository repository.NotificationRepository = repository.NewNotificationRepository(db)
+		taskReminderRepository repository.TaskReminderRepository = repository.NewTaskReminderRepository(db)
+		webhookRepository repository.WebhookRepository = repository.NewWebhookRepository(db)
 
 		// Services
-		userService     service.UserService     = service.NewUserService(userRepository, jwtService)
-		teamService     service.TeamService     = service.NewTeamService(teamRepository)
-		userTeamsService service.UserTeamsService = service.NewUserTeamsService(userTeamsRepository)
-		taskService     service.TaskService     = service.NewTaskService(taskRepository, userRepository)
+		jwtService service.JWTService = service.NewJWTService(signingKeyService, jwtConfig.Issuer, sessionRepository)
+		emailService service.EmailService = service.NewEmailService(emailOutboxRepository, utils.NewSMTPMailer(*emailConfig))
+		notificationService service.NotificationService = service.NewNotificationService(notificationRepository, userRepository, taskAssigneeRepository, emailService)
+		reminderService service.ReminderService = service.NewReminderService(taskReminderRepository, notificationService, reminderConfig)
+		authorizationService service.AuthorizationService = service.NewAuthorizationService(userRepository, userTeamsRepository, taskRepository)
+		webhookService service.WebhookService = service.NewWebhookService(webhookRepository, config.NewWebhookConfig())
+		userService     service.UserService     = service.NewUserService(userRepository, jwtService, fileStorage, sessionRepository, emailService)
+		teamService     service.TeamService     = service.NewTeamService(transactor, teamRepository, userTeamsRepository, taskRepository, labelRepository, authorizationService, webhookService)
+		workflowService service.WorkflowService = service.NewWorkflowService(transactor, workflowRepository, taskRepository)
+		taskAttachmentService service.TaskAttachmentService = service.NewTaskAttachmentService(taskAttachmentRepository, taskRepository, authorizationService, fileStorage)
+		taskService     service.TaskService     = service.NewTaskService(transactor, taskRepository, userRepository, userTeamsRepository, taskChecklistRepository, taskLinkRepository, taskAssigneeRepository, labelRepository, authorizationService, workflowService, taskAttachmentService, service.TaskServiceDeps{
+			History:       taskEventRepository,
+			Notifications: notificationService,
+			Webhooks:      webhookService,
+			Hub:           hub,
+		})
+		taskCommentService service.TaskCommentService = service.NewTaskCommentService(transactor, taskCommentRepository, taskRepository, userTeamsRepository, notificationService)
+		taskChecklistService service.TaskChecklistService = service.NewTaskChecklistService(taskChecklistRepository, taskRepository)
+		taskLinkService service.TaskLinkService = service.NewTaskLinkService(transactor, taskLinkRepository, taskRepository, workflowService)
+		labelService service.LabelService = service.NewLabelService(labelRepository, taskRepository)
+		worklogService service.WorklogService = service.NewWorklogService(transactor, worklogRepository, taskRepository, authorizationService)
+		sprintService service.SprintService = service.NewSprintService(transactor, sprintRepository, taskRepository, workflowService)
+		userTeamsService service.UserTeamsService = service.NewUserTeamsService(transactor, userTeamsRepository, taskService, authorizationService)
 
 		// Controllers
 		userController     controller.UserController     = controller.NewUserController(userService)
 		teamController     controller.TeamController     = controller.NewTeamController(teamService)
 		userTeamsController *controller.UserTeamsController = controller.NewUserTeamsController(userTeamsService) 
 		taskController     controller.TaskController     = controller.NewTaskController(taskService)
+		workflowController controller.WorkflowController = controller.NewWorkflowController(workflowService)
+		taskCommentController controller.TaskCommentController = controller.NewTaskCommentController(taskCommentService)
+		taskAttachmentController controller.TaskAttachmentController = controller.NewTaskAttachmentController(taskAttachmentService)
+		taskChecklistController controller.TaskChecklistController = controller.NewTaskChecklistController(taskChecklistService)
+		taskLinkController controller.TaskLinkController = controller.NewTaskLinkController(taskLinkService)
+		labelController controller.LabelController = controller.NewLabelController(labelService)
+		taskLabelController controller.TaskLabelController = controller.NewTaskLabelController(labelService)
+		worklogController controller.WorklogController = controller.NewWorklogController(worklogService)
+		taskWorklogController controller.TaskWorklogController = controller.NewTaskWorklogController(worklogService)
+		sprintController controller.SprintController = controller.NewSprintController(sprintService)
+		notificationController controller.NotificationController = controller.NewNotificationController(notificationService)
+		webhookController controller.WebhookController = controller.NewWebhookController(webhookService)
+		streamController controller.StreamController = controller.NewStreamController(hub, jwtService, authorizationService, controller.STREAM_HEARTBEAT)
+		jwksController controller.JWKSController = controller.NewJWKSController(signingKeyService)
 	)
 
+	emailService.StartWorker(ctx)
+	reminderService.Start(ctx)
+	webhookService.StartWorker(ctx)
+
 	server := gin.Default()
 	server.Use(middleware.CORSMiddleware())
 
 	// routes
-	routes.User(server, userController, jwtService)
-	routes.Team(server, teamController)
-	routes.UserTeams(server, userTeamsController) 
-	routes.Task(server, taskController)
+	routes.User(server, userController, jwtService, authorizationService)
+	routes.Team(server, teamController, jwtService, authorizationService)
+	routes.UserTeams(server, userTeamsController, jwtService, authorizationService)
+	routes.Task(server, taskController, jwtService, authorizationService)
+	routes.Workflow(server, workflowController, jwtService, authorizationService)
+	routes.TaskComment(server, taskCommentController, jwtService, authorizationService)
+	routes.TaskAttachment(server, taskAttachmentController, jwtService, authorizationService)
+	routes.TaskChecklist(server, taskChecklistController, jwtService, authorizationService)
+	routes.TaskLink(server, taskLinkController, jwtService, authorizationService)
+	routes.Label(server, labelController, jwtService, authorizationService)
+	routes.TaskLabel(server, taskLabelController, jwtService, authorizationService)
+	routes.Worklog(server, worklogController, jwtService, authorizationService)
+	routes.TaskWorklog(server, taskWorklogController, jwtService, authorizationService)
+	routes.Sprint(server, sprintController, jwtService, authorizationService)
+	routes.Notification(server, notificationController, jwtService)
+	routes.Webhook(server, webhookController, jwtService, authorizationService)
+	routes.Stream(server, streamController, jwtService, authorizationService)
+	routes.JWKS(server, jwksController)
+
+	if local, ok := fileStorage.(*storage.LocalStorage); ok {
+		server.GET(strings.TrimSuffix(storageConfig.LocalURL, "/")+"/*key", gin.WrapH(local))
+	}
 
-	server.Static("/assets", "./assets")
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8888"
@@ -76,7 +194,25 @@ func main() {
 		serve = ":" + port
 	}
 
-	if err := server.Run(serve); err != nil {
-		log.Fatalf("error running server:%v", err)
+	srv := &http.Server{
+		Addr:    serve,
+		Handler: server,
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("error running server:%v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Println("Shutting down server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shut down: %v", err)
 	}
 }
@@ -24,25 +24,22 @@ func Authenticate(jwtService service.JWTService) gin.HandlerFunc {
 			return
 		}
 		authHeader = strings.Replace(authHeader, "Bearer ", "", -1)
-		token, err := jwtService.ValidateToken(authHeader)
-		if err != nil {
+		claims, err := jwtService.ParseToken(ctx.Request.Context(), authHeader)
+		if err == dto.ErrTokenInvalid {
 			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_TOKEN_NOT_VALID, nil)
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
 			return
 		}
-		if !token.Valid {
-			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_DENIED_ACCESS, nil)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
-			return
-		}
-		userId, err := jwtService.GetUserIDByToken(authHeader)
 		if err != nil {
 			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
 			return
 		}
 		ctx.Set("token", authHeader)
-		ctx.Set("user_id", userId)
+		ctx.Set("claims", claims)
+		ctx.Set("user_id", claims.UserID)
+		ctx.Set("role", claims.Role)
+		ctx.Set("session_id", claims.SessionID)
 		ctx.Next()
 	}
 }
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Authorize must run after Authenticate. The team an action is scoped to is
+// taken from the :teamId route param, or looked up from :taskId.
+func Authorize(authorizationService service.AuthorizationService, action string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := dto.AuthorizeRequest{
+			UserID: ctx.GetString("user_id"),
+			Role:   ctx.GetString("role"),
+			Action: action,
+			TeamID: ctx.Param("teamId"),
+			TaskID: ctx.Param("taskId"),
+		}
+
+		if err := authorizationService.Authorize(ctx.Request.Context(), req); err != nil {
+			status := http.StatusForbidden
+			if errors.Is(err, dto.ErrTaskNotFound) || errors.Is(err, dto.ErrTeamNotFound) {
+				status = http.StatusNotFound
+			}
+			response := utils.BuildResponseFailed(dto.MESSAGE_FAILED_DENIED_ACCESS, err.Error(), nil)
+			ctx.AbortWithStatusJSON(status, response)
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"gorm.io/gorm"
+)
+
+// Backfill fills in what newer tables need for rows written before they
+// existed. Every step only touches rows that still need it, so it is safe
+// to run on each start.
+func Backfill(db *gorm.DB) error {
+	for _, step := range []func(db *gorm.DB) error{
+		backfillTaskAssignees,
+		backfillTeamOwners,
+		backfillSprintSnapshots,
+	} {
+		if err := step(db); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillTaskAssignees gives the assignee of each task created before
+// tasks could have several assignees a task_assignees row. Until then only
+// tasks.user_id named them.
+func backfillTaskAssignees(db *gorm.DB) error {
+	return db.Exec(`INSERT INTO task_assignees (task_id, user_id, role, created_at)
+		SELECT tasks.id, tasks.user_id, ?, tasks.created_at FROM tasks
+		WHERE tasks.user_id IS NOT NULL AND tasks.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = tasks.user_id)`,
+		constants.ENUM_TASK_ROLE_ASSIGNEE).Error
+}
+
+// backfillTeamOwners makes sure every team has an owner. Teams do not
+// record who created them, but their creator was added as the first member,
+// so the earliest member of a team without owners is promoted.
+func backfillTeamOwners(db *gorm.DB) error {
+	return db.Exec(`UPDATE user_teams SET role = ?
+		WHERE (team_id, user_id) IN (SELECT team_id, user_id FROM (
+			SELECT ut.team_id, ut.user_id FROM user_teams ut
+			WHERE NOT EXISTS (SELECT 1 FROM user_teams o WHERE o.team_id = ut.team_id AND o.role = ?)
+			AND NOT EXISTS (SELECT 1 FROM user_teams e WHERE e.team_id = ut.team_id
+				AND (e.created_at < ut.created_at OR (e.created_at = ut.created_at AND e.user_id < ut.user_id)))
+		) AS earliest)`,
+		constants.ENUM_TEAM_ROLE_OWNER, constants.ENUM_TEAM_ROLE_OWNER).Error
+}
+
+// backfillSprintSnapshots records the tasks of sprints closed before closing
+// took a snapshot. Only the done tasks stayed in those sprints; where the
+// unfinished ones went was not kept.
+func backfillSprintSnapshots(db *gorm.DB) error {
+	return db.Exec(`INSERT INTO sprint_tasks (sprint_id, task_id, title, status, is_done, user_id, priority, estimate, remaining_effort)
+		SELECT tasks.sprint_id, tasks.id, tasks.title, tasks.status, TRUE, tasks.user_id, tasks.priority, tasks.estimate, tasks.remaining_effort
+		FROM tasks JOIN sprints ON sprints.id = tasks.sprint_id
+		WHERE sprints.state = ? AND tasks.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM sprint_tasks st WHERE st.sprint_id = sprints.id)`,
+		constants.ENUM_SPRINT_STATE_CLOSED).Error
+}
+
+// VerifyExistingUsers marks every account verified. It runs once, when
+// email verification becomes mandatory, so that users who signed up while it
+// was switched off are not locked out.
+func VerifyExistingUsers(db *gorm.DB) error {
+	return db.Exec(`UPDATE users SET is_verified = TRUE WHERE is_verified = FALSE`).Error
+}
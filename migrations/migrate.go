@@ -6,13 +6,48 @@ import (
 )
 
 func Migrate(db *gorm.DB) error {
+	// The outbox arrived together with mandatory email verification, so a
+	// database without it still holds accounts that were never asked to
+	// verify.
+	verificationRequired := db.Migrator().HasTable(&entity.EmailOutbox{})
+
 	if err := db.AutoMigrate(
 		&entity.User{},
 		&entity.Team{},
+		&entity.UserTeams{},
 		&entity.Task{},
+		&entity.WorkflowStatus{},
+		&entity.WorkflowTransition{},
+		&entity.TaskEvent{},
+		&entity.TaskComment{},
+		&entity.TaskCommentMention{},
+		&entity.TaskAttachment{},
+		&entity.TaskChecklistItem{},
+		&entity.TaskLink{},
+		&entity.TaskAssignee{},
+		&entity.Label{},
+		&entity.TaskLabel{},
+		&entity.Worklog{},
+		&entity.Sprint{},
+		&entity.SprintTask{},
+		&entity.Session{},
+		&entity.RefreshToken{},
+		&entity.SigningKey{},
+		&entity.EmailOutbox{},
+		&entity.Notification{},
+		&entity.NotificationPreference{},
+		&entity.TaskReminder{},
+		&entity.Webhook{},
+		&entity.WebhookDelivery{},
 	); err != nil {
 		return err
 	}
 
-	return nil
+	if !verificationRequired {
+		if err := VerifyExistingUsers(db); err != nil {
+			return err
+		}
+	}
+
+	return Backfill(db)
 }
@@ -0,0 +1,38 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+)
+
+const (
+	DRIVER_MEMORY = "memory"
+)
+
+var (
+	ErrUnknownDriver = errors.New("unknown realtime broker driver")
+)
+
+// Broker carries published events between app instances. Every instance
+// subscribes once and hands what it receives to its own Hub, which fans the
+// events out to the streams connected to that instance. A single instance
+// can use the in-memory broker; several instances behind a load balancer
+// need a shared one (Redis pub/sub, NATS, ...) implementing this interface.
+type Broker interface {
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe calls handler with every payload published by any instance
+	// until ctx is done.
+	Subscribe(ctx context.Context, handler func(payload []byte)) error
+}
+
+func NewBroker(cfg config.RealtimeConfig) (Broker, error) {
+	switch cfg.Broker {
+	case "", DRIVER_MEMORY:
+		return NewMemoryBroker(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, cfg.Broker)
+	}
+}
@@ -0,0 +1,155 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// SUBSCRIPTION_BUFFER is how many events a stream may fall behind before
+	// it is dropped. The client reconnects and reloads the board instead of
+	// silently missing updates.
+	SUBSCRIPTION_BUFFER = 64
+)
+
+type (
+	// Event is one change pushed to the streams of a team.
+	Event struct {
+		Type       string          `json:"type"`
+		TeamID     int             `json:"team_id"`
+		ActorID    *uuid.UUID      `json:"actor_id,omitempty"`
+		OccurredAt time.Time       `json:"occurred_at"`
+		Data       json.RawMessage `json:"data"`
+	}
+
+	// Hub is the in-process side of the pub/sub: Publish goes out through the
+	// broker, and whatever the broker delivers is fanned out to the local
+	// subscriptions of the event's team.
+	Hub struct {
+		broker Broker
+
+		mu          sync.Mutex
+		subscribers map[int]map[*Subscription]struct{}
+		closed      bool
+	}
+
+	Subscription struct {
+		hub    *Hub
+		teamID int
+		events chan Event
+		once   sync.Once
+	}
+)
+
+func NewHub(broker Broker) *Hub {
+	return &Hub{
+		broker:      broker,
+		subscribers: map[int]map[*Subscription]struct{}{},
+	}
+}
+
+// Start subscribes the hub to the broker. When ctx is done every open
+// subscription is closed, which ends the streams so the server can shut down.
+func (h *Hub) Start(ctx context.Context) error {
+	if err := h.broker.Subscribe(ctx, h.dispatch); err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.closed = true
+		for _, subscriptions := range h.subscribers {
+			for subscription := range subscriptions {
+				subscription.close()
+			}
+		}
+		h.subscribers = map[int]map[*Subscription]struct{}{}
+	}()
+
+	return nil
+}
+
+func (h *Hub) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return h.broker.Publish(ctx, payload)
+}
+
+// Subscribe returns a subscription to the events of a team. The caller must
+// Close it when done; its channel is also closed when the subscriber falls
+// too far behind or the hub stops.
+func (h *Hub) Subscribe(teamID int) *Subscription {
+	subscription := &Subscription{
+		hub:    h,
+		teamID: teamID,
+		events: make(chan Event, SUBSCRIPTION_BUFFER),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		subscription.close()
+		return subscription
+	}
+	if h.subscribers[teamID] == nil {
+		h.subscribers[teamID] = map[*Subscription]struct{}{}
+	}
+	h.subscribers[teamID][subscription] = struct{}{}
+
+	return subscription
+}
+
+func (h *Hub) dispatch(payload []byte) {
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Printf("Dropping malformed realtime event: %v", err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for subscription := range h.subscribers[event.TeamID] {
+		select {
+		case subscription.events <- event:
+		default:
+			h.remove(subscription)
+		}
+	}
+}
+
+// remove must be called with h.mu held.
+func (h *Hub) remove(subscription *Subscription) {
+	if subscriptions, ok := h.subscribers[subscription.teamID]; ok {
+		delete(subscriptions, subscription)
+		if len(subscriptions) == 0 {
+			delete(h.subscribers, subscription.teamID)
+		}
+	}
+	subscription.close()
+}
+
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() { close(s.events) })
+}
@@ -0,0 +1,44 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBroker delivers events within the current process only.
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]func(payload []byte)
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{handlers: map[int]func(payload []byte){}}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, payload []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handler := range b.handlers {
+		handler(payload)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, handler func(payload []byte)) error {
+	b.mu.Lock()
+	b.nextID++
+	id := b.nextID
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}()
+
+	return nil
+}
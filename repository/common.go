@@ -1,10 +1,53 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Transactor lets a service make the writes of several repositories atomic:
+// fn receives the transaction to pass as their tx argument, and everything
+// is rolled back when it returns an error.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{
+		db: db,
+	}
+}
+
+func (t *transactor) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return t.db.WithContext(ctx).Transaction(fn)
+}
+
+const (
+	DEFAULT_PER_PAGE = 20
+	MAX_PER_PAGE     = 100
+)
+
+// PerPage returns the requested page size kept between 1 and MAX_PER_PAGE,
+// or fallback when none was asked for. A page size below 1 would make the
+// LIMIT meaningless, and a huge one would read the whole table.
+func PerPage(requested int, fallback int) int {
+	switch {
+	case requested < 1:
+		return fallback
+	case requested > MAX_PER_PAGE:
+		return MAX_PER_PAGE
+	}
+	return requested
+}
 
 func Paginate(page, perPage int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		offset := (page - 1) * perPage
 		return db.Offset(offset).Limit(perPage)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const cursorTimeFormat = "2006-01-02 15:04:05.999999"
+
+// KeysetColumn is one column of a keyset ordering. The last column must be
+// unique (normally the primary key) so every row has a distinct position.
+type KeysetColumn struct {
+	Name string
+	Desc bool
+}
+
+// cursor is the decoded form of the opaque next_cursor/prev_cursor tokens.
+// Sort pins the ordering the cursor was issued for.
+type cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	Prev   bool     `json:"p,omitempty"`
+}
+
+func EncodeCursor(columns []KeysetColumn, values []string, prev bool) string {
+	data, _ := json.Marshal(cursor{Sort: keysetSignature(columns), Values: values, Prev: prev})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string, columns []KeysetColumn) ([]string, bool, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, false, dto.ErrInvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, false, dto.ErrInvalidCursor
+	}
+
+	if c.Sort != keysetSignature(columns) || len(c.Values) != len(columns) {
+		return nil, false, dto.ErrInvalidCursor
+	}
+
+	return c.Values, c.Prev, nil
+}
+
+// KeysetPaginate loads one page of rows after (or, for a prev cursor, before)
+// the cursor position. query must return a fresh, filtered but unordered
+// query each time it is called; it is reused for the optional count.
+func KeysetPaginate[T any](query func() *gorm.DB, req dto.PaginationRequest, columns []KeysetColumn, values func(T) []string) ([]T, dto.PaginationResponse, error) {
+	var (
+		after    []string
+		backward bool
+		err      error
+	)
+
+	req.PerPage = PerPage(req.PerPage, DEFAULT_PER_PAGE)
+
+	if req.Cursor != "" {
+		after, backward, err = DecodeCursor(req.Cursor, columns)
+		if err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+	}
+
+	db := query()
+	if after != nil {
+		where, vars := keysetWhere(columns, after, backward)
+		db = db.Where(where, vars...)
+	}
+
+	for _, column := range columns {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: column.Name}, Desc: column.Desc != backward})
+	}
+
+	var rows []T
+	if err := db.Limit(req.PerPage + 1).Find(&rows).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	hasMore := len(rows) > req.PerPage
+	if hasMore {
+		rows = rows[:req.PerPage]
+	}
+
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	meta := dto.PaginationResponse{PerPage: req.PerPage}
+	if len(rows) > 0 {
+		// Moving forward past a cursor means there is something behind us,
+		// and moving backward means there is something ahead.
+		if (!backward && hasMore) || backward {
+			meta.NextCursor = EncodeCursor(columns, values(rows[len(rows)-1]), false)
+		}
+		if (backward && hasMore) || (!backward && after != nil) {
+			meta.PrevCursor = EncodeCursor(columns, values(rows[0]), true)
+		}
+	}
+
+	if req.WithCount {
+		if err := query().Count(&meta.Count).Error; err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+		meta.MaxPage = int64(math.Ceil(float64(meta.Count) / float64(req.PerPage)))
+	}
+
+	return rows, meta, nil
+}
+
+// keysetWhere builds "(a > ?) OR (a = ? AND b > ?) ..." honouring each
+// column's direction, flipped when paging backward.
+func keysetWhere(columns []KeysetColumn, values []string, backward bool) (string, []interface{}) {
+	var (
+		clauses []string
+		vars    []interface{}
+	)
+
+	for i, column := range columns {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = ?", columns[j].Name))
+			vars = append(vars, values[j])
+		}
+
+		op := ">"
+		if column.Desc != backward {
+			op = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s ?", column.Name, op))
+		vars = append(vars, values[i])
+
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return strings.Join(clauses, " OR "), vars
+}
+
+func keysetSignature(columns []KeysetColumn) string {
+	parts := make([]string, len(columns))
+	for i, column := range columns {
+		parts[i] = column.Name
+		if column.Desc {
+			parts[i] = "-" + column.Name
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func cursorTime(t time.Time) string {
+	return t.Format(cursorTimeFormat)
+}
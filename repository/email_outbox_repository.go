@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	EmailOutboxRepository interface {
+		Enqueue(ctx context.Context, tx *gorm.DB, email entity.EmailOutbox) (entity.EmailOutbox, error)
+		ClaimDue(ctx context.Context, tx *gorm.DB, now time.Time, lease time.Duration, limit int) ([]entity.EmailOutbox, error)
+		UpdateDelivery(ctx context.Context, tx *gorm.DB, email entity.EmailOutbox) error
+	}
+
+	emailOutboxRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewEmailOutboxRepository(db *gorm.DB) EmailOutboxRepository {
+	return &emailOutboxRepository{
+		db: db,
+	}
+}
+
+func (r *emailOutboxRepository) Enqueue(ctx context.Context, tx *gorm.DB, email entity.EmailOutbox) (entity.EmailOutbox, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if email.Status == "" {
+		email.Status = constants.ENUM_EMAIL_STATUS_PENDING
+	}
+	if email.NextAttemptAt.IsZero() {
+		email.NextAttemptAt = time.Now()
+	}
+
+	if err := tx.WithContext(ctx).Create(&email).Error; err != nil {
+		return entity.EmailOutbox{}, err
+	}
+
+	return email, nil
+}
+
+// ClaimDue leases up to limit pending emails that are due. The lease is taken
+// with a single UPDATE so concurrent workers never claim the same row; a
+// lease that runs out (e.g. the worker crashed mid-send) makes the row
+// claimable again.
+func (r *emailOutboxRepository) ClaimDue(ctx context.Context, tx *gorm.DB, now time.Time, lease time.Duration, limit int) ([]entity.EmailOutbox, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	token := uuid.NewString()
+	if err := tx.WithContext(ctx).Model(&entity.EmailOutbox{}).
+		Where("status = ? AND next_attempt_at <= ?", constants.ENUM_EMAIL_STATUS_PENDING, now).
+		Where("locked_until IS NULL OR locked_until <= ?", now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Updates(map[string]interface{}{
+			"lock_token":   token,
+			"locked_until": now.Add(lease),
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	var emails []entity.EmailOutbox
+	if err := tx.WithContext(ctx).Where("lock_token = ?", token).Order("next_attempt_at ASC").Find(&emails).Error; err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// UpdateDelivery records the outcome of a delivery attempt and releases the
+// lease taken by ClaimDue.
+func (r *emailOutboxRepository) UpdateDelivery(ctx context.Context, tx *gorm.DB, email entity.EmailOutbox) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.EmailOutbox{}).
+		Where("id = ?", email.ID).
+		Updates(map[string]interface{}{
+			"status":          email.Status,
+			"attempts":        email.Attempts,
+			"next_attempt_at": email.NextAttemptAt,
+			"last_error":      email.LastError,
+			"sent_at":         email.SentAt,
+			"lock_token":      "",
+			"locked_until":    nil,
+		}).Error
+}
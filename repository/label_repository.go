@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	LabelRepository interface {
+		CreateLabel(ctx context.Context, tx *gorm.DB, label entity.Label) (entity.Label, error)
+		GetLabelById(ctx context.Context, tx *gorm.DB, teamsID int, labelId int) (entity.Label, error)
+		GetLabelsByTeamId(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Label, error)
+		UpdateLabel(ctx context.Context, tx *gorm.DB, label entity.Label) (entity.Label, error)
+		DeleteLabel(ctx context.Context, tx *gorm.DB, labelId int) error
+		AddTaskLabel(ctx context.Context, tx *gorm.DB, taskId int, labelId int) error
+		RemoveTaskLabel(ctx context.Context, tx *gorm.DB, taskId int, labelId int) error
+		GetTaskLabels(ctx context.Context, tx *gorm.DB, taskIds []int) ([]entity.TaskLabel, error)
+		CountTasksByLabel(ctx context.Context, tx *gorm.DB, teamsID int) ([]dto.LabelCount, error)
+	}
+
+	labelRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewLabelRepository(db *gorm.DB) LabelRepository {
+	return &labelRepository{
+		db: db,
+	}
+}
+
+func (r *labelRepository) CreateLabel(ctx context.Context, tx *gorm.DB, label entity.Label) (entity.Label, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit(clause.Associations).Create(&label).Error; err != nil {
+		return entity.Label{}, err
+	}
+
+	return label, nil
+}
+
+func (r *labelRepository) GetLabelById(ctx context.Context, tx *gorm.DB, teamsID int, labelId int) (entity.Label, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var label entity.Label
+	if err := tx.WithContext(ctx).Where("id = ? AND teams_id = ?", labelId, teamsID).Take(&label).Error; err != nil {
+		return entity.Label{}, err
+	}
+
+	return label, nil
+}
+
+func (r *labelRepository) GetLabelsByTeamId(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Label, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var labels []entity.Label
+	if err := tx.WithContext(ctx).Where("teams_id = ?", teamsID).Order("name ASC, id ASC").Find(&labels).Error; err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+func (r *labelRepository) UpdateLabel(ctx context.Context, tx *gorm.DB, label entity.Label) (entity.Label, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Model(&label).Select("name", "color").Updates(&label).Error; err != nil {
+		return entity.Label{}, err
+	}
+
+	return label, nil
+}
+
+// DeleteLabel removes a label from the catalog and from every task that
+// carries it.
+func (r *labelRepository) DeleteLabel(ctx context.Context, tx *gorm.DB, labelId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.TaskLabel{}, "label_id = ?", labelId).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Label{}, "id = ?", labelId).Error
+	})
+}
+
+// AddTaskLabel puts a label on a task. Adding a label the task already has
+// is not an error.
+func (r *labelRepository) AddTaskLabel(ctx context.Context, tx *gorm.DB, taskId int, labelId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.TaskLabel{TaskID: taskId, LabelID: labelId}).Error
+}
+
+func (r *labelRepository) RemoveTaskLabel(ctx context.Context, tx *gorm.DB, taskId int, labelId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.TaskLabel{}, "task_id = ? AND label_id = ?", taskId, labelId).Error
+}
+
+// GetTaskLabels returns the labels on any of taskIds with the label loaded.
+func (r *labelRepository) GetTaskLabels(ctx context.Context, tx *gorm.DB, taskIds []int) ([]entity.TaskLabel, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var taskLabels []entity.TaskLabel
+	if len(taskIds) == 0 {
+		return taskLabels, nil
+	}
+
+	if err := tx.WithContext(ctx).
+		Preload("Label").
+		Where("task_id IN ?", taskIds).
+		Order("created_at ASC, label_id ASC").
+		Find(&taskLabels).Error; err != nil {
+		return nil, err
+	}
+
+	return taskLabels, nil
+}
+
+// CountTasksByLabel counts the tasks carrying each label of the team,
+// including labels no task carries. Deleted tasks are not counted.
+func (r *labelRepository) CountTasksByLabel(ctx context.Context, tx *gorm.DB, teamsID int) ([]dto.LabelCount, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var counts []dto.LabelCount
+	if err := tx.WithContext(ctx).Model(&entity.Label{}).
+		Select("labels.id, labels.name, labels.color, COUNT(tasks.id) AS count").
+		Joins("LEFT JOIN task_labels ON task_labels.label_id = labels.id").
+		Joins("LEFT JOIN tasks ON tasks.id = task_labels.task_id AND tasks.deleted_at IS NULL").
+		Where("labels.teams_id = ?", teamsID).
+		Group("labels.id, labels.name, labels.color").
+		Order("labels.name ASC").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
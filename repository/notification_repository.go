@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	NotificationRepository interface {
+		CreateNotification(ctx context.Context, tx *gorm.DB, notification entity.Notification) (entity.Notification, error)
+		GetNotificationsWithPagination(ctx context.Context, tx *gorm.DB, userId string, unreadOnly bool, req dto.PaginationRequest) (dto.GetAllNotificationRepositoryResponse, error)
+		GetNotificationById(ctx context.Context, tx *gorm.DB, userId string, notificationId int) (entity.Notification, error)
+		CountUnread(ctx context.Context, tx *gorm.DB, userId string) (int64, error)
+		MarkAsRead(ctx context.Context, tx *gorm.DB, userId string, notificationId int, at time.Time) error
+		MarkAllAsRead(ctx context.Context, tx *gorm.DB, userId string, at time.Time) (int64, error)
+		GetPreferences(ctx context.Context, tx *gorm.DB, userId string) ([]entity.NotificationPreference, error)
+		SavePreferences(ctx context.Context, tx *gorm.DB, preferences []entity.NotificationPreference) error
+	}
+
+	notificationRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) CreateNotification(ctx context.Context, tx *gorm.DB, notification entity.Notification) (entity.Notification, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&notification).Error; err != nil {
+		return entity.Notification{}, err
+	}
+
+	return notification, nil
+}
+
+func (r *notificationRepository) GetNotificationsWithPagination(ctx context.Context, tx *gorm.DB, userId string, unreadOnly bool, req dto.PaginationRequest) (dto.GetAllNotificationRepositoryResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var notifications []entity.Notification
+	var count int64
+
+	req.PerPage = PerPage(req.PerPage, 20)
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	query := func() *gorm.DB {
+		db := tx.WithContext(ctx).Model(&entity.Notification{}).Where("user_id = ?", userId)
+		if unreadOnly {
+			db = db.Where("read_at IS NULL")
+		}
+		return db
+	}
+
+	if err := query().Count(&count).Error; err != nil {
+		return dto.GetAllNotificationRepositoryResponse{}, err
+	}
+
+	if err := query().Order("created_at DESC, id DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&notifications).Error; err != nil {
+		return dto.GetAllNotificationRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.GetAllNotificationRepositoryResponse{
+		Notifications: notifications,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			Count:   count,
+			MaxPage: totalPage,
+		},
+	}, nil
+}
+
+func (r *notificationRepository) GetNotificationById(ctx context.Context, tx *gorm.DB, userId string, notificationId int) (entity.Notification, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var notification entity.Notification
+	if err := tx.WithContext(ctx).Where("id = ? AND user_id = ?", notificationId, userId).Take(&notification).Error; err != nil {
+		return entity.Notification{}, err
+	}
+
+	return notification, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, tx *gorm.DB, userId string) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	err := tx.WithContext(ctx).Model(&entity.Notification{}).Where("user_id = ? AND read_at IS NULL", userId).Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkAsRead(ctx context.Context, tx *gorm.DB, userId string, notificationId int, at time.Time) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationId, userId).
+		Update("read_at", at).Error
+}
+
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, tx *gorm.DB, userId string, at time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) GetPreferences(ctx context.Context, tx *gorm.DB, userId string) ([]entity.NotificationPreference, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var preferences []entity.NotificationPreference
+	if err := tx.WithContext(ctx).Where("user_id = ?", userId).Find(&preferences).Error; err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+func (r *notificationRepository) SavePreferences(ctx context.Context, tx *gorm.DB, preferences []entity.NotificationPreference) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(&preferences).Error
+}
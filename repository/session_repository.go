@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	SessionRepository interface {
+		CreateSession(ctx context.Context, tx *gorm.DB, session entity.Session) (entity.Session, error)
+		GetSessionById(ctx context.Context, tx *gorm.DB, sessionId string) (entity.Session, error)
+		TouchSession(ctx context.Context, tx *gorm.DB, sessionId string, at time.Time) error
+		RevokeSession(ctx context.Context, tx *gorm.DB, sessionId string) error
+		RevokeUserSessions(ctx context.Context, tx *gorm.DB, userId string) error
+		RevokeOtherSessions(ctx context.Context, tx *gorm.DB, userId string, keepSessionId string) error
+		CreateRefreshToken(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) (entity.RefreshToken, error)
+		GetRefreshTokenByHash(ctx context.Context, tx *gorm.DB, hash string) (entity.RefreshToken, error)
+		MarkRefreshTokenUsed(ctx context.Context, tx *gorm.DB, tokenId int) (bool, error)
+	}
+
+	sessionRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (r *sessionRepository) CreateSession(ctx context.Context, tx *gorm.DB, session entity.Session) (entity.Session, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&session).Error; err != nil {
+		return entity.Session{}, err
+	}
+
+	return session, nil
+}
+
+func (r *sessionRepository) GetSessionById(ctx context.Context, tx *gorm.DB, sessionId string) (entity.Session, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var session entity.Session
+	if err := tx.WithContext(ctx).Where("id = ?", sessionId).Take(&session).Error; err != nil {
+		return entity.Session{}, err
+	}
+
+	return session, nil
+}
+
+func (r *sessionRepository) TouchSession(ctx context.Context, tx *gorm.DB, sessionId string, at time.Time) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Session{}).Where("id = ?", sessionId).Update("last_used_at", at).Error
+}
+
+func (r *sessionRepository) RevokeSession(ctx context.Context, tx *gorm.DB, sessionId string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionId).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *sessionRepository) RevokeUserSessions(ctx context.Context, tx *gorm.DB, userId string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *sessionRepository) RevokeOtherSessions(ctx context.Context, tx *gorm.DB, userId string, keepSessionId string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userId, keepSessionId).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *sessionRepository) CreateRefreshToken(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) (entity.RefreshToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit("Session").Create(&token).Error; err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+func (r *sessionRepository) GetRefreshTokenByHash(ctx context.Context, tx *gorm.DB, hash string) (entity.RefreshToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var token entity.RefreshToken
+	if err := tx.WithContext(ctx).Preload("Session").Where("token_hash = ?", hash).Take(&token).Error; err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+// MarkRefreshTokenUsed flips used_at only if it is still unset, so of two
+// concurrent refreshes with the same token exactly one reports true.
+func (r *sessionRepository) MarkRefreshTokenUsed(ctx context.Context, tx *gorm.DB, tokenId int) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", tokenId).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	SigningKeyRepository interface {
+		CreateKey(ctx context.Context, tx *gorm.DB, key entity.SigningKey) (entity.SigningKey, error)
+		GetUnexpiredKeys(ctx context.Context, tx *gorm.DB, now time.Time) ([]entity.SigningKey, error)
+		DeleteExpiredKeys(ctx context.Context, tx *gorm.DB, now time.Time) error
+	}
+
+	signingKeyRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewSigningKeyRepository(db *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{
+		db: db,
+	}
+}
+
+func (r *signingKeyRepository) CreateKey(ctx context.Context, tx *gorm.DB, key entity.SigningKey) (entity.SigningKey, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&key).Error; err != nil {
+		return entity.SigningKey{}, err
+	}
+
+	return key, nil
+}
+
+func (r *signingKeyRepository) GetUnexpiredKeys(ctx context.Context, tx *gorm.DB, now time.Time) ([]entity.SigningKey, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var keys []entity.SigningKey
+	if err := tx.WithContext(ctx).Where("expires_at > ?", now).Order("activates_at").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *signingKeyRepository) DeleteExpiredKeys(ctx context.Context, tx *gorm.DB, now time.Time) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Where("expires_at <= ?", now).Delete(&entity.SigningKey{}).Error
+}
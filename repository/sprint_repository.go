@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	SprintRepository interface {
+		CreateSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) (entity.Sprint, error)
+		GetSprintById(ctx context.Context, tx *gorm.DB, teamsID int, sprintId int) (entity.Sprint, error)
+		GetSprintsByTeamId(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Sprint, error)
+		GetSprintsByTeamIdForUpdate(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Sprint, error)
+		UpdateSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) (entity.Sprint, error)
+		DeleteSprint(ctx context.Context, tx *gorm.DB, sprintId int) error
+		GetSprintTasks(ctx context.Context, tx *gorm.DB, sprintId int) ([]entity.Task, error)
+		SetTaskSprint(ctx context.Context, tx *gorm.DB, taskId int, sprintId *int) error
+		MoveSprintTasks(ctx context.Context, tx *gorm.DB, sprintId int, taskIds []int, nextSprintId *int) error
+		CloseSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) error
+		SaveSprintSnapshot(ctx context.Context, tx *gorm.DB, tasks []entity.SprintTask) error
+		GetSprintSnapshot(ctx context.Context, tx *gorm.DB, sprintId int) ([]entity.SprintTask, error)
+	}
+
+	sprintRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewSprintRepository(db *gorm.DB) SprintRepository {
+	return &sprintRepository{
+		db: db,
+	}
+}
+
+func (r *sprintRepository) CreateSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) (entity.Sprint, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit(clause.Associations).Create(&sprint).Error; err != nil {
+		return entity.Sprint{}, err
+	}
+
+	return sprint, nil
+}
+
+func (r *sprintRepository) GetSprintById(ctx context.Context, tx *gorm.DB, teamsID int, sprintId int) (entity.Sprint, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var sprint entity.Sprint
+	if err := tx.WithContext(ctx).Where("id = ? AND teams_id = ?", sprintId, teamsID).Take(&sprint).Error; err != nil {
+		return entity.Sprint{}, err
+	}
+
+	return sprint, nil
+}
+
+func (r *sprintRepository) GetSprintsByTeamId(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Sprint, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var sprints []entity.Sprint
+	if err := tx.WithContext(ctx).Where("teams_id = ?", teamsID).Order("start_date ASC, id ASC").Find(&sprints).Error; err != nil {
+		return nil, err
+	}
+
+	return sprints, nil
+}
+
+// GetSprintsByTeamIdForUpdate reads the team's sprints and locks them until
+// tx ends, so that no other sprint of the team can change state in between.
+func (r *sprintRepository) GetSprintsByTeamIdForUpdate(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Sprint, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var sprints []entity.Sprint
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("teams_id = ?", teamsID).
+		Order("start_date ASC, id ASC").
+		Find(&sprints).Error; err != nil {
+		return nil, err
+	}
+
+	return sprints, nil
+}
+
+func (r *sprintRepository) UpdateSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) (entity.Sprint, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Model(&sprint).Select("name", "goal", "start_date", "end_date", "state", "closed_at").Updates(&sprint).Error; err != nil {
+		return entity.Sprint{}, err
+	}
+
+	return sprint, nil
+}
+
+// DeleteSprint removes a sprint and puts its tasks back in the backlog.
+func (r *sprintRepository) DeleteSprint(ctx context.Context, tx *gorm.DB, sprintId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Task{}).Where("sprint_id = ?", sprintId).Update("sprint_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Sprint{}, "id = ?", sprintId).Error
+	})
+}
+
+// GetSprintTasks returns the tasks in a sprint, most urgent first.
+func (r *sprintRepository) GetSprintTasks(ctx context.Context, tx *gorm.DB, sprintId int) ([]entity.Task, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var tasks []entity.Task
+	if err := tx.WithContext(ctx).Where("sprint_id = ?", sprintId).Order("priority DESC, id ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// SetTaskSprint moves a task into a sprint, or back to the backlog when
+// sprintId is nil.
+func (r *sprintRepository) SetTaskSprint(ctx context.Context, tx *gorm.DB, taskId int, sprintId *int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Task{}).Where("id = ?", taskId).Update("sprint_id", sprintId).Error
+}
+
+// MoveSprintTasks moves those of taskIds still in the sprint to
+// nextSprintId, or to the backlog when it is nil.
+func (r *sprintRepository) MoveSprintTasks(ctx context.Context, tx *gorm.DB, sprintId int, taskIds []int, nextSprintId *int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if len(taskIds) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Task{}).Where("id IN ? AND sprint_id = ?", taskIds, sprintId).Update("sprint_id", nextSprintId).Error
+}
+
+// CloseSprint saves the sprint as closed, as long as it is still active. It
+// returns gorm.ErrRecordNotFound when it is not, which includes a sprint
+// closed by someone else in the meantime.
+func (r *sprintRepository) CloseSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.Sprint{}).
+		Where("id = ? AND state = ?", sprint.ID, constants.ENUM_SPRINT_STATE_ACTIVE).
+		Updates(map[string]interface{}{"state": sprint.State, "closed_at": sprint.ClosedAt})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *sprintRepository) SaveSprintSnapshot(ctx context.Context, tx *gorm.DB, tasks []entity.SprintTask) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).Omit(clause.Associations).Create(&tasks).Error
+}
+
+// GetSprintSnapshot returns the tasks of a closed sprint as they were when
+// it closed, most urgent first.
+func (r *sprintRepository) GetSprintSnapshot(ctx context.Context, tx *gorm.DB, sprintId int) ([]entity.SprintTask, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var tasks []entity.SprintTask
+	if err := tx.WithContext(ctx).Where("sprint_id = ?", sprintId).Order("priority DESC, task_id ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
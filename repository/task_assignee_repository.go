@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	TaskAssigneeRepository interface {
+		AddAssignee(ctx context.Context, tx *gorm.DB, assignee entity.TaskAssignee) error
+		GetAssignees(ctx context.Context, tx *gorm.DB, taskIds []int) ([]entity.TaskAssignee, error)
+		RemoveAssignee(ctx context.Context, tx *gorm.DB, taskId int, userId uuid.UUID) error
+	}
+
+	taskAssigneeRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTaskAssigneeRepository(db *gorm.DB) TaskAssigneeRepository {
+	return &taskAssigneeRepository{
+		db: db,
+	}
+}
+
+// AddAssignee puts a user on a task, or changes their role if they are
+// already on it.
+func (r *taskAssigneeRepository) AddAssignee(ctx context.Context, tx *gorm.DB, assignee entity.TaskAssignee) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"role"})}).
+		Create(&assignee).Error
+}
+
+// GetAssignees returns everyone on any of taskIds with their user loaded,
+// in the order they were added.
+func (r *taskAssigneeRepository) GetAssignees(ctx context.Context, tx *gorm.DB, taskIds []int) ([]entity.TaskAssignee, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var assignees []entity.TaskAssignee
+	if len(taskIds) == 0 {
+		return assignees, nil
+	}
+
+	if err := tx.WithContext(ctx).
+		Preload("User").
+		Where("task_id IN ?", taskIds).
+		Order("created_at ASC, user_id ASC").
+		Find(&assignees).Error; err != nil {
+		return nil, err
+	}
+
+	return assignees, nil
+}
+
+func (r *taskAssigneeRepository) RemoveAssignee(ctx context.Context, tx *gorm.DB, taskId int, userId uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.TaskAssignee{}, "task_id = ? AND user_id = ?", taskId, userId).Error
+}
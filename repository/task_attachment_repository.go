@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	TaskAttachmentRepository interface {
+		CreateAttachment(ctx context.Context, tx *gorm.DB, attachment entity.TaskAttachment) (entity.TaskAttachment, error)
+		GetAttachmentById(ctx context.Context, tx *gorm.DB, taskId int, attachmentId int) (entity.TaskAttachment, error)
+		GetAttachmentsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskAttachment, error)
+		DeleteAttachment(ctx context.Context, tx *gorm.DB, attachmentId int) error
+		DeleteAttachmentsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) error
+	}
+
+	taskAttachmentRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTaskAttachmentRepository(db *gorm.DB) TaskAttachmentRepository {
+	return &taskAttachmentRepository{
+		db: db,
+	}
+}
+
+func (r *taskAttachmentRepository) CreateAttachment(ctx context.Context, tx *gorm.DB, attachment entity.TaskAttachment) (entity.TaskAttachment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit(clause.Associations).Create(&attachment).Error; err != nil {
+		return entity.TaskAttachment{}, err
+	}
+
+	return attachment, nil
+}
+
+func (r *taskAttachmentRepository) GetAttachmentById(ctx context.Context, tx *gorm.DB, taskId int, attachmentId int) (entity.TaskAttachment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var attachment entity.TaskAttachment
+	if err := tx.WithContext(ctx).Preload("User").Where("id = ? AND task_id = ?", attachmentId, taskId).Take(&attachment).Error; err != nil {
+		return entity.TaskAttachment{}, err
+	}
+
+	return attachment, nil
+}
+
+func (r *taskAttachmentRepository) GetAttachmentsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskAttachment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var attachments []entity.TaskAttachment
+	if err := tx.WithContext(ctx).Preload("User").Where("task_id = ?", taskId).Order("created_at ASC, id ASC").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *taskAttachmentRepository) DeleteAttachment(ctx context.Context, tx *gorm.DB, attachmentId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.TaskAttachment{}, "id = ?", attachmentId).Error
+}
+
+func (r *taskAttachmentRepository) DeleteAttachmentsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.TaskAttachment{}, "task_id = ?", taskId).Error
+}
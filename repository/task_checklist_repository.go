@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	TaskChecklistRepository interface {
+		CreateItem(ctx context.Context, tx *gorm.DB, item entity.TaskChecklistItem) (entity.TaskChecklistItem, error)
+		GetItemById(ctx context.Context, tx *gorm.DB, taskId int, itemId int) (entity.TaskChecklistItem, error)
+		GetItemsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskChecklistItem, error)
+		UpdateItem(ctx context.Context, tx *gorm.DB, item entity.TaskChecklistItem) (entity.TaskChecklistItem, error)
+		DeleteItem(ctx context.Context, tx *gorm.DB, itemId int) error
+		ReorderItems(ctx context.Context, tx *gorm.DB, taskId int, itemIds []int) error
+		CountItems(ctx context.Context, tx *gorm.DB, taskIds []int) ([]dto.ChecklistCount, error)
+	}
+
+	taskChecklistRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTaskChecklistRepository(db *gorm.DB) TaskChecklistRepository {
+	return &taskChecklistRepository{
+		db: db,
+	}
+}
+
+func (r *taskChecklistRepository) CreateItem(ctx context.Context, tx *gorm.DB, item entity.TaskChecklistItem) (entity.TaskChecklistItem, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit(clause.Associations).Create(&item).Error; err != nil {
+		return entity.TaskChecklistItem{}, err
+	}
+
+	return item, nil
+}
+
+func (r *taskChecklistRepository) GetItemById(ctx context.Context, tx *gorm.DB, taskId int, itemId int) (entity.TaskChecklistItem, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var item entity.TaskChecklistItem
+	if err := tx.WithContext(ctx).Where("id = ? AND task_id = ?", itemId, taskId).Take(&item).Error; err != nil {
+		return entity.TaskChecklistItem{}, err
+	}
+
+	return item, nil
+}
+
+func (r *taskChecklistRepository) GetItemsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskChecklistItem, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var items []entity.TaskChecklistItem
+	if err := tx.WithContext(ctx).Where("task_id = ?", taskId).Order("position ASC, id ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *taskChecklistRepository) UpdateItem(ctx context.Context, tx *gorm.DB, item entity.TaskChecklistItem) (entity.TaskChecklistItem, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Model(&item).Select("title", "is_done", "completed_by", "completed_at").Updates(&item).Error; err != nil {
+		return entity.TaskChecklistItem{}, err
+	}
+
+	return item, nil
+}
+
+func (r *taskChecklistRepository) DeleteItem(ctx context.Context, tx *gorm.DB, itemId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.TaskChecklistItem{}, "id = ?", itemId).Error
+}
+
+// ReorderItems gives the checklist items of taskId the positions of their
+// ids in itemIds.
+func (r *taskChecklistRepository) ReorderItems(ctx context.Context, tx *gorm.DB, taskId int, itemIds []int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, itemId := range itemIds {
+			if err := tx.Model(&entity.TaskChecklistItem{}).Where("id = ? AND task_id = ?", itemId, taskId).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *taskChecklistRepository) CountItems(ctx context.Context, tx *gorm.DB, taskIds []int) ([]dto.ChecklistCount, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var counts []dto.ChecklistCount
+	if len(taskIds) == 0 {
+		return counts, nil
+	}
+
+	if err := tx.WithContext(ctx).Model(&entity.TaskChecklistItem{}).
+		Select("task_id, COUNT(*) AS total, SUM(CASE WHEN is_done THEN 1 ELSE 0 END) AS completed").
+		Where("task_id IN ?", taskIds).
+		Group("task_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	TaskCommentRepository interface {
+		CreateComment(ctx context.Context, tx *gorm.DB, comment entity.TaskComment) (entity.TaskComment, error)
+		GetCommentById(ctx context.Context, tx *gorm.DB, taskId int, commentId int) (entity.TaskComment, error)
+		GetCommentsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskComment, error)
+		UpdateComment(ctx context.Context, tx *gorm.DB, comment entity.TaskComment) (entity.TaskComment, error)
+		DeleteComment(ctx context.Context, tx *gorm.DB, commentId int) error
+		ReplaceMentions(ctx context.Context, tx *gorm.DB, commentId int, userIds []uuid.UUID) error
+	}
+
+	taskCommentRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTaskCommentRepository(db *gorm.DB) TaskCommentRepository {
+	return &taskCommentRepository{
+		db: db,
+	}
+}
+
+func (r *taskCommentRepository) CreateComment(ctx context.Context, tx *gorm.DB, comment entity.TaskComment) (entity.TaskComment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit(clause.Associations).Create(&comment).Error; err != nil {
+		return entity.TaskComment{}, err
+	}
+
+	return comment, nil
+}
+
+func (r *taskCommentRepository) GetCommentById(ctx context.Context, tx *gorm.DB, taskId int, commentId int) (entity.TaskComment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var comment entity.TaskComment
+	if err := tx.WithContext(ctx).
+		Preload("User").
+		Preload("Mentions.User").
+		Where("id = ? AND task_id = ?", commentId, taskId).
+		Take(&comment).Error; err != nil {
+		return entity.TaskComment{}, err
+	}
+
+	return comment, nil
+}
+
+// GetCommentsByTaskId includes soft-deleted comments so that replies to a
+// deleted comment can still be placed in their thread.
+func (r *taskCommentRepository) GetCommentsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskComment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var comments []entity.TaskComment
+	if err := tx.WithContext(ctx).Unscoped().
+		Preload("User").
+		Preload("Mentions.User").
+		Where("task_id = ?", taskId).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *taskCommentRepository) UpdateComment(ctx context.Context, tx *gorm.DB, comment entity.TaskComment) (entity.TaskComment, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Model(&comment).Update("body", comment.Body).Error; err != nil {
+		return entity.TaskComment{}, err
+	}
+
+	return comment, nil
+}
+
+func (r *taskCommentRepository) DeleteComment(ctx context.Context, tx *gorm.DB, commentId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.TaskComment{}, "id = ?", commentId).Error
+}
+
+func (r *taskCommentRepository) ReplaceMentions(ctx context.Context, tx *gorm.DB, commentId int, userIds []uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("comment_id = ?", commentId).Delete(&entity.TaskCommentMention{}).Error; err != nil {
+			return err
+		}
+
+		if len(userIds) == 0 {
+			return nil
+		}
+
+		mentions := make([]entity.TaskCommentMention, 0, len(userIds))
+		for _, userId := range userIds {
+			mentions = append(mentions, entity.TaskCommentMention{CommentID: commentId, UserID: userId})
+		}
+
+		return tx.Omit(clause.Associations).Create(&mentions).Error
+	})
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"math"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	// TaskEventRepository deliberately has no update or delete: the history
+	// of a task is immutable once written.
+	TaskEventRepository interface {
+		CreateEvents(ctx context.Context, tx *gorm.DB, events []entity.TaskEvent) error
+		GetEventsByTaskIdWithPagination(ctx context.Context, tx *gorm.DB, taskId int, req dto.PaginationRequest) (dto.GetAllTaskEventRepositoryResponse, error)
+	}
+
+	taskEventRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTaskEventRepository(db *gorm.DB) TaskEventRepository {
+	return &taskEventRepository{
+		db: db,
+	}
+}
+
+func (r *taskEventRepository) CreateEvents(ctx context.Context, tx *gorm.DB, events []entity.TaskEvent) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).Create(&events).Error
+}
+
+func (r *taskEventRepository) GetEventsByTaskIdWithPagination(ctx context.Context, tx *gorm.DB, taskId int, req dto.PaginationRequest) (dto.GetAllTaskEventRepositoryResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var events []entity.TaskEvent
+	var err error
+	var count int64
+
+	req.PerPage = PerPage(req.PerPage, 20)
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	if err := tx.WithContext(ctx).Model(&entity.TaskEvent{}).Where("task_id = ?", taskId).Count(&count).Error; err != nil {
+		return dto.GetAllTaskEventRepositoryResponse{}, err
+	}
+
+	if err := tx.WithContext(ctx).Where("task_id = ?", taskId).Order("created_at DESC, id DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&events).Error; err != nil {
+		return dto.GetAllTaskEventRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.GetAllTaskEventRepositoryResponse{
+		Events: events,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			Count:   count,
+			MaxPage: totalPage,
+		},
+	}, err
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskSortColumns whitelists the fields a task list can be sorted by and maps
+// them to their columns.
+var TaskSortColumns = map[string]string{
+	"id":               "id",
+	"title":            "title",
+	"status":           "status",
+	"due_date":         "due_date",
+	"created_at":       "created_at",
+	"updated_at":       "updated_at",
+	"priority":         "priority",
+	"estimate":         "estimate",
+	"remaining_effort": "remaining_effort",
+}
+
+// FilterTasks narrows a task query. It is shared by the count and the page
+// query so both always see the same rows.
+func FilterTasks(search string, filter dto.TaskFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if search = strings.TrimSpace(search); search != "" {
+			pattern := "%" + escapeLike(search) + "%"
+			db = db.Where("title LIKE ? OR description LIKE ?", pattern, pattern)
+		}
+
+		if len(filter.Statuses) > 0 {
+			db = db.Where("status IN ?", filter.Statuses)
+		}
+
+		if filter.TeamID != 0 {
+			db = db.Where("teams_id = ?", filter.TeamID)
+		}
+
+		if filter.MemberID != nil {
+			db = db.Where("teams_id IN (SELECT team_id FROM user_teams WHERE user_id = ?)", filter.MemberID)
+		}
+
+		if filter.Unassigned {
+			db = db.Where("user_id IS NULL")
+		} else if filter.AssigneeID != nil {
+			db = db.Where("(user_id = ? OR tasks.id IN (SELECT task_id FROM task_assignees WHERE user_id = ? AND role = ?))", filter.AssigneeID, filter.AssigneeID, constants.ENUM_TASK_ROLE_ASSIGNEE)
+		}
+
+		for _, labelId := range filter.LabelIDs {
+			db = db.Where("tasks.id IN (SELECT task_id FROM task_labels WHERE label_id = ?)", labelId)
+		}
+
+		if len(filter.Priorities) > 0 {
+			db = db.Where("priority IN ?", filter.Priorities)
+		}
+
+		if filter.MinEstimate != nil {
+			db = db.Where("estimate >= ?", *filter.MinEstimate)
+		}
+
+		if filter.MaxEstimate != nil {
+			db = db.Where("estimate <= ?", *filter.MaxEstimate)
+		}
+
+		if filter.MinRemaining != nil {
+			db = db.Where("remaining_effort >= ?", *filter.MinRemaining)
+		}
+
+		if filter.MaxRemaining != nil {
+			db = db.Where("remaining_effort <= ?", *filter.MaxRemaining)
+		}
+
+		if filter.DueAfter != nil {
+			db = db.Where("due_date >= ?", filter.DueAfter)
+		}
+
+		if filter.DueBefore != nil {
+			db = db.Where("due_date < ?", filter.DueBefore)
+		}
+
+		if filter.OpenOnly {
+			db = db.Scopes(OpenTasks(filter.DefaultDoneStatuses))
+		}
+
+		return db
+	}
+}
+
+// OpenTasks drops tasks whose status is in the done category of their team's
+// workflow. Teams without a workflow of their own use defaultDone instead.
+func OpenTasks(defaultDone []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("NOT EXISTS (SELECT 1 FROM workflow_statuses ws WHERE ws.teams_id = tasks.teams_id AND ws.name = tasks.status AND ws.category = ? AND ws.deleted_at IS NULL)", constants.ENUM_STATUS_CATEGORY_DONE)
+		if len(defaultDone) > 0 {
+			db = db.Where("tasks.status NOT IN ? OR EXISTS (SELECT 1 FROM workflow_statuses ws WHERE ws.teams_id = tasks.teams_id AND ws.deleted_at IS NULL)", defaultDone)
+		}
+		return db
+	}
+}
+
+// SortTasks orders by the requested fields, falling back to id so pages are
+// stable. Fields must already be validated against TaskSortColumns.
+func SortTasks(sorts []dto.TaskSort) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, column := range TaskKeysetColumns(sorts) {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: column.Name}, Desc: column.Desc})
+		}
+		return db
+	}
+}
+
+// TaskKeysetColumns resolves the requested sort into columns, appending id as
+// the tie-breaker unless it is already part of the sort.
+func TaskKeysetColumns(sorts []dto.TaskSort) []KeysetColumn {
+	var columns []KeysetColumn
+	hasID := false
+	for _, sort := range sorts {
+		column, ok := TaskSortColumns[sort.Field]
+		if !ok {
+			continue
+		}
+
+		hasID = hasID || column == "id"
+		columns = append(columns, KeysetColumn{Name: column, Desc: sort.Desc})
+	}
+
+	if !hasID {
+		columns = append(columns, KeysetColumn{Name: "id"})
+	}
+
+	return columns
+}
+
+func taskKeysetValues(columns []KeysetColumn) func(task entity.Task) []string {
+	return func(task entity.Task) []string {
+		values := make([]string, len(columns))
+		for i, column := range columns {
+			switch column.Name {
+			case "id":
+				values[i] = strconv.Itoa(task.ID)
+			case "title":
+				values[i] = task.Title
+			case "status":
+				values[i] = task.Status
+			case "due_date":
+				values[i] = cursorTime(task.DueDate)
+			case "created_at":
+				values[i] = cursorTime(task.CreatedAt)
+			case "updated_at":
+				values[i] = cursorTime(task.UpdatedAt)
+			case "priority":
+				values[i] = strconv.Itoa(task.Priority)
+			case "estimate":
+				values[i] = strconv.Itoa(task.Estimate)
+			case "remaining_effort":
+				values[i] = strconv.Itoa(task.RemainingEffort)
+			}
+		}
+		return values
+	}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	TaskLinkRepository interface {
+		CreateLink(ctx context.Context, tx *gorm.DB, link entity.TaskLink) (entity.TaskLink, error)
+		GetLinkById(ctx context.Context, tx *gorm.DB, taskId int, linkId int) (entity.TaskLink, error)
+		GetLinksByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskLink, error)
+		LinkExists(ctx context.Context, tx *gorm.DB, sourceTaskId int, targetTaskId int, linkType string) (bool, error)
+		DeleteLink(ctx context.Context, tx *gorm.DB, linkId int) error
+		DeleteLinksByTaskId(ctx context.Context, tx *gorm.DB, taskId int) error
+		GetBlockedTaskIds(ctx context.Context, tx *gorm.DB, taskIds []int) ([]int, error)
+		GetBlockers(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.Task, error)
+		LockLinks(ctx context.Context, tx *gorm.DB, teamId int) error
+	}
+
+	taskLinkRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTaskLinkRepository(db *gorm.DB) TaskLinkRepository {
+	return &taskLinkRepository{
+		db: db,
+	}
+}
+
+// LockLinks locks the team's row until tx ends, so that links between the
+// team's tasks are added one at a time. Locking only the two linked tasks
+// would not do: two blocking links between four different tasks can still
+// close a cycle together.
+func (r *taskLinkRepository) LockLinks(ctx context.Context, tx *gorm.DB, teamId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var team entity.Team
+	return tx.WithContext(ctx).Select("id").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", teamId).
+		Take(&team).Error
+}
+
+func (r *taskLinkRepository) CreateLink(ctx context.Context, tx *gorm.DB, link entity.TaskLink) (entity.TaskLink, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit(clause.Associations).Create(&link).Error; err != nil {
+		return entity.TaskLink{}, err
+	}
+
+	return link, nil
+}
+
+// GetLinkById finds a link that has taskId at either end.
+func (r *taskLinkRepository) GetLinkById(ctx context.Context, tx *gorm.DB, taskId int, linkId int) (entity.TaskLink, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var link entity.TaskLink
+	if err := tx.WithContext(ctx).
+		Where("id = ? AND (source_task_id = ? OR target_task_id = ?)", linkId, taskId, taskId).
+		Take(&link).Error; err != nil {
+		return entity.TaskLink{}, err
+	}
+
+	return link, nil
+}
+
+// GetLinksByTaskId returns the links at either end of taskId with both tasks
+// loaded. A deleted task is loaded as a zero Task.
+func (r *taskLinkRepository) GetLinksByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskLink, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var links []entity.TaskLink
+	if err := tx.WithContext(ctx).
+		Preload("SourceTask").
+		Preload("TargetTask").
+		Where("source_task_id = ? OR target_task_id = ?", taskId, taskId).
+		Order("created_at ASC, id ASC").
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+func (r *taskLinkRepository) LinkExists(ctx context.Context, tx *gorm.DB, sourceTaskId int, targetTaskId int, linkType string) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).Model(&entity.TaskLink{}).
+		Where("source_task_id = ? AND target_task_id = ? AND type = ?", sourceTaskId, targetTaskId, linkType).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *taskLinkRepository) DeleteLink(ctx context.Context, tx *gorm.DB, linkId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.TaskLink{}, "id = ?", linkId).Error
+}
+
+func (r *taskLinkRepository) DeleteLinksByTaskId(ctx context.Context, tx *gorm.DB, taskId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.TaskLink{}, "source_task_id = ? OR target_task_id = ?", taskId, taskId).Error
+}
+
+// GetBlockedTaskIds returns the tasks directly blocked by any of taskIds.
+func (r *taskLinkRepository) GetBlockedTaskIds(ctx context.Context, tx *gorm.DB, taskIds []int) ([]int, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var ids []int
+	if len(taskIds) == 0 {
+		return ids, nil
+	}
+
+	if err := tx.WithContext(ctx).Model(&entity.TaskLink{}).
+		Where("type = ? AND source_task_id IN ?", constants.ENUM_TASK_LINK_BLOCKS, taskIds).
+		Distinct().
+		Pluck("target_task_id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetBlockers returns the tasks that block taskId, leaving out deleted ones.
+func (r *taskLinkRepository) GetBlockers(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.Task, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var tasks []entity.Task
+	if err := tx.WithContext(ctx).
+		Joins("JOIN task_links ON task_links.source_task_id = tasks.id").
+		Where("task_links.target_task_id = ? AND task_links.type = ?", taskId, constants.ENUM_TASK_LINK_BLOCKS).
+		Order("tasks.id ASC").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	TaskReminderRepository interface {
+		GetAssigneesToRemind(ctx context.Context, tx *gorm.DB, kind string, dueAfter *time.Time, dueBefore time.Time, defaultDone []string, limit int) ([]entity.TaskAssignee, error)
+		CreateReminder(ctx context.Context, tx *gorm.DB, reminder entity.TaskReminder) (bool, error)
+	}
+
+	taskReminderRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTaskReminderRepository(db *gorm.DB) TaskReminderRepository {
+	return &taskReminderRepository{
+		db: db,
+	}
+}
+
+// GetAssigneesToRemind returns the assignees of open tasks due in
+// (dueAfter, dueBefore] who have not had a reminder of this kind for the
+// task's current due date yet, each with their task loaded.
+func (r *taskReminderRepository) GetAssigneesToRemind(ctx context.Context, tx *gorm.DB, kind string, dueAfter *time.Time, dueBefore time.Time, defaultDone []string, limit int) ([]entity.TaskAssignee, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	db := tx.WithContext(ctx).Model(&entity.TaskAssignee{}).
+		Joins("JOIN tasks ON tasks.id = task_assignees.task_id AND tasks.deleted_at IS NULL").
+		Where("task_assignees.role = ? AND tasks.due_date <= ?", constants.ENUM_TASK_ROLE_ASSIGNEE, dueBefore).
+		Where("NOT EXISTS (SELECT 1 FROM task_reminders tr WHERE tr.task_id = tasks.id AND tr.kind = ? AND tr.user_id = task_assignees.user_id AND tr.due_date = tasks.due_date)", kind).
+		Scopes(OpenTasks(defaultDone))
+	if dueAfter != nil {
+		db = db.Where("tasks.due_date > ?", dueAfter)
+	}
+
+	var assignees []entity.TaskAssignee
+	if err := db.Select("task_assignees.*").
+		Preload("Task").
+		Order("tasks.due_date ASC, tasks.id ASC, task_assignees.user_id ASC").
+		Limit(limit).
+		Find(&assignees).Error; err != nil {
+		return nil, err
+	}
+
+	return assignees, nil
+}
+
+// CreateReminder records a reminder and reports whether this call created
+// it. When several instances scan at once only one of them gets true, so
+// only one reminder goes out.
+func (r *taskReminderRepository) CreateReminder(ctx context.Context, tx *gorm.DB, reminder entity.TaskReminder) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&reminder)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -14,14 +14,24 @@ import (
 type (
 	TaskRepository interface {
 		RegisterTask(ctx context.Context, tx *gorm.DB, task entity.Task) (entity.Task, error)
-		GetAllTaskWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.GetAllTaskRepositoryResponse, error)
+		GetAllTaskWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest, filter dto.TaskFilter) (dto.GetAllTaskRepositoryResponse, error)
 		GetTaskById(ctx context.Context, tx *gorm.DB, taskId string) (entity.Task, error)
-		GetTasksByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Task, error)
+		GetTasksByTeamID(ctx context.Context, tx *gorm.DB, teamsID int, filter dto.TaskFilter) ([]entity.Task, error)
 		UpdateTask(ctx context.Context, tx *gorm.DB, task entity.Task) (entity.Task, error)
+		UpdateTaskPlanning(ctx context.Context, tx *gorm.DB, task entity.Task) error
 		DeleteTask(ctx context.Context, tx *gorm.DB, taskId string) error
 		AssignUserToTask(ctx context.Context, tx *gorm.DB, taskId string, userID *uuid.UUID) error
 		RemoveUserFromTask(ctx context.Context, tx *gorm.DB, taskId string) error
 		GetTasksByUserID(ctx context.Context, userID string) ([]entity.Task, error)
+		ReassignTeamTasks(ctx context.Context, tx *gorm.DB, teamsID int, fromUserID uuid.UUID, toUserID *uuid.UUID) (int64, error)
+		CountTasksByStatus(ctx context.Context, tx *gorm.DB, teamsID int, status string) (int64, error)
+		CountTeamTasksByStatus(ctx context.Context, tx *gorm.DB, teamsID int) ([]dto.TaskStatusCount, error)
+		RenameStatus(ctx context.Context, tx *gorm.DB, teamsID int, from string, to string) error
+		GetChildren(ctx context.Context, tx *gorm.DB, parentIds []int) ([]entity.Task, error)
+		SetParent(ctx context.Context, tx *gorm.DB, taskId int, parentId *int, position int) error
+		ReorderChildren(ctx context.Context, tx *gorm.DB, parentId int, taskIds []int) error
+		DetachChildren(ctx context.Context, tx *gorm.DB, parentId int) error
+		CountChildrenByStatus(ctx context.Context, tx *gorm.DB, parentIds []int) ([]dto.TaskChildStatusCount, error)
 	}
 
 	taskRepository struct {
@@ -50,7 +60,7 @@ func (r *taskRepository) RegisterTask(ctx context.Context, tx *gorm.DB, task ent
 	return task, nil
 }
 
-func (r *taskRepository) GetAllTaskWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest) (dto.GetAllTaskRepositoryResponse, error) {
+func (r *taskRepository) GetAllTaskWithPagination(ctx context.Context, tx *gorm.DB, req dto.PaginationRequest, filter dto.TaskFilter) (dto.GetAllTaskRepositoryResponse, error) {
 	if tx == nil {
 		tx = r.db
 	}
@@ -59,19 +69,27 @@ func (r *taskRepository) GetAllTaskWithPagination(ctx context.Context, tx *gorm.
 	var err error
 	var count int64
 
-	if req.PerPage == 0 {
-		req.PerPage = 20
+	req.PerPage = PerPage(req.PerPage, 20)
+
+	if req.IsKeyset() {
+		columns := TaskKeysetColumns(filter.Sort)
+		query := func() *gorm.DB {
+			return tx.WithContext(ctx).Model(&entity.Task{}).Scopes(FilterTasks(req.Search, filter))
+		}
+
+		tasks, meta, err := KeysetPaginate(query, req, columns, taskKeysetValues(columns))
+		return dto.GetAllTaskRepositoryResponse{Tasks: tasks, PaginationResponse: meta}, err
 	}
 
 	if req.Page == 0 {
 		req.Page = 1
 	}
 
-	if err := tx.WithContext(ctx).Model(&entity.Task{}).Count(&count).Error; err != nil {
+	if err := tx.WithContext(ctx).Model(&entity.Task{}).Scopes(FilterTasks(req.Search, filter)).Count(&count).Error; err != nil {
 		return dto.GetAllTaskRepositoryResponse{}, err
 	}
 
-	if err := tx.WithContext(ctx).Scopes(Paginate(req.Page, req.PerPage)).Find(&tasks).Error; err != nil {
+	if err := tx.WithContext(ctx).Scopes(FilterTasks(req.Search, filter), SortTasks(filter.Sort), Paginate(req.Page, req.PerPage)).Find(&tasks).Error; err != nil {
 		return dto.GetAllTaskRepositoryResponse{}, err
 	}
 
@@ -101,13 +119,13 @@ func (r *taskRepository) GetTaskById(ctx context.Context, tx *gorm.DB, taskId st
 	return task, nil
 }
 
-func (r *taskRepository) GetTasksByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Task, error) {
+func (r *taskRepository) GetTasksByTeamID(ctx context.Context, tx *gorm.DB, teamsID int, filter dto.TaskFilter) ([]entity.Task, error) {
 	if tx == nil {
 		tx = r.db
 	}
 
 	var tasks []entity.Task
-	if err := tx.WithContext(ctx).Where("teams_id = ?", teamsID).Find(&tasks).Error; err != nil {
+	if err := tx.WithContext(ctx).Where("teams_id = ?", teamsID).Scopes(FilterTasks("", filter), SortTasks(filter.Sort)).Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 
@@ -126,6 +144,16 @@ func (r *taskRepository) UpdateTask(ctx context.Context, tx *gorm.DB, task entit
 	return task, nil
 }
 
+// UpdateTaskPlanning writes the priority and effort of a task, including
+// zero values.
+func (r *taskRepository) UpdateTaskPlanning(ctx context.Context, tx *gorm.DB, task entity.Task) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&task).Select("priority", "estimate", "remaining_effort").Updates(&task).Error
+}
+
 func (r *taskRepository) DeleteTask(ctx context.Context, tx *gorm.DB, taskId string) error {
 	if tx == nil {
 		tx = r.db
@@ -166,8 +194,141 @@ func (r *taskRepository) RemoveUserFromTask(ctx context.Context, tx *gorm.DB, ta
 
 func (r *taskRepository) GetTasksByUserID(ctx context.Context, userID string) ([]entity.Task, error) {
     var tasks []entity.Task
-    if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&tasks).Error; err != nil {
+    onTask := r.db.Model(&entity.TaskAssignee{}).Select("task_id").Where("user_id = ?", userID)
+    if err := r.db.WithContext(ctx).Where("user_id = ? OR id IN (?)", userID, onTask).Find(&tasks).Error; err != nil {
         return nil, err
     }
     return tasks, nil
 }
+
+func (r *taskRepository) ReassignTeamTasks(ctx context.Context, tx *gorm.DB, teamsID int, fromUserID uuid.UUID, toUserID *uuid.UUID) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.Task{}).
+		Where("teams_id = ? AND user_id = ?", teamsID, fromUserID).
+		Update("user_id", toUserID)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *taskRepository) CountTasksByStatus(ctx context.Context, tx *gorm.DB, teamsID int, status string) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).Model(&entity.Task{}).Where("teams_id = ? AND status = ?", teamsID, status).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *taskRepository) CountTeamTasksByStatus(ctx context.Context, tx *gorm.DB, teamsID int) ([]dto.TaskStatusCount, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var counts []dto.TaskStatusCount
+	if err := tx.WithContext(ctx).Model(&entity.Task{}).
+		Select("status, COUNT(*) AS count").
+		Where("teams_id = ?", teamsID).
+		Group("status").
+		Order("status ASC").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *taskRepository) RenameStatus(ctx context.Context, tx *gorm.DB, teamsID int, from string, to string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Task{}).Where("teams_id = ? AND status = ?", teamsID, from).Update("status", to).Error
+}
+
+
+// GetChildren returns the direct subtasks of every task in parentIds,
+// ordered by position within each parent.
+func (r *taskRepository) GetChildren(ctx context.Context, tx *gorm.DB, parentIds []int) ([]entity.Task, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var tasks []entity.Task
+	if len(parentIds) == 0 {
+		return tasks, nil
+	}
+
+	if err := tx.WithContext(ctx).Where("parent_id IN ?", parentIds).Order("parent_id ASC, position ASC, id ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *taskRepository) SetParent(ctx context.Context, tx *gorm.DB, taskId int, parentId *int, position int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Task{}).Where("id = ?", taskId).Updates(map[string]interface{}{
+		"parent_id": parentId,
+		"position":  position,
+	}).Error
+}
+
+// ReorderChildren gives the subtasks of parentId the positions of their ids
+// in taskIds.
+func (r *taskRepository) ReorderChildren(ctx context.Context, tx *gorm.DB, parentId int, taskIds []int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, taskId := range taskIds {
+			if err := tx.Model(&entity.Task{}).Where("id = ? AND parent_id = ?", taskId, parentId).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DetachChildren turns the subtasks of parentId into top-level tasks.
+func (r *taskRepository) DetachChildren(ctx context.Context, tx *gorm.DB, parentId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Task{}).Where("parent_id = ?", parentId).Update("parent_id", nil).Error
+}
+
+func (r *taskRepository) CountChildrenByStatus(ctx context.Context, tx *gorm.DB, parentIds []int) ([]dto.TaskChildStatusCount, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var counts []dto.TaskChildStatusCount
+	if len(parentIds) == 0 {
+		return counts, nil
+	}
+
+	if err := tx.WithContext(ctx).Model(&entity.Task{}).
+		Select("parent_id, status, COUNT(*) AS count").
+		Where("parent_id IN ?", parentIds).
+		Group("parent_id, status").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
\ No newline at end of file
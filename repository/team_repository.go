@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"math"
+	"strconv"
 
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
@@ -54,8 +55,18 @@ func (r *teamRepository) GetAllTeamWithPagination(ctx context.Context, tx *gorm.
 	var err error
 	var count int64
 
-	if req.PerPage == 0 {
-		req.PerPage = 20
+	req.PerPage = PerPage(req.PerPage, 20)
+
+	if req.IsKeyset() {
+		columns := []KeysetColumn{{Name: "id"}}
+		query := func() *gorm.DB {
+			return tx.WithContext(ctx).Model(&entity.Team{})
+		}
+
+		teams, meta, err := KeysetPaginate(query, req, columns, func(row entity.Team) []string {
+			return []string{strconv.Itoa(row.ID)}
+		})
+		return dto.GetAllTeamRepositoryResponse{Teams: teams, PaginationResponse: meta}, err
 	}
 
 	if req.Page == 0 {
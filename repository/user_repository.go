@@ -56,8 +56,18 @@ func (r *userRepository) GetAllUserWithPagination(ctx context.Context, tx *gorm.
 	var err error
 	var count int64
 
-	if req.PerPage == 0 {
-		req.PerPage = 10
+	req.PerPage = PerPage(req.PerPage, 10)
+
+	if req.IsKeyset() {
+		columns := []KeysetColumn{{Name: "id"}}
+		query := func() *gorm.DB {
+			return tx.WithContext(ctx).Model(&entity.User{})
+		}
+
+		users, meta, err := KeysetPaginate(query, req, columns, func(row entity.User) []string {
+			return []string{row.ID.String()}
+		})
+		return dto.GetAllUserRepositoryResponse{Users: users, PaginationResponse: meta}, err
 	}
 
 	if req.Page == 0 {
@@ -9,12 +9,16 @@ import (
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserTeamsRepository interface {
-	AssignUserToTeam(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint) error
+	AssignUserToTeam(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint, role string) error
 	RemoveUserFromTeam(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint) error
 	GetUsersByTeamId(ctx context.Context, tx *gorm.DB, teamId uint) ([]entity.User, error)
+	GetMembership(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint) (entity.UserTeams, error)
+	UpdateRole(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint, role string) error
+	CountByRoleForUpdate(ctx context.Context, tx *gorm.DB, teamId uint, role string) (int64, error)
 }
 
 type userTeamsRepository struct {
@@ -27,7 +31,7 @@ func NewUserTeamsRepository(db *gorm.DB) UserTeamsRepository {
 	}
 }
 
-func (r *userTeamsRepository) AssignUserToTeam(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint) error {
+func (r *userTeamsRepository) AssignUserToTeam(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint, role string) error {
     if tx == nil {
         tx = r.db
     }
@@ -41,9 +45,10 @@ func (r *userTeamsRepository) AssignUserToTeam(ctx context.Context, tx *gorm.DB,
     userTeam := entity.UserTeams{
         UserID:    userId,
         TeamID:    teamId,
+        Role:      role,
         CreatedAt: time.Now(),
     }
-    log.Printf("Assigning user %s to team %d", userId, teamId)
+    log.Printf("Assigning user %s to team %d as %s", userId, teamId, role)
     return tx.WithContext(ctx).Create(&userTeam).Error
 }
 
@@ -68,4 +73,46 @@ func (r *userTeamsRepository) GetUsersByTeamId(ctx context.Context, tx *gorm.DB,
 	}
 
 	return users, nil
+}
+
+func (r *userTeamsRepository) GetMembership(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint) (entity.UserTeams, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var userTeam entity.UserTeams
+	if err := tx.WithContext(ctx).Where("user_id = ? AND team_id = ?", userId, teamId).Take(&userTeam).Error; err != nil {
+		return entity.UserTeams{}, err
+	}
+
+	return userTeam, nil
+}
+
+func (r *userTeamsRepository) UpdateRole(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint, role string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.UserTeams{}).
+		Where("user_id = ? AND team_id = ?", userId, teamId).
+		Update("role", role).Error
+}
+
+// CountByRoleForUpdate counts the team's members with role and locks their
+// rows until tx ends, so that a check made on the count still holds when
+// the caller writes.
+func (r *userTeamsRepository) CountByRoleForUpdate(ctx context.Context, tx *gorm.DB, teamId uint, role string) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).Model(&entity.UserTeams{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("team_id = ? AND role = ?", teamId, role).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
 }
\ No newline at end of file
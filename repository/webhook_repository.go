@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	WebhookRepository interface {
+		CreateWebhook(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error)
+		GetWebhooksByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Webhook, error)
+		GetWebhookById(ctx context.Context, tx *gorm.DB, teamsID int, webhookId int) (entity.Webhook, error)
+		UpdateWebhook(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error)
+		DeleteWebhook(ctx context.Context, tx *gorm.DB, teamsID int, webhookId int) error
+		CreateDeliveries(ctx context.Context, tx *gorm.DB, deliveries []entity.WebhookDelivery) ([]entity.WebhookDelivery, error)
+		GetDeliveriesWithPagination(ctx context.Context, tx *gorm.DB, webhookId int, req dto.PaginationRequest) (dto.GetAllWebhookDeliveryRepositoryResponse, error)
+		GetDeliveryById(ctx context.Context, tx *gorm.DB, webhookId int, deliveryId int) (entity.WebhookDelivery, error)
+		ClaimDueDeliveries(ctx context.Context, tx *gorm.DB, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error)
+		UpdateDelivery(ctx context.Context, tx *gorm.DB, delivery entity.WebhookDelivery) error
+	}
+
+	webhookRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) CreateWebhook(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&webhook).Error; err != nil {
+		return entity.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+func (r *webhookRepository) GetWebhooksByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Webhook, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var webhooks []entity.Webhook
+	if err := tx.WithContext(ctx).Where("teams_id = ?", teamsID).Order("id ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *webhookRepository) GetWebhookById(ctx context.Context, tx *gorm.DB, teamsID int, webhookId int) (entity.Webhook, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var webhook entity.Webhook
+	if err := tx.WithContext(ctx).Where("id = ? AND teams_id = ?", webhookId, teamsID).Take(&webhook).Error; err != nil {
+		return entity.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+func (r *webhookRepository) UpdateWebhook(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	// Select keeps is_active=false and an emptied event list from being skipped
+	if err := tx.WithContext(ctx).Model(&webhook).Select("url", "secret", "events", "is_active").Updates(&webhook).Error; err != nil {
+		return entity.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, tx *gorm.DB, teamsID int, webhookId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ? AND teams_id = ?", webhookId, teamsID).Delete(&entity.Webhook{}).Error
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, tx *gorm.DB, deliveries []entity.WebhookDelivery) ([]entity.WebhookDelivery, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if len(deliveries) == 0 {
+		return deliveries, nil
+	}
+
+	if err := tx.WithContext(ctx).Create(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) GetDeliveriesWithPagination(ctx context.Context, tx *gorm.DB, webhookId int, req dto.PaginationRequest) (dto.GetAllWebhookDeliveryRepositoryResponse, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var deliveries []entity.WebhookDelivery
+	var count int64
+
+	req.PerPage = PerPage(req.PerPage, 20)
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	query := func() *gorm.DB {
+		return tx.WithContext(ctx).Model(&entity.WebhookDelivery{}).Where("webhook_id = ?", webhookId)
+	}
+
+	if err := query().Count(&count).Error; err != nil {
+		return dto.GetAllWebhookDeliveryRepositoryResponse{}, err
+	}
+
+	if err := query().Order("created_at DESC, id DESC").Scopes(Paginate(req.Page, req.PerPage)).Find(&deliveries).Error; err != nil {
+		return dto.GetAllWebhookDeliveryRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PerPage)))
+
+	return dto.GetAllWebhookDeliveryRepositoryResponse{
+		Deliveries: deliveries,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.Page,
+			PerPage: req.PerPage,
+			Count:   count,
+			MaxPage: totalPage,
+		},
+	}, nil
+}
+
+func (r *webhookRepository) GetDeliveryById(ctx context.Context, tx *gorm.DB, webhookId int, deliveryId int) (entity.WebhookDelivery, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var delivery entity.WebhookDelivery
+	if err := tx.WithContext(ctx).Where("id = ? AND webhook_id = ?", deliveryId, webhookId).Take(&delivery).Error; err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries that are due, the
+// same way EmailOutboxRepository.ClaimDue does. The webhook is preloaded; it
+// is left zero when the webhook has since been deleted.
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, tx *gorm.DB, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	token := uuid.NewString()
+	if err := tx.WithContext(ctx).Model(&entity.WebhookDelivery{}).
+		Where("status = ? AND next_attempt_at <= ?", constants.ENUM_WEBHOOK_DELIVERY_PENDING, now).
+		Where("locked_until IS NULL OR locked_until <= ?", now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Updates(map[string]interface{}{
+			"lock_token":   token,
+			"locked_until": now.Add(lease),
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	var deliveries []entity.WebhookDelivery
+	if err := tx.WithContext(ctx).Preload("Webhook").Where("lock_token = ?", token).Order("next_attempt_at ASC").Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery records the outcome of a delivery attempt and releases the
+// lease taken by ClaimDueDeliveries.
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, tx *gorm.DB, delivery entity.WebhookDelivery) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"response_status": delivery.ResponseStatus,
+			"last_error":      delivery.LastError,
+			"delivered_at":    delivery.DeliveredAt,
+			"lock_token":      "",
+			"locked_until":    nil,
+		}).Error
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	WorkflowRepository interface {
+		CreateStatus(ctx context.Context, tx *gorm.DB, status entity.WorkflowStatus) (entity.WorkflowStatus, error)
+		GetStatusesByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.WorkflowStatus, error)
+		GetStatusById(ctx context.Context, tx *gorm.DB, teamsID int, statusId int) (entity.WorkflowStatus, error)
+		UpdateStatus(ctx context.Context, tx *gorm.DB, status entity.WorkflowStatus) (entity.WorkflowStatus, error)
+		DeleteStatus(ctx context.Context, tx *gorm.DB, statusId int) error
+		CreateTransition(ctx context.Context, tx *gorm.DB, transition entity.WorkflowTransition) (entity.WorkflowTransition, error)
+		GetTransitionsByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.WorkflowTransition, error)
+		DeleteTransition(ctx context.Context, tx *gorm.DB, teamsID int, transitionId int) error
+	}
+
+	workflowRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewWorkflowRepository(db *gorm.DB) WorkflowRepository {
+	return &workflowRepository{
+		db: db,
+	}
+}
+
+func (r *workflowRepository) CreateStatus(ctx context.Context, tx *gorm.DB, status entity.WorkflowStatus) (entity.WorkflowStatus, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&status).Error; err != nil {
+		return entity.WorkflowStatus{}, err
+	}
+
+	return status, nil
+}
+
+func (r *workflowRepository) GetStatusesByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.WorkflowStatus, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var statuses []entity.WorkflowStatus
+	if err := tx.WithContext(ctx).Where("teams_id = ?", teamsID).Order("position ASC, id ASC").Find(&statuses).Error; err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+func (r *workflowRepository) GetStatusById(ctx context.Context, tx *gorm.DB, teamsID int, statusId int) (entity.WorkflowStatus, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var status entity.WorkflowStatus
+	if err := tx.WithContext(ctx).Where("id = ? AND teams_id = ?", statusId, teamsID).Take(&status).Error; err != nil {
+		return entity.WorkflowStatus{}, err
+	}
+
+	return status, nil
+}
+
+func (r *workflowRepository) UpdateStatus(ctx context.Context, tx *gorm.DB, status entity.WorkflowStatus) (entity.WorkflowStatus, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	// Select keeps a zero position from being skipped
+	if err := tx.WithContext(ctx).Model(&status).Select("name", "position", "category").Updates(&status).Error; err != nil {
+		return entity.WorkflowStatus{}, err
+	}
+
+	return status, nil
+}
+
+func (r *workflowRepository) DeleteStatus(ctx context.Context, tx *gorm.DB, statusId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Where("from_status_id = ? OR to_status_id = ?", statusId, statusId).Delete(&entity.WorkflowTransition{}).Error; err != nil {
+		return err
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.WorkflowStatus{}, "id = ?", statusId).Error
+}
+
+func (r *workflowRepository) CreateTransition(ctx context.Context, tx *gorm.DB, transition entity.WorkflowTransition) (entity.WorkflowTransition, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&transition).Error; err != nil {
+		return entity.WorkflowTransition{}, err
+	}
+
+	return transition, nil
+}
+
+func (r *workflowRepository) GetTransitionsByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.WorkflowTransition, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var transitions []entity.WorkflowTransition
+	if err := tx.WithContext(ctx).Where("teams_id = ?", teamsID).Find(&transitions).Error; err != nil {
+		return nil, err
+	}
+
+	return transitions, nil
+}
+
+func (r *workflowRepository) DeleteTransition(ctx context.Context, tx *gorm.DB, teamsID int, transitionId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Where("id = ? AND teams_id = ?", transitionId, teamsID).Delete(&entity.WorkflowTransition{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
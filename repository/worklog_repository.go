@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	WorklogRepository interface {
+		CreateWorklog(ctx context.Context, tx *gorm.DB, worklog entity.Worklog) (entity.Worklog, error)
+		GetWorklogById(ctx context.Context, tx *gorm.DB, taskId int, worklogId int) (entity.Worklog, error)
+		GetWorklogsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.Worklog, error)
+		GetRunningWorklog(ctx context.Context, tx *gorm.DB, userId uuid.UUID) (entity.Worklog, error)
+		LockTimers(ctx context.Context, tx *gorm.DB, userId uuid.UUID) error
+		StopWorklog(ctx context.Context, tx *gorm.DB, worklogId int, duration int) error
+		DeleteWorklog(ctx context.Context, tx *gorm.DB, worklogId int) error
+		SumByUser(ctx context.Context, tx *gorm.DB, filter dto.WorklogFilter) ([]dto.WorklogUserTotal, error)
+		SumByTask(ctx context.Context, tx *gorm.DB, filter dto.WorklogFilter) ([]dto.WorklogTaskTotal, error)
+	}
+
+	worklogRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewWorklogRepository(db *gorm.DB) WorklogRepository {
+	return &worklogRepository{
+		db: db,
+	}
+}
+
+func (r *worklogRepository) CreateWorklog(ctx context.Context, tx *gorm.DB, worklog entity.Worklog) (entity.Worklog, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Omit(clause.Associations).Create(&worklog).Error; err != nil {
+		return entity.Worklog{}, err
+	}
+
+	return worklog, nil
+}
+
+func (r *worklogRepository) GetWorklogById(ctx context.Context, tx *gorm.DB, taskId int, worklogId int) (entity.Worklog, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var worklog entity.Worklog
+	if err := tx.WithContext(ctx).
+		Preload("User").
+		Where("id = ? AND task_id = ?", worklogId, taskId).
+		Take(&worklog).Error; err != nil {
+		return entity.Worklog{}, err
+	}
+
+	return worklog, nil
+}
+
+func (r *worklogRepository) GetWorklogsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.Worklog, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var worklogs []entity.Worklog
+	if err := tx.WithContext(ctx).
+		Preload("User").
+		Where("task_id = ?", taskId).
+		Order("started_at DESC, id DESC").
+		Find(&worklogs).Error; err != nil {
+		return nil, err
+	}
+
+	return worklogs, nil
+}
+
+func (r *worklogRepository) GetRunningWorklog(ctx context.Context, tx *gorm.DB, userId uuid.UUID) (entity.Worklog, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var worklog entity.Worklog
+	if err := tx.WithContext(ctx).
+		Where("user_id = ? AND is_running = ?", userId, true).
+		Take(&worklog).Error; err != nil {
+		return entity.Worklog{}, err
+	}
+
+	return worklog, nil
+}
+
+// LockTimers locks the user's row until tx ends, so that a user's timers are
+// started one at a time and a check for a running one still holds when the
+// caller starts another. Locking the worklogs would not do: while none is
+// running there is no row to lock.
+func (r *worklogRepository) LockTimers(ctx context.Context, tx *gorm.DB, userId uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var user entity.User
+	return tx.WithContext(ctx).Select("id").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", userId).
+		Take(&user).Error
+}
+
+func (r *worklogRepository) StopWorklog(ctx context.Context, tx *gorm.DB, worklogId int, duration int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.Worklog{}).
+		Where("id = ? AND is_running = ?", worklogId, true).
+		Updates(map[string]interface{}{"duration": duration, "is_running": false}).Error
+}
+
+func (r *worklogRepository) DeleteWorklog(ctx context.Context, tx *gorm.DB, worklogId int) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Delete(&entity.Worklog{}, "id = ?", worklogId).Error
+}
+
+// SumByUser totals the finished worklogs matching filter per user, largest
+// first.
+func (r *worklogRepository) SumByUser(ctx context.Context, tx *gorm.DB, filter dto.WorklogFilter) ([]dto.WorklogUserTotal, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var totals []dto.WorklogUserTotal
+	if err := tx.WithContext(ctx).Model(&entity.Worklog{}).
+		Select("worklogs.user_id, users.name, SUM(worklogs.duration) AS duration").
+		Joins("JOIN users ON users.id = worklogs.user_id").
+		Scopes(filterWorklogs(filter)).
+		Group("worklogs.user_id, users.name").
+		Order("duration DESC").
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// SumByTask totals the finished worklogs matching filter per task, largest
+// first. Time logged on tasks that were deleted since is still counted.
+func (r *worklogRepository) SumByTask(ctx context.Context, tx *gorm.DB, filter dto.WorklogFilter) ([]dto.WorklogTaskTotal, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var totals []dto.WorklogTaskTotal
+	if err := tx.WithContext(ctx).Model(&entity.Worklog{}).
+		Select("worklogs.task_id, tasks.title, SUM(worklogs.duration) AS duration").
+		Joins("JOIN tasks ON tasks.id = worklogs.task_id").
+		Scopes(filterWorklogs(filter)).
+		Group("worklogs.task_id, tasks.title").
+		Order("duration DESC").
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// filterWorklogs narrows a summary to finished worklogs that started within
+// [From, To).
+func filterWorklogs(filter dto.WorklogFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("worklogs.is_running = ?", false)
+
+		if filter.TaskID != 0 {
+			db = db.Where("worklogs.task_id = ?", filter.TaskID)
+		}
+
+		if filter.TeamID != 0 {
+			db = db.Where("worklogs.task_id IN (SELECT id FROM tasks WHERE teams_id = ?)", filter.TeamID)
+		}
+
+		if filter.UserID != nil {
+			db = db.Where("worklogs.user_id = ?", filter.UserID)
+		}
+
+		if filter.From != nil {
+			db = db.Where("worklogs.started_at >= ?", filter.From)
+		}
+
+		if filter.To != nil {
+			db = db.Where("worklogs.started_at < ?", filter.To)
+		}
+
+		return db
+	}
+}
@@ -0,0 +1,10 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func JWKS(route *gin.Engine, jwksController controller.JWKSController) {
+	route.GET("/.well-known/jwks.json", jwksController.GetJWKS)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func Label(route *gin.Engine, labelController controller.LabelController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/teams/:teamId/labels")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_LABEL_READ), labelController.GetLabelsByTeamId)
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_LABEL_MANAGE), labelController.Create)
+		routes.PATCH("/:labelId", middleware.Authorize(authorizationService, constants.ACTION_LABEL_MANAGE), labelController.Update)
+		routes.DELETE("/:labelId", middleware.Authorize(authorizationService, constants.ACTION_LABEL_MANAGE), labelController.Delete)
+	}
+}
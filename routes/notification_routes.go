@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Notification routes only ever touch the caller's own notifications, so
+// they need authentication but no team permission.
+func Notification(route *gin.Engine, notificationController controller.NotificationController, jwtService service.JWTService) {
+	routes := route.Group("/api/notifications")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", notificationController.GetNotifications)
+		routes.GET("/unread_count", notificationController.CountUnread)
+		routes.PATCH("/read_all", notificationController.MarkAllAsRead)
+		routes.PATCH("/:notificationId/read", notificationController.MarkAsRead)
+		routes.GET("/preferences", notificationController.GetPreferences)
+		routes.PUT("/preferences", notificationController.UpdatePreferences)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func Sprint(route *gin.Engine, sprintController controller.SprintController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/teams/:teamId/sprints")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_SPRINT_READ), sprintController.GetSprintsByTeamId)
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_SPRINT_MANAGE), sprintController.Create)
+		routes.GET("/:sprintId", middleware.Authorize(authorizationService, constants.ACTION_SPRINT_READ), sprintController.GetSprint)
+		routes.PATCH("/:sprintId", middleware.Authorize(authorizationService, constants.ACTION_SPRINT_MANAGE), sprintController.Update)
+		routes.DELETE("/:sprintId", middleware.Authorize(authorizationService, constants.ACTION_SPRINT_MANAGE), sprintController.Delete)
+		routes.POST("/:sprintId/start", middleware.Authorize(authorizationService, constants.ACTION_SPRINT_MANAGE), sprintController.Start)
+		routes.POST("/:sprintId/close", middleware.Authorize(authorizationService, constants.ACTION_SPRINT_MANAGE), sprintController.Close)
+		routes.POST("/:sprintId/tasks", middleware.Authorize(authorizationService, constants.ACTION_SPRINT_PLAN), sprintController.AddTask)
+		routes.DELETE("/:sprintId/tasks/:taskId", middleware.Authorize(authorizationService, constants.ACTION_SPRINT_PLAN), sprintController.RemoveTask)
+	}
+}
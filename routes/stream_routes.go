@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func Stream(route *gin.Engine, streamController controller.StreamController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/teams/:teamId/stream")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_TASK_LIST_TEAM), streamController.StreamTeam)
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TaskAttachment(route *gin.Engine, taskAttachmentController controller.TaskAttachmentController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/tasks/:taskId/attachments")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_TASK_ATTACHMENT_READ), taskAttachmentController.GetAttachmentsByTaskId)
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_TASK_ATTACHMENT_WRITE), taskAttachmentController.Upload)
+		routes.GET("/:attachmentId/download", middleware.Authorize(authorizationService, constants.ACTION_TASK_ATTACHMENT_READ), taskAttachmentController.Download)
+		routes.DELETE("/:attachmentId", middleware.Authorize(authorizationService, constants.ACTION_TASK_ATTACHMENT_WRITE), taskAttachmentController.Delete)
+	}
+}
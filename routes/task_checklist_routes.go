@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TaskChecklist(route *gin.Engine, taskChecklistController controller.TaskChecklistController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/tasks/:taskId/checklist")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_TASK_CHECKLIST_READ), taskChecklistController.GetItemsByTaskId)
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_TASK_CHECKLIST_WRITE), taskChecklistController.Create)
+		routes.PUT("/order", middleware.Authorize(authorizationService, constants.ACTION_TASK_CHECKLIST_WRITE), taskChecklistController.Reorder)
+		routes.PATCH("/:itemId", middleware.Authorize(authorizationService, constants.ACTION_TASK_CHECKLIST_WRITE), taskChecklistController.Update)
+		routes.DELETE("/:itemId", middleware.Authorize(authorizationService, constants.ACTION_TASK_CHECKLIST_WRITE), taskChecklistController.Delete)
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TaskComment(route *gin.Engine, taskCommentController controller.TaskCommentController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/tasks/:taskId/comments")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_TASK_COMMENT_READ), taskCommentController.GetCommentsByTaskId)
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_TASK_COMMENT_WRITE), taskCommentController.Create)
+		routes.PATCH("/:commentId", middleware.Authorize(authorizationService, constants.ACTION_TASK_COMMENT_WRITE), taskCommentController.Update)
+		routes.DELETE("/:commentId", middleware.Authorize(authorizationService, constants.ACTION_TASK_COMMENT_WRITE), taskCommentController.Delete)
+	}
+}
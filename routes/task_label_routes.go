@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TaskLabel(route *gin.Engine, taskLabelController controller.TaskLabelController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/tasks/:taskId/labels")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_TASK_LABEL_READ), taskLabelController.GetTaskLabels)
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_TASK_LABEL_WRITE), taskLabelController.AddTaskLabel)
+		routes.DELETE("/:labelId", middleware.Authorize(authorizationService, constants.ACTION_TASK_LABEL_WRITE), taskLabelController.RemoveTaskLabel)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TaskLink(route *gin.Engine, taskLinkController controller.TaskLinkController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/tasks/:taskId/links")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_TASK_LINK_READ), taskLinkController.GetLinksByTaskId)
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_TASK_LINK_WRITE), taskLinkController.Create)
+		routes.DELETE("/:linkId", middleware.Authorize(authorizationService, constants.ACTION_TASK_LINK_WRITE), taskLinkController.Delete)
+	}
+}
@@ -1,23 +1,36 @@
 package routes
 
 import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
 	"github.com/gin-gonic/gin"
 )
 
-func Task(route *gin.Engine, taskController controller.TaskController) {
+func Task(route *gin.Engine, taskController controller.TaskController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
 	routes := route.Group("/api/tasks")
+	routes.Use(middleware.Authenticate(jwtService))
 	{
 		routes.POST("", taskController.Register)
-		routes.GET("", taskController.GetAllTask)
-		routes.GET("/:taskId", taskController.GetTaskById)
-		routes.PATCH("/:taskId", taskController.Update)
-		routes.DELETE("/:taskId", taskController.Delete)
-        routes.GET("/team/:teamId", taskController.GetTasksByTeamID)
-		routes.POST("/:taskId/assign", taskController.AssignUser)
-		routes.POST("/:taskId/remove", taskController.RemoveUser)
-		routes.GET("/:taskId/user", taskController.GetAssignedUser)
-		routes.GET("/assigned/:userId", taskController.GetTasksByUserID)
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_TASK_LIST), taskController.GetAllTask)
+		routes.GET("/overdue", middleware.Authorize(authorizationService, constants.ACTION_TASK_LIST), taskController.GetOverdueTasks)
+		routes.GET("/:taskId", middleware.Authorize(authorizationService, constants.ACTION_TASK_READ), taskController.GetTaskById)
+		routes.PATCH("/:taskId", middleware.Authorize(authorizationService, constants.ACTION_TASK_UPDATE), taskController.Update)
+		routes.DELETE("/:taskId", middleware.Authorize(authorizationService, constants.ACTION_TASK_DELETE), taskController.Delete)
+		routes.GET("/team/:teamId", middleware.Authorize(authorizationService, constants.ACTION_TASK_LIST_TEAM), taskController.GetTasksByTeamID)
+		routes.POST("/:taskId/assign", middleware.Authorize(authorizationService, constants.ACTION_TASK_ASSIGN), taskController.AssignUser)
+		routes.POST("/:taskId/remove", middleware.Authorize(authorizationService, constants.ACTION_TASK_ASSIGN), taskController.RemoveUser)
+		routes.GET("/:taskId/history", middleware.Authorize(authorizationService, constants.ACTION_TASK_HISTORY), taskController.GetTaskHistory)
+		routes.GET("/:taskId/subtasks", middleware.Authorize(authorizationService, constants.ACTION_TASK_READ), taskController.GetSubtasks)
+		routes.PUT("/:taskId/subtasks/order", middleware.Authorize(authorizationService, constants.ACTION_TASK_UPDATE), taskController.ReorderSubtasks)
+		routes.PUT("/:taskId/parent", middleware.Authorize(authorizationService, constants.ACTION_TASK_UPDATE), taskController.MoveTask)
+		routes.GET("/:taskId/assignees", middleware.Authorize(authorizationService, constants.ACTION_TASK_READ), taskController.GetAssignees)
+		routes.POST("/:taskId/assignees", middleware.Authorize(authorizationService, constants.ACTION_TASK_ASSIGN), taskController.AddAssignee)
+		routes.DELETE("/:taskId/assignees/:userId", middleware.Authorize(authorizationService, constants.ACTION_TASK_ASSIGN), taskController.RemoveAssignee)
+		routes.POST("/:taskId/watch", middleware.Authorize(authorizationService, constants.ACTION_TASK_READ), taskController.WatchTask)
+		routes.DELETE("/:taskId/watch", middleware.Authorize(authorizationService, constants.ACTION_TASK_READ), taskController.UnwatchTask)
+		routes.GET("/:taskId/user", middleware.Authorize(authorizationService, constants.ACTION_TASK_READ), taskController.GetAssignedUser)
+		routes.GET("/assigned/:userId", middleware.Authorize(authorizationService, constants.ACTION_TASK_LIST_USER), taskController.GetTasksByUserID)
 	}
 }
-
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TaskWorklog(route *gin.Engine, taskWorklogController controller.TaskWorklogController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/tasks/:taskId/worklogs")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_TASK_WORKLOG_READ), taskWorklogController.GetWorklogsByTaskId)
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_TASK_WORKLOG_WRITE), taskWorklogController.Create)
+		routes.GET("/summary", middleware.Authorize(authorizationService, constants.ACTION_TASK_WORKLOG_READ), taskWorklogController.GetSummary)
+		routes.POST("/start", middleware.Authorize(authorizationService, constants.ACTION_TASK_WORKLOG_WRITE), taskWorklogController.StartTimer)
+		routes.POST("/stop", middleware.Authorize(authorizationService, constants.ACTION_TASK_WORKLOG_WRITE), taskWorklogController.StopTimer)
+		routes.DELETE("/:worklogId", middleware.Authorize(authorizationService, constants.ACTION_TASK_WORKLOG_WRITE), taskWorklogController.Delete)
+	}
+}
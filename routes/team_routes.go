@@ -1,18 +1,22 @@
 package routes
 
 import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
 	"github.com/gin-gonic/gin"
 )
 
-func Team(route *gin.Engine, teamController controller.TeamController) {
+func Team(route *gin.Engine, teamController controller.TeamController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
 	routes := route.Group("/api/teams")
+	routes.Use(middleware.Authenticate(jwtService))
 	{
-		routes.POST("", teamController.Register)
-		routes.GET("", teamController.GetAllTeam)
-		routes.GET("/:teamId", teamController.GetTeamById)
-		routes.PATCH("/:teamId", teamController.Update)
-		routes.DELETE("/:teamId", teamController.Delete)
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_TEAM_CREATE), teamController.Register)
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_TEAM_READ), teamController.GetAllTeam)
+		routes.GET("/:teamId", middleware.Authorize(authorizationService, constants.ACTION_TEAM_READ), teamController.GetTeamById)
+		routes.PATCH("/:teamId", middleware.Authorize(authorizationService, constants.ACTION_TEAM_UPDATE), teamController.Update)
+		routes.DELETE("/:teamId", middleware.Authorize(authorizationService, constants.ACTION_TEAM_DELETE), teamController.Delete)
+		routes.GET("/:teamId/statistics", middleware.Authorize(authorizationService, constants.ACTION_TEAM_STATS), teamController.GetStatistics)
 	}
 }
-
@@ -1,23 +1,30 @@
 package routes
 
 import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/controller"
 	"github.com/Caknoooo/go-gin-clean-starter/middleware"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
 	"github.com/gin-gonic/gin"
 )
 
-func User(route *gin.Engine, userController controller.UserController, jwtService service.JWTService) {
+func User(route *gin.Engine, userController controller.UserController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
 	routes := route.Group("/api/user")
 	{
 		// User
 		routes.POST("", userController.Register)
-		routes.GET("", userController.GetAllUser)
+		routes.GET("", middleware.Authenticate(jwtService), middleware.Authorize(authorizationService, constants.ACTION_USER_LIST), userController.GetAllUser)
 		routes.POST("/login", userController.Login)
+		routes.POST("/refresh", userController.RefreshToken)
+		routes.POST("/logout", middleware.Authenticate(jwtService), userController.Logout)
+		routes.POST("/logout_all", middleware.Authenticate(jwtService), userController.LogoutAll)
 		routes.DELETE("", middleware.Authenticate(jwtService), userController.Delete)
 		routes.PATCH("", middleware.Authenticate(jwtService), userController.Update)
 		routes.GET("/me", middleware.Authenticate(jwtService), userController.Me)
 		routes.POST("/verify_email", userController.VerifyEmail)
 		routes.POST("/send_verification_email", userController.SendVerificationEmail)
+		routes.POST("/forgot_password", userController.ForgotPassword)
+		routes.POST("/reset_password", userController.ResetPassword)
+		routes.POST("/change_password", middleware.Authenticate(jwtService), userController.ChangePassword)
 	}
 }
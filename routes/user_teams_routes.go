@@ -1,15 +1,20 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
 )
 
-func UserTeams(route *gin.Engine, userTeamsController *controller.UserTeamsController) {
+func UserTeams(route *gin.Engine, userTeamsController *controller.UserTeamsController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
     routes := route.Group("/api/teams")
+    routes.Use(middleware.Authenticate(jwtService))
     {
-        routes.POST("/:teamId/users/:userId", userTeamsController.AssignUserToTeam)
-        routes.DELETE("/:teamId/users/:userId", userTeamsController.RemoveUserFromTeam)
-        routes.GET("/:teamId/users", userTeamsController.GetUsersByTeamId)
+        routes.POST("/:teamId/users/:userId", middleware.Authorize(authorizationService, constants.ACTION_TEAM_MEMBER_ADD), userTeamsController.AssignUserToTeam)
+        routes.DELETE("/:teamId/users/:userId", middleware.Authorize(authorizationService, constants.ACTION_TEAM_MEMBER_REMOVE), userTeamsController.RemoveUserFromTeam)
+        routes.PATCH("/:teamId/users/:userId", middleware.Authorize(authorizationService, constants.ACTION_TEAM_MEMBER_ROLE), userTeamsController.ChangeRole)
+        routes.GET("/:teamId/users", middleware.Authorize(authorizationService, constants.ACTION_TEAM_MEMBER_READ), userTeamsController.GetUsersByTeamId)
     }
-}
+}
\ No newline at end of file
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func Webhook(route *gin.Engine, webhookController controller.WebhookController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/teams/:teamId/webhooks")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.POST("", middleware.Authorize(authorizationService, constants.ACTION_WEBHOOK_MANAGE), webhookController.CreateWebhook)
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_WEBHOOK_MANAGE), webhookController.GetWebhooks)
+		routes.GET("/:webhookId", middleware.Authorize(authorizationService, constants.ACTION_WEBHOOK_MANAGE), webhookController.GetWebhookById)
+		routes.PATCH("/:webhookId", middleware.Authorize(authorizationService, constants.ACTION_WEBHOOK_MANAGE), webhookController.UpdateWebhook)
+		routes.DELETE("/:webhookId", middleware.Authorize(authorizationService, constants.ACTION_WEBHOOK_MANAGE), webhookController.DeleteWebhook)
+		routes.GET("/:webhookId/deliveries", middleware.Authorize(authorizationService, constants.ACTION_WEBHOOK_MANAGE), webhookController.GetDeliveries)
+		routes.POST("/:webhookId/deliveries/:deliveryId/redeliver", middleware.Authorize(authorizationService, constants.ACTION_WEBHOOK_MANAGE), webhookController.Redeliver)
+	}
+}
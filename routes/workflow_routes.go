@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+func Workflow(route *gin.Engine, workflowController controller.WorkflowController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	routes := route.Group("/api/teams/:teamId/workflow")
+	routes.Use(middleware.Authenticate(jwtService))
+	{
+		routes.GET("", middleware.Authorize(authorizationService, constants.ACTION_WORKFLOW_READ), workflowController.GetWorkflow)
+		routes.POST("/statuses", middleware.Authorize(authorizationService, constants.ACTION_WORKFLOW_MANAGE), workflowController.CreateStatus)
+		routes.PATCH("/statuses/:statusId", middleware.Authorize(authorizationService, constants.ACTION_WORKFLOW_MANAGE), workflowController.UpdateStatus)
+		routes.DELETE("/statuses/:statusId", middleware.Authorize(authorizationService, constants.ACTION_WORKFLOW_MANAGE), workflowController.DeleteStatus)
+		routes.POST("/transitions", middleware.Authorize(authorizationService, constants.ACTION_WORKFLOW_MANAGE), workflowController.CreateTransition)
+		routes.DELETE("/transitions/:transitionId", middleware.Authorize(authorizationService, constants.ACTION_WORKFLOW_MANAGE), workflowController.DeleteTransition)
+	}
+}
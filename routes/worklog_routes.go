@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Worklog registers the worklog reports. The caller's own report needs no
+// team permission since it only covers time they logged themselves.
+func Worklog(route *gin.Engine, worklogController controller.WorklogController, jwtService service.JWTService, authorizationService service.AuthorizationService) {
+	own := route.Group("/api/worklogs")
+	own.Use(middleware.Authenticate(jwtService))
+	{
+		own.GET("/summary", worklogController.GetMySummary)
+	}
+
+	team := route.Group("/api/teams/:teamId/worklogs")
+	team.Use(middleware.Authenticate(jwtService))
+	{
+		team.GET("/summary", middleware.Authorize(authorizationService, constants.ACTION_WORKLOG_REPORT), worklogController.GetTeamSummary)
+	}
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	AuthorizationService interface {
+		Authorize(ctx context.Context, req dto.AuthorizeRequest) error
+		AuthorizeTeam(ctx context.Context, userId string, teamId int, action string) error
+		GetTeamRole(ctx context.Context, userId string, teamId int) (string, error)
+	}
+
+	authorizationService struct {
+		userRepo      repository.UserRepository
+		userTeamsRepo repository.UserTeamsRepository
+		taskRepo      repository.TaskRepository
+	}
+
+	// policy lists who may perform an action. A request is allowed when the
+	// caller's global role is in GlobalRoles, or when the action is scoped to
+	// a team and the caller's membership role in that team is in TeamRoles.
+	// Admins are always allowed.
+	policy struct {
+		GlobalRoles []string
+		TeamRoles   []string
+	}
+)
+
+var (
+	teamReaders = []string{
+		constants.ENUM_TEAM_ROLE_OWNER,
+		constants.ENUM_TEAM_ROLE_MAINTAINER,
+		constants.ENUM_TEAM_ROLE_MEMBER,
+		constants.ENUM_TEAM_ROLE_VIEWER,
+	}
+	teamWriters = []string{
+		constants.ENUM_TEAM_ROLE_OWNER,
+		constants.ENUM_TEAM_ROLE_MAINTAINER,
+		constants.ENUM_TEAM_ROLE_MEMBER,
+	}
+	teamMaintainers = []string{constants.ENUM_TEAM_ROLE_OWNER, constants.ENUM_TEAM_ROLE_MAINTAINER}
+	ownerOnly       = []string{constants.ENUM_TEAM_ROLE_OWNER}
+
+	policies = map[string]policy{
+		// No role is listed, so only admins may list every user.
+		constants.ACTION_USER_LIST: {},
+
+		constants.ACTION_TEAM_CREATE: {GlobalRoles: []string{constants.ENUM_ROLE_USER}},
+		constants.ACTION_TEAM_READ:   {GlobalRoles: []string{constants.ENUM_ROLE_USER}},
+		constants.ACTION_TEAM_UPDATE: {TeamRoles: teamMaintainers},
+		constants.ACTION_TEAM_DELETE: {TeamRoles: ownerOnly},
+		constants.ACTION_TEAM_STATS:  {TeamRoles: teamReaders},
+
+		constants.ACTION_TEAM_MEMBER_READ:   {TeamRoles: teamReaders},
+		constants.ACTION_TEAM_MEMBER_ADD:    {TeamRoles: teamMaintainers},
+		constants.ACTION_TEAM_MEMBER_REMOVE: {TeamRoles: teamMaintainers},
+		constants.ACTION_TEAM_MEMBER_ROLE:   {TeamRoles: ownerOnly},
+
+		constants.ACTION_WORKFLOW_READ:   {TeamRoles: teamReaders},
+		constants.ACTION_WORKFLOW_MANAGE: {TeamRoles: teamMaintainers},
+
+		constants.ACTION_LABEL_READ:   {TeamRoles: teamReaders},
+		constants.ACTION_LABEL_MANAGE: {TeamRoles: teamMaintainers},
+
+		constants.ACTION_SPRINT_READ:   {TeamRoles: teamReaders},
+		constants.ACTION_SPRINT_PLAN:   {TeamRoles: teamWriters},
+		constants.ACTION_SPRINT_MANAGE: {TeamRoles: teamMaintainers},
+
+		constants.ACTION_WORKLOG_REPORT: {TeamRoles: teamReaders},
+
+		constants.ACTION_WEBHOOK_MANAGE: {TeamRoles: teamMaintainers},
+
+		// The body carries the team of a new task, so TaskService.Register
+		// checks ACTION_TASK_CREATE itself. The list actions are open to every
+		// user because TaskService narrows the results to the caller's teams,
+		// or to the caller's own tasks.
+		constants.ACTION_TASK_CREATE:    {TeamRoles: teamWriters},
+		constants.ACTION_TASK_LIST:      {GlobalRoles: []string{constants.ENUM_ROLE_USER}},
+		constants.ACTION_TASK_LIST_USER: {GlobalRoles: []string{constants.ENUM_ROLE_USER}},
+		constants.ACTION_TASK_LIST_TEAM: {TeamRoles: teamReaders},
+		constants.ACTION_TASK_READ:      {TeamRoles: teamReaders},
+		constants.ACTION_TASK_HISTORY:   {TeamRoles: teamReaders},
+		constants.ACTION_TASK_UPDATE:    {TeamRoles: teamWriters},
+		constants.ACTION_TASK_DELETE:    {TeamRoles: teamMaintainers},
+		constants.ACTION_TASK_ASSIGN:    {TeamRoles: teamMaintainers},
+		constants.ACTION_TASK_REASSIGN:  {TeamRoles: teamMaintainers},
+
+		constants.ACTION_TASK_COMMENT_READ:  {TeamRoles: teamReaders},
+		constants.ACTION_TASK_COMMENT_WRITE: {TeamRoles: teamWriters},
+
+		constants.ACTION_TASK_ATTACHMENT_READ:   {TeamRoles: teamReaders},
+		constants.ACTION_TASK_ATTACHMENT_WRITE:  {TeamRoles: teamWriters},
+		constants.ACTION_TASK_ATTACHMENT_MANAGE: {TeamRoles: teamMaintainers},
+
+		constants.ACTION_TASK_CHECKLIST_READ:  {TeamRoles: teamReaders},
+		constants.ACTION_TASK_CHECKLIST_WRITE: {TeamRoles: teamWriters},
+
+		constants.ACTION_TASK_LINK_READ:  {TeamRoles: teamReaders},
+		constants.ACTION_TASK_LINK_WRITE: {TeamRoles: teamWriters},
+
+		constants.ACTION_TASK_LABEL_READ:  {TeamRoles: teamReaders},
+		constants.ACTION_TASK_LABEL_WRITE: {TeamRoles: teamWriters},
+
+		constants.ACTION_TASK_WORKLOG_READ:   {TeamRoles: teamReaders},
+		constants.ACTION_TASK_WORKLOG_WRITE:  {TeamRoles: teamWriters},
+		constants.ACTION_TASK_WORKLOG_MANAGE: {TeamRoles: teamMaintainers},
+	}
+)
+
+func NewAuthorizationService(userRepo repository.UserRepository, userTeamsRepo repository.UserTeamsRepository, taskRepo repository.TaskRepository) AuthorizationService {
+	return &authorizationService{
+		userRepo:      userRepo,
+		userTeamsRepo: userTeamsRepo,
+		taskRepo:      taskRepo,
+	}
+}
+
+func (s *authorizationService) Authorize(ctx context.Context, req dto.AuthorizeRequest) error {
+	p, ok := policies[req.Action]
+	if !ok {
+		return dto.ErrUnknownAction
+	}
+
+	if req.Role == constants.ENUM_ROLE_ADMIN || contains(p.GlobalRoles, req.Role) {
+		return nil
+	}
+
+	if len(p.TeamRoles) == 0 {
+		return dto.ErrPermissionDenied
+	}
+
+	teamId, err := s.resolveTeamId(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	teamRole, err := s.GetTeamRole(ctx, req.UserID, teamId)
+	if err != nil {
+		return err
+	}
+
+	if !contains(p.TeamRoles, teamRole) {
+		return dto.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// AuthorizeTeam is used by services that only know the acting user's id, so
+// the global role is looked up rather than read from the token.
+func (s *authorizationService) AuthorizeTeam(ctx context.Context, userId string, teamId int, action string) error {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.ErrPermissionDenied
+	}
+
+	return s.Authorize(ctx, dto.AuthorizeRequest{
+		UserID: userId,
+		Role:   user.Role,
+		Action: action,
+		TeamID: strconv.Itoa(teamId),
+	})
+}
+
+func (s *authorizationService) GetTeamRole(ctx context.Context, userId string, teamId int) (string, error) {
+	userUuid, err := uuid.Parse(userId)
+	if err != nil {
+		return "", dto.ErrPermissionDenied
+	}
+
+	membership, err := s.userTeamsRepo.GetMembership(ctx, nil, userUuid, uint(teamId))
+	if err != nil {
+		return "", dto.ErrNotTeamMember
+	}
+
+	return membership.Role, nil
+}
+
+func (s *authorizationService) resolveTeamId(ctx context.Context, req dto.AuthorizeRequest) (int, error) {
+	if req.TeamID != "" {
+		teamId, err := strconv.Atoi(req.TeamID)
+		if err != nil {
+			return 0, dto.ErrTeamNotFound
+		}
+		return teamId, nil
+	}
+
+	if req.TaskID != "" {
+		task, err := s.taskRepo.GetTaskById(ctx, nil, req.TaskID)
+		if err != nil {
+			return 0, dto.ErrTaskNotFound
+		}
+		return task.TeamsID, nil
+	}
+
+	return 0, dto.ErrPermissionDenied
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+)
+
+type (
+	// EmailService queues outgoing mail in the email outbox and delivers it
+	// in the background, so a slow or unavailable SMTP server never fails
+	// the request that produced the email.
+	EmailService interface {
+		Enqueue(ctx context.Context, toEmail string, subject string, body string) error
+		ProcessOutbox(ctx context.Context) (int, error)
+		StartWorker(ctx context.Context)
+	}
+
+	emailService struct {
+		outboxRepo repository.EmailOutboxRepository
+		mailer     utils.Mailer
+		wake       chan struct{}
+	}
+)
+
+const (
+	EMAIL_BATCH_SIZE    = 20
+	EMAIL_MAX_ATTEMPTS  = 8
+	EMAIL_RETRY_BASE    = 30 * time.Second
+	EMAIL_RETRY_MAX     = time.Hour
+	EMAIL_CLAIM_LEASE   = 2 * time.Minute
+	EMAIL_POLL_INTERVAL = 5 * time.Second
+)
+
+func NewEmailService(outboxRepo repository.EmailOutboxRepository, mailer utils.Mailer) EmailService {
+	return &emailService{
+		outboxRepo: outboxRepo,
+		mailer:     mailer,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+func (s *emailService) Enqueue(ctx context.Context, toEmail string, subject string, body string) error {
+	_, err := s.outboxRepo.Enqueue(ctx, nil, entity.EmailOutbox{
+		ToEmail:       toEmail,
+		Subject:       subject,
+		Body:          body,
+		Status:        constants.ENUM_EMAIL_STATUS_PENDING,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Nudge the worker so fresh mail does not wait for the next poll.
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// ProcessOutbox makes one delivery attempt for a batch of due emails and
+// returns how many it claimed. A failed attempt is retried with exponential
+// backoff until EMAIL_MAX_ATTEMPTS, after which the email is marked failed.
+func (s *emailService) ProcessOutbox(ctx context.Context) (int, error) {
+	emails, err := s.outboxRepo.ClaimDue(ctx, nil, time.Now(), EMAIL_CLAIM_LEASE, EMAIL_BATCH_SIZE)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, email := range emails {
+		email.Attempts++
+
+		if err := s.mailer.Send(email.ToEmail, email.Subject, email.Body); err != nil {
+			email.LastError = truncate(err.Error(), 1000)
+			if email.Attempts >= EMAIL_MAX_ATTEMPTS {
+				email.Status = constants.ENUM_EMAIL_STATUS_FAILED
+				log.Printf("Giving up on email %d to %s after %d attempts: %v", email.ID, email.ToEmail, email.Attempts, err)
+			} else {
+				email.NextAttemptAt = time.Now().Add(EmailRetryDelay(email.Attempts))
+			}
+		} else {
+			sentAt := time.Now()
+			email.Status = constants.ENUM_EMAIL_STATUS_SENT
+			email.SentAt = &sentAt
+			email.LastError = ""
+		}
+
+		if err := s.outboxRepo.UpdateDelivery(ctx, nil, email); err != nil {
+			return len(emails), err
+		}
+	}
+
+	return len(emails), nil
+}
+
+// StartWorker drains the outbox until ctx is cancelled, polling every
+// EMAIL_POLL_INTERVAL and immediately after each Enqueue.
+func (s *emailService) StartWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(EMAIL_POLL_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+
+			for {
+				claimed, err := s.ProcessOutbox(ctx)
+				if err != nil {
+					log.Printf("Failed to process email outbox: %v", err)
+					break
+				}
+				if claimed < EMAIL_BATCH_SIZE {
+					break
+				}
+			}
+		}
+	}()
+}
+
+// EmailRetryDelay is the wait before the next attempt after the given number
+// of failed attempts: EMAIL_RETRY_BASE doubled per failure, capped at
+// EMAIL_RETRY_MAX.
+func EmailRetryDelay(attempts int) time.Duration {
+	return retryDelay(attempts, EMAIL_RETRY_BASE, EMAIL_RETRY_MAX)
+}
+
+func retryDelay(attempts int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -1,74 +1,117 @@
 package service
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"os"
 	"time"
 
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// ACCESS_TOKEN_TTL is kept short because access tokens are bearer
+// credentials; clients renew them through the refresh token.
+const ACCESS_TOKEN_TTL = 15 * time.Minute
+
 type JWTService interface {
-	GenerateToken(userId string, role string) string
+	GenerateToken(userId string, role string, sessionId string) (string, error)
 	ValidateToken(token string) (*jwt.Token, error)
+	ParseToken(ctx context.Context, token string) (dto.TokenClaims, error)
+	ValidateSession(ctx context.Context, token string) (string, error)
 	GetUserIDByToken(token string) (string, error)
+	GetRoleByToken(token string) (string, error)
 }
 
 type jwtCustomClaim struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID    string `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID string `json:"session_id"`
 	jwt.RegisteredClaims
 }
 
 type jwtService struct {
-	secretKey string
-	issuer    string
+	keyService  SigningKeyService
+	issuer      string
+	sessionRepo repository.SessionRepository
 }
 
-func NewJWTService() JWTService {
+func NewJWTService(keyService SigningKeyService, issuer string, sessionRepo repository.SessionRepository) JWTService {
 	return &jwtService{
-		secretKey: getSecretKey(),
-		issuer:    "Template",
-	}
-}
-
-func getSecretKey() string {
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		secretKey = "Template"
+		keyService:  keyService,
+		issuer:      issuer,
+		sessionRepo: sessionRepo,
 	}
-	return secretKey
 }
 
-func (j *jwtService) GenerateToken(userId string, role string) string {
+func (j *jwtService) GenerateToken(userId string, role string, sessionId string) (string, error) {
 	claims := jwtCustomClaim{
 		userId,
 		role,
+		sessionId,
 		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 120)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ACCESS_TOKEN_TTL)),
 			Issuer:    j.issuer,
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tx, err := token.SignedString([]byte(j.secretKey))
+	return j.keyService.Sign(claims)
+}
+
+// ValidateToken also rejects tokens issued by anyone else, so another service
+// sharing the signing key cannot mint tokens this one accepts.
+func (j *jwtService) ValidateToken(token string) (*jwt.Token, error) {
+	t_Token, err := jwt.Parse(token, j.keyService.Keyfunc, jwt.WithValidMethods(j.keyService.ValidMethods()))
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return tx
+
+	claims, ok := t_Token.Claims.(jwt.MapClaims)
+	if !ok || !claims.VerifyIssuer(j.issuer, true) {
+		return nil, dto.ErrTokenInvalid
+	}
+
+	return t_Token, nil
 }
 
-func (j *jwtService) parseToken(t_ *jwt.Token) (any, error) {
-	if _, ok := t_.Method.(*jwt.SigningMethodHMAC); !ok {
-		return nil, fmt.Errorf("unexpected signing method %v", t_.Header["alg"])
+// ParseToken verifies token, checks that the session it was issued for is
+// still active and returns its claims. Tokens without a session are rejected.
+func (j *jwtService) ParseToken(ctx context.Context, token string) (dto.TokenClaims, error) {
+	var claims jwtCustomClaim
+	if _, err := jwt.ParseWithClaims(token, &claims, j.keyService.Keyfunc, jwt.WithValidMethods(j.keyService.ValidMethods())); err != nil {
+		return dto.TokenClaims{}, dto.ErrTokenInvalid
+	}
+	if !claims.VerifyIssuer(j.issuer, true) || claims.SessionID == "" {
+		return dto.TokenClaims{}, dto.ErrTokenInvalid
+	}
+
+	session, err := j.sessionRepo.GetSessionById(ctx, nil, claims.SessionID)
+	if err != nil || !session.IsActive(time.Now()) {
+		return dto.TokenClaims{}, dto.ErrSessionRevoked
+	}
+
+	result := dto.TokenClaims{
+		UserID:    claims.UserID,
+		Role:      claims.Role,
+		SessionID: claims.SessionID,
 	}
-	return []byte(j.secretKey), nil
+	if claims.ExpiresAt != nil {
+		result.ExpiresAt = claims.ExpiresAt.Time
+	}
+
+	return result, nil
 }
 
-func (j *jwtService) ValidateToken(token string) (*jwt.Token, error) {
-	return jwt.Parse(token, j.parseToken)
+// ValidateSession checks that the session the token was issued for is still
+// active and returns its ID.
+func (j *jwtService) ValidateSession(ctx context.Context, token string) (string, error) {
+	claims, err := j.ParseToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.SessionID, nil
 }
 
 func (j *jwtService) GetUserIDByToken(token string) (string, error) {
@@ -81,3 +124,14 @@ func (j *jwtService) GetUserIDByToken(token string) (string, error) {
 	id := fmt.Sprintf("%v", claims["user_id"])
 	return id, nil
 }
+
+func (j *jwtService) GetRoleByToken(token string) (string, error) {
+	t_Token, err := j.ValidateToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	claims := t_Token.Claims.(jwt.MapClaims)
+	role := fmt.Sprintf("%v", claims["role"])
+	return role, nil
+}
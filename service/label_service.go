@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+)
+
+type (
+	LabelService interface {
+		Create(ctx context.Context, teamsID int, req dto.LabelCreateRequest) (dto.LabelResponse, error)
+		GetLabelsByTeamId(ctx context.Context, teamsID int) ([]dto.LabelResponse, error)
+		Update(ctx context.Context, teamsID int, labelId int, req dto.LabelUpdateRequest) (dto.LabelResponse, error)
+		Delete(ctx context.Context, teamsID int, labelId int) error
+		GetTaskLabels(ctx context.Context, taskId string) ([]dto.LabelResponse, error)
+		AddTaskLabel(ctx context.Context, taskId string, req dto.TaskLabelRequest) ([]dto.LabelResponse, error)
+		RemoveTaskLabel(ctx context.Context, taskId string, labelId int) error
+	}
+
+	labelService struct {
+		labelRepo repository.LabelRepository
+		taskRepo  repository.TaskRepository
+	}
+)
+
+func NewLabelService(labelRepo repository.LabelRepository, taskRepo repository.TaskRepository) LabelService {
+	return &labelService{
+		labelRepo: labelRepo,
+		taskRepo:  taskRepo,
+	}
+}
+
+// Create adds a label to the team's catalog. Names are unique within a
+// team, ignoring case.
+func (s *labelService) Create(ctx context.Context, teamsID int, req dto.LabelCreateRequest) (dto.LabelResponse, error) {
+	labels, err := s.labelRepo.GetLabelsByTeamId(ctx, nil, teamsID)
+	if err != nil {
+		return dto.LabelResponse{}, dto.ErrCreateLabel
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if _, ok := findLabel(labels, name); ok {
+		return dto.LabelResponse{}, dto.ErrLabelExists
+	}
+
+	label, err := s.labelRepo.CreateLabel(ctx, nil, entity.Label{
+		TeamsID: teamsID,
+		Name:    name,
+		Color:   strings.ToLower(req.Color),
+	})
+	if err != nil {
+		return dto.LabelResponse{}, dto.ErrCreateLabel
+	}
+
+	return toLabelResponse(label), nil
+}
+
+func (s *labelService) GetLabelsByTeamId(ctx context.Context, teamsID int) ([]dto.LabelResponse, error) {
+	labels, err := s.labelRepo.GetLabelsByTeamId(ctx, nil, teamsID)
+	if err != nil {
+		return nil, dto.ErrGetAllLabel
+	}
+
+	responses := []dto.LabelResponse{}
+	for _, label := range labels {
+		responses = append(responses, toLabelResponse(label))
+	}
+
+	return responses, nil
+}
+
+func (s *labelService) Update(ctx context.Context, teamsID int, labelId int, req dto.LabelUpdateRequest) (dto.LabelResponse, error) {
+	label, err := s.labelRepo.GetLabelById(ctx, nil, teamsID, labelId)
+	if err != nil {
+		return dto.LabelResponse{}, dto.ErrLabelNotFound
+	}
+
+	labels, err := s.labelRepo.GetLabelsByTeamId(ctx, nil, teamsID)
+	if err != nil {
+		return dto.LabelResponse{}, dto.ErrUpdateLabel
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if existing, ok := findLabel(labels, name); ok && existing.ID != label.ID {
+		return dto.LabelResponse{}, dto.ErrLabelExists
+	}
+
+	label.Name = name
+	label.Color = strings.ToLower(req.Color)
+	label, err = s.labelRepo.UpdateLabel(ctx, nil, label)
+	if err != nil {
+		return dto.LabelResponse{}, dto.ErrUpdateLabel
+	}
+
+	return toLabelResponse(label), nil
+}
+
+// Delete removes the label from the catalog and from the tasks carrying it.
+func (s *labelService) Delete(ctx context.Context, teamsID int, labelId int) error {
+	label, err := s.labelRepo.GetLabelById(ctx, nil, teamsID, labelId)
+	if err != nil {
+		return dto.ErrLabelNotFound
+	}
+
+	if err := s.labelRepo.DeleteLabel(ctx, nil, label.ID); err != nil {
+		return dto.ErrDeleteLabel
+	}
+
+	return nil
+}
+
+func (s *labelService) GetTaskLabels(ctx context.Context, taskId string) ([]dto.LabelResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	labels, err := taskLabelsOf(ctx, s.labelRepo, []entity.Task{task})
+	if err != nil {
+		return nil, dto.ErrGetAllTaskLabel
+	}
+
+	responses := labels[task.ID]
+	if responses == nil {
+		responses = []dto.LabelResponse{}
+	}
+
+	return responses, nil
+}
+
+// AddTaskLabel puts a label from the task's team catalog on the task.
+func (s *labelService) AddTaskLabel(ctx context.Context, taskId string, req dto.TaskLabelRequest) ([]dto.LabelResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	label, err := s.labelRepo.GetLabelById(ctx, nil, task.TeamsID, req.LabelID)
+	if err != nil {
+		return nil, dto.ErrLabelNotFound
+	}
+
+	if err := s.labelRepo.AddTaskLabel(ctx, nil, task.ID, label.ID); err != nil {
+		return nil, dto.ErrAddTaskLabel
+	}
+
+	return s.GetTaskLabels(ctx, taskId)
+}
+
+func (s *labelService) RemoveTaskLabel(ctx context.Context, taskId string, labelId int) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	labels, err := taskLabelsOf(ctx, s.labelRepo, []entity.Task{task})
+	if err != nil {
+		return dto.ErrRemoveTaskLabel
+	}
+
+	found := false
+	for _, label := range labels[task.ID] {
+		found = found || label.ID == labelId
+	}
+	if !found {
+		return dto.ErrTaskLabelNotFound
+	}
+
+	if err := s.labelRepo.RemoveTaskLabel(ctx, nil, task.ID, labelId); err != nil {
+		return dto.ErrRemoveTaskLabel
+	}
+
+	return nil
+}
+
+// taskLabelsOf returns the labels on each of tasks in the order they were
+// added.
+func taskLabelsOf(ctx context.Context, labelRepo repository.LabelRepository, tasks []entity.Task) (map[int][]dto.LabelResponse, error) {
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+
+	taskLabels, err := labelRepo.GetTaskLabels(ctx, nil, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[int][]dto.LabelResponse{}
+	for _, taskLabel := range taskLabels {
+		labels[taskLabel.TaskID] = append(labels[taskLabel.TaskID], toLabelResponse(taskLabel.Label))
+	}
+
+	return labels, nil
+}
+
+func findLabel(labels []entity.Label, name string) (entity.Label, bool) {
+	for _, label := range labels {
+		if strings.EqualFold(label.Name, name) {
+			return label, true
+		}
+	}
+	return entity.Label{}, false
+}
+
+func toLabelResponse(label entity.Label) dto.LabelResponse {
+	return dto.LabelResponse{
+		ID:        label.ID,
+		TeamsID:   label.TeamsID,
+		Name:      label.Name,
+		Color:     label.Color,
+		CreatedAt: label.CreatedAt,
+		UpdatedAt: label.UpdatedAt,
+	}
+}
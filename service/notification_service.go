@@ -0,0 +1,434 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/google/uuid"
+)
+
+type (
+	// NotificationService tells users about changes to tasks they are
+	// involved in, in-app and by email, according to their preferences.
+	// The Notify methods run after the change has been saved, so they log
+	// failures instead of returning them.
+	NotificationService interface {
+		NotifyTaskEvents(ctx context.Context, task entity.Task, events []entity.TaskEvent)
+		NotifyComment(ctx context.Context, task entity.Task, comment entity.TaskComment, mentioned []uuid.UUID)
+		NotifyDueSoon(ctx context.Context, task entity.Task, userId uuid.UUID)
+		NotifyOverdue(ctx context.Context, task entity.Task, userId uuid.UUID)
+		GetNotifications(ctx context.Context, userId string, req dto.NotificationListRequest) (dto.NotificationPaginationResponse, error)
+		CountUnread(ctx context.Context, userId string) (dto.NotificationUnreadResponse, error)
+		MarkAsRead(ctx context.Context, userId string, notificationId int) error
+		MarkAllAsRead(ctx context.Context, userId string) (dto.NotificationReadAllResponse, error)
+		GetPreferences(ctx context.Context, userId string) ([]dto.NotificationPreferenceResponse, error)
+		UpdatePreferences(ctx context.Context, userId string, req dto.NotificationPreferenceUpdateRequest) ([]dto.NotificationPreferenceResponse, error)
+	}
+
+	notificationService struct {
+		notificationRepo repository.NotificationRepository
+		userRepo         repository.UserRepository
+		taskAssigneeRepo repository.TaskAssigneeRepository
+		emailService     EmailService
+	}
+
+	// notification is one message to one recipient before it is rendered.
+	notification struct {
+		kind      string
+		recipient uuid.UUID
+		actor     *uuid.UUID
+		task      entity.Task
+		oldStatus string
+		newStatus string
+		comment   string
+		mentioned bool
+	}
+)
+
+// NotificationTypes lists every notification type in the order preferences
+// are reported.
+var NotificationTypes = []string{
+	constants.ENUM_NOTIFICATION_TASK_ASSIGNED,
+	constants.ENUM_NOTIFICATION_TASK_UNASSIGNED,
+	constants.ENUM_NOTIFICATION_TASK_STATUS_CHANGED,
+	constants.ENUM_NOTIFICATION_TASK_COMMENT,
+	constants.ENUM_NOTIFICATION_TASK_DUE_SOON,
+	constants.ENUM_NOTIFICATION_TASK_OVERDUE,
+}
+
+// defaultEmailNotifications are the types that reach the inbox unless the
+// user opts out; everything else is in-app only until they opt in.
+var defaultEmailNotifications = map[string]bool{
+	constants.ENUM_NOTIFICATION_TASK_ASSIGNED: true,
+	constants.ENUM_NOTIFICATION_TASK_DUE_SOON: true,
+	constants.ENUM_NOTIFICATION_TASK_OVERDUE:  true,
+}
+
+const (
+	TASK_ROUTE = "tasks"
+
+	NOTIFICATION_COMMENT_PREVIEW = 500
+)
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository, taskAssigneeRepo repository.TaskAssigneeRepository, emailService EmailService) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		taskAssigneeRepo: taskAssigneeRepo,
+		emailService:     emailService,
+	}
+}
+
+// NotifyTaskEvents turns the history entries of a task change into
+// notifications. task is the task as it is after the change.
+func (s *notificationService) NotifyTaskEvents(ctx context.Context, task entity.Task, events []entity.TaskEvent) {
+	for _, event := range events {
+		switch {
+		case event.Type == constants.ENUM_TASK_EVENT_ASSIGNED || event.Type == constants.ENUM_TASK_EVENT_UNASSIGNED:
+			if to, err := uuid.Parse(event.NewValue); err == nil {
+				s.send(ctx, notification{kind: constants.ENUM_NOTIFICATION_TASK_ASSIGNED, recipient: to, actor: event.UserID, task: task})
+			}
+			if from, err := uuid.Parse(event.OldValue); err == nil {
+				s.send(ctx, notification{kind: constants.ENUM_NOTIFICATION_TASK_UNASSIGNED, recipient: from, actor: event.UserID, task: task})
+			}
+		case event.Type == constants.ENUM_TASK_EVENT_UPDATED && event.Field == "status":
+			for _, userId := range s.participants(ctx, task) {
+				s.send(ctx, notification{
+					kind:      constants.ENUM_NOTIFICATION_TASK_STATUS_CHANGED,
+					recipient: userId,
+					actor:     event.UserID,
+					task:      task,
+					oldStatus: event.OldValue,
+					newStatus: event.NewValue,
+				})
+			}
+		}
+	}
+}
+
+// NotifyComment tells everyone on the task and everyone mentioned about a
+// new comment. Someone who is both is told once, as a mention.
+func (s *notificationService) NotifyComment(ctx context.Context, task entity.Task, comment entity.TaskComment, mentioned []uuid.UUID) {
+	seen := map[uuid.UUID]bool{}
+	for _, userId := range mentioned {
+		if seen[userId] {
+			continue
+		}
+		seen[userId] = true
+		s.send(ctx, notification{kind: constants.ENUM_NOTIFICATION_TASK_COMMENT, recipient: userId, actor: &comment.UserID, task: task, comment: comment.Body, mentioned: true})
+	}
+
+	for _, userId := range s.participants(ctx, task) {
+		if !seen[userId] {
+			s.send(ctx, notification{kind: constants.ENUM_NOTIFICATION_TASK_COMMENT, recipient: userId, actor: &comment.UserID, task: task, comment: comment.Body})
+		}
+	}
+}
+
+// NotifyDueSoon reminds one assignee of task. The reminder service calls it
+// once for each of them.
+func (s *notificationService) NotifyDueSoon(ctx context.Context, task entity.Task, userId uuid.UUID) {
+	s.send(ctx, notification{kind: constants.ENUM_NOTIFICATION_TASK_DUE_SOON, recipient: userId, task: task})
+}
+
+func (s *notificationService) NotifyOverdue(ctx context.Context, task entity.Task, userId uuid.UUID) {
+	s.send(ctx, notification{kind: constants.ENUM_NOTIFICATION_TASK_OVERDUE, recipient: userId, task: task})
+}
+
+// participants lists the users on task, primary assignee first, then its
+// other assignees, reviewers and watchers. If the others cannot be loaded
+// the primary assignee is still returned.
+func (s *notificationService) participants(ctx context.Context, task entity.Task) []uuid.UUID {
+	var users []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	if task.UserID != nil {
+		users = append(users, *task.UserID)
+		seen[*task.UserID] = true
+	}
+
+	assignees, err := s.taskAssigneeRepo.GetAssignees(ctx, nil, []int{task.ID})
+	if err != nil {
+		log.Printf("Failed to load assignees of task %d: %v", task.ID, err)
+		return users
+	}
+
+	for _, assignee := range assignees {
+		if seen[assignee.UserID] {
+			continue
+		}
+		seen[assignee.UserID] = true
+		users = append(users, assignee.UserID)
+	}
+
+	return users
+}
+
+func (s *notificationService) GetNotifications(ctx context.Context, userId string, req dto.NotificationListRequest) (dto.NotificationPaginationResponse, error) {
+	dataWithPaginate, err := s.notificationRepo.GetNotificationsWithPagination(ctx, nil, userId, req.Unread, req.PaginationRequest)
+	if err != nil {
+		return dto.NotificationPaginationResponse{}, dto.ErrGetAllNotification
+	}
+
+	datas := []dto.NotificationResponse{}
+	for _, item := range dataWithPaginate.Notifications {
+		datas = append(datas, toNotificationResponse(item))
+	}
+
+	return dto.NotificationPaginationResponse{
+		Data:               datas,
+		PaginationResponse: dataWithPaginate.PaginationResponse,
+	}, nil
+}
+
+func (s *notificationService) CountUnread(ctx context.Context, userId string) (dto.NotificationUnreadResponse, error) {
+	count, err := s.notificationRepo.CountUnread(ctx, nil, userId)
+	if err != nil {
+		return dto.NotificationUnreadResponse{}, dto.ErrGetAllNotification
+	}
+
+	return dto.NotificationUnreadResponse{Unread: count}, nil
+}
+
+func (s *notificationService) MarkAsRead(ctx context.Context, userId string, notificationId int) error {
+	if _, err := s.notificationRepo.GetNotificationById(ctx, nil, userId, notificationId); err != nil {
+		return dto.ErrNotificationNotFound
+	}
+
+	if err := s.notificationRepo.MarkAsRead(ctx, nil, userId, notificationId, time.Now()); err != nil {
+		return dto.ErrReadNotification
+	}
+
+	return nil
+}
+
+func (s *notificationService) MarkAllAsRead(ctx context.Context, userId string) (dto.NotificationReadAllResponse, error) {
+	updated, err := s.notificationRepo.MarkAllAsRead(ctx, nil, userId, time.Now())
+	if err != nil {
+		return dto.NotificationReadAllResponse{}, dto.ErrReadNotification
+	}
+
+	return dto.NotificationReadAllResponse{Updated: updated}, nil
+}
+
+// GetPreferences reports the effective setting for every type, filling in
+// the defaults for types the user never changed.
+func (s *notificationService) GetPreferences(ctx context.Context, userId string) ([]dto.NotificationPreferenceResponse, error) {
+	preferences, err := s.preferences(ctx, userId)
+	if err != nil {
+		return nil, dto.ErrGetNotificationPreference
+	}
+
+	var datas []dto.NotificationPreferenceResponse
+	for _, notificationType := range NotificationTypes {
+		preference := preferences[notificationType]
+		datas = append(datas, dto.NotificationPreferenceResponse{
+			Type:  notificationType,
+			InApp: preference.InApp,
+			Email: preference.Email,
+		})
+	}
+
+	return datas, nil
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, userId string, req dto.NotificationPreferenceUpdateRequest) ([]dto.NotificationPreferenceResponse, error) {
+	user, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, dto.ErrUserNotFound
+	}
+
+	var preferences []entity.NotificationPreference
+	for _, item := range req.Preferences {
+		preferences = append(preferences, entity.NotificationPreference{
+			UserID: user,
+			Type:   item.Type,
+			InApp:  item.InApp,
+			Email:  item.Email,
+		})
+	}
+
+	if err := s.notificationRepo.SavePreferences(ctx, nil, preferences); err != nil {
+		return nil, dto.ErrUpdateNotificationPreference
+	}
+
+	return s.GetPreferences(ctx, userId)
+}
+
+func (s *notificationService) preferences(ctx context.Context, userId string) (map[string]entity.NotificationPreference, error) {
+	saved, err := s.notificationRepo.GetPreferences(ctx, nil, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := map[string]entity.NotificationPreference{}
+	for _, notificationType := range NotificationTypes {
+		preferences[notificationType] = entity.NotificationPreference{
+			Type:  notificationType,
+			InApp: true,
+			Email: defaultEmailNotifications[notificationType],
+		}
+	}
+	for _, preference := range saved {
+		preferences[preference.Type] = preference
+	}
+
+	return preferences, nil
+}
+
+// send delivers n on the channels the recipient has enabled. Nobody is
+// notified about their own actions.
+func (s *notificationService) send(ctx context.Context, n notification) {
+	if n.actor != nil && *n.actor == n.recipient {
+		return
+	}
+
+	preferences, err := s.preferences(ctx, n.recipient.String())
+	if err != nil {
+		log.Printf("%v: %v", dto.ErrSendNotification, err)
+		return
+	}
+	preference := preferences[n.kind]
+	if !preference.InApp && !preference.Email {
+		return
+	}
+
+	actorName := "Someone"
+	if n.actor != nil {
+		if actor, err := s.userRepo.GetUserById(ctx, nil, n.actor.String()); err == nil {
+			actorName = actor.Name
+		}
+	}
+	title, body := renderNotification(n, actorName)
+
+	if preference.InApp {
+		taskId := n.task.ID
+		if _, err := s.notificationRepo.CreateNotification(ctx, nil, entity.Notification{
+			UserID:  n.recipient,
+			Type:    n.kind,
+			TaskID:  &taskId,
+			ActorID: n.actor,
+			Title:   truncate(title, 255),
+			Body:    body,
+		}); err != nil {
+			log.Printf("%v: %v", dto.ErrSendNotification, err)
+		}
+	}
+
+	if preference.Email {
+		recipient, err := s.userRepo.GetUserById(ctx, nil, n.recipient.String())
+		if err != nil {
+			log.Printf("%v: %v", dto.ErrSendNotification, err)
+			return
+		}
+
+		draftEmail, err := makeNotificationEmail(n, recipient, actorName, title)
+		if err != nil {
+			log.Printf("%v: %v", dto.ErrSendNotification, err)
+			return
+		}
+
+		if err := s.emailService.Enqueue(ctx, recipient.Email, draftEmail["subject"], draftEmail["body"]); err != nil {
+			log.Printf("%v: %v", dto.ErrSendNotification, err)
+		}
+	}
+}
+
+// renderNotification builds the in-app title and body of n.
+func renderNotification(n notification, actorName string) (string, string) {
+	switch n.kind {
+	case constants.ENUM_NOTIFICATION_TASK_ASSIGNED:
+		return fmt.Sprintf("You were assigned to %q", n.task.Title),
+			fmt.Sprintf("%s assigned you to this task.", actorName)
+	case constants.ENUM_NOTIFICATION_TASK_UNASSIGNED:
+		return fmt.Sprintf("You were unassigned from %q", n.task.Title),
+			fmt.Sprintf("%s removed you from this task.", actorName)
+	case constants.ENUM_NOTIFICATION_TASK_STATUS_CHANGED:
+		return fmt.Sprintf("%q moved to %s", n.task.Title, n.newStatus),
+			fmt.Sprintf("%s changed the status from %s to %s.", actorName, n.oldStatus, n.newStatus)
+	case constants.ENUM_NOTIFICATION_TASK_COMMENT:
+		title := fmt.Sprintf("%s commented on %q", actorName, n.task.Title)
+		if n.mentioned {
+			title = fmt.Sprintf("%s mentioned you on %q", actorName, n.task.Title)
+		}
+		return title, truncate(n.comment, NOTIFICATION_COMMENT_PREVIEW)
+	case constants.ENUM_NOTIFICATION_TASK_DUE_SOON:
+		return fmt.Sprintf("%q is due soon", n.task.Title),
+			fmt.Sprintf("This task is due on %s.", n.task.DueDate.Format("2006-01-02 15:04"))
+	case constants.ENUM_NOTIFICATION_TASK_OVERDUE:
+		return fmt.Sprintf("%q is overdue", n.task.Title),
+			fmt.Sprintf("This task was due on %s and is not done yet.", n.task.DueDate.Format("2006-01-02 15:04"))
+	}
+	return n.task.Title, ""
+}
+
+// makeNotificationEmail renders the utils/email-template/<type>_mail.html
+// template for n.
+func makeNotificationEmail(n notification, recipient entity.User, actorName string, title string) (map[string]string, error) {
+	readHtml, err := utils.ReadEmailTemplate(n.kind + "_mail.html")
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		Name      string
+		Title     string
+		Actor     string
+		TaskTitle string
+		TaskLink  string
+		OldStatus string
+		NewStatus string
+		Comment   string
+		DueDate   string
+	}{
+		Name:      recipient.Name,
+		Title:     title,
+		Actor:     actorName,
+		TaskTitle: n.task.Title,
+		TaskLink:  LOCAL_URL + "/" + TASK_ROUTE + "/" + strconv.Itoa(n.task.ID),
+		OldStatus: n.oldStatus,
+		NewStatus: n.newStatus,
+		Comment:   truncate(n.comment, NOTIFICATION_COMMENT_PREVIEW),
+		DueDate:   n.task.DueDate.Format("2006-01-02 15:04"),
+	}
+
+	tmpl, err := template.New("custom").Parse(string(readHtml))
+	if err != nil {
+		return nil, err
+	}
+
+	var strMail bytes.Buffer
+	if err := tmpl.Execute(&strMail, data); err != nil {
+		return nil, err
+	}
+
+	draftEmail := map[string]string{
+		"subject": "Cakno - " + title,
+		"body":    strMail.String(),
+	}
+
+	return draftEmail, nil
+}
+
+func toNotificationResponse(notification entity.Notification) dto.NotificationResponse {
+	return dto.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		TaskID:    notification.TaskID,
+		ActorID:   notification.ActorID,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		IsRead:    notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
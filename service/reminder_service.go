@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	// ReminderService periodically looks for open tasks that are due soon
+	// or overdue and reminds each of their assignees, once per due date.
+	ReminderService interface {
+		Scan(ctx context.Context) (int, error)
+		Start(ctx context.Context)
+	}
+
+	reminderService struct {
+		reminderRepo        repository.TaskReminderRepository
+		notificationService NotificationService
+		config              config.ReminderConfig
+	}
+)
+
+const REMINDER_BATCH_SIZE = 100
+
+func NewReminderService(reminderRepo repository.TaskReminderRepository, notificationService NotificationService, cfg config.ReminderConfig) ReminderService {
+	return &reminderService{
+		reminderRepo:        reminderRepo,
+		notificationService: notificationService,
+		config:              cfg,
+	}
+}
+
+// Scan sends every reminder that is currently due and returns how many it
+// sent.
+func (s *reminderService) Scan(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	dueSoon, err := s.remind(ctx, constants.ENUM_NOTIFICATION_TASK_DUE_SOON, &now, now.Add(s.config.DueSoonWindow), s.notificationService.NotifyDueSoon)
+	if err != nil {
+		return dueSoon, err
+	}
+
+	overdue, err := s.remind(ctx, constants.ENUM_NOTIFICATION_TASK_OVERDUE, nil, now, s.notificationService.NotifyOverdue)
+	return dueSoon + overdue, err
+}
+
+// Start runs Scan right away and then every config.Interval until ctx is
+// cancelled.
+func (s *reminderService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.Scan(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Failed to scan for due tasks: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *reminderService) remind(ctx context.Context, kind string, dueAfter *time.Time, dueBefore time.Time, notify func(context.Context, entity.Task, uuid.UUID)) (int, error) {
+	sent := 0
+	for {
+		assignees, err := s.reminderRepo.GetAssigneesToRemind(ctx, nil, kind, dueAfter, dueBefore, DefaultDoneStatuses(), REMINDER_BATCH_SIZE)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, assignee := range assignees {
+			// Claim the reminder before sending it, so that concurrent
+			// scanners do not both notify.
+			claimed, err := s.reminderRepo.CreateReminder(ctx, nil, entity.TaskReminder{
+				TaskID:  assignee.TaskID,
+				Kind:    kind,
+				UserID:  assignee.UserID,
+				DueDate: assignee.Task.DueDate,
+				SentAt:  time.Now(),
+			})
+			if err != nil {
+				return sent, err
+			}
+			if claimed {
+				notify(ctx, assignee.Task, assignee.UserID)
+				sent++
+			}
+		}
+
+		if len(assignees) < REMINDER_BATCH_SIZE {
+			return sent, nil
+		}
+	}
+}
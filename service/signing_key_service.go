@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// KEY_PREPUBLISH is how long a new key is listed in the JWKS before it
+	// starts signing, so verifiers that cache the JWKS pick it up in time.
+	KEY_PREPUBLISH = time.Hour
+
+	KEY_ROTATION_CHECK = 5 * time.Minute
+)
+
+type (
+	// SigningKeyService owns the keys behind access tokens. The HS256
+	// implementation wraps a single shared secret; the RS256/EdDSA one keeps a
+	// rotating set of key pairs in the database shared by every instance.
+	SigningKeyService interface {
+		Sign(claims jwt.Claims) (string, error)
+		Keyfunc(token *jwt.Token) (any, error)
+		ValidMethods() []string
+		JWKS() dto.JWKSResponse
+		Rotate(ctx context.Context) error
+		StartRotation(ctx context.Context)
+	}
+
+	hmacKeyService struct {
+		secret []byte
+	}
+
+	rotatingKeyService struct {
+		algorithm string
+		interval  time.Duration
+		sealKey   []byte
+		keyRepo   repository.SigningKeyRepository
+
+		mu   sync.RWMutex
+		keys []signingKey
+	}
+
+	signingKey struct {
+		kid         string
+		algorithm   string
+		private     crypto.Signer
+		public      crypto.PublicKey
+		activatesAt time.Time
+		expiresAt   time.Time
+	}
+)
+
+func NewSigningKeyService(cfg config.JWTConfig, keyRepo repository.SigningKeyRepository) SigningKeyService {
+	if cfg.Algorithm == config.JWT_ALGORITHM_HS256 {
+		return &hmacKeyService{secret: []byte(cfg.Secret)}
+	}
+
+	sealKey := sha256.Sum256([]byte(cfg.KeySecret))
+	return &rotatingKeyService{
+		algorithm: cfg.Algorithm,
+		interval:  cfg.RotationInterval,
+		sealKey:   sealKey[:],
+		keyRepo:   keyRepo,
+	}
+}
+
+func (s *hmacKeyService) Sign(claims jwt.Claims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+func (s *hmacKeyService) Keyfunc(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+	}
+	return s.secret, nil
+}
+
+func (s *hmacKeyService) ValidMethods() []string {
+	return []string{jwt.SigningMethodHS256.Alg()}
+}
+
+// JWKS is empty for HS256: a shared secret must never be published.
+func (s *hmacKeyService) JWKS() dto.JWKSResponse {
+	return dto.JWKSResponse{Keys: []dto.JWK{}}
+}
+
+func (s *hmacKeyService) Rotate(ctx context.Context) error {
+	return nil
+}
+
+func (s *hmacKeyService) StartRotation(ctx context.Context) {}
+
+// Sign uses the newest key that has already activated.
+func (s *rotatingKeyService) Sign(claims jwt.Claims) (string, error) {
+	key, err := s.currentKey(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(signingMethod(key.algorithm), claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.private)
+}
+
+// Keyfunc resolves the verification key by kid and refuses a token whose
+// alg does not match the key, which rules out algorithm confusion.
+func (s *rotatingKeyService) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.keys {
+		if key.kid != kid {
+			continue
+		}
+		if token.Method.Alg() != key.algorithm {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		if !time.Now().Before(key.expiresAt) {
+			break
+		}
+		return key.public, nil
+	}
+
+	return nil, dto.ErrUnknownKeyID
+}
+
+func (s *rotatingKeyService) ValidMethods() []string {
+	return []string{config.JWT_ALGORITHM_RS256, config.JWT_ALGORITHM_EDDSA}
+}
+
+// JWKS lists every unexpired key, including ones that have not activated yet
+// and ones that stopped signing but may still have live tokens.
+func (s *rotatingKeyService) JWKS() dto.JWKSResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := []dto.JWK{}
+	now := time.Now()
+	for _, key := range s.keys {
+		if now.Before(key.expiresAt) {
+			keys = append(keys, toJWK(key))
+		}
+	}
+
+	return dto.JWKSResponse{Keys: keys}
+}
+
+// Rotate reloads the keys from the database, picking up keys created by
+// other instances, and generates the next key once the current one is due
+// to be replaced. Each key signs for one interval and stays valid for
+// verification for a second one.
+func (s *rotatingKeyService) Rotate(ctx context.Context) error {
+	now := time.Now()
+
+	stored, err := s.keyRepo.GetUnexpiredKeys(ctx, nil, now)
+	if err != nil {
+		return err
+	}
+
+	var keys []signingKey
+	var latest *signingKey
+	for _, row := range stored {
+		key, err := s.openKey(row)
+		if err != nil {
+			log.Printf("Skipping unreadable signing key %s: %v", row.Kid, err)
+			continue
+		}
+		keys = append(keys, key)
+		if key.algorithm == s.algorithm && (latest == nil || key.activatesAt.After(latest.activatesAt)) {
+			latest = &key
+		}
+	}
+
+	prepublish := KEY_PREPUBLISH
+	if prepublish > s.interval/2 {
+		prepublish = s.interval / 2
+	}
+
+	if latest == nil || !now.Before(latest.activatesAt.Add(s.interval-prepublish)) {
+		activatesAt := now
+		if latest != nil && latest.activatesAt.Add(s.interval).After(now) {
+			activatesAt = latest.activatesAt.Add(s.interval)
+		}
+
+		key, err := s.createKey(ctx, activatesAt)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	if err := s.keyRepo.DeleteExpiredKeys(ctx, nil, now); err != nil {
+		log.Printf("Failed to delete expired signing keys: %v", err)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *rotatingKeyService) StartRotation(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(KEY_ROTATION_CHECK)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Rotate(ctx); err != nil {
+					log.Printf("Failed to rotate signing keys: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *rotatingKeyService) currentKey(now time.Time) (signingKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var current *signingKey
+	for i, key := range s.keys {
+		if key.algorithm != s.algorithm || now.Before(key.activatesAt) || !now.Before(key.expiresAt) {
+			continue
+		}
+		if current == nil || key.activatesAt.After(current.activatesAt) {
+			current = &s.keys[i]
+		}
+	}
+
+	if current == nil {
+		return signingKey{}, dto.ErrSigningKeyNotFound
+	}
+
+	return *current, nil
+}
+
+func (s *rotatingKeyService) createKey(ctx context.Context, activatesAt time.Time) (signingKey, error) {
+	var private crypto.Signer
+	var err error
+
+	switch s.algorithm {
+	case config.JWT_ALGORITHM_RS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case config.JWT_ALGORITHM_EDDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("unsupported signing algorithm %q", s.algorithm)
+	}
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	sealed, err := s.seal(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	thumbprint := sha256.Sum256(publicDER)
+	key := signingKey{
+		kid:         base64.RawURLEncoding.EncodeToString(thumbprint[:12]),
+		algorithm:   s.algorithm,
+		private:     private,
+		public:      private.Public(),
+		activatesAt: activatesAt,
+		expiresAt:   activatesAt.Add(2 * s.interval),
+	}
+
+	if _, err := s.keyRepo.CreateKey(ctx, nil, entity.SigningKey{
+		Kid:         key.kid,
+		Algorithm:   key.algorithm,
+		PrivateKey:  sealed,
+		PublicKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		ActivatesAt: key.activatesAt,
+		ExpiresAt:   key.expiresAt,
+	}); err != nil {
+		return signingKey{}, err
+	}
+
+	return key, nil
+}
+
+func (s *rotatingKeyService) openKey(row entity.SigningKey) (signingKey, error) {
+	privatePEM, err := s.open(row.PrivateKey)
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	block, _ := pem.Decode(privatePEM)
+	if block == nil {
+		return signingKey{}, errors.New("invalid private key PEM")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return signingKey{}, errors.New("private key cannot sign")
+	}
+
+	return signingKey{
+		kid:         row.Kid,
+		algorithm:   row.Algorithm,
+		private:     private,
+		public:      private.Public(),
+		activatesAt: row.ActivatesAt,
+		expiresAt:   row.ExpiresAt,
+	}, nil
+}
+
+// seal encrypts a private key with AES-GCM; the nonce is prepended to the
+// ciphertext.
+func (s *rotatingKeyService) seal(plaintext []byte) (string, error) {
+	block, err := aes.NewCipher(s.sealKey)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (s *rotatingKeyService) open(sealed string) ([]byte, error) {
+	data, err := hex.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(s.sealKey)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("sealed key too short")
+	}
+
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func signingMethod(algorithm string) jwt.SigningMethod {
+	if algorithm == config.JWT_ALGORITHM_EDDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+func toJWK(key signingKey) dto.JWK {
+	jwk := dto.JWK{
+		Kid: key.kid,
+		Use: "sig",
+		Alg: key.algorithm,
+	}
+
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return jwk
+}
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"gorm.io/gorm"
+)
+
+type (
+	SprintService interface {
+		Create(ctx context.Context, teamsID int, req dto.SprintCreateRequest) (dto.SprintResponse, error)
+		GetSprintsByTeamId(ctx context.Context, teamsID int) ([]dto.SprintResponse, error)
+		GetSprint(ctx context.Context, teamsID int, sprintId int) (dto.SprintDetailResponse, error)
+		Update(ctx context.Context, teamsID int, sprintId int, req dto.SprintUpdateRequest) (dto.SprintResponse, error)
+		Delete(ctx context.Context, teamsID int, sprintId int) error
+		Start(ctx context.Context, teamsID int, sprintId int) (dto.SprintResponse, error)
+		Close(ctx context.Context, teamsID int, sprintId int, req dto.SprintCloseRequest) (dto.SprintCloseResponse, error)
+		AddTask(ctx context.Context, teamsID int, sprintId int, req dto.SprintTaskRequest) (dto.SprintDetailResponse, error)
+		RemoveTask(ctx context.Context, teamsID int, sprintId int, taskId int) error
+	}
+
+	sprintService struct {
+		transactor      repository.Transactor
+		sprintRepo      repository.SprintRepository
+		taskRepo        repository.TaskRepository
+		workflowService WorkflowService
+	}
+)
+
+func NewSprintService(transactor repository.Transactor, sprintRepo repository.SprintRepository, taskRepo repository.TaskRepository, workflowService WorkflowService) SprintService {
+	return &sprintService{
+		transactor:      transactor,
+		sprintRepo:      sprintRepo,
+		taskRepo:        taskRepo,
+		workflowService: workflowService,
+	}
+}
+
+// Create plans a new sprint for the team. It starts out planned and holds no
+// tasks.
+func (s *sprintService) Create(ctx context.Context, teamsID int, req dto.SprintCreateRequest) (dto.SprintResponse, error) {
+	start, end, err := parseSprintDates(req.StartDate, req.EndDate)
+	if err != nil {
+		return dto.SprintResponse{}, err
+	}
+
+	sprint, err := s.sprintRepo.CreateSprint(ctx, nil, entity.Sprint{
+		TeamsID:   teamsID,
+		Name:      strings.TrimSpace(req.Name),
+		Goal:      strings.TrimSpace(req.Goal),
+		StartDate: start,
+		EndDate:   end,
+		State:     constants.ENUM_SPRINT_STATE_PLANNED,
+	})
+	if err != nil {
+		return dto.SprintResponse{}, dto.ErrCreateSprint
+	}
+
+	return toSprintResponse(sprint), nil
+}
+
+func (s *sprintService) GetSprintsByTeamId(ctx context.Context, teamsID int) ([]dto.SprintResponse, error) {
+	sprints, err := s.sprintRepo.GetSprintsByTeamId(ctx, nil, teamsID)
+	if err != nil {
+		return nil, dto.ErrGetAllSprint
+	}
+
+	responses := []dto.SprintResponse{}
+	for _, sprint := range sprints {
+		responses = append(responses, toSprintResponse(sprint))
+	}
+
+	return responses, nil
+}
+
+// GetSprint returns the sprint with a summary of each of its tasks and their
+// totals. A closed sprint shows its tasks as they were when it closed,
+// including the unfinished ones that were carried over.
+func (s *sprintService) GetSprint(ctx context.Context, teamsID int, sprintId int) (dto.SprintDetailResponse, error) {
+	sprint, err := s.sprintRepo.GetSprintById(ctx, nil, teamsID, sprintId)
+	if err != nil {
+		return dto.SprintDetailResponse{}, dto.ErrSprintNotFound
+	}
+
+	var snapshot []entity.SprintTask
+	if sprint.State == constants.ENUM_SPRINT_STATE_CLOSED {
+		snapshot, err = s.sprintRepo.GetSprintSnapshot(ctx, nil, sprint.ID)
+	} else {
+		snapshot, err = s.snapshot(ctx, nil, sprint.ID)
+	}
+	if err != nil {
+		return dto.SprintDetailResponse{}, dto.ErrGetSprint
+	}
+
+	detail := dto.SprintDetailResponse{
+		SprintResponse: toSprintResponse(sprint),
+		Tasks:          []dto.SprintTaskSummary{},
+	}
+
+	for _, task := range snapshot {
+		detail.Summary.TaskCount++
+		detail.Summary.Estimate += task.Estimate
+		if task.IsDone {
+			detail.Summary.DoneCount++
+			detail.Summary.DoneEstimate += task.Estimate
+		} else {
+			detail.Summary.RemainingEffort += task.RemainingEffort
+		}
+
+		detail.Tasks = append(detail.Tasks, dto.SprintTaskSummary{
+			ID:              task.TaskID,
+			Title:           task.Title,
+			Status:          task.Status,
+			IsDone:          task.IsDone,
+			CarriedOver:     sprint.State == constants.ENUM_SPRINT_STATE_CLOSED && !task.IsDone,
+			UserID:          task.UserID,
+			Priority:        TaskPriorityName(task.Priority),
+			Estimate:        task.Estimate,
+			RemainingEffort: task.RemainingEffort,
+		})
+	}
+
+	return detail, nil
+}
+
+// Update changes the sprint's name, goal and dates. Closed sprints are kept
+// as they were.
+func (s *sprintService) Update(ctx context.Context, teamsID int, sprintId int, req dto.SprintUpdateRequest) (dto.SprintResponse, error) {
+	sprint, err := s.sprintRepo.GetSprintById(ctx, nil, teamsID, sprintId)
+	if err != nil {
+		return dto.SprintResponse{}, dto.ErrSprintNotFound
+	}
+
+	if sprint.State == constants.ENUM_SPRINT_STATE_CLOSED {
+		return dto.SprintResponse{}, dto.ErrSprintClosed
+	}
+
+	start, end, err := parseSprintDates(req.StartDate, req.EndDate)
+	if err != nil {
+		return dto.SprintResponse{}, err
+	}
+
+	sprint.Name = strings.TrimSpace(req.Name)
+	sprint.Goal = strings.TrimSpace(req.Goal)
+	sprint.StartDate = start
+	sprint.EndDate = end
+	sprint, err = s.sprintRepo.UpdateSprint(ctx, nil, sprint)
+	if err != nil {
+		return dto.SprintResponse{}, dto.ErrUpdateSprint
+	}
+
+	return toSprintResponse(sprint), nil
+}
+
+// Delete removes a sprint that has not started yet and puts its tasks back
+// in the backlog.
+func (s *sprintService) Delete(ctx context.Context, teamsID int, sprintId int) error {
+	sprint, err := s.sprintRepo.GetSprintById(ctx, nil, teamsID, sprintId)
+	if err != nil {
+		return dto.ErrSprintNotFound
+	}
+
+	if sprint.State != constants.ENUM_SPRINT_STATE_PLANNED {
+		return dto.ErrSprintNotPlanned
+	}
+
+	if err := s.sprintRepo.DeleteSprint(ctx, nil, sprint.ID); err != nil {
+		return dto.ErrDeleteSprint
+	}
+
+	return nil
+}
+
+// Start makes a planned sprint the team's active one. A team has at most one
+// active sprint: the team's sprints stay locked from the check until the
+// update commits, so two sprints started at once cannot both become active.
+func (s *sprintService) Start(ctx context.Context, teamsID int, sprintId int) (dto.SprintResponse, error) {
+	var sprint entity.Sprint
+	err := s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		sprints, err := s.sprintRepo.GetSprintsByTeamIdForUpdate(ctx, tx, teamsID)
+		if err != nil {
+			return dto.ErrStartSprint
+		}
+
+		found := false
+		for _, other := range sprints {
+			if other.ID == sprintId {
+				sprint, found = other, true
+			}
+		}
+		if !found {
+			return dto.ErrSprintNotFound
+		}
+
+		if sprint.State != constants.ENUM_SPRINT_STATE_PLANNED {
+			return dto.ErrSprintNotPlanned
+		}
+
+		for _, other := range sprints {
+			if other.State == constants.ENUM_SPRINT_STATE_ACTIVE {
+				return dto.ErrSprintAlreadyActive
+			}
+		}
+
+		sprint.State = constants.ENUM_SPRINT_STATE_ACTIVE
+		sprint, err = s.sprintRepo.UpdateSprint(ctx, tx, sprint)
+		if err != nil {
+			return dto.ErrStartSprint
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.SprintResponse{}, err
+	}
+
+	return toSprintResponse(sprint), nil
+}
+
+// Close ends the active sprint. Its done tasks stay in it as a record of what
+// was delivered, and the unfinished ones move to the next sprint: the one in
+// the request, else the team's earliest planned sprint, else the backlog.
+// Every task is also kept in the sprint's snapshot as it stood at closing.
+func (s *sprintService) Close(ctx context.Context, teamsID int, sprintId int, req dto.SprintCloseRequest) (dto.SprintCloseResponse, error) {
+	sprint, err := s.sprintRepo.GetSprintById(ctx, nil, teamsID, sprintId)
+	if err != nil {
+		return dto.SprintCloseResponse{}, dto.ErrSprintNotFound
+	}
+
+	if sprint.State != constants.ENUM_SPRINT_STATE_ACTIVE {
+		return dto.SprintCloseResponse{}, dto.ErrSprintNotActive
+	}
+
+	nextSprintId, err := s.nextSprint(ctx, sprint, req.NextSprintID)
+	if err != nil {
+		return dto.SprintCloseResponse{}, err
+	}
+
+	closedAt := time.Now()
+	sprint.State = constants.ENUM_SPRINT_STATE_CLOSED
+	sprint.ClosedAt = &closedAt
+
+	var carryOver []int
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.sprintRepo.CloseSprint(ctx, tx, sprint); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.ErrSprintNotActive
+			}
+			return dto.ErrCloseSprint
+		}
+
+		snapshot, err := s.snapshot(ctx, tx, sprint.ID)
+		if err != nil {
+			return dto.ErrCloseSprint
+		}
+
+		carryOver = []int{}
+		for _, task := range snapshot {
+			if !task.IsDone {
+				carryOver = append(carryOver, task.TaskID)
+			}
+		}
+
+		if err := s.sprintRepo.SaveSprintSnapshot(ctx, tx, snapshot); err != nil {
+			return dto.ErrCloseSprint
+		}
+
+		if err := s.sprintRepo.MoveSprintTasks(ctx, tx, sprint.ID, carryOver, nextSprintId); err != nil {
+			return dto.ErrCloseSprint
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.SprintCloseResponse{}, err
+	}
+
+	return dto.SprintCloseResponse{
+		Sprint:       toSprintResponse(sprint),
+		CarriedOver:  carryOver,
+		NextSprintID: nextSprintId,
+	}, nil
+}
+
+// AddTask moves a task of the team into the sprint, taking it out of any
+// sprint it was in before.
+func (s *sprintService) AddTask(ctx context.Context, teamsID int, sprintId int, req dto.SprintTaskRequest) (dto.SprintDetailResponse, error) {
+	sprint, err := s.sprintRepo.GetSprintById(ctx, nil, teamsID, sprintId)
+	if err != nil {
+		return dto.SprintDetailResponse{}, dto.ErrSprintNotFound
+	}
+
+	if sprint.State == constants.ENUM_SPRINT_STATE_CLOSED {
+		return dto.SprintDetailResponse{}, dto.ErrSprintClosed
+	}
+
+	task, err := s.taskRepo.GetTaskById(ctx, nil, strconv.Itoa(req.TaskID))
+	if err != nil {
+		return dto.SprintDetailResponse{}, dto.ErrTaskNotFound
+	}
+
+	if task.TeamsID != sprint.TeamsID {
+		return dto.SprintDetailResponse{}, dto.ErrSprintTaskOtherTeam
+	}
+
+	if err := s.sprintRepo.SetTaskSprint(ctx, nil, task.ID, &sprint.ID); err != nil {
+		return dto.SprintDetailResponse{}, dto.ErrAddSprintTask
+	}
+
+	return s.GetSprint(ctx, teamsID, sprint.ID)
+}
+
+// RemoveTask puts a task of an open sprint back in the backlog.
+func (s *sprintService) RemoveTask(ctx context.Context, teamsID int, sprintId int, taskId int) error {
+	sprint, err := s.sprintRepo.GetSprintById(ctx, nil, teamsID, sprintId)
+	if err != nil {
+		return dto.ErrSprintNotFound
+	}
+
+	if sprint.State == constants.ENUM_SPRINT_STATE_CLOSED {
+		return dto.ErrSprintClosed
+	}
+
+	task, err := s.taskRepo.GetTaskById(ctx, nil, strconv.Itoa(taskId))
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	if task.SprintID == nil || *task.SprintID != sprint.ID {
+		return dto.ErrTaskNotInSprint
+	}
+
+	if err := s.sprintRepo.SetTaskSprint(ctx, nil, task.ID, nil); err != nil {
+		return dto.ErrRemoveSprintTask
+	}
+
+	return nil
+}
+
+// snapshot reads the tasks now in the sprint and whether each is done.
+func (s *sprintService) snapshot(ctx context.Context, tx *gorm.DB, sprintId int) ([]entity.SprintTask, error) {
+	tasks, err := s.sprintRepo.GetSprintTasks(ctx, tx, sprintId)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make([]entity.SprintTask, 0, len(tasks))
+	done := newDoneChecker(s.workflowService)
+	for _, task := range tasks {
+		snapshot = append(snapshot, entity.SprintTask{
+			SprintID:        sprintId,
+			TaskID:          task.ID,
+			Title:           task.Title,
+			Status:          task.Status,
+			IsDone:          done.isDone(ctx, task.TeamsID, task.Status),
+			UserID:          task.UserID,
+			Priority:        task.Priority,
+			Estimate:        task.Estimate,
+			RemainingEffort: task.RemainingEffort,
+		})
+	}
+
+	return snapshot, nil
+}
+
+// nextSprint picks the sprint that receives the unfinished tasks of sprint.
+// A nil result sends them to the backlog.
+func (s *sprintService) nextSprint(ctx context.Context, sprint entity.Sprint, requested *int) (*int, error) {
+	if requested != nil {
+		next, err := s.sprintRepo.GetSprintById(ctx, nil, sprint.TeamsID, *requested)
+		if err != nil || next.ID == sprint.ID || next.State != constants.ENUM_SPRINT_STATE_PLANNED {
+			return nil, dto.ErrInvalidNextSprint
+		}
+		return &next.ID, nil
+	}
+
+	sprints, err := s.sprintRepo.GetSprintsByTeamId(ctx, nil, sprint.TeamsID)
+	if err != nil {
+		return nil, dto.ErrCloseSprint
+	}
+
+	for _, next := range sprints {
+		if next.State == constants.ENUM_SPRINT_STATE_PLANNED {
+			return &next.ID, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// parseSprintDates accepts the same formats as the task list's due date
+// filters. A sprint must end after it starts.
+func parseSprintDates(startDate string, endDate string) (time.Time, time.Time, error) {
+	start, err := parseFilterTime("start_date", startDate)
+	if err != nil || start == nil {
+		return time.Time{}, time.Time{}, dto.ErrInvalidSprintDates
+	}
+
+	end, err := parseFilterTime("end_date", endDate)
+	if err != nil || end == nil || !end.After(*start) {
+		return time.Time{}, time.Time{}, dto.ErrInvalidSprintDates
+	}
+
+	return *start, *end, nil
+}
+
+func toSprintResponse(sprint entity.Sprint) dto.SprintResponse {
+	return dto.SprintResponse{
+		ID:        sprint.ID,
+		TeamsID:   sprint.TeamsID,
+		Name:      sprint.Name,
+		Goal:      sprint.Goal,
+		StartDate: sprint.StartDate,
+		EndDate:   sprint.EndDate,
+		State:     sprint.State,
+		ClosedAt:  sprint.ClosedAt,
+		CreatedAt: sprint.CreatedAt,
+		UpdatedAt: sprint.UpdatedAt,
+	}
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *taskService) GetAssignees(ctx context.Context, taskId string) ([]dto.TaskAssigneeResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	assignees, err := s.assigneesOf(ctx, []entity.Task{task})
+	if err != nil {
+		return nil, dto.ErrGetAllAssignee
+	}
+
+	return toTaskAssigneeResponses(assignees[task.ID]), nil
+}
+
+// AddAssignee puts a team member on the task, or changes their role if they
+// are already on it.
+func (s *taskService) AddAssignee(ctx context.Context, taskId string, req dto.TaskAssigneeRequest, actorId string) ([]dto.TaskAssigneeResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	role := req.Role
+	switch role {
+	case "":
+		role = constants.ENUM_TASK_ROLE_ASSIGNEE
+	case constants.ENUM_TASK_ROLE_ASSIGNEE, constants.ENUM_TASK_ROLE_REVIEWER, constants.ENUM_TASK_ROLE_WATCHER:
+	default:
+		return nil, dto.ErrInvalidAssigneeRole
+	}
+
+	if err := s.ensureTeamMember(ctx, task.TeamsID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.changeRole(ctx, task, req.UserID, role, actorId); err != nil {
+		return nil, err
+	}
+
+	return s.GetAssignees(ctx, taskId)
+}
+
+func (s *taskService) RemoveAssignee(ctx context.Context, taskId string, userId string, actorId string) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	user, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.ErrAssigneeNotFound
+	}
+
+	assignees, err := s.taskAssigneeRepo.GetAssignees(ctx, nil, []int{task.ID})
+	if err != nil {
+		return dto.ErrRemoveAssignee
+	}
+	if roleOf(assignees, user) == "" {
+		return dto.ErrAssigneeNotFound
+	}
+
+	return s.changeRole(ctx, task, user, "", actorId)
+}
+
+// WatchTask subscribes the user to the task's notifications. Someone who is
+// already on the task in any role is left as they are.
+func (s *taskService) WatchTask(ctx context.Context, taskId string, userId string) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	user, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.ErrAddAssignee
+	}
+
+	assignees, err := s.taskAssigneeRepo.GetAssignees(ctx, nil, []int{task.ID})
+	if err != nil {
+		return dto.ErrAddAssignee
+	}
+	if roleOf(assignees, user) != "" {
+		return nil
+	}
+
+	return s.changeRole(ctx, task, user, constants.ENUM_TASK_ROLE_WATCHER, userId)
+}
+
+func (s *taskService) UnwatchTask(ctx context.Context, taskId string, userId string) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	user, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.ErrNotWatching
+	}
+
+	assignees, err := s.taskAssigneeRepo.GetAssignees(ctx, nil, []int{task.ID})
+	if err != nil {
+		return dto.ErrRemoveAssignee
+	}
+	if roleOf(assignees, user) != constants.ENUM_TASK_ROLE_WATCHER {
+		return dto.ErrNotWatching
+	}
+
+	return s.changeRole(ctx, task, user, "", userId)
+}
+
+// changeRole gives user the role on task, or takes them off it when role is
+// empty, then keeps Task.UserID pointing at one of the assignees. Watchers
+// come and go without a history entry; everyone else's changes are recorded
+// like a change of the primary assignee.
+func (s *taskService) changeRole(ctx context.Context, task entity.Task, user uuid.UUID, role string, actorId string) error {
+	failed := dto.ErrAddAssignee
+	if role == "" {
+		failed = dto.ErrRemoveAssignee
+	}
+
+	assignees, err := s.taskAssigneeRepo.GetAssignees(ctx, nil, []int{task.ID})
+	if err != nil {
+		return failed
+	}
+
+	previous := roleOf(assignees, user)
+	if previous == role {
+		return nil
+	}
+
+	var events []entity.TaskEvent
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		if role == "" {
+			err = s.taskAssigneeRepo.RemoveAssignee(ctx, tx, task.ID, user)
+		} else {
+			err = s.taskAssigneeRepo.AddAssignee(ctx, tx, entity.TaskAssignee{TaskID: task.ID, UserID: user, Role: role})
+		}
+		if err != nil {
+			return failed
+		}
+
+		primary, err := s.syncPrimary(ctx, tx, task)
+		if err != nil {
+			return failed
+		}
+
+		covered := false
+		if !sameUser(task.UserID, primary) {
+			events = append(events, assignmentEvent(task.ID, task.UserID, primary, actorId))
+			covered = sameUser(task.UserID, &user) || sameUser(primary, &user)
+		}
+		if !covered {
+			working := func(role string) bool { return role != "" && role != constants.ENUM_TASK_ROLE_WATCHER }
+			switch {
+			case working(role):
+				events = append(events, roleEvent(task.ID, role, nil, &user, actorId))
+			case working(previous):
+				events = append(events, roleEvent(task.ID, previous, &user, nil, actorId))
+			}
+		}
+
+		task.UserID = primary
+		if len(events) == 0 {
+			return nil
+		}
+		return s.recordEvents(ctx, tx, events)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(events) > 0 {
+		s.announce(ctx, task, events)
+	}
+
+	return nil
+}
+
+// syncPrimary points Task.UserID at an assignee: the current one while they
+// are still an assignee, otherwise the longest-standing one, or nobody.
+func (s *taskService) syncPrimary(ctx context.Context, tx *gorm.DB, task entity.Task) (*uuid.UUID, error) {
+	assignees, err := s.taskAssigneeRepo.GetAssignees(ctx, tx, []int{task.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	var primary *uuid.UUID
+	for _, assignee := range assignees {
+		if assignee.Role != constants.ENUM_TASK_ROLE_ASSIGNEE {
+			continue
+		}
+		if sameUser(task.UserID, &assignee.UserID) {
+			return task.UserID, nil
+		}
+		if primary == nil {
+			id := assignee.UserID
+			primary = &id
+		}
+	}
+
+	taskId := intString(&task.ID)
+	switch {
+	case primary != nil:
+		err = s.taskRepo.AssignUserToTask(ctx, tx, taskId, primary)
+	case task.UserID != nil:
+		err = s.taskRepo.RemoveUserFromTask(ctx, tx, taskId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return primary, nil
+}
+
+// assigneesOf returns everyone on each of tasks, with their users loaded.
+func (s *taskService) assigneesOf(ctx context.Context, tasks []entity.Task) (map[int][]entity.TaskAssignee, error) {
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+
+	rows, err := s.taskAssigneeRepo.GetAssignees(ctx, nil, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	assignees := map[int][]entity.TaskAssignee{}
+	for _, row := range rows {
+		assignees[row.TaskID] = append(assignees[row.TaskID], row)
+	}
+
+	return assignees, nil
+}
+
+// taskChange is a change saved by reassignRoles, announced once the
+// transaction it was saved in has been committed.
+type taskChange struct {
+	task   entity.Task
+	events []entity.TaskEvent
+}
+
+// reassignRoles hands fromUser's places on the team's tasks to toUser, or
+// just takes fromUser off them when toUser is nil. The primary assignee has
+// already been moved by the caller.
+func (s *taskService) reassignRoles(ctx context.Context, tx *gorm.DB, tasks []entity.Task, fromUser uuid.UUID, toUser *uuid.UUID, actorId string) ([]taskChange, error) {
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+
+	rows, err := s.taskAssigneeRepo.GetAssignees(ctx, tx, ids)
+	if err != nil {
+		log.Printf("Failed to reassign task roles of %s: %v", fromUser, err)
+		return nil, dto.ErrReassignTasks
+	}
+
+	assignees := map[int][]entity.TaskAssignee{}
+	for _, row := range rows {
+		assignees[row.TaskID] = append(assignees[row.TaskID], row)
+	}
+
+	var changes []taskChange
+	for _, task := range tasks {
+		role := roleOf(assignees[task.ID], fromUser)
+		wasPrimary := sameUser(task.UserID, &fromUser)
+		if role == "" && !wasPrimary {
+			continue
+		}
+		if role == "" {
+			role = constants.ENUM_TASK_ROLE_ASSIGNEE
+		}
+
+		if err := s.taskAssigneeRepo.RemoveAssignee(ctx, tx, task.ID, fromUser); err != nil {
+			log.Printf("Failed to remove %s from task %d: %v", fromUser, task.ID, err)
+			return nil, dto.ErrReassignTasks
+		}
+
+		var events []entity.TaskEvent
+		if toUser != nil && role != constants.ENUM_TASK_ROLE_WATCHER {
+			if current := roleOf(assignees[task.ID], *toUser); current == "" || current == constants.ENUM_TASK_ROLE_WATCHER {
+				if err := s.taskAssigneeRepo.AddAssignee(ctx, tx, entity.TaskAssignee{TaskID: task.ID, UserID: *toUser, Role: role}); err != nil {
+					log.Printf("Failed to add %s to task %d: %v", toUser, task.ID, err)
+					return nil, dto.ErrReassignTasks
+				}
+			}
+			if !wasPrimary {
+				events = append(events, roleEvent(task.ID, role, &fromUser, toUser, actorId))
+			}
+		}
+
+		if wasPrimary {
+			task.UserID = toUser
+			primary, err := s.syncPrimary(ctx, tx, task)
+			if err != nil {
+				log.Printf("Failed to update the assignee of task %d: %v", task.ID, err)
+				return nil, dto.ErrReassignTasks
+			}
+			events = append(events, assignmentEvent(task.ID, &fromUser, primary, actorId))
+			task.UserID = primary
+		} else if toUser == nil && role != constants.ENUM_TASK_ROLE_WATCHER {
+			events = append(events, roleEvent(task.ID, role, &fromUser, nil, actorId))
+		}
+
+		if len(events) > 0 {
+			if err := s.recordEvents(ctx, tx, events); err != nil {
+				return nil, err
+			}
+			changes = append(changes, taskChange{task: task, events: events})
+		}
+	}
+
+	return changes, nil
+}
+
+// roleEvent records a user joining or leaving a task in role, in the same
+// shape as a change of the primary assignee.
+func roleEvent(taskId int, role string, from *uuid.UUID, to *uuid.UUID, actorId string) entity.TaskEvent {
+	event := assignmentEvent(taskId, from, to, actorId)
+	event.Field = role
+	return event
+}
+
+func roleOf(assignees []entity.TaskAssignee, user uuid.UUID) string {
+	for _, assignee := range assignees {
+		if assignee.UserID == user {
+			return assignee.Role
+		}
+	}
+	return ""
+}
+
+func sameUser(a *uuid.UUID, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func toTaskAssigneeResponses(assignees []entity.TaskAssignee) []dto.TaskAssigneeResponse {
+	responses := []dto.TaskAssigneeResponse{}
+	for _, assignee := range assignees {
+		user := toUserResponse(assignee.User)
+		if assignee.User.ID == uuid.Nil {
+			user = dto.UserResponse{ID: assignee.UserID.String()}
+		}
+		responses = append(responses, dto.TaskAssigneeResponse{
+			User:      user,
+			Role:      assignee.Role,
+			CreatedAt: assignee.CreatedAt,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+	"github.com/google/uuid"
+)
+
+type (
+	TaskAttachmentService interface {
+		Upload(ctx context.Context, taskId string, userId string, file *multipart.FileHeader) (dto.AttachmentResponse, error)
+		GetAttachmentsByTaskId(ctx context.Context, taskId string) ([]dto.AttachmentResponse, error)
+		Delete(ctx context.Context, taskId string, attachmentId int, userId string) error
+		DeleteByTaskId(ctx context.Context, taskId int) error
+		Download(ctx context.Context, taskId string, attachmentId int) (dto.AttachmentResponse, io.ReadCloser, error)
+	}
+
+	taskAttachmentService struct {
+		taskAttachmentRepo   repository.TaskAttachmentRepository
+		taskRepo             repository.TaskRepository
+		authorizationService AuthorizationService
+		storage              storage.Storage
+	}
+)
+
+const ATTACHMENT_DIR = "attachments"
+
+// allowedAttachmentTypes maps the types an attachment may have to the
+// extension it is stored under. It is checked against the type sniffed from
+// the file content, never the type or file name the client sent.
+var allowedAttachmentTypes = map[string]string{
+	"image/png":          "png",
+	"image/jpeg":         "jpg",
+	"image/gif":          "gif",
+	"image/webp":         "webp",
+	"application/pdf":    "pdf",
+	"application/zip":    "zip",
+	"application/x-gzip": "gz",
+	"text/plain":         "txt",
+}
+
+func NewTaskAttachmentService(taskAttachmentRepo repository.TaskAttachmentRepository, taskRepo repository.TaskRepository, authorizationService AuthorizationService, fileStorage storage.Storage) TaskAttachmentService {
+	return &taskAttachmentService{
+		taskAttachmentRepo:   taskAttachmentRepo,
+		taskRepo:             taskRepo,
+		authorizationService: authorizationService,
+		storage:              fileStorage,
+	}
+}
+
+func (s *taskAttachmentService) Upload(ctx context.Context, taskId string, userId string, file *multipart.FileHeader) (dto.AttachmentResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.AttachmentResponse{}, dto.ErrTaskNotFound
+	}
+
+	uploader, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.AttachmentResponse{}, dto.ErrUserNotFound
+	}
+
+	if file.Size > constants.ENUM_ATTACHMENT_MAX_SIZE {
+		return dto.AttachmentResponse{}, dto.ErrAttachmentTooLarge
+	}
+
+	mimeType, checksum, err := inspectFile(file)
+	if err != nil {
+		return dto.AttachmentResponse{}, dto.ErrUploadAttachment
+	}
+
+	ext, ok := allowedAttachmentTypes[mimeType]
+	if !ok {
+		return dto.AttachmentResponse{}, dto.ErrAttachmentTypeNotAllowed
+	}
+
+	path := fmt.Sprintf("%s/%s.%s", ATTACHMENT_DIR, uuid.New(), ext)
+
+	if err := storage.PutFile(ctx, s.storage, path, file, mimeType); err != nil {
+		return dto.AttachmentResponse{}, dto.ErrUploadAttachment
+	}
+
+	attachment, err := s.taskAttachmentRepo.CreateAttachment(ctx, nil, entity.TaskAttachment{
+		TaskID:   task.ID,
+		UserID:   uploader,
+		FileName: filepath.Base(file.Filename),
+		Path:     path,
+		MimeType: mimeType,
+		Size:     file.Size,
+		Checksum: checksum,
+	})
+	if err != nil {
+		if err := s.storage.Delete(ctx, path); err != nil {
+			log.Printf("Failed to clean up attachment file %s: %v", path, err)
+		}
+		return dto.AttachmentResponse{}, dto.ErrUploadAttachment
+	}
+
+	attachment, err = s.taskAttachmentRepo.GetAttachmentById(ctx, nil, task.ID, attachment.ID)
+	if err != nil {
+		return dto.AttachmentResponse{}, dto.ErrAttachmentNotFound
+	}
+
+	return toAttachmentResponse(attachment), nil
+}
+
+func (s *taskAttachmentService) GetAttachmentsByTaskId(ctx context.Context, taskId string) ([]dto.AttachmentResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	attachments, err := s.taskAttachmentRepo.GetAttachmentsByTaskId(ctx, nil, task.ID)
+	if err != nil {
+		return nil, dto.ErrGetAllAttachment
+	}
+
+	responses := []dto.AttachmentResponse{}
+	for _, attachment := range attachments {
+		responses = append(responses, toAttachmentResponse(attachment))
+	}
+
+	return responses, nil
+}
+
+// Delete lets the uploader remove their own attachment; anyone else needs
+// to be allowed to manage the team's attachments.
+func (s *taskAttachmentService) Delete(ctx context.Context, taskId string, attachmentId int, userId string) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	attachment, err := s.taskAttachmentRepo.GetAttachmentById(ctx, nil, task.ID, attachmentId)
+	if err != nil {
+		return dto.ErrAttachmentNotFound
+	}
+
+	if attachment.UserID.String() != userId {
+		if err := s.authorizationService.AuthorizeTeam(ctx, userId, task.TeamsID, constants.ACTION_TASK_ATTACHMENT_MANAGE); err != nil {
+			return err
+		}
+	}
+
+	if err := s.taskAttachmentRepo.DeleteAttachment(ctx, nil, attachment.ID); err != nil {
+		return dto.ErrDeleteAttachment
+	}
+
+	if err := s.storage.Delete(ctx, attachment.Path); err != nil {
+		log.Printf("Failed to delete attachment file %s: %v", attachment.Path, err)
+	}
+
+	return nil
+}
+
+func (s *taskAttachmentService) DeleteByTaskId(ctx context.Context, taskId int) error {
+	attachments, err := s.taskAttachmentRepo.GetAttachmentsByTaskId(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrDeleteAttachment
+	}
+
+	if err := s.taskAttachmentRepo.DeleteAttachmentsByTaskId(ctx, nil, taskId); err != nil {
+		return dto.ErrDeleteAttachment
+	}
+
+	for _, attachment := range attachments {
+		if err := s.storage.Delete(ctx, attachment.Path); err != nil {
+			log.Printf("Failed to delete attachment file %s: %v", attachment.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// Download opens an attachment's content. Files are only ever served through
+// this call, behind the task's read authorization.
+func (s *taskAttachmentService) Download(ctx context.Context, taskId string, attachmentId int) (dto.AttachmentResponse, io.ReadCloser, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.AttachmentResponse{}, nil, dto.ErrTaskNotFound
+	}
+
+	attachment, err := s.taskAttachmentRepo.GetAttachmentById(ctx, nil, task.ID, attachmentId)
+	if err != nil {
+		return dto.AttachmentResponse{}, nil, dto.ErrAttachmentNotFound
+	}
+
+	body, err := s.storage.Get(ctx, attachment.Path)
+	if err != nil {
+		return dto.AttachmentResponse{}, nil, dto.ErrDownloadAttachment
+	}
+
+	return toAttachmentResponse(attachment), body, nil
+}
+
+// inspectFile sniffs the media type from the first 512 bytes and hashes the
+// whole file with SHA-256.
+func inspectFile(file *multipart.FileHeader) (string, string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", "", err
+	}
+	head = head[:n]
+
+	hash := sha256.New()
+	hash.Write(head)
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", "", err
+	}
+
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "", "", err
+	}
+
+	return mimeType, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func toAttachmentResponse(attachment entity.TaskAttachment) dto.AttachmentResponse {
+	return dto.AttachmentResponse{
+		ID:        attachment.ID,
+		TaskID:    attachment.TaskID,
+		FileName:  attachment.FileName,
+		Url:       fmt.Sprintf("/api/tasks/%d/attachments/%d/download", attachment.TaskID, attachment.ID),
+		MimeType:  attachment.MimeType,
+		Size:      attachment.Size,
+		Checksum:  attachment.Checksum,
+		Uploader:  toUserResponse(attachment.User),
+		CreatedAt: attachment.CreatedAt,
+	}
+}
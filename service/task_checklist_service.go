@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+)
+
+type (
+	TaskChecklistService interface {
+		Create(ctx context.Context, taskId string, req dto.ChecklistItemCreateRequest) (dto.ChecklistItemResponse, error)
+		GetItemsByTaskId(ctx context.Context, taskId string) ([]dto.ChecklistItemResponse, error)
+		Update(ctx context.Context, taskId string, itemId int, userId string, req dto.ChecklistItemUpdateRequest) (dto.ChecklistItemResponse, error)
+		Delete(ctx context.Context, taskId string, itemId int) error
+		Reorder(ctx context.Context, taskId string, req dto.ChecklistReorderRequest) ([]dto.ChecklistItemResponse, error)
+	}
+
+	taskChecklistService struct {
+		taskChecklistRepo repository.TaskChecklistRepository
+		taskRepo          repository.TaskRepository
+	}
+)
+
+func NewTaskChecklistService(taskChecklistRepo repository.TaskChecklistRepository, taskRepo repository.TaskRepository) TaskChecklistService {
+	return &taskChecklistService{
+		taskChecklistRepo: taskChecklistRepo,
+		taskRepo:          taskRepo,
+	}
+}
+
+// Create adds an item at the end of the task's checklist.
+func (s *taskChecklistService) Create(ctx context.Context, taskId string, req dto.ChecklistItemCreateRequest) (dto.ChecklistItemResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ChecklistItemResponse{}, dto.ErrTaskNotFound
+	}
+
+	items, err := s.taskChecklistRepo.GetItemsByTaskId(ctx, nil, task.ID)
+	if err != nil {
+		return dto.ChecklistItemResponse{}, dto.ErrCreateChecklistItem
+	}
+
+	position := 0
+	for _, item := range items {
+		if item.Position >= position {
+			position = item.Position + 1
+		}
+	}
+
+	item, err := s.taskChecklistRepo.CreateItem(ctx, nil, entity.TaskChecklistItem{
+		TaskID:   task.ID,
+		Title:    req.Title,
+		Position: position,
+	})
+	if err != nil {
+		return dto.ChecklistItemResponse{}, dto.ErrCreateChecklistItem
+	}
+
+	return toChecklistItemResponse(item), nil
+}
+
+func (s *taskChecklistService) GetItemsByTaskId(ctx context.Context, taskId string) ([]dto.ChecklistItemResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	items, err := s.taskChecklistRepo.GetItemsByTaskId(ctx, nil, task.ID)
+	if err != nil {
+		return nil, dto.ErrGetChecklist
+	}
+
+	responses := []dto.ChecklistItemResponse{}
+	for _, item := range items {
+		responses = append(responses, toChecklistItemResponse(item))
+	}
+
+	return responses, nil
+}
+
+// Update renames an item and/or checks it off. Checking an item records who
+// did it and when; unchecking clears both.
+func (s *taskChecklistService) Update(ctx context.Context, taskId string, itemId int, userId string, req dto.ChecklistItemUpdateRequest) (dto.ChecklistItemResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ChecklistItemResponse{}, dto.ErrTaskNotFound
+	}
+
+	item, err := s.taskChecklistRepo.GetItemById(ctx, nil, task.ID, itemId)
+	if err != nil {
+		return dto.ChecklistItemResponse{}, dto.ErrChecklistItemNotFound
+	}
+
+	if req.Title != nil {
+		item.Title = *req.Title
+	}
+
+	if req.IsDone != nil && *req.IsDone != item.IsDone {
+		item.IsDone = *req.IsDone
+		item.CompletedBy = nil
+		item.CompletedAt = nil
+		if item.IsDone {
+			now := time.Now()
+			item.CompletedBy = parseActor(userId)
+			item.CompletedAt = &now
+		}
+	}
+
+	item, err = s.taskChecklistRepo.UpdateItem(ctx, nil, item)
+	if err != nil {
+		return dto.ChecklistItemResponse{}, dto.ErrUpdateChecklistItem
+	}
+
+	return toChecklistItemResponse(item), nil
+}
+
+func (s *taskChecklistService) Delete(ctx context.Context, taskId string, itemId int) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	item, err := s.taskChecklistRepo.GetItemById(ctx, nil, task.ID, itemId)
+	if err != nil {
+		return dto.ErrChecklistItemNotFound
+	}
+
+	if err := s.taskChecklistRepo.DeleteItem(ctx, nil, item.ID); err != nil {
+		return dto.ErrDeleteChecklistItem
+	}
+
+	return nil
+}
+
+func (s *taskChecklistService) Reorder(ctx context.Context, taskId string, req dto.ChecklistReorderRequest) ([]dto.ChecklistItemResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	items, err := s.taskChecklistRepo.GetItemsByTaskId(ctx, nil, task.ID)
+	if err != nil {
+		return nil, dto.ErrReorderChecklistItems
+	}
+
+	current := make([]int, 0, len(items))
+	for _, item := range items {
+		current = append(current, item.ID)
+	}
+	if !isPermutation(current, req.ItemIDs) {
+		return nil, dto.ErrInvalidChecklistOrder
+	}
+
+	if err := s.taskChecklistRepo.ReorderItems(ctx, nil, task.ID, req.ItemIDs); err != nil {
+		return nil, dto.ErrReorderChecklistItems
+	}
+
+	return s.GetItemsByTaskId(ctx, taskId)
+}
+
+func toChecklistItemResponse(item entity.TaskChecklistItem) dto.ChecklistItemResponse {
+	return dto.ChecklistItemResponse{
+		ID:          item.ID,
+		TaskID:      item.TaskID,
+		Title:       item.Title,
+		IsDone:      item.IsDone,
+		Position:    item.Position,
+		CompletedBy: item.CompletedBy,
+		CompletedAt: item.CompletedAt,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	TaskCommentService interface {
+		Create(ctx context.Context, taskId string, userId string, req dto.CommentCreateRequest) (dto.CommentResponse, error)
+		GetCommentsByTaskId(ctx context.Context, taskId string) ([]dto.CommentResponse, error)
+		Update(ctx context.Context, taskId string, commentId int, userId string, req dto.CommentUpdateRequest) (dto.CommentResponse, error)
+		Delete(ctx context.Context, taskId string, commentId int, userId string) error
+	}
+
+	taskCommentService struct {
+		transactor          repository.Transactor
+		taskCommentRepo     repository.TaskCommentRepository
+		taskRepo            repository.TaskRepository
+		userTeamsRepo       repository.UserTeamsRepository
+		notificationService NotificationService
+	}
+)
+
+func NewTaskCommentService(transactor repository.Transactor, taskCommentRepo repository.TaskCommentRepository, taskRepo repository.TaskRepository, userTeamsRepo repository.UserTeamsRepository, notificationService NotificationService) TaskCommentService {
+	return &taskCommentService{
+		transactor:          transactor,
+		taskCommentRepo:     taskCommentRepo,
+		taskRepo:            taskRepo,
+		userTeamsRepo:       userTeamsRepo,
+		notificationService: notificationService,
+	}
+}
+
+func (s *taskCommentService) Create(ctx context.Context, taskId string, userId string, req dto.CommentCreateRequest) (dto.CommentResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.CommentResponse{}, dto.ErrTaskNotFound
+	}
+
+	author, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.CommentResponse{}, dto.ErrUserNotFound
+	}
+
+	if req.ParentID != nil {
+		if _, err := s.taskCommentRepo.GetCommentById(ctx, nil, task.ID, *req.ParentID); err != nil {
+			return dto.CommentResponse{}, dto.ErrParentCommentNotFound
+		}
+	}
+
+	var (
+		comment   entity.TaskComment
+		mentioned []uuid.UUID
+	)
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		comment, err = s.taskCommentRepo.CreateComment(ctx, tx, entity.TaskComment{
+			TaskID:   task.ID,
+			UserID:   author,
+			ParentID: req.ParentID,
+			Body:     req.Body,
+		})
+		if err != nil {
+			return err
+		}
+
+		mentioned, err = s.saveMentions(ctx, tx, task.TeamsID, comment)
+		return err
+	})
+	if err != nil {
+		return dto.CommentResponse{}, dto.ErrCreateComment
+	}
+
+	s.notificationService.NotifyComment(ctx, task, comment, mentioned)
+
+	return s.getComment(ctx, task.ID, comment.ID)
+}
+
+func (s *taskCommentService) GetCommentsByTaskId(ctx context.Context, taskId string) ([]dto.CommentResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	comments, err := s.taskCommentRepo.GetCommentsByTaskId(ctx, nil, task.ID)
+	if err != nil {
+		return nil, dto.ErrGetAllComment
+	}
+
+	return buildCommentThreads(comments), nil
+}
+
+func (s *taskCommentService) Update(ctx context.Context, taskId string, commentId int, userId string, req dto.CommentUpdateRequest) (dto.CommentResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.CommentResponse{}, dto.ErrTaskNotFound
+	}
+
+	comment, err := s.taskCommentRepo.GetCommentById(ctx, nil, task.ID, commentId)
+	if err != nil {
+		return dto.CommentResponse{}, dto.ErrCommentNotFound
+	}
+
+	if comment.UserID.String() != userId {
+		return dto.CommentResponse{}, dto.ErrNotCommentAuthor
+	}
+
+	comment.Body = req.Body
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		if _, err := s.taskCommentRepo.UpdateComment(ctx, tx, comment); err != nil {
+			return err
+		}
+
+		_, err := s.saveMentions(ctx, tx, task.TeamsID, comment)
+		return err
+	})
+	if err != nil {
+		return dto.CommentResponse{}, dto.ErrUpdateComment
+	}
+
+	return s.getComment(ctx, task.ID, comment.ID)
+}
+
+func (s *taskCommentService) Delete(ctx context.Context, taskId string, commentId int, userId string) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	comment, err := s.taskCommentRepo.GetCommentById(ctx, nil, task.ID, commentId)
+	if err != nil {
+		return dto.ErrCommentNotFound
+	}
+
+	if comment.UserID.String() != userId {
+		return dto.ErrNotCommentAuthor
+	}
+
+	if err := s.taskCommentRepo.DeleteComment(ctx, nil, comment.ID); err != nil {
+		return dto.ErrDeleteComment
+	}
+
+	return nil
+}
+
+func (s *taskCommentService) getComment(ctx context.Context, taskId int, commentId int) (dto.CommentResponse, error) {
+	comment, err := s.taskCommentRepo.GetCommentById(ctx, nil, taskId, commentId)
+	if err != nil {
+		return dto.CommentResponse{}, dto.ErrCommentNotFound
+	}
+
+	return toCommentResponse(comment), nil
+}
+
+// saveMentions resolves the @handles in the comment body against the members
+// of the task's team. Handles that match nobody, or more than one member,
+// are ignored. It returns the users that were mentioned.
+func (s *taskCommentService) saveMentions(ctx context.Context, tx *gorm.DB, teamsID int, comment entity.TaskComment) ([]uuid.UUID, error) {
+	handles := helpers.ParseMentions(comment.Body)
+	if len(handles) == 0 {
+		return nil, s.taskCommentRepo.ReplaceMentions(ctx, tx, comment.ID, nil)
+	}
+
+	members, err := s.userTeamsRepo.GetUsersByTeamId(ctx, tx, uint(teamsID))
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[string][]uuid.UUID{}
+	for _, member := range members {
+		for _, handle := range helpers.MentionHandles(member.Name, member.Email) {
+			if ids := owners[handle]; len(ids) > 0 && ids[len(ids)-1] == member.ID {
+				continue
+			}
+			owners[handle] = append(owners[handle], member.ID)
+		}
+	}
+
+	var userIds []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, handle := range handles {
+		ids := owners[handle]
+		if len(ids) != 1 || seen[ids[0]] {
+			continue
+		}
+		seen[ids[0]] = true
+		userIds = append(userIds, ids[0])
+	}
+
+	return userIds, s.taskCommentRepo.ReplaceMentions(ctx, tx, comment.ID, userIds)
+}
+
+// buildCommentThreads nests replies under their parents. A deleted comment
+// is kept, with its body hidden, only while it still has visible replies.
+func buildCommentThreads(comments []entity.TaskComment) []dto.CommentResponse {
+	children := map[int][]entity.TaskComment{}
+	var roots []entity.TaskComment
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+			continue
+		}
+		children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+	}
+
+	var build func(comment entity.TaskComment) (dto.CommentResponse, bool)
+	build = func(comment entity.TaskComment) (dto.CommentResponse, bool) {
+		response := toCommentResponse(comment)
+		for _, child := range children[comment.ID] {
+			if reply, ok := build(child); ok {
+				response.Replies = append(response.Replies, reply)
+			}
+		}
+
+		if comment.DeletedAt.Valid {
+			if len(response.Replies) == 0 {
+				return dto.CommentResponse{}, false
+			}
+			response.Body = ""
+			response.IsDeleted = true
+			response.Mentions = []dto.UserResponse{}
+		}
+
+		return response, true
+	}
+
+	threads := []dto.CommentResponse{}
+	for _, root := range roots {
+		if thread, ok := build(root); ok {
+			threads = append(threads, thread)
+		}
+	}
+
+	return threads
+}
+
+func toCommentResponse(comment entity.TaskComment) dto.CommentResponse {
+	mentions := []dto.UserResponse{}
+	for _, mention := range comment.Mentions {
+		mentions = append(mentions, toUserResponse(mention.User))
+	}
+
+	return dto.CommentResponse{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		ParentID:  comment.ParentID,
+		Body:      comment.Body,
+		Author:    toUserResponse(comment.User),
+		Mentions:  mentions,
+		Replies:   []dto.CommentResponse{},
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
+
+func toUserResponse(user entity.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:         user.ID.String(),
+		Name:       user.Name,
+		Email:      user.Email,
+		TelpNumber: user.TelpNumber,
+		Role:       user.Role,
+		ImageUrl:   user.ImageUrl,
+		IsVerified: user.IsVerified,
+	}
+}
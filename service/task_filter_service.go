@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	TASK_ASSIGNEE_ME   = "me"
+	TASK_ASSIGNEE_NONE = "none"
+)
+
+// ParseTaskFilter validates the task list query. Lists are comma separated,
+// "assignee" takes a user id, "me" or "none", "label" takes label ids that
+// must all be on a task, "priority" takes priority names, the effort bounds
+// take non-negative numbers, due dates accept RFC3339 or YYYY-MM-DD, and
+// "sort" takes whitelisted fields with an optional "-" for descending order.
+func ParseTaskFilter(req dto.TaskListRequest, userId string) (dto.TaskFilter, error) {
+	filter := dto.TaskFilter{
+		Statuses: splitList(req.Status),
+		TeamID:   req.TeamID,
+	}
+
+	switch assignee := strings.ToLower(strings.TrimSpace(req.Assignee)); assignee {
+	case "":
+	case TASK_ASSIGNEE_NONE:
+		filter.Unassigned = true
+	case TASK_ASSIGNEE_ME:
+		id, err := uuid.Parse(userId)
+		if err != nil {
+			return dto.TaskFilter{}, dto.ErrUserNotFound
+		}
+		filter.AssigneeID = &id
+	default:
+		id, err := uuid.Parse(assignee)
+		if err != nil {
+			return dto.TaskFilter{}, fmt.Errorf("%w: assignee must be a user id, %q or %q", dto.ErrInvalidTaskFilter, TASK_ASSIGNEE_ME, TASK_ASSIGNEE_NONE)
+		}
+		filter.AssigneeID = &id
+	}
+
+	for _, label := range splitList(req.Label) {
+		id, err := strconv.Atoi(label)
+		if err != nil {
+			return dto.TaskFilter{}, fmt.Errorf("%w: label must be a list of label ids", dto.ErrInvalidTaskFilter)
+		}
+		filter.LabelIDs = append(filter.LabelIDs, id)
+	}
+
+	for _, priority := range splitList(req.Priority) {
+		rank, err := ParseTaskPriority(priority)
+		if err != nil {
+			return dto.TaskFilter{}, fmt.Errorf("%w: %s", dto.ErrInvalidTaskFilter, err.Error())
+		}
+		filter.Priorities = append(filter.Priorities, rank)
+	}
+
+	var err error
+	if filter.MinEstimate, err = parseFilterEffort("min_estimate", req.MinEstimate); err != nil {
+		return dto.TaskFilter{}, err
+	}
+	if filter.MaxEstimate, err = parseFilterEffort("max_estimate", req.MaxEstimate); err != nil {
+		return dto.TaskFilter{}, err
+	}
+	if filter.MinRemaining, err = parseFilterEffort("min_remaining", req.MinRemaining); err != nil {
+		return dto.TaskFilter{}, err
+	}
+	if filter.MaxRemaining, err = parseFilterEffort("max_remaining", req.MaxRemaining); err != nil {
+		return dto.TaskFilter{}, err
+	}
+
+	if filter.DueBefore, err = parseFilterTime("due_before", req.DueBefore); err != nil {
+		return dto.TaskFilter{}, err
+	}
+	if filter.DueAfter, err = parseFilterTime("due_after", req.DueAfter); err != nil {
+		return dto.TaskFilter{}, err
+	}
+
+	for _, field := range splitList(req.Sort) {
+		sort := dto.TaskSort{Field: strings.ToLower(field)}
+		if strings.HasPrefix(sort.Field, "-") {
+			sort.Field = strings.TrimPrefix(sort.Field, "-")
+			sort.Desc = true
+		} else {
+			sort.Field = strings.TrimPrefix(sort.Field, "+")
+		}
+
+		if _, ok := repository.TaskSortColumns[sort.Field]; !ok {
+			return dto.TaskFilter{}, fmt.Errorf("%w: cannot sort by %q", dto.ErrInvalidTaskFilter, sort.Field)
+		}
+
+		filter.Sort = append(filter.Sort, sort)
+	}
+
+	return filter, nil
+}
+
+func parseFilterTime(name string, value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s must be RFC3339 or YYYY-MM-DD", dto.ErrInvalidTaskFilter, name)
+}
+
+func parseFilterEffort(name string, value string) (*int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	effort, err := strconv.Atoi(value)
+	if err != nil || effort < 0 {
+		return nil, fmt.Errorf("%w: %s must be a non-negative number", dto.ErrInvalidTaskFilter, name)
+	}
+
+	return &effort, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"gorm.io/gorm"
+)
+
+type (
+	TaskLinkService interface {
+		Create(ctx context.Context, taskId string, userId string, req dto.TaskLinkCreateRequest) (dto.TaskLinkResponse, error)
+		GetLinksByTaskId(ctx context.Context, taskId string) ([]dto.TaskLinkResponse, error)
+		Delete(ctx context.Context, taskId string, linkId int) error
+	}
+
+	taskLinkService struct {
+		transactor      repository.Transactor
+		taskLinkRepo    repository.TaskLinkRepository
+		taskRepo        repository.TaskRepository
+		workflowService WorkflowService
+	}
+)
+
+func NewTaskLinkService(transactor repository.Transactor, taskLinkRepo repository.TaskLinkRepository, taskRepo repository.TaskRepository, workflowService WorkflowService) TaskLinkService {
+	return &taskLinkService{
+		transactor:      transactor,
+		taskLinkRepo:    taskLinkRepo,
+		taskRepo:        taskRepo,
+		workflowService: workflowService,
+	}
+}
+
+// Create links the task to another task of the same team. Inverse types are
+// stored as their forward form, so "A blocked_by B" is saved as "B blocks A".
+func (s *taskLinkService) Create(ctx context.Context, taskId string, userId string, req dto.TaskLinkCreateRequest) (dto.TaskLinkResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.TaskLinkResponse{}, dto.ErrTaskNotFound
+	}
+
+	other, err := s.taskRepo.GetTaskById(ctx, nil, strconv.Itoa(req.TaskID))
+	if err != nil {
+		return dto.TaskLinkResponse{}, dto.ErrLinkedTaskNotFound
+	}
+
+	if other.ID == task.ID {
+		return dto.TaskLinkResponse{}, dto.ErrTaskLinkSelf
+	}
+	if other.TeamsID != task.TeamsID {
+		return dto.TaskLinkResponse{}, dto.ErrLinkedTaskOtherTeam
+	}
+
+	link := entity.TaskLink{SourceTaskID: task.ID, TargetTaskID: other.ID, Type: req.Type, CreatedBy: parseActor(userId)}
+	switch req.Type {
+	case constants.ENUM_TASK_LINK_BLOCKS, constants.ENUM_TASK_LINK_RELATES_TO, constants.ENUM_TASK_LINK_DUPLICATES:
+	case constants.ENUM_TASK_LINK_BLOCKED_BY:
+		link = entity.TaskLink{SourceTaskID: other.ID, TargetTaskID: task.ID, Type: constants.ENUM_TASK_LINK_BLOCKS, CreatedBy: link.CreatedBy}
+	case constants.ENUM_TASK_LINK_DUPLICATED_BY:
+		link = entity.TaskLink{SourceTaskID: other.ID, TargetTaskID: task.ID, Type: constants.ENUM_TASK_LINK_DUPLICATES, CreatedBy: link.CreatedBy}
+	default:
+		return dto.TaskLinkResponse{}, dto.ErrInvalidTaskLinkType
+	}
+
+	// The checks below only hold while no other link of the team is added.
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.taskLinkRepo.LockLinks(ctx, tx, task.TeamsID); err != nil {
+			return dto.ErrCreateTaskLink
+		}
+
+		exists, err := s.taskLinkRepo.LinkExists(ctx, tx, link.SourceTaskID, link.TargetTaskID, link.Type)
+		if err == nil && !exists && link.Type == constants.ENUM_TASK_LINK_RELATES_TO {
+			exists, err = s.taskLinkRepo.LinkExists(ctx, tx, link.TargetTaskID, link.SourceTaskID, link.Type)
+		}
+		if err != nil {
+			return dto.ErrCreateTaskLink
+		}
+		if exists {
+			return dto.ErrTaskLinkExists
+		}
+
+		if link.Type == constants.ENUM_TASK_LINK_BLOCKS {
+			cycle, err := s.blocks(ctx, tx, link.TargetTaskID, link.SourceTaskID)
+			if err != nil {
+				return dto.ErrCreateTaskLink
+			}
+			if cycle {
+				return dto.ErrTaskLinkCycle
+			}
+		}
+
+		link, err = s.taskLinkRepo.CreateLink(ctx, tx, link)
+		if err != nil {
+			return dto.ErrCreateTaskLink
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.TaskLinkResponse{}, err
+	}
+
+	link.SourceTask, link.TargetTask = task, other
+	if link.SourceTaskID != task.ID {
+		link.SourceTask, link.TargetTask = other, task
+	}
+
+	return toTaskLinkResponse(link, task.ID, newDoneChecker(s.workflowService).isDone(ctx, other.TeamsID, other.Status)), nil
+}
+
+func (s *taskLinkService) GetLinksByTaskId(ctx context.Context, taskId string) ([]dto.TaskLinkResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	links, err := s.taskLinkRepo.GetLinksByTaskId(ctx, nil, task.ID)
+	if err != nil {
+		return nil, dto.ErrGetAllTaskLink
+	}
+
+	done := newDoneChecker(s.workflowService)
+	responses := []dto.TaskLinkResponse{}
+	for _, link := range links {
+		other := link.TargetTask
+		if link.SourceTaskID != task.ID {
+			other = link.SourceTask
+		}
+		// The other task has been deleted.
+		if other.ID == 0 {
+			continue
+		}
+
+		responses = append(responses, toTaskLinkResponse(link, task.ID, done.isDone(ctx, other.TeamsID, other.Status)))
+	}
+
+	return responses, nil
+}
+
+func (s *taskLinkService) Delete(ctx context.Context, taskId string, linkId int) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	link, err := s.taskLinkRepo.GetLinkById(ctx, nil, task.ID, linkId)
+	if err != nil {
+		return dto.ErrTaskLinkNotFound
+	}
+
+	if err := s.taskLinkRepo.DeleteLink(ctx, nil, link.ID); err != nil {
+		return dto.ErrDeleteTaskLink
+	}
+
+	return nil
+}
+
+// blocks reports whether from blocks to, directly or through other tasks.
+func (s *taskLinkService) blocks(ctx context.Context, tx *gorm.DB, from int, to int) (bool, error) {
+	visited := map[int]bool{from: true}
+	frontier := []int{from}
+	for len(frontier) > 0 {
+		blocked, err := s.taskLinkRepo.GetBlockedTaskIds(ctx, tx, frontier)
+		if err != nil {
+			return false, err
+		}
+
+		frontier = nil
+		for _, id := range blocked {
+			if id == to {
+				return true, nil
+			}
+			if !visited[id] {
+				visited[id] = true
+				frontier = append(frontier, id)
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// ensureUnblocked refuses to let a task reach a done status while any task
+// blocking it is not done itself.
+func ensureUnblocked(ctx context.Context, taskLinkRepo repository.TaskLinkRepository, done *doneChecker, taskId int) error {
+	blockers, err := taskLinkRepo.GetBlockers(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrCheckTaskBlockers
+	}
+
+	var open []string
+	for _, blocker := range blockers {
+		if !done.isDone(ctx, blocker.TeamsID, blocker.Status) {
+			open = append(open, "#"+strconv.Itoa(blocker.ID))
+		}
+	}
+
+	if len(open) > 0 {
+		return fmt.Errorf("%w: %s", dto.ErrTaskBlocked, strings.Join(open, ", "))
+	}
+
+	return nil
+}
+
+// toTaskLinkResponse describes link from the side of taskId.
+func toTaskLinkResponse(link entity.TaskLink, taskId int, otherDone bool) dto.TaskLinkResponse {
+	linkType, other := link.Type, link.TargetTask
+	if link.SourceTaskID != taskId {
+		other = link.SourceTask
+		switch link.Type {
+		case constants.ENUM_TASK_LINK_BLOCKS:
+			linkType = constants.ENUM_TASK_LINK_BLOCKED_BY
+		case constants.ENUM_TASK_LINK_DUPLICATES:
+			linkType = constants.ENUM_TASK_LINK_DUPLICATED_BY
+		}
+	}
+
+	return dto.TaskLinkResponse{
+		ID:   link.ID,
+		Type: linkType,
+		Task: dto.TaskLinkTask{
+			ID:     other.ID,
+			Title:  other.Title,
+			Status: other.Status,
+			IsDone: otherDone,
+		},
+		CreatedBy: link.CreatedBy,
+		CreatedAt: link.CreatedAt,
+	}
+}
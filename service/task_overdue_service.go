@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+// doneChecker tells whether statuses are in the done category, resolving
+// each team/status pair against the workflow only once.
+type doneChecker struct {
+	workflowService WorkflowService
+	done            map[string]bool
+}
+
+func newDoneChecker(workflowService WorkflowService) *doneChecker {
+	return &doneChecker{
+		workflowService: workflowService,
+		done:            map[string]bool{},
+	}
+}
+
+// isDone reports whether status is a done status of the team's workflow. A
+// status the workflow no longer knows counts as not done.
+func (c *doneChecker) isDone(ctx context.Context, teamsID int, status string) bool {
+	key := fmt.Sprintf("%d/%s", teamsID, status)
+	done, ok := c.done[key]
+	if !ok {
+		category, _ := c.workflowService.GetStatusCategory(ctx, teamsID, status)
+		done = category == constants.ENUM_STATUS_CATEGORY_DONE
+		c.done[key] = done
+	}
+
+	return done
+}
+
+// overdueChecker works out dto.TaskResponse.IsOverdue for a batch of tasks.
+type overdueChecker struct {
+	*doneChecker
+	now time.Time
+}
+
+func newOverdueChecker(workflowService WorkflowService) *overdueChecker {
+	return &overdueChecker{
+		doneChecker: newDoneChecker(workflowService),
+		now:         time.Now(),
+	}
+}
+
+// isOverdue reports whether task is past its due date without being in a
+// done status.
+func (c *overdueChecker) isOverdue(ctx context.Context, task entity.Task) bool {
+	if task.DueDate.IsZero() || !task.DueDate.Before(c.now) {
+		return false
+	}
+
+	return !c.isDone(ctx, task.TeamsID, task.Status)
+}
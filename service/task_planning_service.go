@@ -0,0 +1,70 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+)
+
+// taskPriorities lists the priorities from lowest to highest. A priority's
+// rank, which is what a task stores, is its index plus one.
+var taskPriorities = []string{
+	constants.ENUM_TASK_PRIORITY_LOW,
+	constants.ENUM_TASK_PRIORITY_MEDIUM,
+	constants.ENUM_TASK_PRIORITY_HIGH,
+	constants.ENUM_TASK_PRIORITY_URGENT,
+}
+
+// ParseTaskPriority returns the rank of a priority name.
+func ParseTaskPriority(name string) (int, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, priority := range taskPriorities {
+		if priority == name {
+			return i + 1, nil
+		}
+	}
+	return 0, dto.ErrInvalidTaskPriority
+}
+
+// TaskPriorityName returns the name of a priority rank, treating unknown
+// ranks as medium.
+func TaskPriorityName(rank int) string {
+	if rank < 1 || rank > len(taskPriorities) {
+		return constants.ENUM_TASK_PRIORITY_MEDIUM
+	}
+	return taskPriorities[rank-1]
+}
+
+// applyPlanning validates the requested priority and effort and sets them on
+// task. Empty or nil values leave the task's current value. The remaining
+// effort follows the estimate while the task had no estimate of its own.
+func applyPlanning(task *entity.Task, priority string, estimate *int, remaining *int) error {
+	if priority != "" {
+		rank, err := ParseTaskPriority(priority)
+		if err != nil {
+			return err
+		}
+		task.Priority = rank
+	}
+	if task.Priority == 0 {
+		task.Priority, _ = ParseTaskPriority(constants.ENUM_TASK_PRIORITY_MEDIUM)
+	}
+
+	if (estimate != nil && *estimate < 0) || (remaining != nil && *remaining < 0) {
+		return dto.ErrInvalidTaskEstimate
+	}
+
+	if estimate != nil {
+		if remaining == nil && task.Estimate == 0 {
+			task.RemainingEffort = *estimate
+		}
+		task.Estimate = *estimate
+	}
+	if remaining != nil {
+		task.RemainingEffort = *remaining
+	}
+
+	return nil
+}
@@ -2,101 +2,264 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"log"
+	"strconv"
 	"time"
 
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/realtime"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type (
 	TaskService interface {
-		Register(ctx context.Context, req dto.TaskCreateRequest) (dto.TaskResponse, error)
-		GetAllTaskWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.TaskPaginationResponse, error)
+		Register(ctx context.Context, req dto.TaskCreateRequest, userId string) (dto.TaskResponse, error)
+		GetAllTaskWithPagination(ctx context.Context, req dto.TaskListRequest, userId string) (dto.TaskPaginationResponse, error)
+		GetOverdueTasks(ctx context.Context, req dto.TaskListRequest, userId string) (dto.TaskPaginationResponse, error)
 		GetTaskById(ctx context.Context, taskId string) (dto.TaskResponse, error)
-		GetTasksByTeamID(ctx context.Context, teamsID int) ([]dto.TaskResponse, error)
-		Update(ctx context.Context, req dto.TaskUpdateRequest, taskId string) (dto.TaskUpdateResponse, error)
-		Delete(ctx context.Context, taskId string) error
-		AssignUserToTask(ctx context.Context, taskId string, userID *uuid.UUID) error
-		RemoveUserFromTask(ctx context.Context, taskId string) error
+		GetTasksByTeamID(ctx context.Context, teamsID int, req dto.TaskListRequest, userId string) ([]dto.TaskResponse, error)
+		Update(ctx context.Context, req dto.TaskUpdateRequest, taskId string, userId string) (dto.TaskUpdateResponse, error)
+		Delete(ctx context.Context, taskId string, actorId string) error
+		AssignUserToTask(ctx context.Context, taskId string, userID *uuid.UUID, actorId string) error
+		RemoveUserFromTask(ctx context.Context, taskId string, actorId string) error
 		GetAssignedUser(ctx context.Context, taskId string) (dto.UserResponse, error)
-		GetTasksByUserID(ctx context.Context, userID string) ([]dto.TaskResponse, error)
+		GetTasksByUserID(ctx context.Context, userID string, actorId string) ([]dto.TaskResponse, error)
+		ReassignUserTasks(ctx context.Context, tx *gorm.DB, teamsID int, fromUserID uuid.UUID, toUserID *uuid.UUID, actorId string) (func(), error)
+		GetTaskHistory(ctx context.Context, taskId string, req dto.PaginationRequest) (dto.TaskEventPaginationResponse, error)
+		GetSubtasks(ctx context.Context, taskId string) ([]dto.TaskResponse, error)
+		MoveTask(ctx context.Context, taskId string, req dto.TaskMoveRequest, userId string) (dto.TaskResponse, error)
+		ReorderSubtasks(ctx context.Context, taskId string, req dto.TaskReorderRequest) ([]dto.TaskResponse, error)
+		GetAssignees(ctx context.Context, taskId string) ([]dto.TaskAssigneeResponse, error)
+		AddAssignee(ctx context.Context, taskId string, req dto.TaskAssigneeRequest, actorId string) ([]dto.TaskAssigneeResponse, error)
+		RemoveAssignee(ctx context.Context, taskId string, userId string, actorId string) error
+		WatchTask(ctx context.Context, taskId string, userId string) error
+		UnwatchTask(ctx context.Context, taskId string, userId string) error
 	}
 
 	taskService struct {
-		taskRepo repository.TaskRepository
-		userRepo repository.UserRepository
+		transactor            repository.Transactor
+		taskRepo              repository.TaskRepository
+		userRepo              repository.UserRepository
+		userTeamsRepo         repository.UserTeamsRepository
+		taskEventRepo         repository.TaskEventRepository
+		taskChecklistRepo     repository.TaskChecklistRepository
+		taskLinkRepo          repository.TaskLinkRepository
+		taskAssigneeRepo      repository.TaskAssigneeRepository
+		labelRepo             repository.LabelRepository
+		authorizationService  AuthorizationService
+		workflowService       WorkflowService
+		taskAttachmentService TaskAttachmentService
+		notificationService   NotificationService
+		webhookService        WebhookService
+		hub                   *realtime.Hub
+	}
+
+	// TaskServiceDeps are the collaborators that react to task changes:
+	// History records them, Notifications tells the people involved, and
+	// Webhooks and Hub announce them to the team. Leaving one nil turns that
+	// reaction off, so tests only wire up the ones they check. GetTaskHistory
+	// needs History.
+	TaskServiceDeps struct {
+		History       repository.TaskEventRepository
+		Notifications NotificationService
+		Webhooks      WebhookService
+		Hub           *realtime.Hub
 	}
 )
 
-func NewTaskService(taskRepo repository.TaskRepository, userRepo repository.UserRepository) TaskService {
+func NewTaskService(transactor repository.Transactor, taskRepo repository.TaskRepository, userRepo repository.UserRepository, userTeamsRepo repository.UserTeamsRepository, taskChecklistRepo repository.TaskChecklistRepository, taskLinkRepo repository.TaskLinkRepository, taskAssigneeRepo repository.TaskAssigneeRepository, labelRepo repository.LabelRepository, authorizationService AuthorizationService, workflowService WorkflowService, taskAttachmentService TaskAttachmentService, deps TaskServiceDeps) TaskService {
 	return &taskService{
-		taskRepo: taskRepo,
-		userRepo: userRepo,
+		transactor:            transactor,
+		taskRepo:              taskRepo,
+		userRepo:              userRepo,
+		userTeamsRepo:         userTeamsRepo,
+		taskEventRepo:         deps.History,
+		taskChecklistRepo:     taskChecklistRepo,
+		taskLinkRepo:          taskLinkRepo,
+		taskAssigneeRepo:      taskAssigneeRepo,
+		labelRepo:             labelRepo,
+		authorizationService:  authorizationService,
+		workflowService:       workflowService,
+		taskAttachmentService: taskAttachmentService,
+		notificationService:   deps.Notifications,
+		webhookService:        deps.Webhooks,
+		hub:                   deps.Hub,
 	}
 }
 
-func (s *taskService) Register(ctx context.Context, req dto.TaskCreateRequest) (dto.TaskResponse, error) {
+func (s *taskService) Register(ctx context.Context, req dto.TaskCreateRequest, userId string) (dto.TaskResponse, error) {
+	if err := s.authorizationService.AuthorizeTeam(ctx, userId, req.TeamsID, constants.ACTION_TASK_CREATE); err != nil {
+		return dto.TaskResponse{}, err
+	}
+
 	dueDate, err := time.Parse(time.RFC3339, req.DueDate)
 	if err != nil {
 		return dto.TaskResponse{}, err
 	}
 
+	status, err := s.workflowService.ValidateStatus(ctx, req.TeamsID, req.Status)
+	if err != nil {
+		return dto.TaskResponse{}, err
+	}
+
 	task := entity.Task{
 		Title:       req.Title,
 		Description: req.Description,
-		Status:      req.Status,
+		Status:      status,
 		DueDate:     dueDate,
 		TeamsID:     req.TeamsID,
 	}
 
+	if err := applyPlanning(&task, req.Priority, req.Estimate, req.RemainingEffort); err != nil {
+		return dto.TaskResponse{}, err
+	}
+
 	if req.UserID != nil {
+		if err := s.ensureTeamMember(ctx, req.TeamsID, *req.UserID); err != nil {
+			return dto.TaskResponse{}, err
+		}
 		task.UserID = req.UserID
 	}
 
-	taskReg, err := s.taskRepo.RegisterTask(ctx, nil, task)
+	if req.ParentID != nil {
+		if err := s.validateParent(ctx, task, *req.ParentID); err != nil {
+			return dto.TaskResponse{}, err
+		}
+		if task.Position, err = s.nextPosition(ctx, *req.ParentID); err != nil {
+			return dto.TaskResponse{}, dto.ErrCreateTask
+		}
+		task.ParentID = req.ParentID
+	}
+
+	var taskReg entity.Task
+	var events []entity.TaskEvent
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		created, err := s.taskRepo.RegisterTask(ctx, tx, task)
+		if err != nil {
+			return dto.ErrCreateTask
+		}
+		taskReg = created
+
+		events = []entity.TaskEvent{{TaskID: taskReg.ID, UserID: parseActor(userId), Type: constants.ENUM_TASK_EVENT_CREATED}}
+		if taskReg.UserID != nil {
+			if err := s.taskAssigneeRepo.AddAssignee(ctx, tx, entity.TaskAssignee{TaskID: taskReg.ID, UserID: *taskReg.UserID, Role: constants.ENUM_TASK_ROLE_ASSIGNEE}); err != nil {
+				log.Printf("Failed to add assignee of task %d: %v", taskReg.ID, err)
+				return dto.ErrCreateTask
+			}
+			events = append(events, assignmentEvent(taskReg.ID, nil, taskReg.UserID, userId))
+		}
+
+		return s.recordEvents(ctx, tx, events)
+	})
 	if err != nil {
-		return dto.TaskResponse{}, dto.ErrCreateTask
+		return dto.TaskResponse{}, err
 	}
+	s.announce(ctx, taskReg, events)
 
 	return dto.TaskResponse{
-		ID:          taskReg.ID,
-		Title:       taskReg.Title,
-		Description: taskReg.Description,
-		Status:      taskReg.Status,
-		DueDate:     taskReg.DueDate,
-		UserID:      taskReg.UserID,
+		ID:              taskReg.ID,
+		Title:           taskReg.Title,
+		Description:     taskReg.Description,
+		Status:          taskReg.Status,
+		DueDate:         taskReg.DueDate,
+		IsOverdue:       newOverdueChecker(s.workflowService).isOverdue(ctx, taskReg),
+		UserID:          taskReg.UserID,
+		ParentID:        taskReg.ParentID,
+		Priority:        TaskPriorityName(taskReg.Priority),
+		PriorityRank:    taskReg.Priority,
+		Estimate:        taskReg.Estimate,
+		RemainingEffort: taskReg.RemainingEffort,
 	}, nil
 }
 
-func (s *taskService) GetAllTaskWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.TaskPaginationResponse, error) {
-	dataWithPaginate, err := s.taskRepo.GetAllTaskWithPagination(ctx, nil, req)
+func (s *taskService) GetAllTaskWithPagination(ctx context.Context, req dto.TaskListRequest, userId string) (dto.TaskPaginationResponse, error) {
+	filter, err := ParseTaskFilter(req, userId)
+	if err != nil {
+		return dto.TaskPaginationResponse{}, err
+	}
+	if err := s.scopeToMember(ctx, &filter, userId); err != nil {
+		return dto.TaskPaginationResponse{}, err
+	}
+
+	return s.listTasks(ctx, req.PaginationRequest, filter)
+}
+
+// GetOverdueTasks lists open tasks whose due date has passed. It accepts the
+// same filters as GetAllTaskWithPagination.
+func (s *taskService) GetOverdueTasks(ctx context.Context, req dto.TaskListRequest, userId string) (dto.TaskPaginationResponse, error) {
+	filter, err := ParseTaskFilter(req, userId)
 	if err != nil {
 		return dto.TaskPaginationResponse{}, err
 	}
+	if err := s.scopeToMember(ctx, &filter, userId); err != nil {
+		return dto.TaskPaginationResponse{}, err
+	}
+
+	now := time.Now()
+	if filter.DueBefore == nil || filter.DueBefore.After(now) {
+		filter.DueBefore = &now
+	}
+	filter.OpenOnly = true
+	filter.DefaultDoneStatuses = DefaultDoneStatuses()
 
+	return s.listTasks(ctx, req.PaginationRequest, filter)
+}
+
+// scopeToMember limits a list to the teams userId belongs to, unless they
+// are an admin.
+func (s *taskService) scopeToMember(ctx context.Context, filter *dto.TaskFilter, userId string) error {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.ErrUserNotFound
+	}
+
+	if user.Role != constants.ENUM_ROLE_ADMIN {
+		filter.MemberID = &user.ID
+	}
+	return nil
+}
+
+func (s *taskService) listTasks(ctx context.Context, req dto.PaginationRequest, filter dto.TaskFilter) (dto.TaskPaginationResponse, error) {
+	dataWithPaginate, err := s.taskRepo.GetAllTaskWithPagination(ctx, nil, req, filter)
+	if err != nil {
+		return dto.TaskPaginationResponse{}, err
+	}
+
+	labels, err := taskLabelsOf(ctx, s.labelRepo, dataWithPaginate.Tasks)
+	if err != nil {
+		return dto.TaskPaginationResponse{}, err
+	}
+
+	overdue := newOverdueChecker(s.workflowService)
 	var tasks []dto.TaskResponse
 	for _, task := range dataWithPaginate.Tasks {
 		tasks = append(tasks, dto.TaskResponse{
-			ID:          task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			Status:      task.Status,
-			DueDate:     task.DueDate,
-			UserID:      task.UserID,
+			ID:              task.ID,
+			Title:           task.Title,
+			Description:     task.Description,
+			Status:          task.Status,
+			DueDate:         task.DueDate,
+			IsOverdue:       overdue.isOverdue(ctx, task),
+			UserID:          task.UserID,
+			ParentID:        task.ParentID,
+			SprintID:        task.SprintID,
+			Priority:        TaskPriorityName(task.Priority),
+			PriorityRank:    task.Priority,
+			Estimate:        task.Estimate,
+			RemainingEffort: task.RemainingEffort,
+			Labels:          labels[task.ID],
 		})
 	}
 
 	return dto.TaskPaginationResponse{
 		Data: tasks,
-		PaginationResponse: dto.PaginationResponse{
-			Page:    dataWithPaginate.Page,
-			PerPage: dataWithPaginate.PerPage,
-			MaxPage: dataWithPaginate.MaxPage,
-			Count:   dataWithPaginate.Count,
-		},
+		PaginationResponse: dataWithPaginate.PaginationResponse,
 	}, nil
 }
 
@@ -124,28 +287,77 @@ func (s *taskService) GetTaskById(ctx context.Context, taskId string) (dto.TaskR
 		}
 	}
 
+	progress, err := s.progress(ctx, []entity.Task{task})
+	if err != nil {
+		return dto.TaskResponse{}, dto.ErrGetTaskById
+	}
+
+	assignees, err := s.assigneesOf(ctx, []entity.Task{task})
+	if err != nil {
+		return dto.TaskResponse{}, dto.ErrGetTaskById
+	}
+
+	labels, err := taskLabelsOf(ctx, s.labelRepo, []entity.Task{task})
+	if err != nil {
+		return dto.TaskResponse{}, dto.ErrGetTaskById
+	}
+
 	return dto.TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      task.Status,
-		DueDate:     task.DueDate,
-		TeamsID:     task.TeamsID,
-		UserID:      task.UserID,
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
-		User:        userResponse,
+		ID:              task.ID,
+		Title:           task.Title,
+		Description:     task.Description,
+		Status:          task.Status,
+		DueDate:         task.DueDate,
+		IsOverdue:       newOverdueChecker(s.workflowService).isOverdue(ctx, task),
+		TeamsID:         task.TeamsID,
+		UserID:          task.UserID,
+		CreatedAt:       task.CreatedAt,
+		UpdatedAt:       task.UpdatedAt,
+		User:            userResponse,
+		ParentID:        task.ParentID,
+		SprintID:        task.SprintID,
+		Priority:        TaskPriorityName(task.Priority),
+		PriorityRank:    task.Priority,
+		Estimate:        task.Estimate,
+		RemainingEffort: task.RemainingEffort,
+		Progress:        progress[task.ID],
+		Assignees:       toTaskAssigneeResponses(assignees[task.ID]),
+		Labels:          labels[task.ID],
 	}, nil
 }
 
 
-func (s *taskService) GetTasksByTeamID(ctx context.Context, teamsID int) ([]dto.TaskResponse, error) {
+// GetTasksByTeamID lists every task of the team. It accepts the filters and
+// sort of GetAllTaskWithPagination, except that the team comes from teamsID
+// and the list is not paginated.
+func (s *taskService) GetTasksByTeamID(ctx context.Context, teamsID int, req dto.TaskListRequest, userId string) ([]dto.TaskResponse, error) {
+    req.TeamID = 0
+    filter, err := ParseTaskFilter(req, userId)
+    if err != nil {
+        return nil, err
+    }
 
-    tasks, err := s.taskRepo.GetTasksByTeamID(ctx, nil, teamsID)
+    tasks, err := s.taskRepo.GetTasksByTeamID(ctx, nil, teamsID, filter)
     if err != nil {
         return nil, err
     }
 
+    progress, err := s.progress(ctx, tasks)
+    if err != nil {
+        return nil, err
+    }
+
+    assignees, err := s.assigneesOf(ctx, tasks)
+    if err != nil {
+        return nil, err
+    }
+
+    labels, err := taskLabelsOf(ctx, s.labelRepo, tasks)
+    if err != nil {
+        return nil, err
+    }
+
+    overdue := newOverdueChecker(s.workflowService)
     var taskResponses []dto.TaskResponse
     for _, task := range tasks {
     
@@ -169,14 +381,24 @@ func (s *taskService) GetTasksByTeamID(ctx context.Context, teamsID int) ([]dto.
 
     
         taskResponses = append(taskResponses, dto.TaskResponse{
-            ID:          task.ID,
-            Title:       task.Title,
-            Description: task.Description,
-            Status:      task.Status,
-            DueDate:     task.DueDate,
-            TeamsID:     task.TeamsID,
-            UserID:      task.UserID,
-            User:        userResponse,
+            ID:              task.ID,
+            Title:           task.Title,
+            Description:     task.Description,
+            Status:          task.Status,
+            DueDate:         task.DueDate,
+            IsOverdue:       overdue.isOverdue(ctx, task),
+            TeamsID:         task.TeamsID,
+            UserID:          task.UserID,
+            User:            userResponse,
+            ParentID:        task.ParentID,
+            SprintID:        task.SprintID,
+            Priority:        TaskPriorityName(task.Priority),
+            PriorityRank:    task.Priority,
+            Estimate:        task.Estimate,
+            RemainingEffort: task.RemainingEffort,
+            Progress:        progress[task.ID],
+            Assignees:       toTaskAssigneeResponses(assignees[task.ID]),
+            Labels:          labels[task.ID],
         })
     }
 
@@ -184,81 +406,204 @@ func (s *taskService) GetTasksByTeamID(ctx context.Context, teamsID int) ([]dto.
 }
 
 
-func (s *taskService) Update(ctx context.Context, req dto.TaskUpdateRequest, taskId string) (dto.TaskUpdateResponse, error) {
+func (s *taskService) Update(ctx context.Context, req dto.TaskUpdateRequest, taskId string, userId string) (dto.TaskUpdateResponse, error) {
 	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
 	if err != nil {
 		return dto.TaskUpdateResponse{}, dto.ErrTaskNotFound
 	}
 
+	if req.UserID != nil && (task.UserID == nil || *task.UserID != *req.UserID) {
+		if err := s.authorizationService.AuthorizeTeam(ctx, userId, task.TeamsID, constants.ACTION_TASK_REASSIGN); err != nil {
+			return dto.TaskUpdateResponse{}, err
+		}
+		if err := s.ensureTeamMember(ctx, task.TeamsID, *req.UserID); err != nil {
+			return dto.TaskUpdateResponse{}, err
+		}
+	}
+
 	dueDate, err := time.Parse(time.RFC3339, req.DueDate)
 	if err != nil {
 		return dto.TaskUpdateResponse{}, err
 	}
 
+	status, err := s.workflowService.ValidateTransition(ctx, task.TeamsID, task.Status, req.Status)
+	if err != nil {
+		return dto.TaskUpdateResponse{}, err
+	}
+
+	// A task cannot be finished while something it is blocked by is open.
+	done := newDoneChecker(s.workflowService)
+	if status != task.Status && done.isDone(ctx, task.TeamsID, status) {
+		if err := ensureUnblocked(ctx, s.taskLinkRepo, done, task.ID); err != nil {
+			return dto.TaskUpdateResponse{}, err
+		}
+	}
+
 	data := entity.Task{
-		ID:          task.ID,
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      req.Status,
-		DueDate:     dueDate,
+		ID:              task.ID,
+		Title:           req.Title,
+		Description:     req.Description,
+		Status:          status,
+		DueDate:         dueDate,
+		Priority:        task.Priority,
+		Estimate:        task.Estimate,
+		RemainingEffort: task.RemainingEffort,
+	}
+
+	if err := applyPlanning(&data, req.Priority, req.Estimate, req.RemainingEffort); err != nil {
+		return dto.TaskUpdateResponse{}, err
 	}
 
 	if req.UserID != nil {
 		data.UserID = req.UserID
 	}
 
-	taskUpdate, err := s.taskRepo.UpdateTask(ctx, nil, data)
+	updated := task
+	updated.Title = data.Title
+	updated.Description = data.Description
+	updated.Status = data.Status
+	updated.DueDate = data.DueDate
+	updated.Priority = data.Priority
+	updated.Estimate = data.Estimate
+	updated.RemainingEffort = data.RemainingEffort
+	if data.UserID != nil {
+		updated.UserID = data.UserID
+	}
+	events := diffTask(task, data, userId)
+
+	var taskUpdate entity.Task
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		saved, err := s.taskRepo.UpdateTask(ctx, tx, data)
+		if err != nil {
+			return dto.ErrUpdateTask
+		}
+		taskUpdate = saved
+
+		// UpdateTask skips zero values, so the effort is written on its own to
+		// allow clearing an estimate.
+		if err := s.taskRepo.UpdateTaskPlanning(ctx, tx, data); err != nil {
+			return dto.ErrUpdateTask
+		}
+
+		if !sameUser(task.UserID, updated.UserID) {
+			if err := s.replacePrimary(ctx, tx, task, *updated.UserID); err != nil {
+				return dto.ErrUpdateTask
+			}
+		}
+
+		return s.recordEvents(ctx, tx, events)
+	})
 	if err != nil {
-		return dto.TaskUpdateResponse{}, dto.ErrUpdateTask
+		return dto.TaskUpdateResponse{}, err
 	}
+	s.announce(ctx, updated, events)
 
 	return dto.TaskUpdateResponse{
-		ID:          taskUpdate.ID,
-		Title:       taskUpdate.Title,
-		Description: taskUpdate.Description,
-		Status:      taskUpdate.Status,
-		DueDate:     taskUpdate.DueDate,
-		UserID:      taskUpdate.UserID,
+		ID:              taskUpdate.ID,
+		Title:           taskUpdate.Title,
+		Description:     taskUpdate.Description,
+		Status:          taskUpdate.Status,
+		DueDate:         taskUpdate.DueDate,
+		UserID:          taskUpdate.UserID,
+		Priority:        TaskPriorityName(data.Priority),
+		Estimate:        data.Estimate,
+		RemainingEffort: data.RemainingEffort,
 	}, nil
 }
 
-func (s *taskService) Delete(ctx context.Context, taskId string) error {
-	_, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+func (s *taskService) Delete(ctx context.Context, taskId string, actorId string) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
 	if err != nil {
 		return dto.ErrTaskNotFound
 	}
 
-	err = s.taskRepo.DeleteTask(ctx, nil, taskId)
+	actor := parseActor(actorId)
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.detachSubtasks(ctx, tx, task); err != nil {
+			return err
+		}
+
+		if err := s.taskRepo.DeleteTask(ctx, tx, taskId); err != nil {
+			return dto.ErrDeleteTask
+		}
+
+		if err := s.taskLinkRepo.DeleteLinksByTaskId(ctx, tx, task.ID); err != nil {
+			log.Printf("Failed to clean up links of task %d: %v", task.ID, err)
+			return dto.ErrDeleteTask
+		}
+
+		return s.recordEvents(ctx, tx, []entity.TaskEvent{{TaskID: task.ID, UserID: actor, Type: constants.ENUM_TASK_EVENT_DELETED}})
+	})
 	if err != nil {
-		return dto.ErrDeleteTask
+		return err
+	}
+
+	// Stored files cannot be rolled back, so they are only removed once the
+	// task is gone.
+	if err := s.taskAttachmentService.DeleteByTaskId(ctx, task.ID); err != nil {
+		log.Printf("Failed to clean up attachments of task %d: %v", task.ID, err)
 	}
 
+	s.publish(ctx, task.TeamsID, constants.ENUM_WEBHOOK_EVENT_TASK_DELETED, actor, taskChangeData(task, nil))
+
 	return nil
 }
 
-func (s *taskService) AssignUserToTask(ctx context.Context, taskId string, userID *uuid.UUID) error {
+// AssignUserToTask adds userID as an assignee. The first assignee of a
+// task also becomes its primary assignee, Task.UserID.
+func (s *taskService) AssignUserToTask(ctx context.Context, taskId string, userID *uuid.UUID, actorId string) error {
 	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
 	if err != nil {
 		return dto.ErrTaskNotFound
 	}
 
-	if task.UserID != nil {
-		return errors.New("task already assigned to another user")
+	if userID == nil {
+		return dto.ErrAssignUser
 	}
 
-	err = s.taskRepo.AssignUserToTask(ctx, nil, taskId, userID)
-	if err != nil {
+	if err := s.ensureTeamMember(ctx, task.TeamsID, *userID); err != nil {
+		return err
+	}
+
+	if err := s.changeRole(ctx, task, *userID, constants.ENUM_TASK_ROLE_ASSIGNEE, actorId); err != nil {
 		return dto.ErrAssignUser
 	}
 
 	return nil
 }
 
-func (s *taskService) RemoveUserFromTask(ctx context.Context, taskId string) error {
-	err := s.taskRepo.RemoveUserFromTask(ctx, nil, taskId)
+// RemoveUserFromTask takes the primary assignee off the task. The next
+// assignee, if there is one, takes their place.
+func (s *taskService) RemoveUserFromTask(ctx context.Context, taskId string, actorId string) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
 	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	if task.UserID == nil {
+		return nil
+	}
+
+	if err := s.changeRole(ctx, task, *task.UserID, "", actorId); err != nil {
 		return dto.ErrUpdateTask
 	}
+
+	return nil
+}
+
+// replacePrimary swaps the primary assignee's row after Update has changed
+// Task.UserID, leaving everyone else on the task.
+func (s *taskService) replacePrimary(ctx context.Context, tx *gorm.DB, before entity.Task, userID uuid.UUID) error {
+	if before.UserID != nil {
+		if err := s.taskAssigneeRepo.RemoveAssignee(ctx, tx, before.ID, *before.UserID); err != nil {
+			log.Printf("Failed to remove assignee of task %d: %v", before.ID, err)
+			return err
+		}
+	}
+	if err := s.taskAssigneeRepo.AddAssignee(ctx, tx, entity.TaskAssignee{TaskID: before.ID, UserID: userID, Role: constants.ENUM_TASK_ROLE_ASSIGNEE}); err != nil {
+		log.Printf("Failed to add assignee of task %d: %v", before.ID, err)
+		return err
+	}
 	return nil
 }
 
@@ -290,12 +635,32 @@ func (s *taskService) GetAssignedUser(ctx context.Context, taskId string) (dto.U
 	return dto.UserResponse{}, errors.New("no user assigned to this task")
 }
 
-func (s *taskService) GetTasksByUserID(ctx context.Context, userID string) ([]dto.TaskResponse, error) {
+// GetTasksByUserID lists the tasks assigned to userID. Only admins may list
+// another user's tasks.
+func (s *taskService) GetTasksByUserID(ctx context.Context, userID string, actorId string) ([]dto.TaskResponse, error) {
+	if userID != actorId {
+		actor, err := s.userRepo.GetUserById(ctx, nil, actorId)
+		if err != nil || actor.Role != constants.ENUM_ROLE_ADMIN {
+			return nil, dto.ErrPermissionDenied
+		}
+	}
+
 	tasks, err := s.taskRepo.GetTasksByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	assignees, err := s.assigneesOf(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := taskLabelsOf(ctx, s.labelRepo, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	overdue := newOverdueChecker(s.workflowService)
 	var taskResponses []dto.TaskResponse
 	for _, task := range tasks {
 		var userResponse dto.UserResponse
@@ -317,6 +682,183 @@ func (s *taskService) GetTasksByUserID(ctx context.Context, userID string) ([]dt
 		}
 
 		taskResponses = append(taskResponses, dto.TaskResponse{
+			ID:              task.ID,
+			Title:           task.Title,
+			Description:     task.Description,
+			Status:          task.Status,
+			DueDate:         task.DueDate,
+			IsOverdue:       overdue.isOverdue(ctx, task),
+			TeamsID:         task.TeamsID,
+			UserID:          task.UserID,
+			CreatedAt:       task.CreatedAt,
+			UpdatedAt:       task.UpdatedAt,
+			User:            userResponse,
+			ParentID:        task.ParentID,
+			SprintID:        task.SprintID,
+			Priority:        TaskPriorityName(task.Priority),
+			PriorityRank:    task.Priority,
+			Estimate:        task.Estimate,
+			RemainingEffort: task.RemainingEffort,
+			Assignees:       toTaskAssigneeResponses(assignees[task.ID]),
+			Labels:          labels[task.ID],
+		})
+	}
+
+	return taskResponses, nil
+}
+
+// ReassignUserTasks hands fromUserID's places on the team's tasks to
+// toUserID, or leaves them empty when toUserID is nil. It writes in tx so
+// the caller can make it part of a larger change; the returned announce
+// tells everyone involved and must only be called once tx has committed.
+func (s *taskService) ReassignUserTasks(ctx context.Context, tx *gorm.DB, teamsID int, fromUserID uuid.UUID, toUserID *uuid.UUID, actorId string) (func(), error) {
+	if toUserID != nil {
+		if err := s.ensureTeamMember(ctx, teamsID, *toUserID); err != nil {
+			return nil, err
+		}
+	}
+
+	tasks, err := s.taskRepo.GetTasksByTeamID(ctx, tx, teamsID, dto.TaskFilter{})
+	if err != nil {
+		return nil, dto.ErrReassignTasks
+	}
+
+	if _, err := s.taskRepo.ReassignTeamTasks(ctx, tx, teamsID, fromUserID, toUserID); err != nil {
+		return nil, dto.ErrReassignTasks
+	}
+
+	changes, err := s.reassignRoles(ctx, tx, tasks, fromUserID, toUserID, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		for _, change := range changes {
+			s.announce(ctx, change.task, change.events)
+		}
+	}, nil
+}
+
+func (s *taskService) GetTaskHistory(ctx context.Context, taskId string, req dto.PaginationRequest) (dto.TaskEventPaginationResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.TaskEventPaginationResponse{}, dto.ErrTaskNotFound
+	}
+
+	dataWithPaginate, err := s.taskEventRepo.GetEventsByTaskIdWithPagination(ctx, nil, task.ID, req)
+	if err != nil {
+		return dto.TaskEventPaginationResponse{}, dto.ErrGetTaskHistory
+	}
+
+	events := []dto.TaskEventResponse{}
+	for _, event := range dataWithPaginate.Events {
+		events = append(events, dto.TaskEventResponse{
+			ID:        event.ID,
+			TaskID:    event.TaskID,
+			UserID:    event.UserID,
+			Type:      event.Type,
+			Field:     event.Field,
+			OldValue:  event.OldValue,
+			NewValue:  event.NewValue,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	return dto.TaskEventPaginationResponse{
+		Data: events,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    dataWithPaginate.Page,
+			PerPage: dataWithPaginate.PerPage,
+			MaxPage: dataWithPaginate.MaxPage,
+			Count:   dataWithPaginate.Count,
+		},
+	}, nil
+}
+
+// recordEvents saves the history of a change in tx, the transaction that
+// saves the change itself, so a change is never kept without its history.
+func (s *taskService) recordEvents(ctx context.Context, tx *gorm.DB, events []entity.TaskEvent) error {
+	if s.taskEventRepo == nil {
+		return nil
+	}
+
+	if err := s.taskEventRepo.CreateEvents(ctx, tx, events); err != nil {
+		log.Printf("%v: %v", dto.ErrRecordTaskHistory, err)
+		return dto.ErrRecordTaskHistory
+	}
+
+	return nil
+}
+
+// announce tells everyone about a change once it has been committed. task
+// is the task as it is after the change; it is what notifications, webhooks
+// and board streams describe.
+func (s *taskService) announce(ctx context.Context, task entity.Task, events []entity.TaskEvent) {
+	if s.notificationService != nil {
+		s.notificationService.NotifyTaskEvents(ctx, task, events)
+	}
+
+	if len(events) > 0 {
+		s.publish(ctx, task.TeamsID, taskChangeEvent(events), events[0].UserID, taskChangeData(task, events))
+	}
+}
+
+// publish sends a task change to the team's webhooks and to the board
+// streams connected to the team. Webhooks and streams share event names.
+func (s *taskService) publish(ctx context.Context, teamsID int, event string, actor *uuid.UUID, data dto.WebhookTaskData) {
+	if s.webhookService != nil {
+		s.webhookService.Dispatch(ctx, teamsID, event, actor, data)
+	}
+	if s.hub == nil {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err == nil {
+		err = s.hub.Publish(ctx, realtime.Event{
+			Type:       event,
+			TeamID:     teamsID,
+			ActorID:    actor,
+			OccurredAt: time.Now().UTC(),
+			Data:       payload,
+		})
+	}
+	if err != nil {
+		log.Printf("Failed to publish %s for team %d: %v", event, teamsID, err)
+	}
+}
+
+// taskChangeEvent names the event for one change. A change that only
+// (un)assigns the task gets its own event; anything else is an update.
+func taskChangeEvent(events []entity.TaskEvent) string {
+	for _, event := range events {
+		if event.Type == constants.ENUM_TASK_EVENT_CREATED {
+			return constants.ENUM_WEBHOOK_EVENT_TASK_CREATED
+		}
+	}
+
+	if len(events) == 1 {
+		switch events[0].Type {
+		case constants.ENUM_TASK_EVENT_ASSIGNED:
+			return constants.ENUM_WEBHOOK_EVENT_TASK_ASSIGNED
+		case constants.ENUM_TASK_EVENT_UNASSIGNED:
+			return constants.ENUM_WEBHOOK_EVENT_TASK_UNASSIGNED
+		}
+	}
+
+	return constants.ENUM_WEBHOOK_EVENT_TASK_UPDATED
+}
+
+func taskChangeData(task entity.Task, events []entity.TaskEvent) dto.WebhookTaskData {
+	var changes []dto.WebhookChange
+	for _, event := range events {
+		if event.Field != "" {
+			changes = append(changes, dto.WebhookChange{Field: event.Field, OldValue: event.OldValue, NewValue: event.NewValue})
+		}
+	}
+
+	return dto.WebhookTaskData{
+		Task: dto.WebhookTask{
 			ID:          task.ID,
 			Title:       task.Title,
 			Description: task.Description,
@@ -324,11 +866,89 @@ func (s *taskService) GetTasksByUserID(ctx context.Context, userID string) ([]dt
 			DueDate:     task.DueDate,
 			TeamsID:     task.TeamsID,
 			UserID:      task.UserID,
-			CreatedAt:   task.CreatedAt,
-			UpdatedAt:   task.UpdatedAt,
-			User:        userResponse,
+			ParentID:    task.ParentID,
+		},
+		Changes: changes,
+	}
+}
+
+// diffTask lists the fields that differ between before and after. Fields
+// left empty in after are not being changed and are skipped.
+func diffTask(before entity.Task, after entity.Task, actorId string) []entity.TaskEvent {
+	actor := parseActor(actorId)
+
+	var events []entity.TaskEvent
+	changed := func(field string, oldValue string, newValue string) {
+		if oldValue == newValue {
+			return
+		}
+		events = append(events, entity.TaskEvent{
+			TaskID:   before.ID,
+			UserID:   actor,
+			Type:     constants.ENUM_TASK_EVENT_UPDATED,
+			Field:    field,
+			OldValue: oldValue,
+			NewValue: newValue,
 		})
 	}
 
-	return taskResponses, nil
+	if after.Title != "" {
+		changed("title", before.Title, after.Title)
+	}
+	if after.Description != "" {
+		changed("description", before.Description, after.Description)
+	}
+	if after.Status != "" {
+		changed("status", before.Status, after.Status)
+	}
+	if !after.DueDate.IsZero() && !after.DueDate.Equal(before.DueDate) {
+		changed("due_date", before.DueDate.Format(time.RFC3339), after.DueDate.Format(time.RFC3339))
+	}
+	if after.Priority != 0 {
+		changed("priority", TaskPriorityName(before.Priority), TaskPriorityName(after.Priority))
+	}
+	changed("estimate", strconv.Itoa(before.Estimate), strconv.Itoa(after.Estimate))
+	changed("remaining_effort", strconv.Itoa(before.RemainingEffort), strconv.Itoa(after.RemainingEffort))
+	if after.UserID != nil && (before.UserID == nil || *before.UserID != *after.UserID) {
+		events = append(events, assignmentEvent(before.ID, before.UserID, after.UserID, actorId))
+	}
+
+	return events
+}
+
+func assignmentEvent(taskId int, from *uuid.UUID, to *uuid.UUID, actorId string) entity.TaskEvent {
+	event := entity.TaskEvent{
+		TaskID:   taskId,
+		UserID:   parseActor(actorId),
+		Type:     constants.ENUM_TASK_EVENT_ASSIGNED,
+		Field:    "user_id",
+		OldValue: uuidString(from),
+		NewValue: uuidString(to),
+	}
+	if to == nil {
+		event.Type = constants.ENUM_TASK_EVENT_UNASSIGNED
+	}
+	return event
+}
+
+func parseActor(userId string) *uuid.UUID {
+	actor, err := uuid.Parse(userId)
+	if err != nil {
+		return nil
+	}
+	return &actor
+}
+
+func uuidString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func (s *taskService) ensureTeamMember(ctx context.Context, teamsID int, userID uuid.UUID) error {
+	if _, err := s.userTeamsRepo.GetMembership(ctx, nil, userID, uint(teamsID)); err != nil {
+		return dto.ErrAssigneeNotTeamMember
+	}
+	return nil
 }
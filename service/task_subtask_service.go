@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"gorm.io/gorm"
+)
+
+const (
+	// TASK_MAX_DEPTH is how many levels a task tree may have, counting the
+	// top-level task: a task, its subtasks and their subtasks.
+	TASK_MAX_DEPTH = 3
+)
+
+func (s *taskService) GetSubtasks(ctx context.Context, taskId string) ([]dto.TaskResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	children, err := s.taskRepo.GetChildren(ctx, nil, []int{task.ID})
+	if err != nil {
+		return nil, dto.ErrGetSubtasks
+	}
+
+	progress, err := s.progress(ctx, children)
+	if err != nil {
+		return nil, dto.ErrGetSubtasks
+	}
+
+	overdue := newOverdueChecker(s.workflowService)
+	subtasks := []dto.TaskResponse{}
+	for _, child := range children {
+		subtasks = append(subtasks, dto.TaskResponse{
+			ID:              child.ID,
+			Title:           child.Title,
+			Description:     child.Description,
+			Status:          child.Status,
+			DueDate:         child.DueDate,
+			IsOverdue:       overdue.isOverdue(ctx, child),
+			TeamsID:         child.TeamsID,
+			UserID:          child.UserID,
+			ParentID:        child.ParentID,
+			SprintID:        child.SprintID,
+			Priority:        TaskPriorityName(child.Priority),
+			PriorityRank:    child.Priority,
+			Estimate:        child.Estimate,
+			RemainingEffort: child.RemainingEffort,
+			Progress:        progress[child.ID],
+			CreatedAt:       child.CreatedAt,
+			UpdatedAt:       child.UpdatedAt,
+		})
+	}
+
+	return subtasks, nil
+}
+
+// MoveTask nests a task under another task of its team, placing it after
+// the parent's existing subtasks, or makes it a top-level task again.
+func (s *taskService) MoveTask(ctx context.Context, taskId string, req dto.TaskMoveRequest, userId string) (dto.TaskResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.TaskResponse{}, dto.ErrTaskNotFound
+	}
+
+	position := 0
+	if req.ParentID != nil {
+		if err := s.validateParent(ctx, task, *req.ParentID); err != nil {
+			return dto.TaskResponse{}, err
+		}
+		if task.ParentID != nil && *task.ParentID == *req.ParentID {
+			return s.GetTaskById(ctx, taskId)
+		}
+		if position, err = s.nextPosition(ctx, *req.ParentID); err != nil {
+			return dto.TaskResponse{}, dto.ErrMoveTask
+		}
+	} else if task.ParentID == nil {
+		return s.GetTaskById(ctx, taskId)
+	}
+
+	events := []entity.TaskEvent{{
+		TaskID:   task.ID,
+		UserID:   parseActor(userId),
+		Type:     constants.ENUM_TASK_EVENT_UPDATED,
+		Field:    "parent_id",
+		OldValue: intString(task.ParentID),
+		NewValue: intString(req.ParentID),
+	}}
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.taskRepo.SetParent(ctx, tx, task.ID, req.ParentID, position); err != nil {
+			return dto.ErrMoveTask
+		}
+		return s.recordEvents(ctx, tx, events)
+	})
+	if err != nil {
+		return dto.TaskResponse{}, err
+	}
+
+	task.ParentID = req.ParentID
+	s.announce(ctx, task, events)
+
+	return s.GetTaskById(ctx, taskId)
+}
+
+func (s *taskService) ReorderSubtasks(ctx context.Context, taskId string, req dto.TaskReorderRequest) ([]dto.TaskResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	children, err := s.taskRepo.GetChildren(ctx, nil, []int{task.ID})
+	if err != nil {
+		return nil, dto.ErrReorderTasks
+	}
+
+	current := make([]int, 0, len(children))
+	for _, child := range children {
+		current = append(current, child.ID)
+	}
+	if !isPermutation(current, req.TaskIDs) {
+		return nil, dto.ErrInvalidTaskOrder
+	}
+
+	if err := s.taskRepo.ReorderChildren(ctx, nil, task.ID, req.TaskIDs); err != nil {
+		return nil, dto.ErrReorderTasks
+	}
+
+	return s.GetSubtasks(ctx, taskId)
+}
+
+// validateParent checks that task may be nested under parentId: the parent
+// must be a task of the same team, must not be task itself or one of its
+// subtasks, and the resulting tree must stay within TASK_MAX_DEPTH. task
+// may be a task that is still being created, with no ID yet.
+func (s *taskService) validateParent(ctx context.Context, task entity.Task, parentId int) error {
+	parent, err := s.taskRepo.GetTaskById(ctx, nil, strconv.Itoa(parentId))
+	if err != nil {
+		return dto.ErrParentTaskNotFound
+	}
+
+	if parent.TeamsID != task.TeamsID {
+		return dto.ErrParentTaskOtherTeam
+	}
+
+	// levels counts the parent and its ancestors. The walk is bounded in
+	// case the stored tree is already broken.
+	levels := 1
+	for ancestor := parent; ; levels++ {
+		if ancestor.ID == task.ID {
+			return dto.ErrTaskCycle
+		}
+		if ancestor.ParentID == nil || levels > TASK_MAX_DEPTH {
+			break
+		}
+
+		next, err := s.taskRepo.GetTaskById(ctx, nil, strconv.Itoa(*ancestor.ParentID))
+		if err != nil {
+			break
+		}
+		ancestor = next
+	}
+
+	height, err := s.subtreeHeight(ctx, task.ID)
+	if err != nil {
+		return dto.ErrMoveTask
+	}
+
+	if levels+1+height > TASK_MAX_DEPTH {
+		return dto.ErrTaskTooDeep
+	}
+
+	return nil
+}
+
+// subtreeHeight is how many levels of subtasks sit below taskId. It stops
+// looking once the subtree is already too deep to be nested anywhere.
+func (s *taskService) subtreeHeight(ctx context.Context, taskId int) (int, error) {
+	if taskId == 0 {
+		return 0, nil
+	}
+
+	height := 0
+	level := []int{taskId}
+	for height < TASK_MAX_DEPTH {
+		children, err := s.taskRepo.GetChildren(ctx, nil, level)
+		if err != nil {
+			return 0, err
+		}
+		if len(children) == 0 {
+			break
+		}
+
+		height++
+		level = make([]int, 0, len(children))
+		for _, child := range children {
+			level = append(level, child.ID)
+		}
+	}
+
+	return height, nil
+}
+
+func (s *taskService) nextPosition(ctx context.Context, parentId int) (int, error) {
+	children, err := s.taskRepo.GetChildren(ctx, nil, []int{parentId})
+	if err != nil {
+		return 0, err
+	}
+
+	position := 0
+	for _, child := range children {
+		if child.Position >= position {
+			position = child.Position + 1
+		}
+	}
+
+	return position, nil
+}
+
+// progress rolls up the direct subtasks and checklist items of each task.
+// Tasks with neither are left out of the result.
+func (s *taskService) progress(ctx context.Context, tasks []entity.Task) (map[int]*dto.TaskProgress, error) {
+	teams := make(map[int]int, len(tasks))
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		teams[task.ID] = task.TeamsID
+		ids = append(ids, task.ID)
+	}
+
+	statusCounts, err := s.taskRepo.CountChildrenByStatus(ctx, nil, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	checklistCounts, err := s.taskChecklistRepo.CountItems(ctx, nil, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := map[int]*dto.TaskProgress{}
+	get := func(taskId int) *dto.TaskProgress {
+		if progress[taskId] == nil {
+			progress[taskId] = &dto.TaskProgress{}
+		}
+		return progress[taskId]
+	}
+
+	done := newDoneChecker(s.workflowService)
+	for _, count := range statusCounts {
+		p := get(count.ParentID)
+		p.Total += count.Count
+		if done.isDone(ctx, teams[count.ParentID], count.Status) {
+			p.Completed += count.Count
+		}
+	}
+	for _, count := range checklistCounts {
+		p := get(count.TaskID)
+		p.Total += count.Total
+		p.Completed += count.Completed
+	}
+
+	return progress, nil
+}
+
+// isPermutation reports whether requested lists exactly the ids in current,
+// each once, in any order.
+func isPermutation(current []int, requested []int) bool {
+	if len(current) != len(requested) {
+		return false
+	}
+
+	a := append([]int(nil), current...)
+	b := append([]int(nil), requested...)
+	sort.Ints(a)
+	sort.Ints(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func intString(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+// detachSubtasks is called before a task is deleted so its subtasks are
+// kept as top-level tasks rather than left pointing at a deleted parent.
+func (s *taskService) detachSubtasks(ctx context.Context, tx *gorm.DB, task entity.Task) error {
+	if err := s.taskRepo.DetachChildren(ctx, tx, task.ID); err != nil {
+		log.Printf("Failed to detach subtasks of task %d: %v", task.ID, err)
+		return dto.ErrDeleteTask
+	}
+	return nil
+}
@@ -3,38 +3,67 @@ package service
 import (
 	"context"
 	"strconv"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
 	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type (
 	TeamService interface {
-		Register(ctx context.Context, req dto.TeamCreateRequest) (dto.TeamResponse, error)
+		Register(ctx context.Context, req dto.TeamCreateRequest, userId string) (dto.TeamResponse, error)
 		GetAllTeamWithPagination(ctx context.Context, req dto.PaginationRequest) (dto.TeamPaginationResponse, error)
 		GetTeamById(ctx context.Context, teamId string) (dto.TeamResponse, error)
 		Update(ctx context.Context, req dto.TeamUpdateRequest, teamId string) (dto.TeamUpdateResponse, error)
-		Delete(ctx context.Context, teamId string) error
+		Delete(ctx context.Context, teamId string, userId string) error
+		GetStatistics(ctx context.Context, teamId string) (dto.TeamStatisticsResponse, error)
 	}
 
 	teamService struct {
-		teamRepo   repository.TeamRepository
+		transactor           repository.Transactor
+		teamRepo             repository.TeamRepository
+		userTeamsRepo        repository.UserTeamsRepository
+		taskRepo             repository.TaskRepository
+		labelRepo            repository.LabelRepository
+		authorizationService AuthorizationService
+		webhookService       WebhookService
 	}
 )
 
-func NewTeamService(teamRepo repository.TeamRepository) TeamService {
+func NewTeamService(transactor repository.Transactor, teamRepo repository.TeamRepository, userTeamsRepo repository.UserTeamsRepository, taskRepo repository.TaskRepository, labelRepo repository.LabelRepository, authorizationService AuthorizationService, webhookService WebhookService) TeamService {
 	return &teamService{
-		teamRepo:   teamRepo,
+		transactor:           transactor,
+		teamRepo:             teamRepo,
+		userTeamsRepo:        userTeamsRepo,
+		taskRepo:             taskRepo,
+		labelRepo:            labelRepo,
+		authorizationService: authorizationService,
+		webhookService:       webhookService,
 	}
 }
 
-func (s *teamService) Register(ctx context.Context, req dto.TeamCreateRequest) (dto.TeamResponse, error) {
+func (s *teamService) Register(ctx context.Context, req dto.TeamCreateRequest, userId string) (dto.TeamResponse, error) {
+	ownerId, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.TeamResponse{}, dto.ErrUserNotFound
+	}
+
 	team := entity.Team{
 		Name:       	req.Name,
 		Description: 	req.Description,
 	}
 
-	teamReg, err := s.teamRepo.RegisterTeam(ctx, nil, team)
+	var teamReg entity.Team
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		if teamReg, err = s.teamRepo.RegisterTeam(ctx, tx, team); err != nil {
+			return err
+		}
+
+		// the creator owns the team, otherwise nobody could manage it
+		return s.userTeamsRepo.AssignUserToTeam(ctx, tx, ownerId, uint(teamReg.ID), constants.ENUM_TEAM_ROLE_OWNER)
+	})
 	if err != nil {
 		return dto.TeamResponse{}, dto.ErrCreateTeam
 	}
@@ -65,12 +94,7 @@ func (s *teamService) GetAllTeamWithPagination(ctx context.Context, req dto.Pagi
 
 	return dto.TeamPaginationResponse{
 		Data: datas,
-		PaginationResponse: dto.PaginationResponse{
-			Page:    dataWithPaginate.Page,
-			PerPage: dataWithPaginate.PerPage,
-			MaxPage: dataWithPaginate.MaxPage,
-			Count:   dataWithPaginate.Count,
-		},
+		PaginationResponse: dataWithPaginate.PaginationResponse,
 	}, nil
 }
 
@@ -87,6 +111,36 @@ func (s *teamService) GetTeamById(ctx context.Context, teamId string) (dto.TeamR
 	}, nil
 }
 
+// GetStatistics counts the team's tasks per status and per label.
+func (s *teamService) GetStatistics(ctx context.Context, teamId string) (dto.TeamStatisticsResponse, error) {
+	team, err := s.teamRepo.GetTeamById(ctx, nil, teamId)
+	if err != nil {
+		return dto.TeamStatisticsResponse{}, dto.ErrTeamNotFound
+	}
+
+	statuses, err := s.taskRepo.CountTeamTasksByStatus(ctx, nil, team.ID)
+	if err != nil {
+		return dto.TeamStatisticsResponse{}, dto.ErrGetTeamStatistics
+	}
+
+	labels, err := s.labelRepo.CountTasksByLabel(ctx, nil, team.ID)
+	if err != nil {
+		return dto.TeamStatisticsResponse{}, dto.ErrGetTeamStatistics
+	}
+
+	stats := dto.TeamStatisticsResponse{
+		Statuses: []dto.TaskStatusCount{},
+		Labels:   []dto.LabelCount{},
+	}
+	for _, status := range statuses {
+		stats.TotalTasks += status.Count
+		stats.Statuses = append(stats.Statuses, status)
+	}
+	stats.Labels = append(stats.Labels, labels...)
+
+	return stats, nil
+}
+
 func (s *teamService) Update(ctx context.Context, req dto.TeamUpdateRequest, teamId string) (dto.TeamUpdateResponse, error) {
 	team, err := s.teamRepo.GetTeamById(ctx, nil, teamId)
 	if err != nil {
@@ -104,6 +158,16 @@ func (s *teamService) Update(ctx context.Context, req dto.TeamUpdateRequest, tea
 		return dto.TeamUpdateResponse{}, dto.ErrUpdateTeam
 	}
 
+	// Updates skips empty fields, so they keep their stored value
+	updated := team
+	if data.Name != "" {
+		updated.Name = data.Name
+	}
+	if data.Description != "" {
+		updated.Description = data.Description
+	}
+	s.webhookService.Dispatch(ctx, team.ID, constants.ENUM_WEBHOOK_EVENT_TEAM_UPDATED, nil, teamWebhookData(updated))
+
 	return dto.TeamUpdateResponse{
 		ID:         	strconv.Itoa(teamUpdate.ID),
 		Name:       	teamUpdate.Name,
@@ -111,16 +175,32 @@ func (s *teamService) Update(ctx context.Context, req dto.TeamUpdateRequest, tea
 	}, nil
 }
 
-func (s *teamService) Delete(ctx context.Context, teamId string) error {
+func (s *teamService) Delete(ctx context.Context, teamId string, userId string) error {
 	team, err := s.teamRepo.GetTeamById(ctx, nil, teamId)
 	if err != nil {
 		return dto.ErrTeamNotFound
 	}
 
+	if err := s.authorizationService.AuthorizeTeam(ctx, userId, team.ID, constants.ACTION_TEAM_DELETE); err != nil {
+		return err
+	}
+
 	err = s.teamRepo.DeleteTeam(ctx, nil, strconv.Itoa(team.ID))
 	if err != nil {
 		return dto.ErrDeleteTeam
 	}
 
+	s.webhookService.Dispatch(ctx, team.ID, constants.ENUM_WEBHOOK_EVENT_TEAM_DELETED, parseActor(userId), teamWebhookData(team))
+
 	return nil
+}
+
+func teamWebhookData(team entity.Team) dto.WebhookTeamData {
+	return dto.WebhookTeamData{
+		Team: dto.WebhookTeam{
+			ID:          team.ID,
+			Name:        team.Name,
+			Description: team.Description,
+		},
+	}
 }
\ No newline at end of file
@@ -3,9 +3,13 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"html/template"
-	"os"
+	"log"
 	"strings"
 	"time"
 
@@ -14,6 +18,7 @@ import (
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 	"github.com/Caknoooo/go-gin-clean-starter/helpers"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
 	"github.com/Caknoooo/go-gin-clean-starter/utils"
 	"github.com/google/uuid"
 )
@@ -29,24 +34,42 @@ type (
 		Update(ctx context.Context, req dto.UserUpdateRequest, userId string) (dto.UserUpdateResponse, error)
 		Delete(ctx context.Context, userId string) error
 		Verify(ctx context.Context, req dto.UserLoginRequest) (dto.UserLoginResponse, error)
+		RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.UserLoginResponse, error)
+		Logout(ctx context.Context, sessionId string) error
+		LogoutAll(ctx context.Context, userId string) error
+		ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest)
+		ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error
+		ChangePassword(ctx context.Context, req dto.ChangePasswordRequest, userId string, sessionId string) error
 	}
 
 	userService struct {
-		userRepo   repository.UserRepository
-		jwtService JWTService
+		userRepo     repository.UserRepository
+		jwtService   JWTService
+		storage      storage.Storage
+		sessionRepo  repository.SessionRepository
+		emailService EmailService
 	}
 )
 
-func NewUserService(userRepo repository.UserRepository, jwtService JWTService) UserService {
+func NewUserService(userRepo repository.UserRepository, jwtService JWTService, fileStorage storage.Storage, sessionRepo repository.SessionRepository, emailService EmailService) UserService {
 	return &userService{
-		userRepo:   userRepo,
-		jwtService: jwtService,
+		userRepo:     userRepo,
+		jwtService:   jwtService,
+		storage:      fileStorage,
+		sessionRepo:  sessionRepo,
+		emailService: emailService,
 	}
 }
 
 const (
-	LOCAL_URL          = "http://localhost:3000"
-	VERIFY_EMAIL_ROUTE = "register/verify_email"
+	LOCAL_URL            = "http://localhost:3000"
+	VERIFY_EMAIL_ROUTE   = "register/verify_email"
+	RESET_PASSWORD_ROUTE = "reset_password"
+
+	RESET_PASSWORD_TTL = time.Hour
+
+	// SESSION_TTL bounds how long a login can be kept alive by refreshing.
+	SESSION_TTL = 30 * 24 * time.Hour
 )
 
 func (s *userService) Register(ctx context.Context, req dto.UserCreateRequest) (dto.UserResponse, error) {
@@ -59,10 +82,17 @@ func (s *userService) Register(ctx context.Context, req dto.UserCreateRequest) (
 
 	if req.Image != nil {
 		imageId := uuid.New()
-		ext := utils.GetExtensions(req.Image.Filename)
+		filename = fmt.Sprintf("profile/%s", imageId)
+		if ext := utils.GetExtensions(req.Image.Filename); ext != "" {
+			filename = fmt.Sprintf("%s.%s", filename, ext)
+		}
+
+		mimeType, _, err := inspectFile(req.Image)
+		if err != nil {
+			return dto.UserResponse{}, err
+		}
 
-		filename = fmt.Sprintf("profile/%s.%s", imageId, ext)
-		if err := utils.UploadFile(req.Image, filename); err != nil {
+		if err := storage.PutFile(ctx, s.storage, filename, req.Image, mimeType); err != nil {
 			return dto.UserResponse{}, err
 		}
 	}
@@ -82,21 +112,18 @@ func (s *userService) Register(ctx context.Context, req dto.UserCreateRequest) (
 		return dto.UserResponse{}, dto.ErrCreateUser
 	}
 
-	// draftEmail, err := makeVerificationEmail(userReg.Email)
-	// if err != nil {
-	// 	return dto.UserResponse{}, err
-	// }
-
-	// err = utils.SendMail(userReg.Email, draftEmail["subject"], draftEmail["body"])
-	// if err != nil {
-	// 	return dto.UserResponse{}, err
-	// }
+	// The account already exists at this point, so a mail that cannot be
+	// queued is logged rather than failing the registration; the user can
+	// ask for it again through SendVerificationEmail.
+	if err := s.enqueueVerificationEmail(ctx, userReg.Email); err != nil {
+		log.Printf("Failed to queue verification email for %s: %v", userReg.Email, err)
+	}
 
 	return dto.UserResponse{
 		ID:         userReg.ID.String(),
 		Name:       userReg.Name,
 		TelpNumber: userReg.TelpNumber,
-		ImageUrl:   userReg.ImageUrl,
+		ImageUrl:   s.imageUrl(ctx, userReg.ImageUrl),
 		Role:       userReg.Role,
 		Email:      userReg.Email,
 		IsVerified: userReg.IsVerified,
@@ -113,7 +140,7 @@ func makeVerificationEmail(receiverEmail string) (map[string]string, error) {
 
 	verifyLink := LOCAL_URL + "/" + VERIFY_EMAIL_ROUTE + "?token=" + token
 
-	readHtml, err := os.ReadFile("utils/email-template/base_mail.html")
+	readHtml, err := utils.ReadEmailTemplate("base_mail.html")
 	if err != nil {
 		return nil, err
 	}
@@ -144,23 +171,77 @@ func makeVerificationEmail(receiverEmail string) (map[string]string, error) {
 	return draftEmail, nil
 }
 
+// makeResetPasswordEmail builds a reset link whose token carries a fingerprint
+// of the current password hash. Any password change, including the reset the
+// link performs, alters the hash and so invalidates every outstanding link.
+func makeResetPasswordEmail(user entity.User) (map[string]string, error) {
+	expired := time.Now().Add(RESET_PASSWORD_TTL)
+	plainText := strings.Join([]string{user.Email, expired.Format(time.RFC3339), passwordFingerprint(user.Password)}, "|")
+	token, err := utils.AESEncrypt(plainText)
+	if err != nil {
+		return nil, err
+	}
+
+	resetLink := LOCAL_URL + "/" + RESET_PASSWORD_ROUTE + "?token=" + token
+
+	readHtml, err := utils.ReadEmailTemplate("reset_password_mail.html")
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		Email   string
+		Reset   string
+		Expired string
+	}{
+		Email:   user.Email,
+		Reset:   resetLink,
+		Expired: expired.Format("2006-01-02 15:04:05"),
+	}
+
+	tmpl, err := template.New("custom").Parse(string(readHtml))
+	if err != nil {
+		return nil, err
+	}
+
+	var strMail bytes.Buffer
+	if err := tmpl.Execute(&strMail, data); err != nil {
+		return nil, err
+	}
+
+	draftEmail := map[string]string{
+		"subject": "Cakno - Reset Your Password",
+		"body":    strMail.String(),
+	}
+
+	return draftEmail, nil
+}
+
+func passwordFingerprint(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return hex.EncodeToString(sum[:8])
+}
+
 func (s *userService) SendVerificationEmail(ctx context.Context, req dto.SendVerificationEmailRequest) error {
-	// user, err := s.userRepo.GetUserByEmail(ctx, nil, req.Email)
-	// if err != nil {
-	// 	return dto.ErrEmailNotFound
-	// }
+	user, err := s.userRepo.GetUserByEmail(ctx, nil, req.Email)
+	if err != nil {
+		return dto.ErrEmailNotFound
+	}
+
+	if user.IsVerified {
+		return dto.ErrAccountAlreadyVerified
+	}
 
-	// draftEmail, err := makeVerificationEmail(user.Email)
-	// if err != nil {
-	// 	return err
-	// }
+	return s.enqueueVerificationEmail(ctx, user.Email)
+}
 
-	// err = utils.SendMail(user.Email, draftEmail["subject"], draftEmail["body"])
-	// if err != nil {
-	// 	return err
-	// }
+func (s *userService) enqueueVerificationEmail(ctx context.Context, email string) error {
+	draftEmail, err := makeVerificationEmail(email)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return s.emailService.Enqueue(ctx, email, draftEmail["subject"], draftEmail["body"])
 }
 
 func (s *userService) VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (dto.VerifyEmailResponse, error) {
@@ -173,9 +254,10 @@ func (s *userService) VerifyEmail(ctx context.Context, req dto.VerifyEmailReques
 		return dto.VerifyEmailResponse{}, dto.ErrTokenInvalid
 	}
 
-	decryptedTokenSplit := strings.Split(decryptedToken, "_")
-	email := decryptedTokenSplit[0]
-	expired := decryptedTokenSplit[1]
+	// Emails may contain underscores themselves; the expiry never does.
+	separator := strings.LastIndex(decryptedToken, "_")
+	email := decryptedToken[:separator]
+	expired := decryptedToken[separator+1:]
 
 	now := time.Now()
 	expiredTime, err := time.Parse("2006-01-02 15:04:05", expired)
@@ -227,7 +309,7 @@ func (s *userService) GetAllUserWithPagination(ctx context.Context, req dto.Pagi
 			Email:      user.Email,
 			Role:       user.Role,
 			TelpNumber: user.TelpNumber,
-			ImageUrl:   user.ImageUrl,
+			ImageUrl:   s.imageUrl(ctx, user.ImageUrl),
 			IsVerified: user.IsVerified,
 		}
 
@@ -236,12 +318,7 @@ func (s *userService) GetAllUserWithPagination(ctx context.Context, req dto.Pagi
 
 	return dto.UserPaginationResponse{
 		Data: datas,
-		PaginationResponse: dto.PaginationResponse{
-			Page:    dataWithPaginate.Page,
-			PerPage: dataWithPaginate.PerPage,
-			MaxPage: dataWithPaginate.MaxPage,
-			Count:   dataWithPaginate.Count,
-		},
+		PaginationResponse: dataWithPaginate.PaginationResponse,
 	}, nil
 }
 
@@ -257,7 +334,7 @@ func (s *userService) GetUserById(ctx context.Context, userId string) (dto.UserR
 		TelpNumber: user.TelpNumber,
 		Role:       user.Role,
 		Email:      user.Email,
-		ImageUrl:   user.ImageUrl,
+		ImageUrl:   s.imageUrl(ctx, user.ImageUrl),
 		IsVerified: user.IsVerified,
 	}, nil
 }
@@ -274,7 +351,7 @@ func (s *userService) GetUserByEmail(ctx context.Context, email string) (dto.Use
 		TelpNumber: emails.TelpNumber,
 		Role:       emails.Role,
 		Email:      emails.Email,
-		ImageUrl:   emails.ImageUrl,
+		ImageUrl:   s.imageUrl(ctx, emails.ImageUrl),
 		IsVerified: emails.IsVerified,
 	}, nil
 }
@@ -319,6 +396,10 @@ func (s *userService) Delete(ctx context.Context, userId string) error {
 		return dto.ErrDeleteUser
 	}
 
+	if err := s.sessionRepo.RevokeUserSessions(ctx, nil, user.ID.String()); err != nil {
+		return dto.ErrDeleteUser
+	}
+
 	return nil
 }
 
@@ -328,19 +409,228 @@ func (s *userService) Verify(ctx context.Context, req dto.UserLoginRequest) (dto
 		return dto.UserLoginResponse{}, dto.ErrEmailNotFound
 	}
 
-	// if !check.IsVerified {
-	// 	return dto.UserLoginResponse{}, dto.ErrAccountNotVerified
-	// }
-
 	checkPassword, err := helpers.CheckPassword(check.Password, []byte(req.Password))
 	if err != nil || !checkPassword {
 		return dto.UserLoginResponse{}, dto.ErrPasswordNotMatch
 	}
 
-	token := s.jwtService.GenerateToken(check.ID.String(), check.Role)
+	// Checked after the password so that whether an account is verified is
+	// only told to whoever can sign in to it.
+	if !check.IsVerified {
+		return dto.UserLoginResponse{}, dto.ErrAccountNotVerified
+	}
+
+	now := time.Now()
+	session, err := s.sessionRepo.CreateSession(ctx, nil, entity.Session{
+		UserID:     check.ID,
+		UserAgent:  truncate(req.UserAgent, 255),
+		IPAddress:  req.IPAddress,
+		ExpiresAt:  now.Add(SESSION_TTL),
+		LastUsedAt: now,
+	})
+	if err != nil {
+		return dto.UserLoginResponse{}, dto.ErrCreateSession
+	}
+
+	return s.issueTokens(ctx, check, session)
+}
+
+// RefreshToken rotates the refresh token: each one can be exchanged once.
+// Presenting an already used token means it was copied, so the whole session
+// is revoked and both parties have to sign in again.
+func (s *userService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.UserLoginResponse, error) {
+	token, err := s.sessionRepo.GetRefreshTokenByHash(ctx, nil, hashRefreshToken(req.RefreshToken))
+	if err != nil {
+		return dto.UserLoginResponse{}, dto.ErrRefreshTokenInvalid
+	}
+
+	now := time.Now()
+	if !token.Session.IsActive(now) {
+		return dto.UserLoginResponse{}, dto.ErrRefreshTokenInvalid
+	}
+
+	fresh, err := s.sessionRepo.MarkRefreshTokenUsed(ctx, nil, token.ID)
+	if err != nil {
+		return dto.UserLoginResponse{}, dto.ErrRefreshTokenInvalid
+	}
+
+	if !fresh {
+		if err := s.sessionRepo.RevokeSession(ctx, nil, token.SessionID.String()); err != nil {
+			return dto.UserLoginResponse{}, dto.ErrRefreshTokenInvalid
+		}
+		return dto.UserLoginResponse{}, dto.ErrRefreshTokenReused
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, nil, token.Session.UserID.String())
+	if err != nil {
+		return dto.UserLoginResponse{}, dto.ErrUserNotFound
+	}
+
+	if err := s.sessionRepo.TouchSession(ctx, nil, token.SessionID.String(), now); err != nil {
+		return dto.UserLoginResponse{}, dto.ErrRefreshTokenInvalid
+	}
+
+	return s.issueTokens(ctx, user, token.Session)
+}
+
+func (s *userService) Logout(ctx context.Context, sessionId string) error {
+	return s.sessionRepo.RevokeSession(ctx, nil, sessionId)
+}
+
+func (s *userService) LogoutAll(ctx context.Context, userId string) error {
+	return s.sessionRepo.RevokeUserSessions(ctx, nil, userId)
+}
+
+// ForgotPassword never reveals whether the email is registered: it has no
+// result, and a mail that cannot be built or queued is only logged.
+func (s *userService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) {
+	user, err := s.userRepo.GetUserByEmail(ctx, nil, req.Email)
+	if err != nil {
+		return
+	}
+
+	draftEmail, err := makeResetPasswordEmail(user)
+	if err != nil {
+		log.Printf("Failed to build reset password email for %s: %v", user.Email, err)
+		return
+	}
+
+	if err := s.emailService.Enqueue(ctx, user.Email, draftEmail["subject"], draftEmail["body"]); err != nil {
+		log.Printf("Failed to queue reset password email for %s: %v", user.Email, err)
+	}
+}
+
+func (s *userService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
+	decryptedToken, err := utils.AESDecrypt(req.Token)
+	if err != nil {
+		return dto.ErrTokenInvalid
+	}
+
+	parts := strings.Split(decryptedToken, "|")
+	if len(parts) < 3 {
+		return dto.ErrTokenInvalid
+	}
+
+	email := strings.Join(parts[:len(parts)-2], "|")
+	fingerprint := parts[len(parts)-1]
+
+	expired, err := time.Parse(time.RFC3339, parts[len(parts)-2])
+	if err != nil {
+		return dto.ErrTokenInvalid
+	}
+
+	if time.Now().After(expired) {
+		return dto.ErrTokenExpired
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, nil, email)
+	if err != nil {
+		return dto.ErrTokenInvalid
+	}
+
+	if passwordFingerprint(user.Password) != fingerprint {
+		return dto.ErrTokenInvalid
+	}
+
+	if err := s.updatePassword(ctx, user, req.Password); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeUserSessions(ctx, nil, user.ID.String())
+}
+
+// ChangePassword keeps the session it is called from and signs out every
+// other device.
+func (s *userService) ChangePassword(ctx context.Context, req dto.ChangePasswordRequest, userId string, sessionId string) error {
+	user, err := s.userRepo.GetUserById(ctx, nil, userId)
+	if err != nil {
+		return dto.ErrUserNotFound
+	}
+
+	checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.OldPassword))
+	if err != nil || !checkPassword {
+		return dto.ErrPasswordNotMatch
+	}
+
+	if err := s.updatePassword(ctx, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeOtherSessions(ctx, nil, userId, sessionId)
+}
+
+func (s *userService) updatePassword(ctx context.Context, user entity.User, password string) error {
+	hashed, err := helpers.HashPassword(password)
+	if err != nil {
+		return dto.ErrUpdateUser
+	}
+
+	if _, err := s.userRepo.UpdateUser(ctx, nil, entity.User{
+		ID:       user.ID,
+		Password: hashed,
+	}); err != nil {
+		return dto.ErrUpdateUser
+	}
+
+	return nil
+}
+
+func (s *userService) issueTokens(ctx context.Context, user entity.User, session entity.Session) (dto.UserLoginResponse, error) {
+	token, err := s.jwtService.GenerateToken(user.ID.String(), user.Role, session.ID.String())
+	if err != nil {
+		return dto.UserLoginResponse{}, dto.ErrGenerateToken
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return dto.UserLoginResponse{}, dto.ErrCreateSession
+	}
+
+	if _, err := s.sessionRepo.CreateRefreshToken(ctx, nil, entity.RefreshToken{
+		SessionID: session.ID,
+		TokenHash: hashRefreshToken(refreshToken),
+	}); err != nil {
+		return dto.UserLoginResponse{}, dto.ErrCreateSession
+	}
 
 	return dto.UserLoginResponse{
-		Token: token,
-		Role:  check.Role,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(ACCESS_TOKEN_TTL),
+		Role:         user.Role,
 	}, nil
 }
+
+func generateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
+
+// imageUrl turns a stored profile image key into a URL the client can fetch.
+func (s *userService) imageUrl(ctx context.Context, key string) string {
+	if key == "" {
+		return ""
+	}
+
+	url, err := s.storage.SignedURL(ctx, key, storage.DEFAULT_SIGNED_URL_EXPIRY)
+	if err != nil {
+		return ""
+	}
+
+	return url
+}
\ No newline at end of file
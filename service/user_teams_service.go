@@ -3,35 +3,130 @@ package service
 import (
 	"context"
 
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type UserTeamsService interface {
 	AssignUserToTeam(userId uuid.UUID, teamId uint) error
-	RemoveUserFromTeam(userId uuid.UUID, teamId uint) error
+	RemoveUserFromTeam(userId uuid.UUID, teamId uint, actorId string, reassignTo *uuid.UUID) error
 	GetUsersByTeamId(teamId uint) ([]entity.User, error)
+	ChangeRole(userId uuid.UUID, teamId uint, role string) error
 }
 
 type userTeamsService struct {
-	userTeamsRepo repository.UserTeamsRepository
+	transactor           repository.Transactor
+	userTeamsRepo        repository.UserTeamsRepository
+	taskService          TaskService
+	authorizationService AuthorizationService
 }
 
-func NewUserTeamsService(userTeamsRepo repository.UserTeamsRepository) UserTeamsService {
+func NewUserTeamsService(transactor repository.Transactor, userTeamsRepo repository.UserTeamsRepository, taskService TaskService, authorizationService AuthorizationService) UserTeamsService {
 	return &userTeamsService{
-		userTeamsRepo: userTeamsRepo,
+		transactor:           transactor,
+		userTeamsRepo:        userTeamsRepo,
+		taskService:          taskService,
+		authorizationService: authorizationService,
 	}
 }
 
 func (s *userTeamsService) AssignUserToTeam(userId uuid.UUID, teamId uint) error {
-	return s.userTeamsRepo.AssignUserToTeam(context.Background(), nil, userId, teamId)
+	return s.userTeamsRepo.AssignUserToTeam(context.Background(), nil, userId, teamId, constants.ENUM_TEAM_ROLE_MEMBER)
 }
 
-func (s *userTeamsService) RemoveUserFromTeam(userId uuid.UUID, teamId uint) error {
-	return s.userTeamsRepo.RemoveUserFromTeam(context.Background(), nil, userId, teamId)
+// RemoveUserFromTeam hands the leaving user's tasks in this team over to
+// reassignTo, or leaves them unassigned when reassignTo is nil.
+func (s *userTeamsService) RemoveUserFromTeam(userId uuid.UUID, teamId uint, actorId string, reassignTo *uuid.UUID) error {
+	ctx := context.Background()
+
+	membership, err := s.userTeamsRepo.GetMembership(ctx, nil, userId, teamId)
+	if err != nil {
+		return dto.ErrMembershipNotFound
+	}
+
+	if membership.Role == constants.ENUM_TEAM_ROLE_OWNER {
+		// maintainers may remove members, but only owners may remove an owner
+		if err := s.authorizationService.AuthorizeTeam(ctx, actorId, int(teamId), constants.ACTION_TEAM_MEMBER_ROLE); err != nil {
+			return err
+		}
+	}
+
+	if reassignTo != nil && *reassignTo == userId {
+		return dto.ErrAssigneeNotTeamMember
+	}
+
+	// The user only leaves with their tasks handed over, and the other way
+	// round.
+	var announce func()
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		if membership.Role == constants.ENUM_TEAM_ROLE_OWNER {
+			if err := s.ensureNotLastOwner(ctx, tx, teamId); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		announce, err = s.taskService.ReassignUserTasks(ctx, tx, int(teamId), userId, reassignTo, actorId)
+		if err != nil {
+			return err
+		}
+
+		return s.userTeamsRepo.RemoveUserFromTeam(ctx, tx, userId, teamId)
+	})
+	if err != nil {
+		return err
+	}
+
+	announce()
+	return nil
 }
 
 func (s *userTeamsService) GetUsersByTeamId(teamId uint) ([]entity.User, error) {
 	return s.userTeamsRepo.GetUsersByTeamId(context.Background(), nil, teamId)
+}
+
+func (s *userTeamsService) ChangeRole(userId uuid.UUID, teamId uint, role string) error {
+	ctx := context.Background()
+
+	membership, err := s.userTeamsRepo.GetMembership(ctx, nil, userId, teamId)
+	if err != nil {
+		return dto.ErrMembershipNotFound
+	}
+
+	if membership.Role == role {
+		return nil
+	}
+
+	return s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		if membership.Role == constants.ENUM_TEAM_ROLE_OWNER {
+			if err := s.ensureNotLastOwner(ctx, tx, teamId); err != nil {
+				return err
+			}
+		}
+
+		if err := s.userTeamsRepo.UpdateRole(ctx, tx, userId, teamId, role); err != nil {
+			return dto.ErrChangeTeamRole
+		}
+
+		return nil
+	})
+}
+
+// ensureNotLastOwner locks the team's owners in tx, so that two owners
+// stepping down at once cannot both pass the check.
+func (s *userTeamsService) ensureNotLastOwner(ctx context.Context, tx *gorm.DB, teamId uint) error {
+	owners, err := s.userTeamsRepo.CountByRoleForUpdate(ctx, tx, teamId, constants.ENUM_TEAM_ROLE_OWNER)
+	if err != nil {
+		return err
+	}
+
+	if owners <= 1 {
+		return dto.ErrLastTeamOwner
+	}
+
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,585 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	// WebhookService manages team webhooks and delivers their payloads in the
+	// background. Dispatch only records a delivery per subscribed webhook, so
+	// a slow or unreachable receiver never fails the request that caused the
+	// event.
+	WebhookService interface {
+		CreateWebhook(ctx context.Context, teamsID int, req dto.WebhookCreateRequest) (dto.WebhookResponse, error)
+		GetWebhooks(ctx context.Context, teamsID int) ([]dto.WebhookResponse, error)
+		GetWebhookById(ctx context.Context, teamsID int, webhookId int) (dto.WebhookResponse, error)
+		UpdateWebhook(ctx context.Context, teamsID int, webhookId int, req dto.WebhookUpdateRequest) (dto.WebhookResponse, error)
+		DeleteWebhook(ctx context.Context, teamsID int, webhookId int) error
+		GetDeliveries(ctx context.Context, teamsID int, webhookId int, req dto.PaginationRequest) (dto.WebhookDeliveryPaginationResponse, error)
+		Redeliver(ctx context.Context, teamsID int, webhookId int, deliveryId int) (dto.WebhookDeliveryResponse, error)
+		Dispatch(ctx context.Context, teamsID int, event string, actor *uuid.UUID, data interface{})
+		ProcessDeliveries(ctx context.Context) (int, error)
+		StartWorker(ctx context.Context)
+	}
+
+	webhookService struct {
+		webhookRepo repository.WebhookRepository
+		config      config.WebhookConfig
+		client      *http.Client
+		wake        chan struct{}
+	}
+)
+
+const (
+	WEBHOOK_BATCH_SIZE    = 20
+	WEBHOOK_MAX_ATTEMPTS  = 6
+	WEBHOOK_RETRY_BASE    = 30 * time.Second
+	WEBHOOK_RETRY_MAX     = time.Hour
+	WEBHOOK_CLAIM_LEASE   = 2 * time.Minute
+	WEBHOOK_POLL_INTERVAL = 5 * time.Second
+	WEBHOOK_TIMEOUT       = 10 * time.Second
+
+	WEBHOOK_RESPONSE_LIMIT = 1000
+
+	WEBHOOK_HEADER_EVENT     = "X-Webhook-Event"
+	WEBHOOK_HEADER_DELIVERY  = "X-Webhook-Delivery"
+	WEBHOOK_HEADER_TIMESTAMP = "X-Webhook-Timestamp"
+	WEBHOOK_HEADER_SIGNATURE = "X-Webhook-Signature"
+)
+
+// WebhookEvents lists every event a webhook can subscribe to.
+var WebhookEvents = []string{
+	constants.ENUM_WEBHOOK_EVENT_TASK_CREATED,
+	constants.ENUM_WEBHOOK_EVENT_TASK_UPDATED,
+	constants.ENUM_WEBHOOK_EVENT_TASK_ASSIGNED,
+	constants.ENUM_WEBHOOK_EVENT_TASK_UNASSIGNED,
+	constants.ENUM_WEBHOOK_EVENT_TASK_DELETED,
+	constants.ENUM_WEBHOOK_EVENT_TEAM_UPDATED,
+	constants.ENUM_WEBHOOK_EVENT_TEAM_DELETED,
+}
+
+// NewWebhookService delivers with a client that times out after
+// WEBHOOK_TIMEOUT and does not follow redirects, so a receiver answering with
+// a redirect counts as a failed delivery. Unless cfg allows private networks,
+// the client also refuses to connect to internal addresses. That check runs
+// on the address actually dialled, so a host that resolved to a public
+// address when the webhook was saved cannot be rebound to an internal one.
+func NewWebhookService(webhookRepo repository.WebhookRepository, cfg config.WebhookConfig) WebhookService {
+	dialer := &net.Dialer{Timeout: WEBHOOK_TIMEOUT}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = refuseInternalAddress
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		config:      cfg,
+		client: &http.Client{
+			Timeout:   WEBHOOK_TIMEOUT,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		wake: make(chan struct{}, 1),
+	}
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, teamsID int, req dto.WebhookCreateRequest) (dto.WebhookResponse, error) {
+	if err := s.validateURL(ctx, req.URL); err != nil {
+		return dto.WebhookResponse{}, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return dto.WebhookResponse{}, dto.ErrCreateWebhook
+		}
+		secret = generated
+	}
+
+	webhook, err := s.webhookRepo.CreateWebhook(ctx, nil, entity.Webhook{
+		TeamsID:  teamsID,
+		URL:      req.URL,
+		Secret:   secret,
+		Events:   joinWebhookEvents(req.Events),
+		IsActive: true,
+	})
+	if err != nil {
+		return dto.WebhookResponse{}, dto.ErrCreateWebhook
+	}
+
+	res := toWebhookResponse(webhook)
+	res.Secret = webhook.Secret
+	return res, nil
+}
+
+func (s *webhookService) GetWebhooks(ctx context.Context, teamsID int) ([]dto.WebhookResponse, error) {
+	webhooks, err := s.webhookRepo.GetWebhooksByTeamID(ctx, nil, teamsID)
+	if err != nil {
+		return nil, dto.ErrGetWebhook
+	}
+
+	responses := []dto.WebhookResponse{}
+	for _, webhook := range webhooks {
+		responses = append(responses, toWebhookResponse(webhook))
+	}
+
+	return responses, nil
+}
+
+func (s *webhookService) GetWebhookById(ctx context.Context, teamsID int, webhookId int) (dto.WebhookResponse, error) {
+	webhook, err := s.webhookRepo.GetWebhookById(ctx, nil, teamsID, webhookId)
+	if err != nil {
+		return dto.WebhookResponse{}, dto.ErrWebhookNotFound
+	}
+
+	return toWebhookResponse(webhook), nil
+}
+
+func (s *webhookService) UpdateWebhook(ctx context.Context, teamsID int, webhookId int, req dto.WebhookUpdateRequest) (dto.WebhookResponse, error) {
+	webhook, err := s.webhookRepo.GetWebhookById(ctx, nil, teamsID, webhookId)
+	if err != nil {
+		return dto.WebhookResponse{}, dto.ErrWebhookNotFound
+	}
+
+	if req.URL != nil {
+		if err := s.validateURL(ctx, *req.URL); err != nil {
+			return dto.WebhookResponse{}, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		webhook.Events = joinWebhookEvents(req.Events)
+	}
+	if req.IsActive != nil {
+		webhook.IsActive = *req.IsActive
+	}
+	if req.RotateSecret {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return dto.WebhookResponse{}, dto.ErrUpdateWebhook
+		}
+		webhook.Secret = secret
+	}
+
+	updated, err := s.webhookRepo.UpdateWebhook(ctx, nil, webhook)
+	if err != nil {
+		return dto.WebhookResponse{}, dto.ErrUpdateWebhook
+	}
+
+	res := toWebhookResponse(updated)
+	if req.RotateSecret {
+		res.Secret = updated.Secret
+	}
+	return res, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, teamsID int, webhookId int) error {
+	if _, err := s.webhookRepo.GetWebhookById(ctx, nil, teamsID, webhookId); err != nil {
+		return dto.ErrWebhookNotFound
+	}
+
+	if err := s.webhookRepo.DeleteWebhook(ctx, nil, teamsID, webhookId); err != nil {
+		return dto.ErrDeleteWebhook
+	}
+
+	return nil
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, teamsID int, webhookId int, req dto.PaginationRequest) (dto.WebhookDeliveryPaginationResponse, error) {
+	if _, err := s.webhookRepo.GetWebhookById(ctx, nil, teamsID, webhookId); err != nil {
+		return dto.WebhookDeliveryPaginationResponse{}, dto.ErrWebhookNotFound
+	}
+
+	dataWithPaginate, err := s.webhookRepo.GetDeliveriesWithPagination(ctx, nil, webhookId, req)
+	if err != nil {
+		return dto.WebhookDeliveryPaginationResponse{}, dto.ErrGetWebhookDelivery
+	}
+
+	deliveries := []dto.WebhookDeliveryResponse{}
+	for _, delivery := range dataWithPaginate.Deliveries {
+		deliveries = append(deliveries, toWebhookDeliveryResponse(delivery))
+	}
+
+	return dto.WebhookDeliveryPaginationResponse{
+		Data:               deliveries,
+		PaginationResponse: dataWithPaginate.PaginationResponse,
+	}, nil
+}
+
+// Redeliver queues the payload of an earlier delivery again. The original
+// delivery is left as it was, so the log keeps every attempt.
+func (s *webhookService) Redeliver(ctx context.Context, teamsID int, webhookId int, deliveryId int) (dto.WebhookDeliveryResponse, error) {
+	if _, err := s.webhookRepo.GetWebhookById(ctx, nil, teamsID, webhookId); err != nil {
+		return dto.WebhookDeliveryResponse{}, dto.ErrWebhookNotFound
+	}
+
+	original, err := s.webhookRepo.GetDeliveryById(ctx, nil, webhookId, deliveryId)
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, dto.ErrWebhookDeliveryNotFound
+	}
+
+	deliveries, err := s.webhookRepo.CreateDeliveries(ctx, nil, []entity.WebhookDelivery{{
+		WebhookID:     webhookId,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        constants.ENUM_WEBHOOK_DELIVERY_PENDING,
+		NextAttemptAt: time.Now(),
+	}})
+	if err != nil || len(deliveries) == 0 {
+		return dto.WebhookDeliveryResponse{}, dto.ErrRedeliverWebhook
+	}
+	s.nudge()
+
+	return toWebhookDeliveryResponse(deliveries[0]), nil
+}
+
+// Dispatch queues event for every active webhook of the team subscribed to
+// it. It runs after the change has been saved, so failures are logged rather
+// than returned.
+func (s *webhookService) Dispatch(ctx context.Context, teamsID int, event string, actor *uuid.UUID, data interface{}) {
+	webhooks, err := s.webhookRepo.GetWebhooksByTeamID(ctx, nil, teamsID)
+	if err != nil {
+		log.Printf("%v: %v", dto.ErrDispatchWebhook, err)
+		return
+	}
+
+	var subscribed []entity.Webhook
+	for _, webhook := range webhooks {
+		if webhook.IsActive && subscribesTo(webhook, event) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(dto.WebhookPayload{
+		Event:      event,
+		TeamID:     teamsID,
+		ActorID:    actor,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		log.Printf("%v: %v", dto.ErrDispatchWebhook, err)
+		return
+	}
+
+	now := time.Now()
+	deliveries := make([]entity.WebhookDelivery, 0, len(subscribed))
+	for _, webhook := range subscribed {
+		deliveries = append(deliveries, entity.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        constants.ENUM_WEBHOOK_DELIVERY_PENDING,
+			NextAttemptAt: now,
+		})
+	}
+
+	if _, err := s.webhookRepo.CreateDeliveries(ctx, nil, deliveries); err != nil {
+		log.Printf("%v: %v", dto.ErrDispatchWebhook, err)
+		return
+	}
+	s.nudge()
+}
+
+// ProcessDeliveries makes one attempt for a batch of due deliveries and
+// returns how many it claimed. Any non-2xx answer is retried with
+// exponential backoff until WEBHOOK_MAX_ATTEMPTS, after which the delivery
+// is marked failed.
+func (s *webhookService) ProcessDeliveries(ctx context.Context) (int, error) {
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, nil, time.Now(), WEBHOOK_CLAIM_LEASE, WEBHOOK_BATCH_SIZE)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		delivery.Attempts++
+
+		switch {
+		case delivery.Webhook.ID == 0:
+			delivery.Status = constants.ENUM_WEBHOOK_DELIVERY_FAILED
+			delivery.LastError = "webhook was deleted"
+		case !delivery.Webhook.IsActive:
+			delivery.Status = constants.ENUM_WEBHOOK_DELIVERY_FAILED
+			delivery.LastError = "webhook is disabled"
+		default:
+			s.deliver(ctx, &delivery)
+		}
+
+		if err := s.webhookRepo.UpdateDelivery(ctx, nil, delivery); err != nil {
+			return len(deliveries), err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// StartWorker drains pending deliveries until ctx is cancelled, polling every
+// WEBHOOK_POLL_INTERVAL and immediately after new deliveries are queued.
+func (s *webhookService) StartWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(WEBHOOK_POLL_INTERVAL)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+
+			for {
+				claimed, err := s.ProcessDeliveries(ctx)
+				if err != nil {
+					log.Printf("Failed to process webhook deliveries: %v", err)
+					break
+				}
+				if claimed < WEBHOOK_BATCH_SIZE {
+					break
+				}
+			}
+		}
+	}()
+}
+
+func (s *webhookService) deliver(ctx context.Context, delivery *entity.WebhookDelivery) {
+	status, err := s.post(ctx, delivery)
+	delivery.ResponseStatus = status
+
+	if errors.Is(err, dto.ErrWebhookAddressBlocked) {
+		// Keep the resolved internal address out of the delivery log.
+		err = dto.ErrWebhookAddressBlocked
+	}
+	if err == nil && (status < 200 || status > 299) {
+		err = fmt.Errorf("receiver answered %d", status)
+	}
+
+	if err != nil {
+		delivery.LastError = truncate(err.Error(), WEBHOOK_RESPONSE_LIMIT)
+		if delivery.Attempts >= WEBHOOK_MAX_ATTEMPTS {
+			delivery.Status = constants.ENUM_WEBHOOK_DELIVERY_FAILED
+			log.Printf("Giving up on webhook delivery %d to %s after %d attempts: %v", delivery.ID, delivery.Webhook.URL, delivery.Attempts, err)
+		} else {
+			delivery.NextAttemptAt = time.Now().Add(WebhookRetryDelay(delivery.Attempts))
+		}
+		return
+	}
+
+	deliveredAt := time.Now()
+	delivery.Status = constants.ENUM_WEBHOOK_DELIVERY_DELIVERED
+	delivery.DeliveredAt = &deliveredAt
+	delivery.LastError = ""
+}
+
+// post sends the delivery and returns the status code. The response body is
+// discarded: the receiver controls it, and it is not ours to store or show.
+func (s *webhookService) post(ctx context.Context, delivery *entity.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-gin-clean-starter-webhook")
+	req.Header.Set(WEBHOOK_HEADER_EVENT, delivery.Event)
+	req.Header.Set(WEBHOOK_HEADER_DELIVERY, strconv.Itoa(delivery.ID))
+	req.Header.Set(WEBHOOK_HEADER_TIMESTAMP, timestamp)
+	req.Header.Set(WEBHOOK_HEADER_SIGNATURE, SignWebhookPayload(delivery.Webhook.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// validateURL checks that raw is an absolute http or https URL and, unless
+// private networks are allowed, that every address its host resolves to is
+// public.
+func (s *webhookService) validateURL(ctx context.Context, raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return dto.ErrInvalidWebhookURL
+	}
+	if s.config.AllowPrivateNetworks {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, parsed.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return dto.ErrInvalidWebhookURL
+	}
+	for _, addr := range addrs {
+		if !isPublicAddress(addr.IP) {
+			return dto.ErrWebhookAddressBlocked
+		}
+	}
+
+	return nil
+}
+
+func (s *webhookService) nudge() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature value for a payload:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with
+// the webhook secret. Receivers recompute it, compare in constant time and
+// reject stale timestamps to stop replays.
+func SignWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookRetryDelay is the wait before the next attempt after the given
+// number of failed attempts: WEBHOOK_RETRY_BASE doubled per failure, capped
+// at WEBHOOK_RETRY_MAX.
+func WebhookRetryDelay(attempts int) time.Duration {
+	return retryDelay(attempts, WEBHOOK_RETRY_BASE, WEBHOOK_RETRY_MAX)
+}
+
+// refuseInternalAddress is a net.Dialer Control hook that fails the
+// connection unless the resolved address is public.
+func refuseInternalAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicAddress(ip) {
+		return dto.ErrWebhookAddressBlocked
+	}
+	return nil
+}
+
+// blockedWebhookNetworks are the non-public ranges the net.IP helpers do
+// not cover.
+var blockedWebhookNetworks = parseCIDRs(
+	"0.0.0.0/8",     // "this network"
+	"100.64.0.0/10", // carrier-grade NAT
+	"198.18.0.0/15", // benchmarking
+	"64:ff9b::/96",  // NAT64, which can embed any IPv4 address
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func isPublicAddress(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range blockedWebhookNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func joinWebhookEvents(events []string) string {
+	var unique []string
+	for _, event := range events {
+		if !contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return strings.Join(unique, ",")
+}
+
+func splitWebhookEvents(events string) []string {
+	if events == "" {
+		return []string{}
+	}
+	return strings.Split(events, ",")
+}
+
+func subscribesTo(webhook entity.Webhook, event string) bool {
+	return webhook.Events == "" || contains(splitWebhookEvents(webhook.Events), event)
+}
+
+func toWebhookResponse(webhook entity.Webhook) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:        webhook.ID,
+		TeamsID:   webhook.TeamsID,
+		URL:       webhook.URL,
+		Events:    splitWebhookEvents(webhook.Events),
+		IsActive:  webhook.IsActive,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery entity.WebhookDelivery) dto.WebhookDeliveryResponse {
+	return dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"gorm.io/gorm"
+)
+
+type (
+	WorkflowService interface {
+		GetWorkflow(ctx context.Context, teamsID int) (dto.WorkflowResponse, error)
+		CreateStatus(ctx context.Context, teamsID int, req dto.WorkflowStatusCreateRequest) (dto.WorkflowStatusResponse, error)
+		UpdateStatus(ctx context.Context, teamsID int, statusId int, req dto.WorkflowStatusUpdateRequest) (dto.WorkflowStatusResponse, error)
+		DeleteStatus(ctx context.Context, teamsID int, statusId int) error
+		CreateTransition(ctx context.Context, teamsID int, req dto.WorkflowTransitionCreateRequest) (dto.WorkflowTransitionResponse, error)
+		DeleteTransition(ctx context.Context, teamsID int, transitionId int) error
+		ValidateStatus(ctx context.Context, teamsID int, status string) (string, error)
+		ValidateTransition(ctx context.Context, teamsID int, from string, to string) (string, error)
+		GetStatusCategory(ctx context.Context, teamsID int, status string) (string, error)
+	}
+
+	workflowService struct {
+		transactor   repository.Transactor
+		workflowRepo repository.WorkflowRepository
+		taskRepo     repository.TaskRepository
+	}
+)
+
+// defaultStatuses apply to teams that have not defined their own workflow.
+// Any transition between them is allowed. The first change a team makes to
+// its workflow copies them into the team's own tables.
+var defaultStatuses = []entity.WorkflowStatus{
+	{Name: "To Do", Position: 0, Category: constants.ENUM_STATUS_CATEGORY_TODO},
+	{Name: "In Progress", Position: 1, Category: constants.ENUM_STATUS_CATEGORY_IN_PROGRESS},
+	{Name: "Done", Position: 2, Category: constants.ENUM_STATUS_CATEGORY_DONE},
+}
+
+func NewWorkflowService(transactor repository.Transactor, workflowRepo repository.WorkflowRepository, taskRepo repository.TaskRepository) WorkflowService {
+	return &workflowService{
+		transactor:   transactor,
+		workflowRepo: workflowRepo,
+		taskRepo:     taskRepo,
+	}
+}
+
+func (s *workflowService) GetWorkflow(ctx context.Context, teamsID int) (dto.WorkflowResponse, error) {
+	statuses, transitions, isDefault, err := s.load(ctx, teamsID)
+	if err != nil {
+		return dto.WorkflowResponse{}, dto.ErrGetWorkflow
+	}
+
+	statusResponses := []dto.WorkflowStatusResponse{}
+	for _, status := range statuses {
+		statusResponses = append(statusResponses, toWorkflowStatusResponse(status))
+	}
+
+	transitionResponses := []dto.WorkflowTransitionResponse{}
+	for _, transition := range transitions {
+		transitionResponses = append(transitionResponses, dto.WorkflowTransitionResponse{
+			ID:           transition.ID,
+			FromStatusID: transition.FromStatusID,
+			ToStatusID:   transition.ToStatusID,
+		})
+	}
+
+	return dto.WorkflowResponse{
+		TeamsID:     teamsID,
+		IsDefault:   isDefault,
+		Statuses:    statusResponses,
+		Transitions: transitionResponses,
+	}, nil
+}
+
+func (s *workflowService) CreateStatus(ctx context.Context, teamsID int, req dto.WorkflowStatusCreateRequest) (dto.WorkflowStatusResponse, error) {
+	var status entity.WorkflowStatus
+	err := s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		statuses, err := s.customize(ctx, tx, teamsID)
+		if err != nil {
+			return dto.ErrCreateWorkflowStatus
+		}
+
+		name := strings.TrimSpace(req.Name)
+		if _, ok := findStatus(statuses, name); ok {
+			return dto.ErrWorkflowStatusExists
+		}
+
+		status, err = s.workflowRepo.CreateStatus(ctx, tx, entity.WorkflowStatus{
+			TeamsID:  teamsID,
+			Name:     name,
+			Position: req.Position,
+			Category: req.Category,
+		})
+		if err != nil {
+			return dto.ErrCreateWorkflowStatus
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.WorkflowStatusResponse{}, err
+	}
+
+	return toWorkflowStatusResponse(status), nil
+}
+
+func (s *workflowService) UpdateStatus(ctx context.Context, teamsID int, statusId int, req dto.WorkflowStatusUpdateRequest) (dto.WorkflowStatusResponse, error) {
+	status, err := s.workflowRepo.GetStatusById(ctx, nil, teamsID, statusId)
+	if err != nil {
+		return dto.WorkflowStatusResponse{}, dto.ErrWorkflowStatusNotFound
+	}
+
+	statuses, err := s.workflowRepo.GetStatusesByTeamID(ctx, nil, teamsID)
+	if err != nil {
+		return dto.WorkflowStatusResponse{}, dto.ErrUpdateWorkflowStatus
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if existing, ok := findStatus(statuses, name); ok && existing.ID != status.ID {
+		return dto.WorkflowStatusResponse{}, dto.ErrWorkflowStatusExists
+	}
+
+	previous := status.Name
+	status.Name = name
+	status.Position = req.Position
+	status.Category = req.Category
+
+	var updated entity.WorkflowStatus
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		// tasks store the status by name, so a rename has to follow through
+		if name != previous {
+			if err := s.taskRepo.RenameStatus(ctx, tx, teamsID, previous, name); err != nil {
+				return dto.ErrUpdateWorkflowStatus
+			}
+		}
+
+		updated, err = s.workflowRepo.UpdateStatus(ctx, tx, status)
+		if err != nil {
+			return dto.ErrUpdateWorkflowStatus
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.WorkflowStatusResponse{}, err
+	}
+
+	return toWorkflowStatusResponse(updated), nil
+}
+
+func (s *workflowService) DeleteStatus(ctx context.Context, teamsID int, statusId int) error {
+	status, err := s.workflowRepo.GetStatusById(ctx, nil, teamsID, statusId)
+	if err != nil {
+		return dto.ErrWorkflowStatusNotFound
+	}
+
+	count, err := s.taskRepo.CountTasksByStatus(ctx, nil, teamsID, status.Name)
+	if err != nil {
+		return dto.ErrDeleteWorkflowStatus
+	}
+
+	if count > 0 {
+		return dto.ErrWorkflowStatusInUse
+	}
+
+	if err := s.workflowRepo.DeleteStatus(ctx, nil, status.ID); err != nil {
+		return dto.ErrDeleteWorkflowStatus
+	}
+
+	return nil
+}
+
+func (s *workflowService) CreateTransition(ctx context.Context, teamsID int, req dto.WorkflowTransitionCreateRequest) (dto.WorkflowTransitionResponse, error) {
+	if _, err := s.workflowRepo.GetStatusById(ctx, nil, teamsID, req.FromStatusID); err != nil {
+		return dto.WorkflowTransitionResponse{}, dto.ErrWorkflowStatusNotFound
+	}
+
+	if _, err := s.workflowRepo.GetStatusById(ctx, nil, teamsID, req.ToStatusID); err != nil {
+		return dto.WorkflowTransitionResponse{}, dto.ErrWorkflowStatusNotFound
+	}
+
+	transitions, err := s.workflowRepo.GetTransitionsByTeamID(ctx, nil, teamsID)
+	if err != nil {
+		return dto.WorkflowTransitionResponse{}, dto.ErrCreateWorkflowTransition
+	}
+
+	for _, transition := range transitions {
+		if transition.FromStatusID == req.FromStatusID && transition.ToStatusID == req.ToStatusID {
+			return dto.WorkflowTransitionResponse{}, dto.ErrWorkflowTransitionExists
+		}
+	}
+
+	transition, err := s.workflowRepo.CreateTransition(ctx, nil, entity.WorkflowTransition{
+		TeamsID:      teamsID,
+		FromStatusID: req.FromStatusID,
+		ToStatusID:   req.ToStatusID,
+	})
+	if err != nil {
+		return dto.WorkflowTransitionResponse{}, dto.ErrCreateWorkflowTransition
+	}
+
+	return dto.WorkflowTransitionResponse{
+		ID:           transition.ID,
+		FromStatusID: transition.FromStatusID,
+		ToStatusID:   transition.ToStatusID,
+	}, nil
+}
+
+func (s *workflowService) DeleteTransition(ctx context.Context, teamsID int, transitionId int) error {
+	if err := s.workflowRepo.DeleteTransition(ctx, nil, teamsID, transitionId); err != nil {
+		return dto.ErrTransitionNotFound
+	}
+
+	return nil
+}
+
+// ValidateStatus returns the status name as spelled in the workflow, so
+// "done" and "DONE" are both stored as "Done".
+func (s *workflowService) ValidateStatus(ctx context.Context, teamsID int, status string) (string, error) {
+	statuses, _, _, err := s.load(ctx, teamsID)
+	if err != nil {
+		return "", err
+	}
+
+	found, ok := findStatus(statuses, status)
+	if !ok {
+		return "", dto.ErrInvalidStatus
+	}
+
+	return found.Name, nil
+}
+
+// ValidateTransition checks that a task may move from one status to another.
+// The default workflow allows any move between its statuses, a customized
+// one only the moves it lists, and a task whose current status is no longer
+// in the workflow may move to any valid status.
+func (s *workflowService) ValidateTransition(ctx context.Context, teamsID int, from string, to string) (string, error) {
+	statuses, transitions, isDefault, err := s.load(ctx, teamsID)
+	if err != nil {
+		return "", err
+	}
+
+	target, ok := findStatus(statuses, to)
+	if !ok {
+		return "", dto.ErrInvalidStatus
+	}
+
+	current, ok := findStatus(statuses, from)
+	if !ok || isDefault || current.ID == target.ID {
+		return target.Name, nil
+	}
+
+	for _, transition := range transitions {
+		if transition.FromStatusID == current.ID && transition.ToStatusID == target.ID {
+			return target.Name, nil
+		}
+	}
+
+	return "", dto.ErrTransitionNotAllowed
+}
+
+func (s *workflowService) GetStatusCategory(ctx context.Context, teamsID int, status string) (string, error) {
+	statuses, _, _, err := s.load(ctx, teamsID)
+	if err != nil {
+		return "", err
+	}
+
+	found, ok := findStatus(statuses, status)
+	if !ok {
+		return "", dto.ErrInvalidStatus
+	}
+
+	return found.Category, nil
+}
+
+func (s *workflowService) load(ctx context.Context, teamsID int) ([]entity.WorkflowStatus, []entity.WorkflowTransition, bool, error) {
+	statuses, err := s.workflowRepo.GetStatusesByTeamID(ctx, nil, teamsID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if len(statuses) == 0 {
+		return defaultStatuses, nil, true, nil
+	}
+
+	transitions, err := s.workflowRepo.GetTransitionsByTeamID(ctx, nil, teamsID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return statuses, transitions, false, nil
+}
+
+// customize returns the team's own statuses, first copying the default
+// workflow into the team's tables if it has none. Every move between the
+// copied statuses is allowed, as it was before, so the team's tasks are
+// not stuck once its workflow stops being the default.
+func (s *workflowService) customize(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.WorkflowStatus, error) {
+	statuses, err := s.workflowRepo.GetStatusesByTeamID(ctx, tx, teamsID)
+	if err != nil || len(statuses) > 0 {
+		return statuses, err
+	}
+
+	for _, status := range defaultStatuses {
+		status.TeamsID = teamsID
+		created, err := s.workflowRepo.CreateStatus(ctx, tx, status)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, created)
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if from.ID == to.ID {
+				continue
+			}
+			if _, err := s.workflowRepo.CreateTransition(ctx, tx, entity.WorkflowTransition{TeamsID: teamsID, FromStatusID: from.ID, ToStatusID: to.ID}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return statuses, nil
+}
+
+// DefaultDoneStatuses names the done statuses of the default workflow, for
+// queries that have to tell open tasks from finished ones in SQL.
+func DefaultDoneStatuses() []string {
+	var names []string
+	for _, status := range defaultStatuses {
+		if status.Category == constants.ENUM_STATUS_CATEGORY_DONE {
+			names = append(names, status.Name)
+		}
+	}
+	return names
+}
+
+func findStatus(statuses []entity.WorkflowStatus, name string) (entity.WorkflowStatus, bool) {
+	name = strings.TrimSpace(name)
+	for _, status := range statuses {
+		if strings.EqualFold(status.Name, name) {
+			return status, true
+		}
+	}
+	return entity.WorkflowStatus{}, false
+}
+
+func toWorkflowStatusResponse(status entity.WorkflowStatus) dto.WorkflowStatusResponse {
+	return dto.WorkflowStatusResponse{
+		ID:       status.ID,
+		Name:     status.Name,
+		Position: status.Position,
+		Category: status.Category,
+	}
+}
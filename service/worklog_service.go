@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	WorklogService interface {
+		Create(ctx context.Context, taskId string, userId string, req dto.WorklogCreateRequest) (dto.WorklogResponse, error)
+		GetWorklogsByTaskId(ctx context.Context, taskId string) ([]dto.WorklogResponse, error)
+		Delete(ctx context.Context, taskId string, worklogId int, userId string) error
+		StartTimer(ctx context.Context, taskId string, userId string, req dto.WorklogTimerRequest) (dto.WorklogResponse, error)
+		StopTimer(ctx context.Context, taskId string, userId string) (dto.WorklogResponse, error)
+		GetTaskSummary(ctx context.Context, taskId string, req dto.WorklogSummaryRequest) (dto.WorklogSummaryResponse, error)
+		GetTeamSummary(ctx context.Context, teamsID int, req dto.WorklogSummaryRequest) (dto.WorklogSummaryResponse, error)
+		GetUserSummary(ctx context.Context, userId string, req dto.WorklogSummaryRequest) (dto.WorklogSummaryResponse, error)
+	}
+
+	worklogService struct {
+		transactor           repository.Transactor
+		worklogRepo          repository.WorklogRepository
+		taskRepo             repository.TaskRepository
+		authorizationService AuthorizationService
+	}
+)
+
+func NewWorklogService(transactor repository.Transactor, worklogRepo repository.WorklogRepository, taskRepo repository.TaskRepository, authorizationService AuthorizationService) WorklogService {
+	return &worklogService{
+		transactor:           transactor,
+		worklogRepo:          worklogRepo,
+		taskRepo:             taskRepo,
+		authorizationService: authorizationService,
+	}
+}
+
+// Create logs time the caller already spent on the task. A manual entry
+// cannot end in the future.
+func (s *worklogService) Create(ctx context.Context, taskId string, userId string, req dto.WorklogCreateRequest) (dto.WorklogResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.WorklogResponse{}, dto.ErrTaskNotFound
+	}
+
+	user, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.WorklogResponse{}, dto.ErrUserNotFound
+	}
+
+	startedAt, err := time.Parse(time.RFC3339, req.StartedAt)
+	if err != nil || req.Duration < 1 || startedAt.Add(time.Duration(req.Duration)*time.Second).After(time.Now()) {
+		return dto.WorklogResponse{}, dto.ErrInvalidWorklog
+	}
+
+	worklog, err := s.worklogRepo.CreateWorklog(ctx, nil, entity.Worklog{
+		TaskID:    task.ID,
+		UserID:    user,
+		StartedAt: startedAt,
+		Duration:  req.Duration,
+		Note:      strings.TrimSpace(req.Note),
+	})
+	if err != nil {
+		return dto.WorklogResponse{}, dto.ErrCreateWorklog
+	}
+
+	return s.getWorklog(ctx, task.ID, worklog.ID)
+}
+
+func (s *worklogService) GetWorklogsByTaskId(ctx context.Context, taskId string) ([]dto.WorklogResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return nil, dto.ErrTaskNotFound
+	}
+
+	worklogs, err := s.worklogRepo.GetWorklogsByTaskId(ctx, nil, task.ID)
+	if err != nil {
+		return nil, dto.ErrGetAllWorklog
+	}
+
+	responses := []dto.WorklogResponse{}
+	for _, worklog := range worklogs {
+		responses = append(responses, toWorklogResponse(worklog))
+	}
+
+	return responses, nil
+}
+
+// Delete lets a user remove their own worklog; anyone else needs to be
+// allowed to manage the team's worklogs.
+func (s *worklogService) Delete(ctx context.Context, taskId string, worklogId int, userId string) error {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.ErrTaskNotFound
+	}
+
+	worklog, err := s.worklogRepo.GetWorklogById(ctx, nil, task.ID, worklogId)
+	if err != nil {
+		return dto.ErrWorklogNotFound
+	}
+
+	if worklog.UserID.String() != userId {
+		if err := s.authorizationService.AuthorizeTeam(ctx, userId, task.TeamsID, constants.ACTION_TASK_WORKLOG_MANAGE); err != nil {
+			return err
+		}
+	}
+
+	if err := s.worklogRepo.DeleteWorklog(ctx, nil, worklog.ID); err != nil {
+		return dto.ErrDeleteWorklog
+	}
+
+	return nil
+}
+
+// StartTimer starts timing the caller's work on the task. A user has at most
+// one running timer, so it must be stopped before another is started. The
+// user is locked while that is checked, so two timers started at once cannot
+// both get through.
+func (s *worklogService) StartTimer(ctx context.Context, taskId string, userId string, req dto.WorklogTimerRequest) (dto.WorklogResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.WorklogResponse{}, dto.ErrTaskNotFound
+	}
+
+	user, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.WorklogResponse{}, dto.ErrUserNotFound
+	}
+
+	var worklog entity.Worklog
+	err = s.transactor.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.worklogRepo.LockTimers(ctx, tx, user); err != nil {
+			return dto.ErrStartTimer
+		}
+
+		running, err := s.worklogRepo.GetRunningWorklog(ctx, tx, user)
+		if err == nil {
+			return fmt.Errorf("%w on task #%d", dto.ErrTimerRunning, running.TaskID)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.ErrStartTimer
+		}
+
+		worklog, err = s.worklogRepo.CreateWorklog(ctx, tx, entity.Worklog{
+			TaskID:    task.ID,
+			UserID:    user,
+			StartedAt: time.Now(),
+			Note:      strings.TrimSpace(req.Note),
+			IsRunning: true,
+		})
+		if err != nil {
+			return dto.ErrStartTimer
+		}
+
+		return nil
+	})
+	if err != nil {
+		return dto.WorklogResponse{}, err
+	}
+
+	return s.getWorklog(ctx, task.ID, worklog.ID)
+}
+
+// StopTimer stops the caller's running timer on the task and records the
+// time elapsed since it was started.
+func (s *worklogService) StopTimer(ctx context.Context, taskId string, userId string) (dto.WorklogResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.WorklogResponse{}, dto.ErrTaskNotFound
+	}
+
+	user, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.WorklogResponse{}, dto.ErrUserNotFound
+	}
+
+	running, err := s.worklogRepo.GetRunningWorklog(ctx, nil, user)
+	if err != nil || running.TaskID != task.ID {
+		return dto.WorklogResponse{}, dto.ErrNoRunningTimer
+	}
+
+	duration := int(time.Since(running.StartedAt).Round(time.Second) / time.Second)
+	if err := s.worklogRepo.StopWorklog(ctx, nil, running.ID, duration); err != nil {
+		return dto.WorklogResponse{}, dto.ErrStopTimer
+	}
+
+	return s.getWorklog(ctx, task.ID, running.ID)
+}
+
+// GetTaskSummary totals the time logged on a task per user.
+func (s *worklogService) GetTaskSummary(ctx context.Context, taskId string, req dto.WorklogSummaryRequest) (dto.WorklogSummaryResponse, error) {
+	task, err := s.taskRepo.GetTaskById(ctx, nil, taskId)
+	if err != nil {
+		return dto.WorklogSummaryResponse{}, dto.ErrTaskNotFound
+	}
+
+	filter, err := parseWorklogFilter(req)
+	if err != nil {
+		return dto.WorklogSummaryResponse{}, err
+	}
+	filter.TaskID = task.ID
+
+	return s.summarize(ctx, filter, true, false)
+}
+
+// GetTeamSummary totals the time logged on a team's tasks per user and per
+// task, optionally for a single member.
+func (s *worklogService) GetTeamSummary(ctx context.Context, teamsID int, req dto.WorklogSummaryRequest) (dto.WorklogSummaryResponse, error) {
+	filter, err := parseWorklogFilter(req)
+	if err != nil {
+		return dto.WorklogSummaryResponse{}, err
+	}
+	filter.TeamID = teamsID
+
+	return s.summarize(ctx, filter, true, true)
+}
+
+// GetUserSummary totals the time the user logged per task, across teams.
+func (s *worklogService) GetUserSummary(ctx context.Context, userId string, req dto.WorklogSummaryRequest) (dto.WorklogSummaryResponse, error) {
+	user, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.WorklogSummaryResponse{}, dto.ErrUserNotFound
+	}
+
+	req.UserID = ""
+	filter, err := parseWorklogFilter(req)
+	if err != nil {
+		return dto.WorklogSummaryResponse{}, err
+	}
+	filter.UserID = &user
+
+	return s.summarize(ctx, filter, false, true)
+}
+
+func (s *worklogService) summarize(ctx context.Context, filter dto.WorklogFilter, byUser bool, byTask bool) (dto.WorklogSummaryResponse, error) {
+	summary := dto.WorklogSummaryResponse{From: filter.From, To: filter.To}
+
+	if byUser {
+		users, err := s.worklogRepo.SumByUser(ctx, nil, filter)
+		if err != nil {
+			return dto.WorklogSummaryResponse{}, dto.ErrGetWorklogSummary
+		}
+		summary.Users = append([]dto.WorklogUserTotal{}, users...)
+	}
+
+	if byTask {
+		tasks, err := s.worklogRepo.SumByTask(ctx, nil, filter)
+		if err != nil {
+			return dto.WorklogSummaryResponse{}, dto.ErrGetWorklogSummary
+		}
+		summary.Tasks = append([]dto.WorklogTaskTotal{}, tasks...)
+	}
+
+	// Both breakdowns cover the same worklogs, so either one gives the total.
+	if byUser {
+		for _, user := range summary.Users {
+			summary.TotalDuration += user.Duration
+		}
+	} else {
+		for _, task := range summary.Tasks {
+			summary.TotalDuration += task.Duration
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *worklogService) getWorklog(ctx context.Context, taskId int, worklogId int) (dto.WorklogResponse, error) {
+	worklog, err := s.worklogRepo.GetWorklogById(ctx, nil, taskId, worklogId)
+	if err != nil {
+		return dto.WorklogResponse{}, dto.ErrWorklogNotFound
+	}
+
+	return toWorklogResponse(worklog), nil
+}
+
+// parseWorklogFilter validates the date range, which accepts the same
+// formats as the task list's due date filters, and the optional user.
+func parseWorklogFilter(req dto.WorklogSummaryRequest) (dto.WorklogFilter, error) {
+	from, err := parseFilterTime("from", req.From)
+	if err != nil {
+		return dto.WorklogFilter{}, dto.ErrInvalidWorklogDate
+	}
+
+	to, err := parseFilterTime("to", req.To)
+	if err != nil {
+		return dto.WorklogFilter{}, dto.ErrInvalidWorklogDate
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return dto.WorklogFilter{}, dto.ErrInvalidWorklogDate
+	}
+
+	filter := dto.WorklogFilter{From: from, To: to}
+	if userId := strings.TrimSpace(req.UserID); userId != "" {
+		user, err := uuid.Parse(userId)
+		if err != nil {
+			return dto.WorklogFilter{}, dto.ErrUserNotFound
+		}
+		filter.UserID = &user
+	}
+
+	return filter, nil
+}
+
+func toWorklogResponse(worklog entity.Worklog) dto.WorklogResponse {
+	return dto.WorklogResponse{
+		ID:        worklog.ID,
+		TaskID:    worklog.TaskID,
+		User:      toUserResponse(worklog.User),
+		StartedAt: worklog.StartedAt,
+		Duration:  worklog.Duration,
+		Note:      worklog.Note,
+		IsRunning: worklog.IsRunning,
+		CreatedAt: worklog.CreatedAt,
+	}
+}
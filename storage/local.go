@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage keeps files on disk and serves them itself through ServeHTTP,
+// which only accepts URLs issued by SignedURL.
+type LocalStorage struct {
+	root    string
+	baseURL string
+	secret  []byte
+	// Now is the clock signed URLs are issued and checked against.
+	Now func() time.Time
+}
+
+func NewLocalStorage(root string, baseURL string, secret []byte) *LocalStorage {
+	return &LocalStorage{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+		Now:     time.Now,
+	}
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+// Put writes to a temporary file first so readers never see a partial
+// upload under the final name.
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+
+	return f, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// SignedURL returns a URL under baseURL that ServeHTTP accepts until expiry
+// has passed. The signature is an HMAC-SHA256 of the key and the expiry.
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	if expiry <= 0 {
+		expiry = DEFAULT_SIGNED_URL_EXPIRY
+	}
+
+	expires := strconv.FormatInt(s.Now().Add(expiry).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(cleaned, expires))
+
+	return s.baseURL + "/" + (&url.URL{Path: cleaned}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// ServeHTTP serves a file named by a URL from SignedURL. A missing, forged
+// or expired signature is rejected, and the response is sandboxed so an
+// uploaded file can never run as a page of the API origin.
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, s.baseURL+"/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	expires := r.URL.Query().Get("expires")
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.Now().Unix() > expiresAt ||
+		!hmac.Equal([]byte(r.URL.Query().Get("signature")), []byte(s.sign(cleaned, expires))) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	filePath, _ := s.path(cleaned)
+	f, err := os.Open(filePath)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	http.ServeContent(w, r, filepath.Base(filePath), info.ModTime(), f)
+}
+
+func (s *LocalStorage) sign(key string, expires string) string {
+	return hex.EncodeToString(hmacSHA256(s.secret, key+"\n"+expires))
+}
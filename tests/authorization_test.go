@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// fakeMembershipRepository knows every membership by user and team, unlike
+// fakeUserTeamsRepository which keeps a single team per user.
+type fakeMembershipRepository struct {
+	repository.UserTeamsRepository
+	roles map[string]string
+}
+
+func newFakeMembershipRepository() *fakeMembershipRepository {
+	return &fakeMembershipRepository{roles: map[string]string{}}
+}
+
+func (r *fakeMembershipRepository) add(userId uuid.UUID, teamId int, role string) {
+	r.roles[fmt.Sprintf("%s/%d", userId, teamId)] = role
+}
+
+func (r *fakeMembershipRepository) GetMembership(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint) (entity.UserTeams, error) {
+	role, ok := r.roles[fmt.Sprintf("%s/%d", userId, teamId)]
+	if !ok {
+		return entity.UserTeams{}, gorm.ErrRecordNotFound
+	}
+	return entity.UserTeams{UserID: userId, TeamID: teamId, Role: role}, nil
+}
+
+type authorizationTest struct {
+	users   *fakeUserRepository
+	members *fakeMembershipRepository
+	tasks   *fakeTaskTreeRepository
+	service service.AuthorizationService
+}
+
+func setUpAuthorizationTest() authorizationTest {
+	users := &fakeUserRepository{}
+	members := newFakeMembershipRepository()
+	tasks := newFakeTaskTreeRepository()
+
+	return authorizationTest{
+		users:   users,
+		members: members,
+		tasks:   tasks,
+		service: service.NewAuthorizationService(users, members, tasks),
+	}
+}
+
+// user registers a user with the given global role and team memberships,
+// passed as team id and team role pairs.
+func (at authorizationTest) user(role string, memberships map[int]string) string {
+	user := entity.User{ID: uuid.New(), Role: role}
+	at.users.users = append(at.users.users, user)
+	for teamId, teamRole := range memberships {
+		at.members.add(user.ID, teamId, teamRole)
+	}
+	return user.ID.String()
+}
+
+func Test_Authorization_Policies(t *testing.T) {
+	at := setUpAuthorizationTest()
+	owner := at.user(constants.ENUM_ROLE_USER, map[int]string{1: constants.ENUM_TEAM_ROLE_OWNER})
+	maintainer := at.user(constants.ENUM_ROLE_USER, map[int]string{1: constants.ENUM_TEAM_ROLE_MAINTAINER})
+	member := at.user(constants.ENUM_ROLE_USER, map[int]string{1: constants.ENUM_TEAM_ROLE_MEMBER})
+	viewer := at.user(constants.ENUM_ROLE_USER, map[int]string{1: constants.ENUM_TEAM_ROLE_VIEWER})
+	outsider := at.user(constants.ENUM_ROLE_USER, map[int]string{2: constants.ENUM_TEAM_ROLE_OWNER})
+
+	allowed := map[string][]string{
+		constants.ACTION_USER_LIST:            {},
+		constants.ACTION_TEAM_CREATE:          {owner, maintainer, member, viewer, outsider},
+		constants.ACTION_TASK_LIST:            {owner, maintainer, member, viewer, outsider},
+		constants.ACTION_TASK_READ:            {owner, maintainer, member, viewer},
+		constants.ACTION_TASK_CREATE:          {owner, maintainer, member},
+		constants.ACTION_TASK_UPDATE:          {owner, maintainer, member},
+		constants.ACTION_TASK_DELETE:          {owner, maintainer},
+		constants.ACTION_TEAM_MEMBER_ADD:      {owner, maintainer},
+		constants.ACTION_TEAM_MEMBER_ROLE:     {owner},
+		constants.ACTION_TEAM_DELETE:          {owner},
+		constants.ACTION_WEBHOOK_MANAGE:       {owner, maintainer},
+		constants.ACTION_TASK_ATTACHMENT_READ: {owner, maintainer, member, viewer},
+	}
+
+	names := map[string]string{owner: "owner", maintainer: "maintainer", member: "member", viewer: "viewer", outsider: "outsider"}
+	for action, users := range allowed {
+		for userId, name := range names {
+			err := at.service.Authorize(context.Background(), dto.AuthorizeRequest{
+				UserID: userId,
+				Role:   constants.ENUM_ROLE_USER,
+				Action: action,
+				TeamID: "1",
+			})
+			if contains(users, userId) {
+				assert.NoError(t, err, "%s may %s", name, action)
+			} else {
+				assert.Error(t, err, "%s may not %s", name, action)
+			}
+		}
+	}
+}
+
+func Test_Authorization_Middleware(t *testing.T) {
+	at := setUpAuthorizationTest()
+	task, err := at.tasks.RegisterTask(context.Background(), nil, entity.Task{Title: "Ship it", TeamsID: 1})
+	require.NoError(t, err)
+
+	admin := at.user(constants.ENUM_ROLE_ADMIN, nil)
+	member := at.user(constants.ENUM_ROLE_USER, map[int]string{1: constants.ENUM_TEAM_ROLE_MEMBER})
+	viewer := at.user(constants.ENUM_ROLE_USER, map[int]string{1: constants.ENUM_TEAM_ROLE_VIEWER})
+	outsider := at.user(constants.ENUM_ROLE_USER, map[int]string{2: constants.ENUM_TEAM_ROLE_OWNER})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authenticate := func(ctx *gin.Context) {
+		userId := ctx.GetHeader("X-User")
+		user, _ := at.users.GetUserById(ctx, nil, userId)
+		ctx.Set("user_id", userId)
+		ctx.Set("role", user.Role)
+	}
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+	router.POST("/api/team", authenticate, middleware.Authorize(at.service, constants.ACTION_TEAM_CREATE), ok)
+	router.DELETE("/api/team/:teamId", authenticate, middleware.Authorize(at.service, constants.ACTION_TEAM_DELETE), ok)
+	router.GET("/api/tasks/:taskId", authenticate, middleware.Authorize(at.service, constants.ACTION_TASK_READ), ok)
+	router.PATCH("/api/tasks/:taskId", authenticate, middleware.Authorize(at.service, constants.ACTION_TASK_UPDATE), ok)
+	router.GET("/api/user", authenticate, middleware.Authorize(at.service, constants.ACTION_USER_LIST), ok)
+	router.GET("/api/unknown", authenticate, middleware.Authorize(at.service, "task:unknown"), ok)
+
+	taskUrl := fmt.Sprintf("/api/tasks/%d", task.ID)
+	for _, tc := range []struct {
+		name   string
+		user   string
+		method string
+		url    string
+		status int
+	}{
+		{"admin bypasses team roles", admin, http.MethodDelete, "/api/team/1", http.StatusOK},
+		{"global role allows", outsider, http.MethodPost, "/api/team", http.StatusOK},
+		{"team role allows", member, http.MethodPatch, taskUrl, http.StatusOK},
+		{"team role too low", viewer, http.MethodPatch, taskUrl, http.StatusForbidden},
+		{"viewer reads task", viewer, http.MethodGet, taskUrl, http.StatusOK},
+		{"non-member of task team", outsider, http.MethodGet, taskUrl, http.StatusForbidden},
+		{"non-member of team", member, http.MethodDelete, "/api/team/2", http.StatusForbidden},
+		{"unknown task", member, http.MethodGet, "/api/tasks/999", http.StatusNotFound},
+		{"invalid team", member, http.MethodDelete, "/api/team/core", http.StatusNotFound},
+		{"admin lists users", admin, http.MethodGet, "/api/user", http.StatusOK},
+		{"user may not list users", outsider, http.MethodGet, "/api/user", http.StatusForbidden},
+		{"unknown action", admin, http.MethodGet, "/api/unknown", http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.url, nil)
+			req.Header.Set("X-User", tc.user)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+func Test_Task_CreateRequiresTeamWriter(t *testing.T) {
+	st := setUpSubtaskTest()
+	nt := setUpNotificationTest()
+	members := newFakeMembershipRepository()
+	members.add(nt.alice.ID, 1, constants.ENUM_TEAM_ROLE_MEMBER)
+	members.add(nt.bob.ID, 1, constants.ENUM_TEAM_ROLE_VIEWER)
+	taskService := service.NewTaskService(fakeTransactor{}, st.taskRepo, nt.userRepo, members, st.checklistRepo, nil, nt.assignees, newFakeLabelRepository(), service.NewAuthorizationService(nt.userRepo, members, st.taskRepo), service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil), nil, service.TaskServiceDeps{})
+
+	create := func(userId string, teamsID int) error {
+		_, err := taskService.Register(context.Background(), dto.TaskCreateRequest{
+			Title:       "task",
+			Description: "description",
+			Status:      "To Do",
+			DueDate:     "2030-01-01T00:00:00Z",
+			TeamsID:     teamsID,
+		}, userId)
+		return err
+	}
+
+	assert.NoError(t, create(nt.alice.ID.String(), 1))
+	assert.ErrorIs(t, create(nt.alice.ID.String(), 2), dto.ErrNotTeamMember)
+	assert.ErrorIs(t, create(nt.bob.ID.String(), 1), dto.ErrPermissionDenied)
+	assert.ErrorIs(t, create(nt.carol.ID.String(), 1), dto.ErrNotTeamMember)
+	assert.Len(t, st.taskRepo.tasks, 1)
+}
+
+func Test_Task_AssignedListIsOwnUnlessAdmin(t *testing.T) {
+	nt := setUpNotificationTest()
+	admin := entity.User{ID: uuid.New(), Role: constants.ENUM_ROLE_ADMIN}
+	nt.userRepo.users = append(nt.userRepo.users, admin)
+	taskService := service.NewTaskService(fakeTransactor{}, &fakeAssignedTaskRepository{}, nt.userRepo, nil, nil, nil, nt.assignees, newFakeLabelRepository(), nil, service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil), nil, service.TaskServiceDeps{})
+	ctx := context.Background()
+
+	_, err := taskService.GetTasksByUserID(ctx, nt.bob.ID.String(), nt.bob.ID.String())
+	assert.NoError(t, err)
+	_, err = taskService.GetTasksByUserID(ctx, nt.bob.ID.String(), admin.ID.String())
+	assert.NoError(t, err)
+	_, err = taskService.GetTasksByUserID(ctx, nt.bob.ID.String(), nt.alice.ID.String())
+	assert.ErrorIs(t, err, dto.ErrPermissionDenied)
+}
+
+type fakeAssignedTaskRepository struct {
+	repository.TaskRepository
+}
+
+func (r *fakeAssignedTaskRepository) GetTasksByUserID(ctx context.Context, userID string) ([]entity.Task, error) {
+	return nil, nil
+}
+
+func Test_FilterTasks_MemberScope(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	memberId := uuid.MustParse("8f9b3a63-31c4-4d0c-9f4f-5a2a6f9a0c11")
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var tasks []entity.Task
+		return tx.Scopes(repository.FilterTasks("", dto.TaskFilter{MemberID: &memberId})).Find(&tasks)
+	})
+
+	assert.Contains(t, sql, "teams_id IN (SELECT team_id FROM user_teams WHERE user_id = '8f9b3a63-31c4-4d0c-9f4f-5a2a6f9a0c11')")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
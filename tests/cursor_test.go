@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_Cursor_RoundTrip(t *testing.T) {
+	columns := repository.TaskKeysetColumns([]dto.TaskSort{{Field: "due_date", Desc: true}})
+	token := repository.EncodeCursor(columns, []string{"2024-06-01 00:00:00", "42"}, true)
+
+	values, prev, err := repository.DecodeCursor(token, columns)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-06-01 00:00:00", "42"}, values)
+	assert.True(t, prev)
+
+	// A cursor is only valid for the ordering it was issued with.
+	_, _, err = repository.DecodeCursor(token, repository.TaskKeysetColumns(nil))
+	assert.ErrorIs(t, err, dto.ErrInvalidCursor)
+
+	_, _, err = repository.DecodeCursor("not-a-cursor", columns)
+	assert.ErrorIs(t, err, dto.ErrInvalidCursor)
+}
+
+func Test_KeysetPaginate_Query(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var queries []string
+	db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		queries = append(queries, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+
+	columns := repository.TaskKeysetColumns([]dto.TaskSort{{Field: "due_date", Desc: true}})
+	query := func() *gorm.DB { return db.Model(&entity.Task{}) }
+	values := func(task entity.Task) []string { return nil }
+
+	_, meta, err := repository.KeysetPaginate(query, dto.PaginationRequest{PerPage: 10, Keyset: true}, columns, values)
+	require.NoError(t, err)
+	assert.Empty(t, meta.NextCursor)
+	assert.Zero(t, meta.Count)
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "ORDER BY `due_date` DESC,`id` LIMIT 11")
+
+	queries = nil
+	next := repository.EncodeCursor(columns, []string{"2024-06-01 00:00:00", "42"}, false)
+	_, _, err = repository.KeysetPaginate(query, dto.PaginationRequest{PerPage: 10, Cursor: next}, columns, values)
+	require.NoError(t, err)
+	assert.Contains(t, queries[0], "WHERE ((due_date < '2024-06-01 00:00:00') OR (due_date = '2024-06-01 00:00:00' AND id > '42'))")
+	assert.Contains(t, queries[0], "ORDER BY `due_date` DESC,`id` LIMIT 11")
+
+	queries = nil
+	prev := repository.EncodeCursor(columns, []string{"2024-06-01 00:00:00", "42"}, true)
+	_, _, err = repository.KeysetPaginate(query, dto.PaginationRequest{PerPage: 10, Cursor: prev, WithCount: true}, columns, values)
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+	assert.Contains(t, queries[0], "WHERE ((due_date > '2024-06-01 00:00:00') OR (due_date = '2024-06-01 00:00:00' AND id < '42'))")
+	assert.Contains(t, queries[0], "ORDER BY `due_date`,`id` DESC LIMIT 11")
+	assert.Contains(t, queries[1], "SELECT count(*) FROM `tasks`")
+}
+
+func Test_KeysetPaginate_PageSize(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var queries []string
+	db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		queries = append(queries, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+
+	columns := repository.TaskKeysetColumns(nil)
+	query := func() *gorm.DB { return db.Model(&entity.Task{}) }
+	values := func(task entity.Task) []string { return nil }
+
+	for perPage, limit := range map[int]string{0: "LIMIT 21", -1: "LIMIT 21", -2: "LIMIT 21", 100000: "LIMIT 101"} {
+		queries = nil
+		_, meta, err := repository.KeysetPaginate(query, dto.PaginationRequest{PerPage: perPage, Keyset: true, WithCount: true}, columns, values)
+		require.NoError(t, err, perPage)
+		assert.True(t, strings.HasSuffix(queries[0], limit), queries[0])
+		assert.Positive(t, meta.PerPage)
+	}
+}
+
+func Test_Pagination_PerPageBinding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	bind := func(query string) error {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+		var req dto.PaginationRequest
+		return ctx.ShouldBind(&req)
+	}
+
+	// 0 is the same as leaving it out, and gets the default.
+	assert.NoError(t, bind(""))
+	assert.NoError(t, bind("per_page=0"))
+	assert.NoError(t, bind("per_page=100"))
+	assert.Error(t, bind("per_page=-2"))
+	assert.Error(t, bind("per_page=101"))
+}
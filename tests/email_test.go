@@ -0,0 +1,317 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+	"github.com/Caknoooo/go-gin-clean-starter/migrations"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeEmailOutboxRepository struct {
+	mu     sync.Mutex
+	emails []entity.EmailOutbox
+	err    error
+}
+
+func newFakeEmailOutboxRepository() *fakeEmailOutboxRepository {
+	return &fakeEmailOutboxRepository{}
+}
+
+func (r *fakeEmailOutboxRepository) Enqueue(ctx context.Context, tx *gorm.DB, email entity.EmailOutbox) (entity.EmailOutbox, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return entity.EmailOutbox{}, r.err
+	}
+	email.ID = len(r.emails) + 1
+	r.emails = append(r.emails, email)
+	return email, nil
+}
+
+func (r *fakeEmailOutboxRepository) ClaimDue(ctx context.Context, tx *gorm.DB, now time.Time, lease time.Duration, limit int) ([]entity.EmailOutbox, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []entity.EmailOutbox
+	for i := range r.emails {
+		email := &r.emails[i]
+		if len(claimed) == limit || email.Status != constants.ENUM_EMAIL_STATUS_PENDING || email.NextAttemptAt.After(now) {
+			continue
+		}
+		if email.LockedUntil != nil && email.LockedUntil.After(now) {
+			continue
+		}
+		lockedUntil := now.Add(lease)
+		email.LockedUntil = &lockedUntil
+		claimed = append(claimed, *email)
+	}
+	return claimed, nil
+}
+
+func (r *fakeEmailOutboxRepository) UpdateDelivery(ctx context.Context, tx *gorm.DB, email entity.EmailOutbox) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	email.LockedUntil = nil
+	r.emails[email.ID-1] = email
+	return nil
+}
+
+func (r *fakeEmailOutboxRepository) get(id int) entity.EmailOutbox {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.emails[id-1]
+}
+
+// fakeSMTPServer speaks just enough SMTP for gomail to hand over a message.
+type fakeSMTPServer struct {
+	listener net.Listener
+	messages chan *mail.Message
+}
+
+func startFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTPServer{listener: listener, messages: make(chan *mail.Message, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTPServer) config() config.EmailConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return config.EmailConfig{Host: addr.IP.String(), Port: addr.Port, AuthEmail: "no-reply@example.com"}
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost fake SMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case command == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			if msg, err := mail.ReadMessage(strings.NewReader(data.String())); err == nil {
+				s.messages <- msg
+			}
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) receive(t *testing.T) *mail.Message {
+	select {
+	case msg := <-s.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message reached the fake SMTP server")
+		return nil
+	}
+}
+
+func Test_EmailOutbox_DeliversThroughSMTP(t *testing.T) {
+	ctx := context.Background()
+	server := startFakeSMTPServer(t)
+	outbox := newFakeEmailOutboxRepository()
+	emailService := service.NewEmailService(outbox, utils.NewSMTPMailer(server.config()))
+
+	require.NoError(t, emailService.Enqueue(ctx, "bob@example.com", "Hello", "<p>Hi Bob</p>"))
+
+	claimed, err := emailService.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+
+	msg := server.receive(t)
+	assert.Equal(t, "Hello", msg.Header.Get("Subject"))
+	assert.Equal(t, "bob@example.com", msg.Header.Get("To"))
+
+	sent := outbox.get(1)
+	assert.Equal(t, constants.ENUM_EMAIL_STATUS_SENT, sent.Status)
+	assert.Equal(t, 1, sent.Attempts)
+	assert.NotNil(t, sent.SentAt)
+}
+
+func Test_EmailOutbox_RetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+
+	// Nothing listens on a closed listener's port, so every send fails.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	outbox := newFakeEmailOutboxRepository()
+	emailService := service.NewEmailService(outbox, utils.NewSMTPMailer(config.EmailConfig{Host: "127.0.0.1", Port: port, AuthEmail: "no-reply@example.com"}))
+	require.NoError(t, emailService.Enqueue(ctx, "bob@example.com", "Hello", "<p>Hi Bob</p>"))
+
+	before := time.Now()
+	_, err = emailService.ProcessOutbox(ctx)
+	require.NoError(t, err)
+
+	failed := outbox.get(1)
+	assert.Equal(t, constants.ENUM_EMAIL_STATUS_PENDING, failed.Status)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.NotEmpty(t, failed.LastError)
+	assert.False(t, failed.NextAttemptAt.Before(before.Add(service.EMAIL_RETRY_BASE)))
+
+	// Not due yet, so nothing is claimed.
+	claimed, err := emailService.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, claimed)
+
+	failed.Attempts = service.EMAIL_MAX_ATTEMPTS - 1
+	failed.NextAttemptAt = time.Now().Add(-time.Second)
+	require.NoError(t, outbox.UpdateDelivery(ctx, nil, failed))
+
+	_, err = emailService.ProcessOutbox(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_EMAIL_STATUS_FAILED, outbox.get(1).Status)
+}
+
+func Test_EmailRetryDelay(t *testing.T) {
+	assert.Equal(t, service.EMAIL_RETRY_BASE, service.EmailRetryDelay(1))
+	assert.Equal(t, 2*service.EMAIL_RETRY_BASE, service.EmailRetryDelay(2))
+	assert.Equal(t, 4*service.EMAIL_RETRY_BASE, service.EmailRetryDelay(3))
+	assert.Equal(t, service.EMAIL_RETRY_MAX, service.EmailRetryDelay(service.EMAIL_MAX_ATTEMPTS))
+}
+
+func Test_EmailOutbox_ClaimDueSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	var sql string
+	db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	})
+
+	_, err = repository.NewEmailOutboxRepository(db).ClaimDue(context.Background(), nil, time.Now(), time.Minute, 5)
+	require.NoError(t, err)
+	assert.Contains(t, sql, "UPDATE `email_outboxes` SET")
+	assert.Contains(t, sql, "ORDER BY next_attempt_at ASC LIMIT ?")
+}
+
+var verifyTokenPattern = regexp.MustCompile(`token=([0-9a-f]+)`)
+
+func Test_Register_VerificationEmailFlow(t *testing.T) {
+	ctx := context.Background()
+	server := startFakeSMTPServer(t)
+	outbox := newFakeEmailOutboxRepository()
+	emailService := service.NewEmailService(outbox, utils.NewSMTPMailer(server.config()))
+
+	userRepo := &fakeUserRepository{}
+	sessionRepo := newFakeSessionRepository()
+	keyService := service.NewSigningKeyService(config.JWTConfig{Algorithm: config.JWT_ALGORITHM_HS256, Secret: "test"}, nil)
+	jwtService := service.NewJWTService(keyService, "Template", sessionRepo)
+	userService := service.NewUserService(userRepo, jwtService, nil, sessionRepo, emailService)
+
+	_, err := userService.Register(ctx, dto.UserCreateRequest{Name: "Carol", Email: "carol_doe@example.com", Password: "secret"})
+	require.NoError(t, err)
+	userRepo.users[0].Password = hashPassword(t, "secret")
+
+	// Only someone who knows the password learns the account is unverified.
+	_, err = userService.Verify(ctx, dto.UserLoginRequest{Email: "carol_doe@example.com", Password: "guess"})
+	assert.ErrorIs(t, err, dto.ErrPasswordNotMatch)
+
+	_, err = userService.Verify(ctx, dto.UserLoginRequest{Email: "carol_doe@example.com", Password: "secret"})
+	assert.ErrorIs(t, err, dto.ErrAccountNotVerified)
+
+	_, err = emailService.ProcessOutbox(ctx)
+	require.NoError(t, err)
+
+	msg := server.receive(t)
+	assert.Equal(t, "carol_doe@example.com", msg.Header.Get("To"))
+	body, err := io.ReadAll(quotedPrintable(msg))
+	require.NoError(t, err)
+	match := verifyTokenPattern.FindSubmatch(body)
+	require.NotNil(t, match, "verification link missing from mail body")
+
+	verified, err := userService.VerifyEmail(ctx, dto.VerifyEmailRequest{Token: string(match[1])})
+	require.NoError(t, err)
+	assert.Equal(t, "carol_doe@example.com", verified.Email)
+	assert.True(t, verified.IsVerified)
+
+	_, err = userService.Verify(ctx, dto.UserLoginRequest{Email: "carol_doe@example.com", Password: "secret"})
+	assert.NoError(t, err)
+
+	err = userService.SendVerificationEmail(ctx, dto.SendVerificationEmailRequest{Email: "carol_doe@example.com"})
+	assert.ErrorIs(t, err, dto.ErrAccountAlreadyVerified)
+}
+
+func quotedPrintable(msg *mail.Message) io.Reader {
+	if strings.EqualFold(msg.Header.Get("Content-Transfer-Encoding"), "quoted-printable") {
+		return quotedprintable.NewReader(msg.Body)
+	}
+	return msg.Body
+}
+
+func hashPassword(t *testing.T, password string) string {
+	hashed, err := helpers.HashPassword(password)
+	require.NoError(t, err)
+	return hashed
+}
+
+func Test_VerifyExistingUsersSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql string
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}))
+
+	require.NoError(t, migrations.VerifyExistingUsers(db))
+	assert.Equal(t, "UPDATE users SET is_verified = TRUE WHERE is_verified = FALSE", sql)
+}
@@ -0,0 +1,257 @@
+package tests
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeLabelRepository struct {
+	repository.LabelRepository
+	nextID     int
+	labels     map[int]entity.Label
+	taskLabels []entity.TaskLabel
+}
+
+func newFakeLabelRepository() *fakeLabelRepository {
+	return &fakeLabelRepository{labels: map[int]entity.Label{}}
+}
+
+func (r *fakeLabelRepository) CreateLabel(ctx context.Context, tx *gorm.DB, label entity.Label) (entity.Label, error) {
+	r.nextID++
+	label.ID = r.nextID
+	r.labels[label.ID] = label
+	return label, nil
+}
+
+func (r *fakeLabelRepository) GetLabelById(ctx context.Context, tx *gorm.DB, teamsID int, labelId int) (entity.Label, error) {
+	label, ok := r.labels[labelId]
+	if !ok || label.TeamsID != teamsID {
+		return entity.Label{}, gorm.ErrRecordNotFound
+	}
+	return label, nil
+}
+
+func (r *fakeLabelRepository) GetLabelsByTeamId(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Label, error) {
+	var labels []entity.Label
+	for id := 1; id <= r.nextID; id++ {
+		if label, ok := r.labels[id]; ok && label.TeamsID == teamsID {
+			labels = append(labels, label)
+		}
+	}
+	return labels, nil
+}
+
+func (r *fakeLabelRepository) UpdateLabel(ctx context.Context, tx *gorm.DB, label entity.Label) (entity.Label, error) {
+	r.labels[label.ID] = label
+	return label, nil
+}
+
+func (r *fakeLabelRepository) DeleteLabel(ctx context.Context, tx *gorm.DB, labelId int) error {
+	delete(r.labels, labelId)
+	var kept []entity.TaskLabel
+	for _, taskLabel := range r.taskLabels {
+		if taskLabel.LabelID != labelId {
+			kept = append(kept, taskLabel)
+		}
+	}
+	r.taskLabels = kept
+	return nil
+}
+
+func (r *fakeLabelRepository) AddTaskLabel(ctx context.Context, tx *gorm.DB, taskId int, labelId int) error {
+	for _, taskLabel := range r.taskLabels {
+		if taskLabel.TaskID == taskId && taskLabel.LabelID == labelId {
+			return nil
+		}
+	}
+	r.taskLabels = append(r.taskLabels, entity.TaskLabel{TaskID: taskId, LabelID: labelId})
+	return nil
+}
+
+func (r *fakeLabelRepository) RemoveTaskLabel(ctx context.Context, tx *gorm.DB, taskId int, labelId int) error {
+	for i, taskLabel := range r.taskLabels {
+		if taskLabel.TaskID == taskId && taskLabel.LabelID == labelId {
+			r.taskLabels = append(r.taskLabels[:i], r.taskLabels[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *fakeLabelRepository) GetTaskLabels(ctx context.Context, tx *gorm.DB, taskIds []int) ([]entity.TaskLabel, error) {
+	var taskLabels []entity.TaskLabel
+	for _, taskLabel := range r.taskLabels {
+		for _, taskId := range taskIds {
+			if taskLabel.TaskID == taskId {
+				taskLabel.Label = r.labels[taskLabel.LabelID]
+				taskLabels = append(taskLabels, taskLabel)
+			}
+		}
+	}
+	return taskLabels, nil
+}
+
+func (r *fakeLabelRepository) CountTasksByLabel(ctx context.Context, tx *gorm.DB, teamsID int) ([]dto.LabelCount, error) {
+	labels, _ := r.GetLabelsByTeamId(ctx, tx, teamsID)
+
+	var counts []dto.LabelCount
+	for _, label := range labels {
+		count := dto.LabelCount{ID: label.ID, Name: label.Name, Color: label.Color}
+		for _, taskLabel := range r.taskLabels {
+			if taskLabel.LabelID == label.ID {
+				count.Count++
+			}
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+type fakeStatisticsTaskRepository struct {
+	repository.TaskRepository
+	statuses []dto.TaskStatusCount
+}
+
+func (r *fakeStatisticsTaskRepository) CountTeamTasksByStatus(ctx context.Context, tx *gorm.DB, teamsID int) ([]dto.TaskStatusCount, error) {
+	return r.statuses, nil
+}
+
+type labelTest struct {
+	subtaskTest
+	labelRepo *fakeLabelRepository
+	labels    service.LabelService
+}
+
+func setUpLabelTest() labelTest {
+	nt := setUpNotificationTest()
+	st := setUpSubtaskTest()
+	labelRepo := newFakeLabelRepository()
+	st.service = service.NewTaskService(fakeTransactor{}, st.taskRepo, nil, nil, st.checklistRepo, nil, nt.assignees, labelRepo, st.auth, service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil), nil, service.TaskServiceDeps{})
+
+	return labelTest{
+		subtaskTest: st,
+		labelRepo:   labelRepo,
+		labels:      service.NewLabelService(labelRepo, st.taskRepo),
+	}
+}
+
+func (lt labelTest) label(t *testing.T, teamsID int, name string) dto.LabelResponse {
+	label, err := lt.labels.Create(context.Background(), teamsID, dto.LabelCreateRequest{Name: name, Color: "#FF0000"})
+	require.NoError(t, err)
+	return label
+}
+
+func Test_Label_NamesAreUniquePerTeam(t *testing.T) {
+	ctx := context.Background()
+	lt := setUpLabelTest()
+
+	bug := lt.label(t, 1, "Bug")
+	assert.Equal(t, "#ff0000", bug.Color)
+	feature := lt.label(t, 1, "Feature")
+	lt.label(t, 2, "Bug")
+
+	_, err := lt.labels.Create(ctx, 1, dto.LabelCreateRequest{Name: " bug ", Color: "#00ff00"})
+	assert.ErrorIs(t, err, dto.ErrLabelExists)
+
+	_, err = lt.labels.Update(ctx, 1, feature.ID, dto.LabelUpdateRequest{Name: "BUG", Color: "#00ff00"})
+	assert.ErrorIs(t, err, dto.ErrLabelExists)
+
+	// Changing only the case of a label's own name is allowed.
+	renamed, err := lt.labels.Update(ctx, 1, bug.ID, dto.LabelUpdateRequest{Name: "BUG", Color: "#00ff00"})
+	require.NoError(t, err)
+	assert.Equal(t, "BUG", renamed.Name)
+
+	_, err = lt.labels.Update(ctx, 2, feature.ID, dto.LabelUpdateRequest{Name: "Other", Color: "#00ff00"})
+	assert.ErrorIs(t, err, dto.ErrLabelNotFound)
+}
+
+func Test_Label_TaskLabels(t *testing.T) {
+	ctx := context.Background()
+	lt := setUpLabelTest()
+
+	task := lt.create(t, 1, "To Do", nil)
+	taskId := strconv.Itoa(task.ID)
+	bug := lt.label(t, 1, "Bug")
+	urgent := lt.label(t, 1, "Urgent")
+	other := lt.label(t, 2, "Other")
+
+	_, err := lt.labels.AddTaskLabel(ctx, taskId, dto.TaskLabelRequest{LabelID: other.ID})
+	assert.ErrorIs(t, err, dto.ErrLabelNotFound)
+
+	_, err = lt.labels.AddTaskLabel(ctx, taskId, dto.TaskLabelRequest{LabelID: bug.ID})
+	require.NoError(t, err)
+	labels, err := lt.labels.AddTaskLabel(ctx, taskId, dto.TaskLabelRequest{LabelID: urgent.ID})
+	require.NoError(t, err)
+	require.Len(t, labels, 2)
+	assert.Equal(t, "Bug", labels[0].Name)
+	assert.Equal(t, "Urgent", labels[1].Name)
+
+	got, err := lt.service.GetTaskById(ctx, taskId)
+	require.NoError(t, err)
+	assert.Len(t, got.Labels, 2)
+
+	require.NoError(t, lt.labels.RemoveTaskLabel(ctx, taskId, bug.ID))
+	assert.ErrorIs(t, lt.labels.RemoveTaskLabel(ctx, taskId, bug.ID), dto.ErrTaskLabelNotFound)
+
+	// Deleting a label takes it off every task.
+	require.NoError(t, lt.labels.Delete(ctx, 1, urgent.ID))
+	labels, err = lt.labels.GetTaskLabels(ctx, taskId)
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+}
+
+func Test_Label_TeamStatistics(t *testing.T) {
+	ctx := context.Background()
+	lt := setUpLabelTest()
+
+	task := lt.create(t, 1, "To Do", nil)
+	bug := lt.label(t, 1, "Bug")
+	lt.label(t, 1, "Feature")
+	_, err := lt.labels.AddTaskLabel(ctx, strconv.Itoa(task.ID), dto.TaskLabelRequest{LabelID: bug.ID})
+	require.NoError(t, err)
+
+	taskRepo := &fakeStatisticsTaskRepository{statuses: []dto.TaskStatusCount{
+		{Status: "To Do", Count: 3},
+		{Status: "Done", Count: 2},
+	}}
+	teamService := service.NewTeamService(nil, &fakeWebhookTeamRepository{team: entity.Team{ID: 1}}, nil, taskRepo, lt.labelRepo, nil, nil)
+
+	stats, err := teamService.GetStatistics(ctx, "1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, stats.TotalTasks)
+	assert.Len(t, stats.Statuses, 2)
+	assert.Equal(t, []dto.LabelCount{
+		{ID: bug.ID, Name: "Bug", Color: "#ff0000", Count: 1},
+		{ID: bug.ID + 1, Name: "Feature", Color: "#ff0000", Count: 0},
+	}, stats.Labels)
+
+	_, err = teamService.GetStatistics(ctx, "2")
+	assert.ErrorIs(t, err, dto.ErrTeamNotFound)
+}
+
+func Test_Label_CountTasksByLabelSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql string
+	db.Callback().Row().After("gorm:row").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	})
+
+	// Scan is not supported in dry run mode, so only the SQL is checked.
+	repository.NewLabelRepository(db).CountTasksByLabel(context.Background(), nil, 1)
+
+	assert.Contains(t, sql, "LEFT JOIN task_labels ON task_labels.label_id = labels.id")
+	assert.Contains(t, sql, "LEFT JOIN tasks ON tasks.id = task_labels.task_id AND tasks.deleted_at IS NULL")
+	assert.Contains(t, sql, "WHERE labels.teams_id = ?")
+}
@@ -0,0 +1,21 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseMentions(t *testing.T) {
+	body := "@Alice can you pair with @bob.smith@example.com? cc (@alice) and mail me at carol@example.com."
+
+	assert.Equal(t, []string{"alice", "bob.smith@example.com"}, helpers.ParseMentions(body))
+	assert.Empty(t, helpers.ParseMentions("no mentions, just dave@example.com"))
+}
+
+func Test_MentionHandles(t *testing.T) {
+	handles := helpers.MentionHandles("Bob Smith", "Bob.Smith@Example.com")
+
+	assert.Equal(t, []string{"bob.smith@example.com", "bob.smith", "bobsmith"}, handles)
+}
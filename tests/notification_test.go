@@ -0,0 +1,279 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeNotificationRepository struct {
+	notifications []entity.Notification
+	preferences   map[string]entity.NotificationPreference
+}
+
+func newFakeNotificationRepository() *fakeNotificationRepository {
+	return &fakeNotificationRepository{preferences: map[string]entity.NotificationPreference{}}
+}
+
+func (r *fakeNotificationRepository) CreateNotification(ctx context.Context, tx *gorm.DB, notification entity.Notification) (entity.Notification, error) {
+	notification.ID = len(r.notifications) + 1
+	notification.CreatedAt = time.Now()
+	r.notifications = append(r.notifications, notification)
+	return notification, nil
+}
+
+func (r *fakeNotificationRepository) GetNotificationsWithPagination(ctx context.Context, tx *gorm.DB, userId string, unreadOnly bool, req dto.PaginationRequest) (dto.GetAllNotificationRepositoryResponse, error) {
+	var notifications []entity.Notification
+	for i := len(r.notifications) - 1; i >= 0; i-- {
+		notification := r.notifications[i]
+		if notification.UserID.String() == userId && (!unreadOnly || notification.ReadAt == nil) {
+			notifications = append(notifications, notification)
+		}
+	}
+	return dto.GetAllNotificationRepositoryResponse{
+		Notifications:      notifications,
+		PaginationResponse: dto.PaginationResponse{Page: 1, PerPage: 20, Count: int64(len(notifications)), MaxPage: 1},
+	}, nil
+}
+
+func (r *fakeNotificationRepository) GetNotificationById(ctx context.Context, tx *gorm.DB, userId string, notificationId int) (entity.Notification, error) {
+	for _, notification := range r.notifications {
+		if notification.ID == notificationId && notification.UserID.String() == userId {
+			return notification, nil
+		}
+	}
+	return entity.Notification{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeNotificationRepository) CountUnread(ctx context.Context, tx *gorm.DB, userId string) (int64, error) {
+	var count int64
+	for _, notification := range r.notifications {
+		if notification.UserID.String() == userId && notification.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeNotificationRepository) MarkAsRead(ctx context.Context, tx *gorm.DB, userId string, notificationId int, at time.Time) error {
+	for i := range r.notifications {
+		if r.notifications[i].ID == notificationId && r.notifications[i].UserID.String() == userId && r.notifications[i].ReadAt == nil {
+			r.notifications[i].ReadAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *fakeNotificationRepository) MarkAllAsRead(ctx context.Context, tx *gorm.DB, userId string, at time.Time) (int64, error) {
+	var updated int64
+	for i := range r.notifications {
+		if r.notifications[i].UserID.String() == userId && r.notifications[i].ReadAt == nil {
+			r.notifications[i].ReadAt = &at
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+func (r *fakeNotificationRepository) GetPreferences(ctx context.Context, tx *gorm.DB, userId string) ([]entity.NotificationPreference, error) {
+	var preferences []entity.NotificationPreference
+	for _, preference := range r.preferences {
+		if preference.UserID.String() == userId {
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+func (r *fakeNotificationRepository) SavePreferences(ctx context.Context, tx *gorm.DB, preferences []entity.NotificationPreference) error {
+	for _, preference := range preferences {
+		r.preferences[preference.UserID.String()+preference.Type] = preference
+	}
+	return nil
+}
+
+type notificationTest struct {
+	service       service.NotificationService
+	notifications *fakeNotificationRepository
+	outbox        *fakeEmailOutboxRepository
+	userRepo      *fakeUserRepository
+	assignees     *fakeTaskAssigneeRepository
+	alice         entity.User
+	bob           entity.User
+	carol         entity.User
+	task          entity.Task
+}
+
+func setUpNotificationTest() notificationTest {
+	alice := entity.User{ID: uuid.New(), Name: "Alice", Email: "alice@example.com"}
+	bob := entity.User{ID: uuid.New(), Name: "Bob", Email: "bob@example.com"}
+	carol := entity.User{ID: uuid.New(), Name: "Carol", Email: "carol@example.com"}
+
+	notifications := newFakeNotificationRepository()
+	outbox := newFakeEmailOutboxRepository()
+	userRepo := &fakeUserRepository{users: []entity.User{alice, bob, carol}}
+	assignees := newFakeTaskAssigneeRepository(userRepo)
+
+	return notificationTest{
+		service:       service.NewNotificationService(notifications, userRepo, assignees, service.NewEmailService(outbox, nil)),
+		notifications: notifications,
+		outbox:        outbox,
+		userRepo:      userRepo,
+		assignees:     assignees,
+		alice:         alice,
+		bob:           bob,
+		carol:         carol,
+		task:          entity.Task{ID: 7, Title: "Ship it", Status: "todo", DueDate: time.Now().Add(time.Hour), UserID: &bob.ID},
+	}
+}
+
+func Test_Notification_Assigned(t *testing.T) {
+	ctx := context.Background()
+	nt := setUpNotificationTest()
+
+	nt.service.NotifyTaskEvents(ctx, nt.task, []entity.TaskEvent{{
+		TaskID:   nt.task.ID,
+		UserID:   &nt.alice.ID,
+		Type:     constants.ENUM_TASK_EVENT_ASSIGNED,
+		Field:    "user_id",
+		NewValue: nt.bob.ID.String(),
+	}})
+
+	require.Len(t, nt.notifications.notifications, 1)
+	notification := nt.notifications.notifications[0]
+	assert.Equal(t, nt.bob.ID, notification.UserID)
+	assert.Equal(t, constants.ENUM_NOTIFICATION_TASK_ASSIGNED, notification.Type)
+	assert.Equal(t, `You were assigned to "Ship it"`, notification.Title)
+	assert.Equal(t, "Alice assigned you to this task.", notification.Body)
+
+	// Assignments are emailed by default.
+	require.Len(t, nt.outbox.emails, 1)
+	assert.Equal(t, "bob@example.com", nt.outbox.emails[0].ToEmail)
+	assert.Contains(t, nt.outbox.emails[0].Body, "Ship it")
+	assert.Contains(t, nt.outbox.emails[0].Body, "/tasks/7")
+}
+
+func Test_Notification_NotForOwnActions(t *testing.T) {
+	ctx := context.Background()
+	nt := setUpNotificationTest()
+
+	nt.service.NotifyTaskEvents(ctx, nt.task, []entity.TaskEvent{{
+		TaskID:   nt.task.ID,
+		UserID:   &nt.bob.ID,
+		Type:     constants.ENUM_TASK_EVENT_ASSIGNED,
+		NewValue: nt.bob.ID.String(),
+	}})
+
+	assert.Empty(t, nt.notifications.notifications)
+	assert.Empty(t, nt.outbox.emails)
+}
+
+func Test_Notification_Preferences(t *testing.T) {
+	ctx := context.Background()
+	nt := setUpNotificationTest()
+	statusChanged := []entity.TaskEvent{{
+		TaskID:   nt.task.ID,
+		UserID:   &nt.alice.ID,
+		Type:     constants.ENUM_TASK_EVENT_UPDATED,
+		Field:    "status",
+		OldValue: "todo",
+		NewValue: "doing",
+	}}
+
+	// Status changes are in-app only by default.
+	nt.service.NotifyTaskEvents(ctx, nt.task, statusChanged)
+	require.Len(t, nt.notifications.notifications, 1)
+	assert.Equal(t, `"Ship it" moved to doing`, nt.notifications.notifications[0].Title)
+	assert.Empty(t, nt.outbox.emails)
+
+	preferences, err := nt.service.UpdatePreferences(ctx, nt.bob.ID.String(), dto.NotificationPreferenceUpdateRequest{
+		Preferences: []dto.NotificationPreferenceItem{{Type: constants.ENUM_NOTIFICATION_TASK_STATUS_CHANGED, InApp: false, Email: true}},
+	})
+	require.NoError(t, err)
+	require.Len(t, preferences, len(service.NotificationTypes))
+	for _, preference := range preferences {
+		if preference.Type == constants.ENUM_NOTIFICATION_TASK_STATUS_CHANGED {
+			assert.False(t, preference.InApp)
+			assert.True(t, preference.Email)
+		}
+	}
+
+	nt.service.NotifyTaskEvents(ctx, nt.task, statusChanged)
+	assert.Len(t, nt.notifications.notifications, 1)
+	require.Len(t, nt.outbox.emails, 1)
+	assert.Contains(t, nt.outbox.emails[0].Body, "doing")
+}
+
+func Test_Notification_EveryTypeHasEmailTemplate(t *testing.T) {
+	ctx := context.Background()
+	nt := setUpNotificationTest()
+
+	var items []dto.NotificationPreferenceItem
+	for _, notificationType := range service.NotificationTypes {
+		items = append(items, dto.NotificationPreferenceItem{Type: notificationType, Email: true})
+	}
+	_, err := nt.service.UpdatePreferences(ctx, nt.bob.ID.String(), dto.NotificationPreferenceUpdateRequest{Preferences: items})
+	require.NoError(t, err)
+
+	nt.service.NotifyTaskEvents(ctx, nt.task, []entity.TaskEvent{
+		{UserID: &nt.alice.ID, Type: constants.ENUM_TASK_EVENT_ASSIGNED, NewValue: nt.bob.ID.String()},
+		{UserID: &nt.alice.ID, Type: constants.ENUM_TASK_EVENT_UNASSIGNED, OldValue: nt.bob.ID.String()},
+		{UserID: &nt.alice.ID, Type: constants.ENUM_TASK_EVENT_UPDATED, Field: "status", OldValue: "todo", NewValue: "done"},
+	})
+	nt.service.NotifyComment(ctx, nt.task, entity.TaskComment{UserID: nt.alice.ID, Body: "Looks good"}, nil)
+	nt.service.NotifyDueSoon(ctx, nt.task, nt.bob.ID)
+	nt.service.NotifyOverdue(ctx, nt.task, nt.bob.ID)
+
+	assert.Empty(t, nt.notifications.notifications)
+	assert.Len(t, nt.outbox.emails, len(service.NotificationTypes))
+}
+
+func Test_Notification_CommentMentionsAreNotDuplicated(t *testing.T) {
+	ctx := context.Background()
+	nt := setUpNotificationTest()
+
+	nt.service.NotifyComment(ctx, nt.task, entity.TaskComment{UserID: nt.alice.ID, Body: "@bob please check"}, []uuid.UUID{nt.bob.ID})
+
+	require.Len(t, nt.notifications.notifications, 1)
+	assert.Equal(t, `Alice mentioned you on "Ship it"`, nt.notifications.notifications[0].Title)
+	assert.Equal(t, "@bob please check", nt.notifications.notifications[0].Body)
+}
+
+func Test_Notification_MarkAsRead(t *testing.T) {
+	ctx := context.Background()
+	nt := setUpNotificationTest()
+
+	nt.service.NotifyDueSoon(ctx, nt.task, nt.bob.ID)
+	nt.service.NotifyComment(ctx, nt.task, entity.TaskComment{UserID: nt.alice.ID, Body: "Any update?"}, nil)
+	require.Len(t, nt.notifications.notifications, 2)
+
+	unread, err := nt.service.CountUnread(ctx, nt.bob.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), unread.Unread)
+
+	// Nobody can read someone else's notification.
+	assert.ErrorIs(t, nt.service.MarkAsRead(ctx, nt.alice.ID.String(), 1), dto.ErrNotificationNotFound)
+
+	require.NoError(t, nt.service.MarkAsRead(ctx, nt.bob.ID.String(), 1))
+	list, err := nt.service.GetNotifications(ctx, nt.bob.ID.String(), dto.NotificationListRequest{Unread: true})
+	require.NoError(t, err)
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, 2, list.Data[0].ID)
+
+	readAll, err := nt.service.MarkAllAsRead(ctx, nt.bob.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), readAll.Updated)
+
+	unread, err = nt.service.CountUnread(ctx, nt.bob.ID.String())
+	require.NoError(t, err)
+	assert.Zero(t, unread.Unread)
+}
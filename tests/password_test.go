@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	userService, jwtService, _ := setUpSessionTest(t)
+
+	current, err := userService.Verify(ctx, dto.UserLoginRequest{Email: "alice@example.com", Password: "secret"})
+	require.NoError(t, err)
+	other, err := userService.Verify(ctx, dto.UserLoginRequest{Email: "alice@example.com", Password: "secret"})
+	require.NoError(t, err)
+
+	userId, err := jwtService.GetUserIDByToken(current.Token)
+	require.NoError(t, err)
+	sessionId, err := jwtService.ValidateSession(ctx, current.Token)
+	require.NoError(t, err)
+
+	err = userService.ChangePassword(ctx, dto.ChangePasswordRequest{OldPassword: "wrong", NewPassword: "new-secret"}, userId, sessionId)
+	assert.ErrorIs(t, err, dto.ErrPasswordNotMatch)
+
+	require.NoError(t, userService.ChangePassword(ctx, dto.ChangePasswordRequest{OldPassword: "secret", NewPassword: "new-secret"}, userId, sessionId))
+
+	_, err = jwtService.ValidateSession(ctx, current.Token)
+	assert.NoError(t, err, "the session that changed the password stays signed in")
+	_, err = jwtService.ValidateSession(ctx, other.Token)
+	assert.ErrorIs(t, err, dto.ErrSessionRevoked)
+
+	_, err = userService.Verify(ctx, dto.UserLoginRequest{Email: "alice@example.com", Password: "secret"})
+	assert.ErrorIs(t, err, dto.ErrPasswordNotMatch)
+	_, err = userService.Verify(ctx, dto.UserLoginRequest{Email: "alice@example.com", Password: "new-secret"})
+	assert.NoError(t, err)
+}
+
+func Test_ResetPassword_RejectsBadTokens(t *testing.T) {
+	ctx := context.Background()
+	userService, _, _ := setUpSessionTest(t)
+
+	err := userService.ResetPassword(ctx, dto.ResetPasswordRequest{Token: "garbage", Password: "new-secret"})
+	assert.ErrorIs(t, err, dto.ErrTokenInvalid)
+
+	expired, err := utils.AESEncrypt(strings.Join([]string{"alice@example.com", time.Now().Add(-time.Minute).Format(time.RFC3339), "0000"}, "|"))
+	require.NoError(t, err)
+	err = userService.ResetPassword(ctx, dto.ResetPasswordRequest{Token: expired, Password: "new-secret"})
+	assert.ErrorIs(t, err, dto.ErrTokenExpired)
+
+	// A token whose fingerprint does not match the current password hash was
+	// issued before a password change and must not work.
+	stale, err := utils.AESEncrypt(strings.Join([]string{"alice@example.com", time.Now().Add(time.Hour).Format(time.RFC3339), "0000"}, "|"))
+	require.NoError(t, err)
+	err = userService.ResetPassword(ctx, dto.ResetPasswordRequest{Token: stale, Password: "new-secret"})
+	assert.ErrorIs(t, err, dto.ErrTokenInvalid)
+}
+
+func Test_ForgotPassword_AlwaysOK(t *testing.T) {
+	userRepo := &fakeUserRepository{users: []entity.User{{ID: uuid.New(), Email: "alice@example.com", Password: "hash"}}}
+	outbox := newFakeEmailOutboxRepository()
+	userService := service.NewUserService(userRepo, nil, nil, nil, service.NewEmailService(outbox, nil))
+
+	r := SetUpRoutes()
+	r.POST("/api/user/forgot_password", controller.NewUserController(userService).ForgotPassword)
+
+	forgot := func(email string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/forgot_password", strings.NewReader(`{"email":"`+email+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, forgot("alice@example.com"))
+	require.Len(t, outbox.emails, 1)
+	assert.Equal(t, "alice@example.com", outbox.emails[0].ToEmail)
+
+	assert.Equal(t, http.StatusOK, forgot("nobody@example.com"))
+	assert.Len(t, outbox.emails, 1)
+
+	// A mail that cannot be queued looks the same to the caller.
+	outbox.err = errors.New("outbox unavailable")
+	assert.Equal(t, http.StatusOK, forgot("alice@example.com"))
+}
@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeTaskReminderRepository struct {
+	assignees []entity.TaskAssignee
+	reminders map[string]bool
+}
+
+func reminderKey(taskId int, kind string, userId uuid.UUID, dueDate time.Time) string {
+	return fmt.Sprintf("%d/%s/%s/%s", taskId, kind, userId, dueDate.Format(time.RFC3339))
+}
+
+func (r *fakeTaskReminderRepository) GetAssigneesToRemind(ctx context.Context, tx *gorm.DB, kind string, dueAfter *time.Time, dueBefore time.Time, defaultDone []string, limit int) ([]entity.TaskAssignee, error) {
+	var assignees []entity.TaskAssignee
+	for _, assignee := range r.assignees {
+		task := assignee.Task
+		if assignee.Role != constants.ENUM_TASK_ROLE_ASSIGNEE || task.DueDate.After(dueBefore) || (dueAfter != nil && !task.DueDate.After(*dueAfter)) {
+			continue
+		}
+		done := false
+		for _, status := range defaultDone {
+			done = done || status == task.Status
+		}
+		if done || r.reminders[reminderKey(task.ID, kind, assignee.UserID, task.DueDate)] {
+			continue
+		}
+		assignees = append(assignees, assignee)
+	}
+	return assignees, nil
+}
+
+func (r *fakeTaskReminderRepository) CreateReminder(ctx context.Context, tx *gorm.DB, reminder entity.TaskReminder) (bool, error) {
+	key := reminderKey(reminder.TaskID, reminder.Kind, reminder.UserID, reminder.DueDate)
+	if r.reminders[key] {
+		return false, nil
+	}
+	r.reminders[key] = true
+	return true, nil
+}
+
+func Test_Reminder_Scan(t *testing.T) {
+	ctx := context.Background()
+	nt := setUpNotificationTest()
+	now := time.Now()
+
+	dueSoon := entity.Task{ID: 1, Title: "Due soon", Status: "To Do", DueDate: now.Add(time.Hour), UserID: &nt.bob.ID}
+	overdue := entity.Task{ID: 2, Title: "Overdue", Status: "In Progress", DueDate: now.Add(-time.Hour), UserID: &nt.bob.ID}
+	finished := entity.Task{ID: 3, Title: "Finished", Status: "Done", DueDate: now.Add(-time.Hour), UserID: &nt.bob.ID}
+	farAway := entity.Task{ID: 4, Title: "Far away", Status: "To Do", DueDate: now.Add(72 * time.Hour), UserID: &nt.bob.ID}
+	reminders := &fakeTaskReminderRepository{
+		reminders: map[string]bool{},
+		assignees: []entity.TaskAssignee{
+			{TaskID: 1, UserID: nt.bob.ID, Role: constants.ENUM_TASK_ROLE_ASSIGNEE, Task: dueSoon},
+			{TaskID: 2, UserID: nt.bob.ID, Role: constants.ENUM_TASK_ROLE_ASSIGNEE, Task: overdue},
+			{TaskID: 2, UserID: nt.carol.ID, Role: constants.ENUM_TASK_ROLE_ASSIGNEE, Task: overdue},
+			{TaskID: 2, UserID: nt.alice.ID, Role: constants.ENUM_TASK_ROLE_WATCHER, Task: overdue},
+			{TaskID: 3, UserID: nt.bob.ID, Role: constants.ENUM_TASK_ROLE_ASSIGNEE, Task: finished},
+			{TaskID: 4, UserID: nt.bob.ID, Role: constants.ENUM_TASK_ROLE_ASSIGNEE, Task: farAway},
+		},
+	}
+	reminderService := service.NewReminderService(reminders, nt.service, config.ReminderConfig{Interval: time.Minute, DueSoonWindow: 24 * time.Hour})
+
+	sent, err := reminderService.Scan(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+
+	// Every assignee gets their own reminder, and only that one.
+	require.Len(t, nt.notifications.notifications, 3)
+	assert.Equal(t, constants.ENUM_NOTIFICATION_TASK_DUE_SOON, nt.notifications.notifications[0].Type)
+	assert.Equal(t, 1, *nt.notifications.notifications[0].TaskID)
+	assert.Equal(t, nt.bob.ID, nt.notifications.notifications[0].UserID)
+	for i, userId := range []uuid.UUID{nt.bob.ID, nt.carol.ID} {
+		notification := nt.notifications.notifications[i+1]
+		assert.Equal(t, constants.ENUM_NOTIFICATION_TASK_OVERDUE, notification.Type)
+		assert.Equal(t, 2, *notification.TaskID)
+		assert.Equal(t, userId, notification.UserID)
+	}
+
+	// Each reminder goes out once...
+	sent, err = reminderService.Scan(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// ...until the due date moves.
+	reminders.assignees[1].Task.DueDate = now.Add(-30 * time.Minute)
+	sent, err = reminderService.Scan(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+}
+
+func Test_Reminder_StopsWithContext(t *testing.T) {
+	nt := setUpNotificationTest()
+	reminders := &fakeTaskReminderRepository{reminders: map[string]bool{}}
+	reminderService := service.NewReminderService(reminders, nt.service, config.ReminderConfig{Interval: time.Minute, DueSoonWindow: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reminderService.Start(ctx)
+	cancel()
+}
+
+func Test_Reminder_Query(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql string
+	db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	})
+
+	_, err = repository.NewTaskReminderRepository(db).GetAssigneesToRemind(context.Background(), nil, constants.ENUM_NOTIFICATION_TASK_OVERDUE, nil, time.Now(), service.DefaultDoneStatuses(), 10)
+	require.NoError(t, err)
+	assert.Contains(t, sql, "JOIN tasks ON tasks.id = task_assignees.task_id AND tasks.deleted_at IS NULL")
+	assert.Contains(t, sql, "task_assignees.role = ? AND tasks.due_date <= ?")
+	assert.Contains(t, sql, "tr.user_id = task_assignees.user_id AND tr.due_date = tasks.due_date")
+	assert.Contains(t, sql, "NOT EXISTS (SELECT 1 FROM workflow_statuses ws WHERE ws.teams_id = tasks.teams_id AND ws.name = tasks.status")
+	assert.Contains(t, sql, "(tasks.status NOT IN (?) OR EXISTS")
+	assert.NotContains(t, sql, "tasks.due_date > ?")
+}
+
+type fakeOverdueTaskRepository struct {
+	repository.TaskRepository
+	tasks []entity.Task
+}
+
+func (r *fakeOverdueTaskRepository) GetTaskById(ctx context.Context, tx *gorm.DB, taskId string) (entity.Task, error) {
+	for _, task := range r.tasks {
+		if strconv.Itoa(task.ID) == taskId {
+			return task, nil
+		}
+	}
+	return entity.Task{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeOverdueTaskRepository) CountChildrenByStatus(ctx context.Context, tx *gorm.DB, parentIds []int) ([]dto.TaskChildStatusCount, error) {
+	return nil, nil
+}
+
+func Test_Task_IsOverdue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	taskRepo := &fakeOverdueTaskRepository{tasks: []entity.Task{
+		{ID: 1, Status: "To Do", DueDate: now.Add(-time.Hour)},
+		{ID: 2, Status: "Done", DueDate: now.Add(-time.Hour)},
+		{ID: 3, Status: "To Do", DueDate: now.Add(time.Hour)},
+	}}
+	workflowService := service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil)
+	taskService := service.NewTaskService(fakeTransactor{}, taskRepo, nil, nil, newFakeTaskChecklistRepository(), nil, newFakeTaskAssigneeRepository(nil), newFakeLabelRepository(), nil, workflowService, nil, service.TaskServiceDeps{})
+
+	for taskId, want := range map[string]bool{"1": true, "2": false, "3": false} {
+		task, err := taskService.GetTaskById(ctx, taskId)
+		require.NoError(t, err)
+		assert.Equal(t, want, task.IsOverdue, "task %s", taskId)
+	}
+}
+
+func Test_Task_OverdueFilterSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	now := time.Now()
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var tasks []entity.Task
+		return tx.Scopes(repository.FilterTasks("", dto.TaskFilter{DueBefore: &now, OpenOnly: true, DefaultDoneStatuses: []string{"Done"}})).Find(&tasks)
+	})
+	assert.Contains(t, sql, "due_date <")
+	assert.Contains(t, sql, "ws.category = 'done'")
+	assert.Contains(t, sql, "tasks.status NOT IN ('Done')")
+}
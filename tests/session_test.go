@@ -0,0 +1,270 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/helpers"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	users []entity.User
+}
+
+func (r *fakeUserRepository) CheckEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, true, nil
+		}
+	}
+	return entity.User{}, false, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) GetUserById(ctx context.Context, tx *gorm.DB, userId string) (entity.User, error) {
+	for _, user := range r.users {
+		if user.ID.String() == userId {
+			return user, nil
+		}
+	}
+	return entity.User{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, error) {
+	user, _, err := r.CheckEmail(ctx, tx, email)
+	return user, err
+}
+
+func (r *fakeUserRepository) RegisterUser(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error) {
+	user.ID = uuid.New()
+	r.users = append(r.users, user)
+	return user, nil
+}
+
+func (r *fakeUserRepository) UpdateUser(ctx context.Context, tx *gorm.DB, user entity.User) (entity.User, error) {
+	for i := range r.users {
+		if r.users[i].ID != user.ID {
+			continue
+		}
+		if user.Password != "" {
+			r.users[i].Password = user.Password
+		}
+		if user.IsVerified {
+			r.users[i].IsVerified = true
+		}
+	}
+	return user, nil
+}
+
+type fakeSessionRepository struct {
+	sessions map[string]*entity.Session
+	tokens   []*entity.RefreshToken
+}
+
+func newFakeSessionRepository() *fakeSessionRepository {
+	return &fakeSessionRepository{sessions: map[string]*entity.Session{}}
+}
+
+func (r *fakeSessionRepository) CreateSession(ctx context.Context, tx *gorm.DB, session entity.Session) (entity.Session, error) {
+	session.ID = uuid.New()
+	r.sessions[session.ID.String()] = &session
+	return session, nil
+}
+
+func (r *fakeSessionRepository) GetSessionById(ctx context.Context, tx *gorm.DB, sessionId string) (entity.Session, error) {
+	session, ok := r.sessions[sessionId]
+	if !ok {
+		return entity.Session{}, gorm.ErrRecordNotFound
+	}
+	return *session, nil
+}
+
+func (r *fakeSessionRepository) TouchSession(ctx context.Context, tx *gorm.DB, sessionId string, at time.Time) error {
+	r.sessions[sessionId].LastUsedAt = at
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeSession(ctx context.Context, tx *gorm.DB, sessionId string) error {
+	now := time.Now()
+	r.sessions[sessionId].RevokedAt = &now
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeUserSessions(ctx context.Context, tx *gorm.DB, userId string) error {
+	now := time.Now()
+	for _, session := range r.sessions {
+		if session.UserID.String() == userId {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeOtherSessions(ctx context.Context, tx *gorm.DB, userId string, keepSessionId string) error {
+	now := time.Now()
+	for id, session := range r.sessions {
+		if session.UserID.String() == userId && id != keepSessionId {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRepository) CreateRefreshToken(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) (entity.RefreshToken, error) {
+	token.ID = len(r.tokens) + 1
+	r.tokens = append(r.tokens, &token)
+	return token, nil
+}
+
+func (r *fakeSessionRepository) GetRefreshTokenByHash(ctx context.Context, tx *gorm.DB, hash string) (entity.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			found := *token
+			found.Session = *r.sessions[token.SessionID.String()]
+			return found, nil
+		}
+	}
+	return entity.RefreshToken{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSessionRepository) MarkRefreshTokenUsed(ctx context.Context, tx *gorm.DB, tokenId int) (bool, error) {
+	token := r.tokens[tokenId-1]
+	if token.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return true, nil
+}
+
+func setUpSessionTest(t *testing.T) (service.UserService, service.JWTService, *fakeSessionRepository) {
+	password, err := helpers.HashPassword("secret")
+	require.NoError(t, err)
+
+	userRepo := &fakeUserRepository{users: []entity.User{{
+		ID:         uuid.New(),
+		Email:      "alice@example.com",
+		Password:   password,
+		Role:       constants.ENUM_ROLE_USER,
+		IsVerified: true,
+	}}}
+	sessionRepo := newFakeSessionRepository()
+	keyService := service.NewSigningKeyService(config.JWTConfig{Algorithm: config.JWT_ALGORITHM_HS256, Secret: "test"}, nil)
+	jwtService := service.NewJWTService(keyService, "Template", sessionRepo)
+	emailService := service.NewEmailService(newFakeEmailOutboxRepository(), nil)
+
+	return service.NewUserService(userRepo, jwtService, nil, sessionRepo, emailService), jwtService, sessionRepo
+}
+
+func Test_RefreshToken_RotationAndReuse(t *testing.T) {
+	ctx := context.Background()
+	userService, jwtService, _ := setUpSessionTest(t)
+
+	login, err := userService.Verify(ctx, dto.UserLoginRequest{Email: "alice@example.com", Password: "secret"})
+	require.NoError(t, err)
+
+	sessionId, err := jwtService.ValidateSession(ctx, login.Token)
+	require.NoError(t, err)
+
+	refreshed, err := userService.RefreshToken(ctx, dto.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	require.NoError(t, err)
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+
+	refreshedSession, err := jwtService.ValidateSession(ctx, refreshed.Token)
+	require.NoError(t, err)
+	assert.Equal(t, sessionId, refreshedSession)
+
+	// Replaying the rotated token revokes the session, so the legitimate
+	// holder of the newer tokens is signed out too.
+	_, err = userService.RefreshToken(ctx, dto.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assert.ErrorIs(t, err, dto.ErrRefreshTokenReused)
+
+	_, err = userService.RefreshToken(ctx, dto.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	assert.ErrorIs(t, err, dto.ErrRefreshTokenInvalid)
+
+	_, err = jwtService.ValidateSession(ctx, refreshed.Token)
+	assert.ErrorIs(t, err, dto.ErrSessionRevoked)
+}
+
+func Test_Logout_RevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	userService, jwtService, _ := setUpSessionTest(t)
+
+	phone, err := userService.Verify(ctx, dto.UserLoginRequest{Email: "alice@example.com", Password: "secret"})
+	require.NoError(t, err)
+	laptop, err := userService.Verify(ctx, dto.UserLoginRequest{Email: "alice@example.com", Password: "secret"})
+	require.NoError(t, err)
+
+	phoneSession, err := jwtService.ValidateSession(ctx, phone.Token)
+	require.NoError(t, err)
+	require.NoError(t, userService.Logout(ctx, phoneSession))
+
+	_, err = jwtService.ValidateSession(ctx, phone.Token)
+	assert.ErrorIs(t, err, dto.ErrSessionRevoked)
+	_, err = jwtService.ValidateSession(ctx, laptop.Token)
+	assert.NoError(t, err)
+
+	userId, err := jwtService.GetUserIDByToken(laptop.Token)
+	require.NoError(t, err)
+	require.NoError(t, userService.LogoutAll(ctx, userId))
+
+	_, err = jwtService.ValidateSession(ctx, laptop.Token)
+	assert.ErrorIs(t, err, dto.ErrSessionRevoked)
+}
+
+func Test_JWT_RejectsOtherIssuer(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := newFakeSessionRepository()
+	keyService := service.NewSigningKeyService(config.JWTConfig{Algorithm: config.JWT_ALGORITHM_HS256, Secret: "test"}, nil)
+	jwtService := service.NewJWTService(keyService, "Template", sessionRepo)
+
+	session, err := sessionRepo.CreateSession(ctx, nil, entity.Session{UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	token, err := jwtService.GenerateToken(session.UserID.String(), constants.ENUM_ROLE_USER, session.ID.String())
+	require.NoError(t, err)
+	_, err = jwtService.ParseToken(ctx, token)
+	require.NoError(t, err)
+
+	// Same key, different issuer.
+	forged, err := service.NewJWTService(keyService, "Elsewhere", sessionRepo).GenerateToken(session.UserID.String(), constants.ENUM_ROLE_ADMIN, session.ID.String())
+	require.NoError(t, err)
+	_, err = jwtService.ValidateToken(forged)
+	assert.ErrorIs(t, err, dto.ErrTokenInvalid)
+	_, err = jwtService.ParseToken(ctx, forged)
+	assert.ErrorIs(t, err, dto.ErrTokenInvalid)
+}
+
+type failingKeyService struct {
+	service.SigningKeyService
+}
+
+func (s failingKeyService) Sign(claims jwt.Claims) (string, error) {
+	return "", errors.New("no active key")
+}
+
+func Test_Login_FailsWhenTokenCannotBeSigned(t *testing.T) {
+	sessionRepo := newFakeSessionRepository()
+	keyService := failingKeyService{service.NewSigningKeyService(config.JWTConfig{Algorithm: config.JWT_ALGORITHM_HS256, Secret: "test"}, nil)}
+	password, err := helpers.HashPassword("secret")
+	require.NoError(t, err)
+	userRepo := &fakeUserRepository{users: []entity.User{{ID: uuid.New(), Email: "alice@example.com", Password: password, Role: constants.ENUM_ROLE_USER, IsVerified: true}}}
+	userService := service.NewUserService(userRepo, service.NewJWTService(keyService, "Template", sessionRepo), nil, sessionRepo, service.NewEmailService(newFakeEmailOutboxRepository(), nil))
+
+	login, err := userService.Verify(context.Background(), dto.UserLoginRequest{Email: "alice@example.com", Password: "secret"})
+	assert.ErrorIs(t, err, dto.ErrGenerateToken)
+	assert.Empty(t, login.Token)
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeSigningKeyRepository struct {
+	keys []entity.SigningKey
+}
+
+func (r *fakeSigningKeyRepository) CreateKey(ctx context.Context, tx *gorm.DB, key entity.SigningKey) (entity.SigningKey, error) {
+	r.keys = append(r.keys, key)
+	return key, nil
+}
+
+func (r *fakeSigningKeyRepository) GetUnexpiredKeys(ctx context.Context, tx *gorm.DB, now time.Time) ([]entity.SigningKey, error) {
+	var keys []entity.SigningKey
+	for _, key := range r.keys {
+		if key.ExpiresAt.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeSigningKeyRepository) DeleteExpiredKeys(ctx context.Context, tx *gorm.DB, now time.Time) error {
+	return nil
+}
+
+func newTestKeyService(algorithm string, repo *fakeSigningKeyRepository) service.SigningKeyService {
+	return service.NewSigningKeyService(config.JWTConfig{
+		Algorithm:        algorithm,
+		KeySecret:        "test-key-secret",
+		RotationInterval: 24 * time.Hour,
+	}, repo)
+}
+
+func parseWith(keyService service.SigningKeyService, token string) (*jwt.Token, error) {
+	return jwt.Parse(token, keyService.Keyfunc, jwt.WithValidMethods(keyService.ValidMethods()))
+}
+
+func Test_SigningKeyService_SignAndPublish(t *testing.T) {
+	for algorithm, kty := range map[string]string{config.JWT_ALGORITHM_RS256: "RSA", config.JWT_ALGORITHM_EDDSA: "OKP"} {
+		repo := &fakeSigningKeyRepository{}
+		keyService := newTestKeyService(algorithm, repo)
+		require.NoError(t, keyService.Rotate(context.Background()))
+
+		require.Len(t, repo.keys, 1)
+		assert.NotContains(t, repo.keys[0].PrivateKey, "PRIVATE KEY", "private keys are stored sealed")
+
+		jwks := keyService.JWKS()
+		require.Len(t, jwks.Keys, 1)
+		assert.Equal(t, kty, jwks.Keys[0].Kty)
+		assert.Equal(t, algorithm, jwks.Keys[0].Alg)
+
+		signed, err := keyService.Sign(jwt.RegisteredClaims{Subject: "alice"})
+		require.NoError(t, err)
+
+		token, err := parseWith(keyService, signed)
+		require.NoError(t, err)
+		assert.Equal(t, jwks.Keys[0].Kid, token.Header["kid"])
+
+		// Rotating again straight away must not mint another key.
+		require.NoError(t, keyService.Rotate(context.Background()))
+		assert.Len(t, repo.keys, 1)
+	}
+}
+
+func Test_SigningKeyService_Rotation(t *testing.T) {
+	repo := &fakeSigningKeyRepository{}
+	first := newTestKeyService(config.JWT_ALGORITHM_EDDSA, repo)
+	require.NoError(t, first.Rotate(context.Background()))
+
+	oldToken, err := first.Sign(jwt.RegisteredClaims{Subject: "alice"})
+	require.NoError(t, err)
+
+	// Pretend the key has been signing for a whole interval.
+	repo.keys[0].ActivatesAt = repo.keys[0].ActivatesAt.Add(-24 * time.Hour)
+
+	// A second instance loads the shared keys and rotates.
+	second := newTestKeyService(config.JWT_ALGORITHM_EDDSA, repo)
+	require.NoError(t, second.Rotate(context.Background()))
+	require.Len(t, repo.keys, 2)
+	assert.Len(t, second.JWKS().Keys, 2)
+
+	newToken, err := second.Sign(jwt.RegisteredClaims{Subject: "alice"})
+	require.NoError(t, err)
+
+	parsed, err := parseWith(second, newToken)
+	require.NoError(t, err)
+	assert.Equal(t, repo.keys[1].Kid, parsed.Header["kid"])
+
+	_, err = parseWith(second, oldToken)
+	assert.NoError(t, err, "tokens signed by the previous key stay valid")
+}
+
+func Test_SigningKeyService_RejectsOtherAlgorithms(t *testing.T) {
+	repo := &fakeSigningKeyRepository{}
+	keyService := newTestKeyService(config.JWT_ALGORITHM_RS256, repo)
+	require.NoError(t, keyService.Rotate(context.Background()))
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "mallory"})
+	forged.Header["kid"] = repo.keys[0].Kid
+	signed, err := forged.SignedString([]byte(repo.keys[0].PublicKey))
+	require.NoError(t, err)
+
+	_, err = parseWith(keyService, signed)
+	assert.Error(t, err)
+
+	other := newTestKeyService(config.JWT_ALGORITHM_RS256, &fakeSigningKeyRepository{})
+	require.NoError(t, other.Rotate(context.Background()))
+	signed, err = other.Sign(jwt.RegisteredClaims{Subject: "mallory"})
+	require.NoError(t, err)
+
+	_, err = parseWith(keyService, signed)
+	assert.ErrorIs(t, err, dto.ErrUnknownKeyID)
+}
+
+func Test_NewJWTConfig_Production(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_KEY_SECRET", "")
+
+	t.Setenv("JWT_ALGORITHM", config.JWT_ALGORITHM_RS256)
+	_, err := config.NewJWTConfig()
+	assert.Error(t, err)
+
+	t.Setenv("JWT_ALGORITHM", config.JWT_ALGORITHM_HS256)
+	_, err = config.NewJWTConfig()
+	assert.Error(t, err)
+
+	t.Setenv("JWT_SECRET", "a-real-secret")
+	cfg, err := config.NewJWTConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "a-real-secret", cfg.Secret)
+
+	t.Setenv("APP_ENV", "localhost")
+	t.Setenv("JWT_ALGORITHM", config.JWT_ALGORITHM_EDDSA)
+	cfg, err = config.NewJWTConfig()
+	require.NoError(t, err)
+	assert.NotEmpty(t, cfg.KeySecret)
+}
@@ -0,0 +1,404 @@
+package tests
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/migrations"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeSprintRepository struct {
+	repository.SprintRepository
+	tasks     *fakeTaskTreeRepository
+	nextID    int
+	sprints   map[int]entity.Sprint
+	snapshots map[int][]entity.SprintTask
+	lockedTx  *gorm.DB
+}
+
+func (r *fakeSprintRepository) CreateSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) (entity.Sprint, error) {
+	r.nextID++
+	sprint.ID = r.nextID
+	r.sprints[sprint.ID] = sprint
+	return sprint, nil
+}
+
+func (r *fakeSprintRepository) GetSprintById(ctx context.Context, tx *gorm.DB, teamsID int, sprintId int) (entity.Sprint, error) {
+	sprint, ok := r.sprints[sprintId]
+	if !ok || sprint.TeamsID != teamsID {
+		return entity.Sprint{}, gorm.ErrRecordNotFound
+	}
+	return sprint, nil
+}
+
+func (r *fakeSprintRepository) GetSprintsByTeamId(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Sprint, error) {
+	var sprints []entity.Sprint
+	for _, sprint := range r.sprints {
+		if sprint.TeamsID == teamsID {
+			sprints = append(sprints, sprint)
+		}
+	}
+	sort.Slice(sprints, func(i, j int) bool {
+		if !sprints[i].StartDate.Equal(sprints[j].StartDate) {
+			return sprints[i].StartDate.Before(sprints[j].StartDate)
+		}
+		return sprints[i].ID < sprints[j].ID
+	})
+	return sprints, nil
+}
+
+func (r *fakeSprintRepository) GetSprintsByTeamIdForUpdate(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Sprint, error) {
+	r.lockedTx = tx
+	return r.GetSprintsByTeamId(ctx, tx, teamsID)
+}
+
+func (r *fakeSprintRepository) UpdateSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) (entity.Sprint, error) {
+	r.sprints[sprint.ID] = sprint
+	return sprint, nil
+}
+
+func (r *fakeSprintRepository) DeleteSprint(ctx context.Context, tx *gorm.DB, sprintId int) error {
+	for _, task := range r.tasks.tasks {
+		if task.SprintID != nil && *task.SprintID == sprintId {
+			r.SetTaskSprint(ctx, tx, task.ID, nil)
+		}
+	}
+	delete(r.sprints, sprintId)
+	return nil
+}
+
+func (r *fakeSprintRepository) GetSprintTasks(ctx context.Context, tx *gorm.DB, sprintId int) ([]entity.Task, error) {
+	var tasks []entity.Task
+	for id := 1; id <= r.tasks.nextID; id++ {
+		if task, ok := r.tasks.tasks[id]; ok && task.SprintID != nil && *task.SprintID == sprintId {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *fakeSprintRepository) SetTaskSprint(ctx context.Context, tx *gorm.DB, taskId int, sprintId *int) error {
+	task := r.tasks.tasks[taskId]
+	task.SprintID = sprintId
+	r.tasks.tasks[taskId] = task
+	return nil
+}
+
+func (r *fakeSprintRepository) MoveSprintTasks(ctx context.Context, tx *gorm.DB, sprintId int, taskIds []int, nextSprintId *int) error {
+	for _, taskId := range taskIds {
+		if task := r.tasks.tasks[taskId]; task.SprintID != nil && *task.SprintID == sprintId {
+			r.SetTaskSprint(ctx, tx, taskId, nextSprintId)
+		}
+	}
+	return nil
+}
+
+func (r *fakeSprintRepository) CloseSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) error {
+	if r.sprints[sprint.ID].State != constants.ENUM_SPRINT_STATE_ACTIVE {
+		return gorm.ErrRecordNotFound
+	}
+	r.sprints[sprint.ID] = sprint
+	return nil
+}
+
+func (r *fakeSprintRepository) SaveSprintSnapshot(ctx context.Context, tx *gorm.DB, tasks []entity.SprintTask) error {
+	for _, task := range tasks {
+		r.snapshots[task.SprintID] = append(r.snapshots[task.SprintID], task)
+	}
+	return nil
+}
+
+func (r *fakeSprintRepository) GetSprintSnapshot(ctx context.Context, tx *gorm.DB, sprintId int) ([]entity.SprintTask, error) {
+	return r.snapshots[sprintId], nil
+}
+
+type sprintTest struct {
+	subtaskTest
+	sprintRepo *fakeSprintRepository
+	sprints    service.SprintService
+}
+
+func setUpSprintTest() sprintTest {
+	st := setUpSubtaskTest()
+	sprintRepo := &fakeSprintRepository{tasks: st.taskRepo, sprints: map[int]entity.Sprint{}, snapshots: map[int][]entity.SprintTask{}}
+	workflowService := service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil)
+
+	return sprintTest{
+		subtaskTest: st,
+		sprintRepo:  sprintRepo,
+		sprints:     service.NewSprintService(fakeTransactor{}, sprintRepo, st.taskRepo, workflowService),
+	}
+}
+
+func (st sprintTest) sprint(t *testing.T, name string, start string, end string) dto.SprintResponse {
+	sprint, err := st.sprints.Create(context.Background(), 1, dto.SprintCreateRequest{Name: name, StartDate: start, EndDate: end})
+	require.NoError(t, err)
+	return sprint
+}
+
+func (st sprintTest) plan(t *testing.T, sprintId int, taskIds ...int) {
+	for _, taskId := range taskIds {
+		_, err := st.sprints.AddTask(context.Background(), 1, sprintId, dto.SprintTaskRequest{TaskID: taskId})
+		require.NoError(t, err)
+	}
+}
+
+func sprintTaskIds(detail dto.SprintDetailResponse) []int {
+	ids := []int{}
+	for _, task := range detail.Tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func Test_Sprint_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSprintTest()
+
+	for _, dates := range [][2]string{
+		{"2024-06-14", "2024-06-01"},
+		{"2024-06-01", "2024-06-01"},
+		{"June", "2024-06-14"},
+	} {
+		_, err := st.sprints.Create(ctx, 1, dto.SprintCreateRequest{Name: "Sprint", StartDate: dates[0], EndDate: dates[1]})
+		assert.ErrorIs(t, err, dto.ErrInvalidSprintDates, dates)
+	}
+
+	first := st.sprint(t, " Sprint 1 ", "2024-06-01", "2024-06-14")
+	second := st.sprint(t, "Sprint 2", "2024-06-15", "2024-06-28")
+	assert.Equal(t, "Sprint 1", first.Name)
+	assert.Equal(t, constants.ENUM_SPRINT_STATE_PLANNED, first.State)
+
+	_, err := st.sprints.Close(ctx, 1, first.ID, dto.SprintCloseRequest{})
+	assert.ErrorIs(t, err, dto.ErrSprintNotActive)
+
+	started, err := st.sprints.Start(ctx, 1, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_SPRINT_STATE_ACTIVE, started.State)
+
+	_, err = st.sprints.Start(ctx, 1, second.ID)
+	assert.ErrorIs(t, err, dto.ErrSprintAlreadyActive)
+	_, err = st.sprints.Start(ctx, 1, first.ID)
+	assert.ErrorIs(t, err, dto.ErrSprintNotPlanned)
+	assert.ErrorIs(t, st.sprints.Delete(ctx, 1, first.ID), dto.ErrSprintNotPlanned)
+
+	// Sprints belong to their team.
+	_, err = st.sprints.GetSprint(ctx, 2, first.ID)
+	assert.ErrorIs(t, err, dto.ErrSprintNotFound)
+
+	require.NoError(t, st.sprints.Delete(ctx, 1, second.ID))
+	sprints, err := st.sprints.GetSprintsByTeamId(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, sprints, 1)
+}
+
+func Test_Sprint_MoveTasks(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSprintTest()
+	first := st.sprint(t, "Sprint 1", "2024-06-01", "2024-06-14")
+	second := st.sprint(t, "Sprint 2", "2024-06-15", "2024-06-28")
+	task := st.create(t, 1, "To Do", nil)
+	other := st.create(t, 2, "To Do", nil)
+
+	st.plan(t, first.ID, task.ID)
+	_, err := st.sprints.AddTask(ctx, 1, first.ID, dto.SprintTaskRequest{TaskID: other.ID})
+	assert.ErrorIs(t, err, dto.ErrSprintTaskOtherTeam)
+
+	// Adding a task to another sprint moves it there.
+	detail, err := st.sprints.AddTask(ctx, 1, second.ID, dto.SprintTaskRequest{TaskID: task.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []int{task.ID}, sprintTaskIds(detail))
+	assert.ErrorIs(t, st.sprints.RemoveTask(ctx, 1, first.ID, task.ID), dto.ErrTaskNotInSprint)
+
+	require.NoError(t, st.sprints.RemoveTask(ctx, 1, second.ID, task.ID))
+	assert.Nil(t, st.taskRepo.tasks[task.ID].SprintID)
+}
+
+func Test_Sprint_DetailSummary(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSprintTest()
+	sprint := st.sprint(t, "Sprint 1", "2024-06-01", "2024-06-14")
+	done := st.create(t, 1, "Done", nil)
+	open := st.create(t, 1, "To Do", nil)
+	st.create(t, 1, "To Do", nil)
+
+	for id, effort := range map[int][2]int{done.ID: {5, 0}, open.ID: {3, 2}} {
+		task := st.taskRepo.tasks[id]
+		task.Estimate, task.RemainingEffort = effort[0], effort[1]
+		st.taskRepo.tasks[id] = task
+	}
+	st.plan(t, sprint.ID, done.ID, open.ID)
+
+	detail, err := st.sprints.GetSprint(ctx, 1, sprint.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{done.ID, open.ID}, sprintTaskIds(detail))
+	assert.True(t, detail.Tasks[0].IsDone)
+	assert.False(t, detail.Tasks[1].IsDone)
+	assert.Equal(t, dto.SprintSummary{TaskCount: 2, DoneCount: 1, Estimate: 8, DoneEstimate: 5, RemainingEffort: 2}, detail.Summary)
+}
+
+func Test_Sprint_CloseCarriesUnfinishedTasks(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSprintTest()
+	first := st.sprint(t, "Sprint 1", "2024-06-01", "2024-06-14")
+	later := st.sprint(t, "Sprint 3", "2024-06-29", "2024-07-12")
+	next := st.sprint(t, "Sprint 2", "2024-06-15", "2024-06-28")
+	done := st.create(t, 1, "Done", nil)
+	open := st.create(t, 1, "To Do", nil)
+	started := st.create(t, 1, "In Progress", nil)
+	st.plan(t, first.ID, done.ID, open.ID, started.ID)
+
+	_, err := st.sprints.Start(ctx, 1, first.ID)
+	require.NoError(t, err)
+
+	_, err = st.sprints.Close(ctx, 1, first.ID, dto.SprintCloseRequest{NextSprintID: &first.ID})
+	assert.ErrorIs(t, err, dto.ErrInvalidNextSprint)
+
+	// Without a next sprint the earliest planned one gets the leftovers.
+	closed, err := st.sprints.Close(ctx, 1, first.ID, dto.SprintCloseRequest{})
+	require.NoError(t, err)
+	_, err = st.sprints.Close(ctx, 1, first.ID, dto.SprintCloseRequest{})
+	assert.ErrorIs(t, err, dto.ErrSprintNotActive)
+	assert.Equal(t, constants.ENUM_SPRINT_STATE_CLOSED, closed.Sprint.State)
+	assert.NotNil(t, closed.Sprint.ClosedAt)
+	assert.Equal(t, []int{open.ID, started.ID}, closed.CarriedOver)
+	require.NotNil(t, closed.NextSprintID)
+	assert.Equal(t, next.ID, *closed.NextSprintID)
+
+	// The closed sprint still shows what it was given, and what moved on.
+	detail, err := st.sprints.GetSprint(ctx, 1, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{done.ID, open.ID, started.ID}, sprintTaskIds(detail))
+	assert.Equal(t, []bool{false, true, true}, []bool{detail.Tasks[0].CarriedOver, detail.Tasks[1].CarriedOver, detail.Tasks[2].CarriedOver})
+	assert.Equal(t, 3, detail.Summary.TaskCount)
+	assert.Equal(t, 1, detail.Summary.DoneCount)
+	detail, err = st.sprints.GetSprint(ctx, 1, next.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{open.ID, started.ID}, sprintTaskIds(detail))
+
+	_, err = st.sprints.AddTask(ctx, 1, first.ID, dto.SprintTaskRequest{TaskID: open.ID})
+	assert.ErrorIs(t, err, dto.ErrSprintClosed)
+	_, err = st.sprints.Update(ctx, 1, first.ID, dto.SprintUpdateRequest{Name: "Sprint 1", StartDate: "2024-06-01", EndDate: "2024-06-14"})
+	assert.ErrorIs(t, err, dto.ErrSprintClosed)
+
+	// The last sprint hands its leftovers back to the backlog.
+	require.NoError(t, st.sprints.Delete(ctx, 1, later.ID))
+	_, err = st.sprints.Start(ctx, 1, next.ID)
+	require.NoError(t, err)
+	closed, err = st.sprints.Close(ctx, 1, next.ID, dto.SprintCloseRequest{})
+	require.NoError(t, err)
+	assert.Nil(t, closed.NextSprintID)
+	assert.Nil(t, st.taskRepo.tasks[open.ID].SprintID)
+	assert.Nil(t, st.taskRepo.tasks[started.ID].SprintID)
+}
+
+// Test_Sprint_CloseOnlyOnce covers a close that lost the race: the sprint was
+// still active when it was read, but another close committed first.
+func Test_Sprint_CloseOnlyOnce(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSprintTest()
+	sprint := st.sprint(t, "Sprint 1", "2024-06-01", "2024-06-14")
+	open := st.create(t, 1, "To Do", nil)
+	st.plan(t, sprint.ID, open.ID)
+	_, err := st.sprints.Start(ctx, 1, sprint.ID)
+	require.NoError(t, err)
+
+	racing := &racingSprintRepository{fakeSprintRepository: st.sprintRepo}
+	sprints := service.NewSprintService(fakeTransactor{}, racing, st.taskRepo, service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil))
+
+	_, err = sprints.Close(ctx, 1, sprint.ID, dto.SprintCloseRequest{})
+	assert.ErrorIs(t, err, dto.ErrSprintNotActive)
+	assert.Empty(t, st.sprintRepo.snapshots[sprint.ID])
+	assert.Equal(t, sprint.ID, *st.taskRepo.tasks[open.ID].SprintID)
+}
+
+// racingSprintRepository closes the sprint behind the caller's back just
+// before the caller's own close.
+type racingSprintRepository struct {
+	*fakeSprintRepository
+}
+
+func (r *racingSprintRepository) CloseSprint(ctx context.Context, tx *gorm.DB, sprint entity.Sprint) error {
+	closed := r.sprints[sprint.ID]
+	closed.State = constants.ENUM_SPRINT_STATE_CLOSED
+	r.sprints[sprint.ID] = closed
+	return r.fakeSprintRepository.CloseSprint(ctx, tx, sprint)
+}
+
+func Test_Sprint_SnapshotBackfillSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var statements []string
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+
+	require.NoError(t, migrations.Backfill(db))
+
+	require.Len(t, statements, 3)
+	assert.Contains(t, statements[2], "INSERT INTO sprint_tasks")
+	assert.Contains(t, statements[2], "NOT EXISTS (SELECT 1 FROM sprint_tasks st WHERE st.sprint_id = sprints.id)")
+}
+
+func Test_Sprint_CloseSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	var statement string
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		statement = tx.Statement.SQL.String()
+	}))
+
+	closedAt := time.Now()
+	err = repository.NewSprintRepository(db).CloseSprint(context.Background(), nil, entity.Sprint{ID: 1, State: constants.ENUM_SPRINT_STATE_CLOSED, ClosedAt: &closedAt})
+
+	// A dry run affects no rows, which reads as a sprint that is no longer
+	// active.
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Contains(t, statement, "WHERE id = ? AND state = ?")
+}
+
+func Test_Sprint_StartLocksTeamSprints(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSprintTest()
+	sprint := st.sprint(t, "Sprint 1", "2024-06-01", "2024-06-14")
+
+	tx := &gorm.DB{}
+	sprints := service.NewSprintService(fakeTransactor{tx: tx}, st.sprintRepo, st.taskRepo, service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil))
+
+	_, err := sprints.Start(ctx, 2, sprint.ID)
+	assert.ErrorIs(t, err, dto.ErrSprintNotFound)
+
+	_, err = sprints.Start(ctx, 1, sprint.ID)
+	require.NoError(t, err)
+	assert.Same(t, tx, st.sprintRepo.lockedTx)
+}
+
+func Test_Sprint_LockTeamSprintsSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var statement string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		statement = tx.Statement.SQL.String()
+	}))
+
+	_, err = repository.NewSprintRepository(db).GetSprintsByTeamIdForUpdate(context.Background(), nil, 1)
+	require.NoError(t, err)
+
+	assert.Contains(t, statement, "WHERE teams_id = ?")
+	assert.True(t, strings.HasSuffix(statement, "FOR UPDATE"), statement)
+}
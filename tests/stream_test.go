@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/realtime"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startHub(t *testing.T) (*realtime.Hub, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	hub := realtime.NewHub(realtime.NewMemoryBroker())
+	require.NoError(t, hub.Start(ctx))
+	return hub, cancel
+}
+
+func receiveEvent(t *testing.T, subscription *realtime.Subscription) realtime.Event {
+	select {
+	case event, ok := <-subscription.Events():
+		require.True(t, ok, "subscription closed")
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event reached the subscription")
+		return realtime.Event{}
+	}
+}
+
+func Test_Hub_FansOutPerTeam(t *testing.T) {
+	ctx := context.Background()
+	hub, _ := startHub(t)
+
+	first := hub.Subscribe(1)
+	defer first.Close()
+	second := hub.Subscribe(1)
+	defer second.Close()
+	other := hub.Subscribe(2)
+	defer other.Close()
+
+	require.NoError(t, hub.Publish(ctx, realtime.Event{Type: constants.ENUM_WEBHOOK_EVENT_TASK_CREATED, TeamID: 1, Data: json.RawMessage(`{}`)}))
+
+	assert.Equal(t, constants.ENUM_WEBHOOK_EVENT_TASK_CREATED, receiveEvent(t, first).Type)
+	assert.Equal(t, constants.ENUM_WEBHOOK_EVENT_TASK_CREATED, receiveEvent(t, second).Type)
+	assert.Empty(t, other.Events())
+}
+
+func Test_Hub_DropsSlowSubscribers(t *testing.T) {
+	ctx := context.Background()
+	hub, _ := startHub(t)
+
+	slow := hub.Subscribe(1)
+	defer slow.Close()
+
+	for i := 0; i <= realtime.SUBSCRIPTION_BUFFER; i++ {
+		require.NoError(t, hub.Publish(ctx, realtime.Event{Type: constants.ENUM_WEBHOOK_EVENT_TASK_UPDATED, TeamID: 1, Data: json.RawMessage(`{}`)}))
+	}
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	assert.Equal(t, realtime.SUBSCRIPTION_BUFFER, received)
+}
+
+func Test_Hub_ClosesSubscriptionsOnStop(t *testing.T) {
+	hub, stop := startHub(t)
+	subscription := hub.Subscribe(1)
+
+	stop()
+
+	select {
+	case _, ok := <-subscription.Events():
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription was not closed")
+	}
+	subscription.Close()
+}
+
+// openStream serves the team stream as if Authenticate had let the given
+// token and claims through, and returns a function reading the next event.
+func openStream(t *testing.T, stream controller.StreamController, teamId int, token string, claims dto.TokenClaims) func() [2]string {
+	r := SetUpRoutes()
+	r.GET("/api/teams/:teamId/stream", func(ctx *gin.Context) {
+		ctx.Set("token", token)
+		ctx.Set("claims", claims)
+	}, stream.StreamTeam)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/teams/"+strconv.Itoa(teamId)+"/stream", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := make(chan [2]string, 10)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		var name string
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event:"):
+				name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			case strings.HasPrefix(line, "data:"):
+				events <- [2]string{name, strings.TrimSpace(strings.TrimPrefix(line, "data:"))}
+			}
+		}
+		close(events)
+	}()
+
+	return func() [2]string {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "stream ended")
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event on the stream")
+			return [2]string{}
+		}
+	}
+}
+
+func Test_Stream_PushesTaskEvents(t *testing.T) {
+	ctx := context.Background()
+	hub, _ := startHub(t)
+	nt := setUpNotificationTest()
+
+	task := nt.task
+	task.TeamsID = 4
+	nt.assignees.assignees = append(nt.assignees.assignees, entity.TaskAssignee{TaskID: task.ID, UserID: nt.bob.ID, Role: constants.ENUM_TASK_ROLE_ASSIGNEE})
+	taskService := service.NewTaskService(fakeTransactor{}, &fakeWebhookTaskRepository{task: task}, nil, nil, nil, nil, nt.assignees, newFakeLabelRepository(), nil, nil, nil, service.TaskServiceDeps{
+		Hub: hub,
+	})
+
+	stream := controller.NewStreamController(hub, nil, nil, controller.STREAM_HEARTBEAT)
+	next := openStream(t, stream, 4, "token", dto.TokenClaims{UserID: nt.alice.ID.String(), Role: constants.ENUM_ROLE_USER})
+
+	// The stream is subscribed once it says it is ready.
+	assert.Equal(t, "ready", next()[0])
+
+	require.NoError(t, taskService.RemoveUserFromTask(ctx, strconv.Itoa(task.ID), nt.alice.ID.String()))
+
+	event := next()
+	assert.Equal(t, constants.ENUM_WEBHOOK_EVENT_TASK_UNASSIGNED, event[0])
+
+	var payload struct {
+		Type   string              `json:"type"`
+		TeamID int                 `json:"team_id"`
+		Data   dto.WebhookTaskData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(event[1]), &payload))
+	assert.Equal(t, 4, payload.TeamID)
+	assert.Equal(t, task.ID, payload.Data.Task.ID)
+	assert.Nil(t, payload.Data.Task.UserID)
+}
+
+func Test_Stream_ClosesWhenAccessEnds(t *testing.T) {
+	ctx := context.Background()
+	hub, _ := startHub(t)
+
+	open := func(t *testing.T) (func() [2]string, *fakeSessionRepository, *fakeUserTeamsRepository, dto.TokenClaims) {
+		userService, jwtService, sessionRepo := setUpSessionTest(t)
+		login, err := userService.Verify(ctx, dto.UserLoginRequest{Email: "alice@example.com", Password: "secret"})
+		require.NoError(t, err)
+		claims, err := jwtService.ParseToken(ctx, login.Token)
+		require.NoError(t, err)
+
+		members := newFakeUserTeamsRepository(entity.UserTeams{UserID: uuid.MustParse(claims.UserID), TeamID: 4, Role: constants.ENUM_TEAM_ROLE_MEMBER})
+		stream := controller.NewStreamController(hub, jwtService, service.NewAuthorizationService(nil, members, nil), 10*time.Millisecond)
+		next := openStream(t, stream, 4, login.Token, claims)
+		require.Equal(t, "ready", next()[0])
+		return next, sessionRepo, members, claims
+	}
+
+	t.Run("session revoked", func(t *testing.T) {
+		next, sessionRepo, _, claims := open(t)
+		require.NoError(t, sessionRepo.RevokeSession(ctx, nil, claims.SessionID))
+
+		assert.Equal(t, [2]string{"closed", `{"reason":"` + dto.ErrSessionRevoked.Error() + `"}`}, next())
+	})
+
+	t.Run("membership removed", func(t *testing.T) {
+		next, _, members, claims := open(t)
+		require.NoError(t, members.RemoveUserFromTeam(ctx, nil, uuid.MustParse(claims.UserID), 4))
+
+		assert.Equal(t, [2]string{"closed", `{"reason":"` + dto.ErrNotTeamMember.Error() + `"}`}, next())
+	})
+}
+
+func Test_Stream_ClosesWhenTokenExpires(t *testing.T) {
+	hub, _ := startHub(t)
+
+	stream := controller.NewStreamController(hub, nil, nil, controller.STREAM_HEARTBEAT)
+	next := openStream(t, stream, 4, "token", dto.TokenClaims{
+		UserID:    uuid.NewString(),
+		Role:      constants.ENUM_ROLE_USER,
+		ExpiresAt: time.Now().Add(50 * time.Millisecond),
+	})
+
+	assert.Equal(t, "ready", next()[0])
+	assert.Equal(t, [2]string{"closed", `{"reason":"` + dto.ErrTokenExpired.Error() + `"}`}, next())
+}
@@ -0,0 +1,482 @@
+package tests
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeTaskTreeRepository struct {
+	repository.TaskRepository
+	nextID int
+	tasks  map[int]entity.Task
+}
+
+func newFakeTaskTreeRepository() *fakeTaskTreeRepository {
+	return &fakeTaskTreeRepository{tasks: map[int]entity.Task{}}
+}
+
+func (r *fakeTaskTreeRepository) RegisterTask(ctx context.Context, tx *gorm.DB, task entity.Task) (entity.Task, error) {
+	r.nextID++
+	task.ID = r.nextID
+	r.tasks[task.ID] = task
+	return task, nil
+}
+
+func (r *fakeTaskTreeRepository) GetTaskById(ctx context.Context, tx *gorm.DB, taskId string) (entity.Task, error) {
+	id, _ := strconv.Atoi(taskId)
+	task, ok := r.tasks[id]
+	if !ok {
+		return entity.Task{}, gorm.ErrRecordNotFound
+	}
+	return task, nil
+}
+
+func (r *fakeTaskTreeRepository) GetTasksByTeamID(ctx context.Context, tx *gorm.DB, teamsID int, filter dto.TaskFilter) ([]entity.Task, error) {
+	var tasks []entity.Task
+	for id := 1; id <= r.nextID; id++ {
+		if task, ok := r.tasks[id]; ok && task.TeamsID == teamsID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *fakeTaskTreeRepository) UpdateTask(ctx context.Context, tx *gorm.DB, task entity.Task) (entity.Task, error) {
+	stored := r.tasks[task.ID]
+	stored.Title = task.Title
+	stored.Description = task.Description
+	stored.Status = task.Status
+	stored.DueDate = task.DueDate
+	if task.UserID != nil {
+		stored.UserID = task.UserID
+	}
+	r.tasks[task.ID] = stored
+	return stored, nil
+}
+
+func (r *fakeTaskTreeRepository) UpdateTaskPlanning(ctx context.Context, tx *gorm.DB, task entity.Task) error {
+	stored := r.tasks[task.ID]
+	stored.Priority = task.Priority
+	stored.Estimate = task.Estimate
+	stored.RemainingEffort = task.RemainingEffort
+	r.tasks[task.ID] = stored
+	return nil
+}
+
+func (r *fakeTaskTreeRepository) AssignUserToTask(ctx context.Context, tx *gorm.DB, taskId string, userID *uuid.UUID) error {
+	id, _ := strconv.Atoi(taskId)
+	task := r.tasks[id]
+	task.UserID = userID
+	r.tasks[id] = task
+	return nil
+}
+
+func (r *fakeTaskTreeRepository) RemoveUserFromTask(ctx context.Context, tx *gorm.DB, taskId string) error {
+	return r.AssignUserToTask(ctx, tx, taskId, nil)
+}
+
+func (r *fakeTaskTreeRepository) GetChildren(ctx context.Context, tx *gorm.DB, parentIds []int) ([]entity.Task, error) {
+	var children []entity.Task
+	for _, task := range r.tasks {
+		for _, parentId := range parentIds {
+			if task.ParentID != nil && *task.ParentID == parentId {
+				children = append(children, task)
+			}
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if *children[i].ParentID != *children[j].ParentID {
+			return *children[i].ParentID < *children[j].ParentID
+		}
+		if children[i].Position != children[j].Position {
+			return children[i].Position < children[j].Position
+		}
+		return children[i].ID < children[j].ID
+	})
+	return children, nil
+}
+
+func (r *fakeTaskTreeRepository) SetParent(ctx context.Context, tx *gorm.DB, taskId int, parentId *int, position int) error {
+	task := r.tasks[taskId]
+	task.ParentID = parentId
+	task.Position = position
+	r.tasks[taskId] = task
+	return nil
+}
+
+func (r *fakeTaskTreeRepository) ReassignTeamTasks(ctx context.Context, tx *gorm.DB, teamsID int, fromUserID uuid.UUID, toUserID *uuid.UUID) (int64, error) {
+	var count int64
+	for id, task := range r.tasks {
+		if task.TeamsID == teamsID && task.UserID != nil && *task.UserID == fromUserID {
+			task.UserID = toUserID
+			r.tasks[id] = task
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeTaskTreeRepository) DetachChildren(ctx context.Context, tx *gorm.DB, parentId int) error {
+	for id, task := range r.tasks {
+		if task.ParentID != nil && *task.ParentID == parentId {
+			task.ParentID = nil
+			r.tasks[id] = task
+		}
+	}
+	return nil
+}
+
+func (r *fakeTaskTreeRepository) DeleteTask(ctx context.Context, tx *gorm.DB, taskId string) error {
+	id, _ := strconv.Atoi(taskId)
+	delete(r.tasks, id)
+	return nil
+}
+
+func (r *fakeTaskTreeRepository) ReorderChildren(ctx context.Context, tx *gorm.DB, parentId int, taskIds []int) error {
+	for position, taskId := range taskIds {
+		task := r.tasks[taskId]
+		task.Position = position
+		r.tasks[taskId] = task
+	}
+	return nil
+}
+
+func (r *fakeTaskTreeRepository) CountChildrenByStatus(ctx context.Context, tx *gorm.DB, parentIds []int) ([]dto.TaskChildStatusCount, error) {
+	children, _ := r.GetChildren(ctx, tx, parentIds)
+	counts := map[[2]string]*dto.TaskChildStatusCount{}
+	var result []dto.TaskChildStatusCount
+	for _, child := range children {
+		key := [2]string{strconv.Itoa(*child.ParentID), child.Status}
+		if counts[key] == nil {
+			counts[key] = &dto.TaskChildStatusCount{ParentID: *child.ParentID, Status: child.Status}
+		}
+		counts[key].Count++
+	}
+	for _, count := range counts {
+		result = append(result, *count)
+	}
+	return result, nil
+}
+
+type fakeTaskChecklistRepository struct {
+	repository.TaskChecklistRepository
+	nextID int
+	items  map[int]entity.TaskChecklistItem
+}
+
+func newFakeTaskChecklistRepository() *fakeTaskChecklistRepository {
+	return &fakeTaskChecklistRepository{items: map[int]entity.TaskChecklistItem{}}
+}
+
+func (r *fakeTaskChecklistRepository) CreateItem(ctx context.Context, tx *gorm.DB, item entity.TaskChecklistItem) (entity.TaskChecklistItem, error) {
+	r.nextID++
+	item.ID = r.nextID
+	r.items[item.ID] = item
+	return item, nil
+}
+
+func (r *fakeTaskChecklistRepository) GetItemById(ctx context.Context, tx *gorm.DB, taskId int, itemId int) (entity.TaskChecklistItem, error) {
+	item, ok := r.items[itemId]
+	if !ok || item.TaskID != taskId {
+		return entity.TaskChecklistItem{}, gorm.ErrRecordNotFound
+	}
+	return item, nil
+}
+
+func (r *fakeTaskChecklistRepository) GetItemsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskChecklistItem, error) {
+	var items []entity.TaskChecklistItem
+	for _, item := range r.items {
+		if item.TaskID == taskId {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Position != items[j].Position {
+			return items[i].Position < items[j].Position
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items, nil
+}
+
+func (r *fakeTaskChecklistRepository) UpdateItem(ctx context.Context, tx *gorm.DB, item entity.TaskChecklistItem) (entity.TaskChecklistItem, error) {
+	r.items[item.ID] = item
+	return item, nil
+}
+
+func (r *fakeTaskChecklistRepository) ReorderItems(ctx context.Context, tx *gorm.DB, taskId int, itemIds []int) error {
+	for position, itemId := range itemIds {
+		item := r.items[itemId]
+		item.Position = position
+		r.items[itemId] = item
+	}
+	return nil
+}
+
+func (r *fakeTaskChecklistRepository) CountItems(ctx context.Context, tx *gorm.DB, taskIds []int) ([]dto.ChecklistCount, error) {
+	var counts []dto.ChecklistCount
+	for _, taskId := range taskIds {
+		count := dto.ChecklistCount{TaskID: taskId}
+		for _, item := range r.items {
+			if item.TaskID != taskId {
+				continue
+			}
+			count.Total++
+			if item.IsDone {
+				count.Completed++
+			}
+		}
+		if count.Total > 0 {
+			counts = append(counts, count)
+		}
+	}
+	return counts, nil
+}
+
+type subtaskTest struct {
+	taskRepo      *fakeTaskTreeRepository
+	checklistRepo *fakeTaskChecklistRepository
+	auth          service.AuthorizationService
+	service       service.TaskService
+	actor         string
+}
+
+// memberAuthorization makes alice a member of every team, which is enough to
+// create and edit tasks.
+func memberAuthorization(nt notificationTest) service.AuthorizationService {
+	members := newFakeUserTeamsRepository(entity.UserTeams{UserID: nt.alice.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER})
+	return service.NewAuthorizationService(nt.userRepo, members, nil)
+}
+
+func setUpSubtaskTest() subtaskTest {
+	nt := setUpNotificationTest()
+	taskRepo := newFakeTaskTreeRepository()
+	checklistRepo := newFakeTaskChecklistRepository()
+	workflowService := service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil)
+
+	auth := memberAuthorization(nt)
+
+	return subtaskTest{
+		taskRepo:      taskRepo,
+		checklistRepo: checklistRepo,
+		auth:          auth,
+		service:       service.NewTaskService(fakeTransactor{}, taskRepo, nil, nil, checklistRepo, nil, nt.assignees, newFakeLabelRepository(), auth, workflowService, nil, service.TaskServiceDeps{}),
+		actor:         nt.alice.ID.String(),
+	}
+}
+
+func (st subtaskTest) create(t *testing.T, teamsID int, status string, parentId *int) dto.TaskResponse {
+	task, err := st.service.Register(context.Background(), dto.TaskCreateRequest{
+		Title:       "task",
+		Description: "description",
+		Status:      status,
+		DueDate:     "2030-01-01T00:00:00Z",
+		TeamsID:     teamsID,
+		ParentID:    parentId,
+	}, st.actor)
+	require.NoError(t, err)
+	return task
+}
+
+func (st subtaskTest) move(taskId int, parentId *int) error {
+	_, err := st.service.MoveTask(context.Background(), strconv.Itoa(taskId), dto.TaskMoveRequest{ParentID: parentId}, st.actor)
+	return err
+}
+
+func Test_Subtask_CreateAppendsToParent(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSubtaskTest()
+
+	parent := st.create(t, 1, "To Do", nil)
+	first := st.create(t, 1, "To Do", &parent.ID)
+	second := st.create(t, 1, "To Do", &parent.ID)
+
+	assert.Equal(t, &parent.ID, first.ParentID)
+	assert.Equal(t, 1, st.taskRepo.tasks[second.ID].Position)
+
+	subtasks, err := st.service.GetSubtasks(ctx, strconv.Itoa(parent.ID))
+	require.NoError(t, err)
+	require.Len(t, subtasks, 2)
+	assert.Equal(t, first.ID, subtasks[0].ID)
+	assert.Equal(t, second.ID, subtasks[1].ID)
+}
+
+func Test_Subtask_RejectsCycles(t *testing.T) {
+	st := setUpSubtaskTest()
+
+	a := st.create(t, 1, "To Do", nil)
+	b := st.create(t, 1, "To Do", &a.ID)
+	c := st.create(t, 1, "To Do", &b.ID)
+
+	assert.ErrorIs(t, st.move(a.ID, &a.ID), dto.ErrTaskCycle)
+	assert.ErrorIs(t, st.move(a.ID, &c.ID), dto.ErrTaskCycle)
+	assert.ErrorIs(t, st.move(b.ID, &c.ID), dto.ErrTaskCycle)
+}
+
+func Test_Subtask_DepthLimit(t *testing.T) {
+	st := setUpSubtaskTest()
+
+	a := st.create(t, 1, "To Do", nil)
+	b := st.create(t, 1, "To Do", &a.ID)
+	c := st.create(t, 1, "To Do", &b.ID)
+
+	_, err := st.service.Register(context.Background(), dto.TaskCreateRequest{
+		Title:       "too deep",
+		Description: "description",
+		Status:      "To Do",
+		DueDate:     "2030-01-01T00:00:00Z",
+		TeamsID:     1,
+		ParentID:    &c.ID,
+	}, st.actor)
+	assert.ErrorIs(t, err, dto.ErrTaskTooDeep)
+
+	// d has a subtask of its own, so nesting it under b would need four levels.
+	d := st.create(t, 1, "To Do", nil)
+	st.create(t, 1, "To Do", &d.ID)
+	assert.ErrorIs(t, st.move(d.ID, &b.ID), dto.ErrTaskTooDeep)
+	require.NoError(t, st.move(d.ID, &a.ID))
+
+	// Moving c back to the top level is always allowed.
+	require.NoError(t, st.move(c.ID, nil))
+	assert.Nil(t, st.taskRepo.tasks[c.ID].ParentID)
+}
+
+func Test_Subtask_ParentMustBeInSameTeam(t *testing.T) {
+	st := setUpSubtaskTest()
+
+	parent := st.create(t, 1, "To Do", nil)
+	task := st.create(t, 2, "To Do", nil)
+	missing := 999
+
+	assert.ErrorIs(t, st.move(task.ID, &parent.ID), dto.ErrParentTaskOtherTeam)
+	assert.ErrorIs(t, st.move(task.ID, &missing), dto.ErrParentTaskNotFound)
+}
+
+func Test_Subtask_Reorder(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSubtaskTest()
+
+	parent := st.create(t, 1, "To Do", nil)
+	first := st.create(t, 1, "To Do", &parent.ID)
+	second := st.create(t, 1, "To Do", &parent.ID)
+	third := st.create(t, 1, "To Do", &parent.ID)
+
+	_, err := st.service.ReorderSubtasks(ctx, strconv.Itoa(parent.ID), dto.TaskReorderRequest{TaskIDs: []int{third.ID, first.ID}})
+	assert.ErrorIs(t, err, dto.ErrInvalidTaskOrder)
+	_, err = st.service.ReorderSubtasks(ctx, strconv.Itoa(parent.ID), dto.TaskReorderRequest{TaskIDs: []int{third.ID, first.ID, first.ID}})
+	assert.ErrorIs(t, err, dto.ErrInvalidTaskOrder)
+
+	subtasks, err := st.service.ReorderSubtasks(ctx, strconv.Itoa(parent.ID), dto.TaskReorderRequest{TaskIDs: []int{third.ID, first.ID, second.ID}})
+	require.NoError(t, err)
+	require.Len(t, subtasks, 3)
+	assert.Equal(t, []int{third.ID, first.ID, second.ID}, []int{subtasks[0].ID, subtasks[1].ID, subtasks[2].ID})
+}
+
+func Test_Subtask_ProgressRollUp(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSubtaskTest()
+	checklist := service.NewTaskChecklistService(st.checklistRepo, st.taskRepo)
+
+	parent := st.create(t, 1, "To Do", nil)
+	st.create(t, 1, "Done", &parent.ID)
+	st.create(t, 1, "In Progress", &parent.ID)
+	lonely := st.create(t, 1, "To Do", nil)
+
+	item, err := checklist.Create(ctx, strconv.Itoa(parent.ID), dto.ChecklistItemCreateRequest{Title: "write docs"})
+	require.NoError(t, err)
+	_, err = checklist.Create(ctx, strconv.Itoa(parent.ID), dto.ChecklistItemCreateRequest{Title: "ship"})
+	require.NoError(t, err)
+	done := true
+	_, err = checklist.Update(ctx, strconv.Itoa(parent.ID), item.ID, st.actor, dto.ChecklistItemUpdateRequest{IsDone: &done})
+	require.NoError(t, err)
+
+	task, err := st.service.GetTaskById(ctx, strconv.Itoa(parent.ID))
+	require.NoError(t, err)
+	assert.Equal(t, &dto.TaskProgress{Completed: 2, Total: 4}, task.Progress)
+
+	tasks, err := st.service.GetTasksByTeamID(ctx, 1, dto.TaskListRequest{}, st.actor)
+	require.NoError(t, err)
+	for _, task := range tasks {
+		switch task.ID {
+		case parent.ID:
+			assert.Equal(t, &dto.TaskProgress{Completed: 2, Total: 4}, task.Progress)
+		case lonely.ID:
+			assert.Nil(t, task.Progress)
+		}
+	}
+}
+
+func Test_Checklist_CheckOffAndReorder(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSubtaskTest()
+	checklist := service.NewTaskChecklistService(st.checklistRepo, st.taskRepo)
+
+	task := st.create(t, 1, "To Do", nil)
+	taskId := strconv.Itoa(task.ID)
+	first, err := checklist.Create(ctx, taskId, dto.ChecklistItemCreateRequest{Title: "first"})
+	require.NoError(t, err)
+	second, err := checklist.Create(ctx, taskId, dto.ChecklistItemCreateRequest{Title: "second"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.Position)
+
+	done := true
+	checked, err := checklist.Update(ctx, taskId, first.ID, st.actor, dto.ChecklistItemUpdateRequest{IsDone: &done})
+	require.NoError(t, err)
+	assert.True(t, checked.IsDone)
+	require.NotNil(t, checked.CompletedBy)
+	assert.Equal(t, st.actor, checked.CompletedBy.String())
+	assert.NotNil(t, checked.CompletedAt)
+
+	done = false
+	title := "first, renamed"
+	unchecked, err := checklist.Update(ctx, taskId, first.ID, st.actor, dto.ChecklistItemUpdateRequest{Title: &title, IsDone: &done})
+	require.NoError(t, err)
+	assert.False(t, unchecked.IsDone)
+	assert.Nil(t, unchecked.CompletedBy)
+	assert.Nil(t, unchecked.CompletedAt)
+	assert.Equal(t, title, unchecked.Title)
+
+	_, err = checklist.Update(ctx, strconv.Itoa(st.create(t, 1, "To Do", nil).ID), first.ID, st.actor, dto.ChecklistItemUpdateRequest{IsDone: &done})
+	assert.ErrorIs(t, err, dto.ErrChecklistItemNotFound)
+
+	_, err = checklist.Reorder(ctx, taskId, dto.ChecklistReorderRequest{ItemIDs: []int{second.ID}})
+	assert.ErrorIs(t, err, dto.ErrInvalidChecklistOrder)
+
+	items, err := checklist.Reorder(ctx, taskId, dto.ChecklistReorderRequest{ItemIDs: []int{second.ID, first.ID}})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, second.ID, items[0].ID)
+	assert.Equal(t, first.ID, items[1].ID)
+}
+
+func Test_Subtask_ProgressSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql []string
+	db.Callback().Row().After("gorm:row").Register("test:capture", func(tx *gorm.DB) {
+		sql = append(sql, tx.Statement.SQL.String())
+	})
+
+	// Scan is not supported in dry run mode, so only the SQL is checked.
+	ctx := context.Background()
+	repository.NewTaskRepository(db).CountChildrenByStatus(ctx, nil, []int{1, 2})
+	repository.NewTaskChecklistRepository(db).CountItems(ctx, nil, []int{1})
+
+	require.Len(t, sql, 2)
+	assert.Contains(t, sql[0], "SELECT parent_id, status, COUNT(*) AS count FROM `tasks` WHERE parent_id IN (?,?) AND `tasks`.`deleted_at` IS NULL GROUP BY parent_id, status")
+	assert.Contains(t, sql[1], "FROM `task_checklist_items` WHERE task_id IN (?) AND `task_checklist_items`.`deleted_at` IS NULL GROUP BY `task_id`")
+}
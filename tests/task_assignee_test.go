@@ -0,0 +1,274 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/migrations"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeTaskAssigneeRepository struct {
+	repository.TaskAssigneeRepository
+	users     *fakeUserRepository
+	assignees []entity.TaskAssignee
+}
+
+func newFakeTaskAssigneeRepository(users *fakeUserRepository) *fakeTaskAssigneeRepository {
+	return &fakeTaskAssigneeRepository{users: users}
+}
+
+func (r *fakeTaskAssigneeRepository) AddAssignee(ctx context.Context, tx *gorm.DB, assignee entity.TaskAssignee) error {
+	for i, existing := range r.assignees {
+		if existing.TaskID == assignee.TaskID && existing.UserID == assignee.UserID {
+			r.assignees[i].Role = assignee.Role
+			return nil
+		}
+	}
+	r.assignees = append(r.assignees, assignee)
+	return nil
+}
+
+func (r *fakeTaskAssigneeRepository) GetAssignees(ctx context.Context, tx *gorm.DB, taskIds []int) ([]entity.TaskAssignee, error) {
+	var assignees []entity.TaskAssignee
+	for _, assignee := range r.assignees {
+		for _, taskId := range taskIds {
+			if assignee.TaskID != taskId {
+				continue
+			}
+			if r.users != nil {
+				assignee.User, _ = r.users.GetUserById(ctx, tx, assignee.UserID.String())
+			}
+			assignees = append(assignees, assignee)
+		}
+	}
+	return assignees, nil
+}
+
+func (r *fakeTaskAssigneeRepository) RemoveAssignee(ctx context.Context, tx *gorm.DB, taskId int, userId uuid.UUID) error {
+	for i, assignee := range r.assignees {
+		if assignee.TaskID == taskId && assignee.UserID == userId {
+			r.assignees = append(r.assignees[:i], r.assignees[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+type taskAssigneeTest struct {
+	notificationTest
+	taskRepo *fakeTaskTreeRepository
+	events   *fakeTaskEventRepository
+	service  service.TaskService
+}
+
+func setUpTaskAssigneeTest() taskAssigneeTest {
+	nt := setUpNotificationTest()
+	taskRepo := newFakeTaskTreeRepository()
+	events := &fakeTaskEventRepository{}
+	members := newFakeUserTeamsRepository(
+		entity.UserTeams{UserID: nt.alice.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+		entity.UserTeams{UserID: nt.bob.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+		entity.UserTeams{UserID: nt.carol.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+	)
+
+	return taskAssigneeTest{
+		notificationTest: nt,
+		taskRepo:         taskRepo,
+		events:           events,
+		service: service.NewTaskService(fakeTransactor{}, taskRepo, nt.userRepo, members, newFakeTaskChecklistRepository(), nil, nt.assignees, newFakeLabelRepository(), service.NewAuthorizationService(nt.userRepo, members, nil), service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil), nil, service.TaskServiceDeps{
+			History:       events,
+			Notifications: nt.service,
+		}),
+	}
+}
+
+// create adds a task of team 1 assigned to bob.
+func (at taskAssigneeTest) create(t *testing.T) string {
+	task, err := at.service.Register(context.Background(), dto.TaskCreateRequest{
+		Title:       "task",
+		Description: "description",
+		Status:      "To Do",
+		DueDate:     "2030-01-01T00:00:00Z",
+		TeamsID:     1,
+		UserID:      &at.bob.ID,
+	}, at.alice.ID.String())
+	require.NoError(t, err)
+	return strconv.Itoa(task.ID)
+}
+
+func (at taskAssigneeTest) roles(t *testing.T, taskId string) map[string]string {
+	assignees, err := at.service.GetAssignees(context.Background(), taskId)
+	require.NoError(t, err)
+
+	roles := map[string]string{}
+	for _, assignee := range assignees {
+		roles[assignee.User.Name] = assignee.Role
+	}
+	return roles
+}
+
+func Test_TaskAssignee_AssignAddsAnotherAssignee(t *testing.T) {
+	ctx := context.Background()
+	at := setUpTaskAssigneeTest()
+	taskId := at.create(t)
+
+	require.NoError(t, at.service.AssignUserToTask(ctx, taskId, &at.carol.ID, at.alice.ID.String()))
+
+	task, err := at.service.GetTaskById(ctx, taskId)
+	require.NoError(t, err)
+	assert.Equal(t, at.bob.ID, *task.UserID)
+	assert.Equal(t, map[string]string{"Bob": constants.ENUM_TASK_ROLE_ASSIGNEE, "Carol": constants.ENUM_TASK_ROLE_ASSIGNEE}, at.roles(t, taskId))
+
+	last := at.events.events[len(at.events.events)-1]
+	assert.Equal(t, constants.ENUM_TASK_EVENT_ASSIGNED, last.Type)
+	assert.Equal(t, constants.ENUM_TASK_ROLE_ASSIGNEE, last.Field)
+	assert.Equal(t, at.carol.ID.String(), last.NewValue)
+}
+
+func Test_TaskAssignee_RemovingPrimaryPromotesNext(t *testing.T) {
+	ctx := context.Background()
+	at := setUpTaskAssigneeTest()
+	taskId := at.create(t)
+
+	_, err := at.service.AddAssignee(ctx, taskId, dto.TaskAssigneeRequest{UserID: at.carol.ID}, at.alice.ID.String())
+	require.NoError(t, err)
+
+	require.NoError(t, at.service.RemoveUserFromTask(ctx, taskId, at.alice.ID.String()))
+
+	task, err := at.service.GetTaskById(ctx, taskId)
+	require.NoError(t, err)
+	require.NotNil(t, task.UserID)
+	assert.Equal(t, at.carol.ID, *task.UserID)
+	assert.Equal(t, map[string]string{"Carol": constants.ENUM_TASK_ROLE_ASSIGNEE}, at.roles(t, taskId))
+
+	last := at.events.events[len(at.events.events)-1]
+	assert.Equal(t, "user_id", last.Field)
+	assert.Equal(t, at.bob.ID.String(), last.OldValue)
+	assert.Equal(t, at.carol.ID.String(), last.NewValue)
+
+	require.NoError(t, at.service.RemoveAssignee(ctx, taskId, at.carol.ID.String(), at.alice.ID.String()))
+	task, err = at.service.GetTaskById(ctx, taskId)
+	require.NoError(t, err)
+	assert.Nil(t, task.UserID)
+	assert.Empty(t, task.Assignees)
+}
+
+func Test_TaskAssignee_ReviewerIsNotPrimary(t *testing.T) {
+	ctx := context.Background()
+	at := setUpTaskAssigneeTest()
+	taskId := at.create(t)
+
+	require.NoError(t, at.service.RemoveUserFromTask(ctx, taskId, at.alice.ID.String()))
+	_, err := at.service.AddAssignee(ctx, taskId, dto.TaskAssigneeRequest{UserID: at.carol.ID, Role: constants.ENUM_TASK_ROLE_REVIEWER}, at.alice.ID.String())
+	require.NoError(t, err)
+
+	task, err := at.service.GetTaskById(ctx, taskId)
+	require.NoError(t, err)
+	assert.Nil(t, task.UserID)
+
+	_, err = at.service.AddAssignee(ctx, taskId, dto.TaskAssigneeRequest{UserID: at.carol.ID, Role: "owner"}, at.alice.ID.String())
+	assert.ErrorIs(t, err, dto.ErrInvalidAssigneeRole)
+
+	_, err = at.service.AddAssignee(ctx, taskId, dto.TaskAssigneeRequest{UserID: uuid.New()}, at.alice.ID.String())
+	assert.ErrorIs(t, err, dto.ErrAssigneeNotTeamMember)
+}
+
+func Test_TaskAssignee_WatcherIsNotified(t *testing.T) {
+	ctx := context.Background()
+	at := setUpTaskAssigneeTest()
+	taskId := at.create(t)
+
+	require.NoError(t, at.service.WatchTask(ctx, taskId, at.carol.ID.String()))
+	assert.Equal(t, constants.ENUM_TASK_ROLE_WATCHER, at.roles(t, taskId)["Carol"])
+	assert.ErrorIs(t, at.service.UnwatchTask(ctx, taskId, at.bob.ID.String()), dto.ErrNotWatching)
+
+	_, err := at.service.Update(ctx, dto.TaskUpdateRequest{
+		Title:       "task",
+		Description: "description",
+		Status:      "In Progress",
+		DueDate:     "2030-01-01T00:00:00Z",
+	}, taskId, at.alice.ID.String())
+	require.NoError(t, err)
+
+	notified := map[uuid.UUID]bool{}
+	for _, notification := range at.notifications.notifications {
+		if notification.Type == constants.ENUM_NOTIFICATION_TASK_STATUS_CHANGED {
+			notified[notification.UserID] = true
+		}
+	}
+	assert.Equal(t, map[uuid.UUID]bool{at.bob.ID: true, at.carol.ID: true}, notified)
+
+	require.NoError(t, at.service.UnwatchTask(ctx, taskId, at.carol.ID.String()))
+	assert.NotContains(t, at.roles(t, taskId), "Carol")
+}
+
+func Test_TaskAssignee_GetTasksByUserIDSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}))
+
+	_, err = repository.NewTaskRepository(db).GetTasksByUserID(context.Background(), uuid.NewString())
+	require.NoError(t, err)
+
+	assert.True(t, strings.Contains(sql, "user_id = ? OR id IN (SELECT `task_id` FROM `task_assignees` WHERE user_id = ?"), sql)
+}
+
+type failingTaskAssigneeRepository struct {
+	*fakeTaskAssigneeRepository
+}
+
+func (r failingTaskAssigneeRepository) AddAssignee(ctx context.Context, tx *gorm.DB, assignee entity.TaskAssignee) error {
+	return errors.New("connection lost")
+}
+
+func Test_TaskAssignee_CreateFailsWithoutAssigneeRow(t *testing.T) {
+	at := setUpTaskAssigneeTest()
+	members := newFakeUserTeamsRepository(
+		entity.UserTeams{UserID: at.alice.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+		entity.UserTeams{UserID: at.bob.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+	)
+	taskService := service.NewTaskService(fakeTransactor{}, at.taskRepo, at.userRepo, members, newFakeTaskChecklistRepository(), nil, failingTaskAssigneeRepository{at.assignees}, newFakeLabelRepository(), service.NewAuthorizationService(at.userRepo, members, nil), service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil), nil, service.TaskServiceDeps{})
+
+	_, err := taskService.Register(context.Background(), dto.TaskCreateRequest{
+		Title:       "task",
+		Description: "description",
+		Status:      "To Do",
+		DueDate:     "2030-01-01T00:00:00Z",
+		TeamsID:     1,
+		UserID:      &at.bob.ID,
+	}, at.alice.ID.String())
+	assert.ErrorIs(t, err, dto.ErrCreateTask)
+}
+
+func Test_TaskAssignee_BackfillSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var statements []string
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+
+	require.NoError(t, migrations.Backfill(db))
+
+	require.NotEmpty(t, statements)
+	assert.Contains(t, statements[0], "INSERT INTO task_assignees (task_id, user_id, role, created_at)")
+	assert.Contains(t, statements[0], "NOT EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = tasks.user_id)")
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/controller"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/middleware"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeTaskAttachmentRepository struct {
+	repository.TaskAttachmentRepository
+	nextID      int
+	attachments map[int]entity.TaskAttachment
+}
+
+func (r *fakeTaskAttachmentRepository) CreateAttachment(ctx context.Context, tx *gorm.DB, attachment entity.TaskAttachment) (entity.TaskAttachment, error) {
+	r.nextID++
+	attachment.ID = r.nextID
+	r.attachments[attachment.ID] = attachment
+	return attachment, nil
+}
+
+func (r *fakeTaskAttachmentRepository) GetAttachmentById(ctx context.Context, tx *gorm.DB, taskId int, attachmentId int) (entity.TaskAttachment, error) {
+	attachment, ok := r.attachments[attachmentId]
+	if !ok || attachment.TaskID != taskId {
+		return entity.TaskAttachment{}, gorm.ErrRecordNotFound
+	}
+	return attachment, nil
+}
+
+type attachmentTest struct {
+	nt          notificationTest
+	task        entity.Task
+	attachments *fakeTaskAttachmentRepository
+	service     service.TaskAttachmentService
+	auth        service.AuthorizationService
+}
+
+// setUpAttachmentTest puts one task in team 1, where bob is a member and
+// carol is not.
+func setUpAttachmentTest(t *testing.T) attachmentTest {
+	nt := setUpNotificationTest()
+	taskRepo := newFakeTaskTreeRepository()
+	task, _ := taskRepo.RegisterTask(context.Background(), nil, entity.Task{Title: "Ship it", TeamsID: 1})
+	attachments := &fakeTaskAttachmentRepository{attachments: map[int]entity.TaskAttachment{}}
+	auth := service.NewAuthorizationService(nt.userRepo, newFakeUserTeamsRepository(
+		entity.UserTeams{UserID: nt.bob.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+	), taskRepo)
+	fileStorage := storage.NewLocalStorage(t.TempDir(), "/assets", []byte("secret"))
+
+	return attachmentTest{
+		nt:          nt,
+		task:        task,
+		attachments: attachments,
+		service:     service.NewTaskAttachmentService(attachments, taskRepo, auth, fileStorage),
+		auth:        auth,
+	}
+}
+
+func (at attachmentTest) upload(t *testing.T, filename string, contentType string, content string) (dto.AttachmentResponse, error) {
+	return at.service.Upload(context.Background(), "1", at.nt.bob.ID.String(), multipartFile(t, filename, contentType, []byte(content)))
+}
+
+func Test_Attachment_ExtensionFollowsContent(t *testing.T) {
+	at := setUpAttachmentTest(t)
+
+	attachment, err := at.upload(t, "notes.html", "text/html", "just some plain notes")
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", attachment.MimeType)
+	assert.Equal(t, "notes.html", attachment.FileName)
+	assert.Equal(t, "/api/tasks/1/attachments/1/download", attachment.Url)
+	assert.True(t, strings.HasSuffix(at.attachments.attachments[attachment.ID].Path, ".txt"))
+
+	_, err = at.upload(t, "page.txt", "text/plain", "<html><script>alert(1)</script></html>")
+	assert.ErrorIs(t, err, dto.ErrAttachmentTypeNotAllowed)
+}
+
+func Test_Attachment_Download(t *testing.T) {
+	at := setUpAttachmentTest(t)
+	_, err := at.upload(t, "notes.html", "text/html", "just some plain notes")
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/tasks/:taskId/attachments/:attachmentId/download",
+		func(ctx *gin.Context) {
+			ctx.Set("user_id", ctx.GetHeader("X-User"))
+			ctx.Set("role", constants.ENUM_ROLE_USER)
+		},
+		middleware.Authorize(at.auth, constants.ACTION_TASK_ATTACHMENT_READ),
+		controller.NewTaskAttachmentController(at.service).Download)
+
+	download := func(userId string, url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("X-User", userId)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := download(at.nt.bob.ID.String(), "/api/tasks/1/attachments/1/download")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "just some plain notes", w.Body.String())
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=notes.html`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+	assert.Equal(t, http.StatusForbidden, download(at.nt.carol.ID.String(), "/api/tasks/1/attachments/1/download").Code)
+	assert.Equal(t, http.StatusBadRequest, download(at.nt.bob.ID.String(), "/api/tasks/1/attachments/2/download").Code)
+}
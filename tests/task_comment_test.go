@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeTaskCommentRepository struct {
+	repository.TaskCommentRepository
+	nextID     int
+	comments   map[int]entity.TaskComment
+	mentions   map[int][]uuid.UUID
+	mentionsTx *gorm.DB
+	err        error
+}
+
+func newFakeTaskCommentRepository() *fakeTaskCommentRepository {
+	return &fakeTaskCommentRepository{comments: map[int]entity.TaskComment{}, mentions: map[int][]uuid.UUID{}}
+}
+
+func (r *fakeTaskCommentRepository) CreateComment(ctx context.Context, tx *gorm.DB, comment entity.TaskComment) (entity.TaskComment, error) {
+	r.nextID++
+	comment.ID = r.nextID
+	r.comments[comment.ID] = comment
+	return comment, nil
+}
+
+func (r *fakeTaskCommentRepository) GetCommentById(ctx context.Context, tx *gorm.DB, taskId int, commentId int) (entity.TaskComment, error) {
+	comment, ok := r.comments[commentId]
+	if !ok || comment.TaskID != taskId {
+		return entity.TaskComment{}, gorm.ErrRecordNotFound
+	}
+	for _, userId := range r.mentions[commentId] {
+		comment.Mentions = append(comment.Mentions, entity.TaskCommentMention{CommentID: commentId, UserID: userId, User: entity.User{ID: userId}})
+	}
+	return comment, nil
+}
+
+func (r *fakeTaskCommentRepository) UpdateComment(ctx context.Context, tx *gorm.DB, comment entity.TaskComment) (entity.TaskComment, error) {
+	comment.Mentions = nil
+	r.comments[comment.ID] = comment
+	return comment, nil
+}
+
+func (r *fakeTaskCommentRepository) ReplaceMentions(ctx context.Context, tx *gorm.DB, commentId int, userIds []uuid.UUID) error {
+	r.mentionsTx = tx
+	if r.err != nil {
+		return r.err
+	}
+	r.mentions[commentId] = userIds
+	return nil
+}
+
+// fakeTeamDirectory lists the team's members with their names and emails,
+// which mentions are resolved against.
+type fakeTeamDirectory struct {
+	*fakeUserTeamsRepository
+	users []entity.User
+}
+
+func (r fakeTeamDirectory) GetUsersByTeamId(ctx context.Context, tx *gorm.DB, teamId uint) ([]entity.User, error) {
+	return r.users, nil
+}
+
+type commentTest struct {
+	notificationTest
+	tx       *gorm.DB
+	tasks    [2]entity.Task
+	comments *fakeTaskCommentRepository
+	service  service.TaskCommentService
+}
+
+// setUpCommentTest puts two tasks in team 1, whose members are alice, bob,
+// carol and a second Bob.
+func setUpCommentTest() commentTest {
+	nt := setUpNotificationTest()
+	tx := &gorm.DB{}
+	taskRepo := newFakeTaskTreeRepository()
+	first, _ := taskRepo.RegisterTask(context.Background(), nil, entity.Task{Title: "Ship it", TeamsID: 1})
+	second, _ := taskRepo.RegisterTask(context.Background(), nil, entity.Task{Title: "Test it", TeamsID: 1})
+	comments := newFakeTaskCommentRepository()
+	members := fakeTeamDirectory{
+		fakeUserTeamsRepository: newFakeUserTeamsRepository(),
+		users:                   []entity.User{nt.alice, nt.bob, nt.carol, {ID: uuid.New(), Name: "Bob", Email: "bobby@example.com"}},
+	}
+
+	return commentTest{
+		notificationTest: nt,
+		tx:               tx,
+		tasks:            [2]entity.Task{first, second},
+		comments:         comments,
+		service:          service.NewTaskCommentService(fakeTransactor{tx: tx}, comments, taskRepo, members, nt.service),
+	}
+}
+
+func (ct commentTest) comment(task entity.Task, body string, parentId *int) (dto.CommentResponse, error) {
+	return ct.service.Create(context.Background(), strconv.Itoa(task.ID), ct.alice.ID.String(), dto.CommentCreateRequest{Body: body, ParentID: parentId})
+}
+
+func Test_Comment_Reply(t *testing.T) {
+	ct := setUpCommentTest()
+
+	parent, err := ct.comment(ct.tasks[0], "first", nil)
+	require.NoError(t, err)
+
+	reply, err := ct.comment(ct.tasks[0], "second", &parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &parent.ID, reply.ParentID)
+}
+
+func Test_Comment_ReplyNeedsParentOnSameTask(t *testing.T) {
+	ct := setUpCommentTest()
+
+	other, err := ct.comment(ct.tasks[1], "on the other task", nil)
+	require.NoError(t, err)
+
+	_, err = ct.comment(ct.tasks[0], "reply", &other.ID)
+	assert.ErrorIs(t, err, dto.ErrParentCommentNotFound)
+
+	missing := 99
+	_, err = ct.comment(ct.tasks[0], "reply", &missing)
+	assert.ErrorIs(t, err, dto.ErrParentCommentNotFound)
+
+	assert.Len(t, ct.comments.comments, 1)
+}
+
+func Test_Comment_ResolvesMentions(t *testing.T) {
+	ct := setUpCommentTest()
+
+	// @bob matches both Bobs and @dave matches nobody, so neither counts.
+	comment, err := ct.comment(ct.tasks[0], "@carol @Bob @bob@example.com @dave @carol", nil)
+	require.NoError(t, err)
+	assert.Same(t, ct.tx, ct.comments.mentionsTx)
+
+	var mentioned []string
+	for _, user := range comment.Mentions {
+		mentioned = append(mentioned, user.ID)
+	}
+	assert.Equal(t, []string{ct.carol.ID.String(), ct.bob.ID.String()}, mentioned)
+
+	for _, notification := range ct.notifications.notifications {
+		assert.Equal(t, constants.ENUM_NOTIFICATION_TASK_COMMENT, notification.Type)
+	}
+	require.Len(t, ct.notifications.notifications, 2)
+
+	// Editing the comment replaces its mentions.
+	updated, err := ct.service.Update(context.Background(), strconv.Itoa(ct.tasks[0].ID), comment.ID, ct.alice.ID.String(), dto.CommentUpdateRequest{Body: "never mind"})
+	require.NoError(t, err)
+	assert.Empty(t, updated.Mentions)
+}
+
+func Test_Comment_FailedMentionsFailTheComment(t *testing.T) {
+	ct := setUpCommentTest()
+	ct.comments.err = errors.New("deadlock")
+
+	_, err := ct.comment(ct.tasks[0], "@carol have a look", nil)
+	assert.ErrorIs(t, err, dto.ErrCreateComment)
+	assert.Empty(t, ct.notifications.notifications)
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func Test_ParseTaskFilter(t *testing.T) {
+	userId := uuid.New()
+
+	filter, err := service.ParseTaskFilter(dto.TaskListRequest{
+		Status:    "todo, doing,,",
+		TeamID:    3,
+		Assignee:  "me",
+		Label:     "4, 9",
+		DueBefore: "2024-06-01",
+		DueAfter:  "2024-05-01T08:00:00Z",
+		Sort:      "-due_date,title",
+	}, userId.String())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"todo", "doing"}, filter.Statuses)
+	assert.Equal(t, 3, filter.TeamID)
+	assert.Equal(t, userId, *filter.AssigneeID)
+	assert.Equal(t, []int{4, 9}, filter.LabelIDs)
+	assert.Equal(t, "2024-06-01T00:00:00Z", filter.DueBefore.Format("2006-01-02T15:04:05Z07:00"))
+	assert.Equal(t, 8, filter.DueAfter.Hour())
+	assert.Equal(t, []dto.TaskSort{{Field: "due_date", Desc: true}, {Field: "title"}}, filter.Sort)
+
+	filter, err = service.ParseTaskFilter(dto.TaskListRequest{Assignee: "none"}, userId.String())
+	require.NoError(t, err)
+	assert.True(t, filter.Unassigned)
+}
+
+func Test_ParseTaskFilter_Invalid(t *testing.T) {
+	for _, req := range []dto.TaskListRequest{
+		{Sort: "password"},
+		{Sort: "-deleted_at"},
+		{Assignee: "someone"},
+		{Label: "bug"},
+		{Priority: "high,critical"},
+		{MaxEstimate: "-1"},
+		{MinRemaining: "a lot"},
+		{DueBefore: "next week"},
+	} {
+		_, err := service.ParseTaskFilter(req, uuid.NewString())
+		assert.ErrorIs(t, err, dto.ErrInvalidTaskFilter, req)
+	}
+}
+
+func Test_FilterTasks_CountAndPageMatch(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	filter, err := service.ParseTaskFilter(dto.TaskListRequest{
+		Status:   "todo,doing",
+		Assignee: "none",
+		Sort:     "-due_date",
+	}, uuid.NewString())
+	require.NoError(t, err)
+
+	countSQL := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var count int64
+		return tx.Model(&entity.Task{}).Scopes(repository.FilterTasks("50%_off", filter)).Count(&count)
+	})
+	pageSQL := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var tasks []entity.Task
+		return tx.Scopes(repository.FilterTasks("50%_off", filter), repository.SortTasks(filter.Sort), repository.Paginate(2, 10)).Find(&tasks)
+	})
+
+	where := `WHERE (title LIKE '%50\%\_off%' OR description LIKE '%50\%\_off%') AND status IN ('todo','doing') AND user_id IS NULL AND ` + "`tasks`.`deleted_at` IS NULL"
+	assert.Contains(t, countSQL, where)
+	assert.Contains(t, pageSQL, where)
+	assert.Contains(t, pageSQL, "ORDER BY `due_date` DESC,`id` LIMIT 10 OFFSET 10")
+}
+
+func Test_FilterTasks_LabelsMustAllMatch(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var tasks []entity.Task
+		return tx.Scopes(repository.FilterTasks("", dto.TaskFilter{LabelIDs: []int{4, 9}})).Find(&tasks)
+	})
+
+	assert.Contains(t, sql, "WHERE tasks.id IN (SELECT task_id FROM task_labels WHERE label_id = 4) AND tasks.id IN (SELECT task_id FROM task_labels WHERE label_id = 9)")
+}
+
+func Test_FilterTasks_PriorityAndEffort(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	filter, err := service.ParseTaskFilter(dto.TaskListRequest{
+		Priority:     "High, urgent",
+		MinEstimate:  "1",
+		MaxRemaining: "0",
+		Sort:         "-priority,remaining_effort",
+	}, uuid.NewString())
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, filter.Priorities)
+
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		var tasks []entity.Task
+		return tx.Where("teams_id = ?", 1).Scopes(repository.FilterTasks("", filter), repository.SortTasks(filter.Sort)).Find(&tasks)
+	})
+
+	assert.Contains(t, sql, "WHERE teams_id = 1 AND priority IN (3,4) AND estimate >= 1 AND remaining_effort <= 0")
+	assert.Contains(t, sql, "ORDER BY `priority` DESC,`remaining_effort`,`id`")
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeTransactor runs fn straight away with tx, which the fakes ignore.
+// Nothing is rolled back when fn fails.
+type fakeTransactor struct {
+	tx *gorm.DB
+}
+
+func (t fakeTransactor) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return fn(t.tx)
+}
+
+// fakeTaskAttachmentService records the tasks whose files were removed.
+type fakeTaskAttachmentService struct {
+	service.TaskAttachmentService
+	deleted []int
+}
+
+func (s *fakeTaskAttachmentService) DeleteByTaskId(ctx context.Context, taskId int) error {
+	s.deleted = append(s.deleted, taskId)
+	return nil
+}
+
+type taskHistoryTest struct {
+	notificationTest
+	tx          *gorm.DB
+	taskRepo    *fakeTaskTreeRepository
+	events      *fakeTaskEventRepository
+	attachments *fakeTaskAttachmentService
+	service     service.TaskService
+}
+
+func setUpTaskHistoryTest() taskHistoryTest {
+	nt := setUpNotificationTest()
+	tx := &gorm.DB{}
+	taskRepo := newFakeTaskTreeRepository()
+	events := &fakeTaskEventRepository{}
+	attachments := &fakeTaskAttachmentService{}
+	members := newFakeUserTeamsRepository(
+		entity.UserTeams{UserID: nt.alice.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MAINTAINER},
+		entity.UserTeams{UserID: nt.bob.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+		entity.UserTeams{UserID: nt.carol.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+	)
+
+	return taskHistoryTest{
+		notificationTest: nt,
+		tx:               tx,
+		taskRepo:         taskRepo,
+		events:           events,
+		attachments:      attachments,
+		service: service.NewTaskService(fakeTransactor{tx: tx}, taskRepo, nt.userRepo, members, newFakeTaskChecklistRepository(), &fakeTaskLinkRepository{tasks: taskRepo}, nt.assignees, newFakeLabelRepository(), service.NewAuthorizationService(nt.userRepo, members, nil), service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil), attachments, service.TaskServiceDeps{
+			History:       events,
+			Notifications: nt.service,
+		}),
+	}
+}
+
+func (ht taskHistoryTest) create(t *testing.T) string {
+	task, err := ht.service.Register(context.Background(), dto.TaskCreateRequest{
+		Title:       "task",
+		Description: "description",
+		Status:      "To Do",
+		DueDate:     "2030-01-01T00:00:00Z",
+		TeamsID:     1,
+		UserID:      &ht.bob.ID,
+	}, ht.alice.ID.String())
+	require.NoError(t, err)
+	return strconv.Itoa(task.ID)
+}
+
+func Test_TaskHistory_WrittenWithTheChange(t *testing.T) {
+	ctx := context.Background()
+	ht := setUpTaskHistoryTest()
+	taskId := ht.create(t)
+	assert.Same(t, ht.tx, ht.events.tx)
+
+	for _, change := range []func() error{
+		func() error {
+			_, err := ht.service.Update(ctx, dto.TaskUpdateRequest{Title: "renamed", Description: "description", Status: "To Do", DueDate: "2030-01-01T00:00:00Z"}, taskId, ht.alice.ID.String())
+			return err
+		},
+		func() error { return ht.service.AssignUserToTask(ctx, taskId, &ht.carol.ID, ht.alice.ID.String()) },
+		func() error { return ht.service.Delete(ctx, taskId, ht.alice.ID.String()) },
+	} {
+		ht.events.tx = nil
+		require.NoError(t, change())
+		assert.Same(t, ht.tx, ht.events.tx)
+	}
+
+	last := ht.events.events[len(ht.events.events)-1]
+	assert.Equal(t, constants.ENUM_TASK_EVENT_DELETED, last.Type)
+	assert.Equal(t, ht.alice.ID, *last.UserID)
+}
+
+func Test_TaskHistory_FailureFailsTheChange(t *testing.T) {
+	ctx := context.Background()
+	ht := setUpTaskHistoryTest()
+	taskId := ht.create(t)
+	notified := len(ht.notifications.notifications)
+	ht.events.err = errors.New("disk full")
+
+	_, err := ht.service.Update(ctx, dto.TaskUpdateRequest{Title: "task", Description: "description", Status: "In Progress", DueDate: "2030-01-01T00:00:00Z"}, taskId, ht.alice.ID.String())
+	assert.ErrorIs(t, err, dto.ErrRecordTaskHistory)
+
+	assert.Error(t, ht.service.AssignUserToTask(ctx, taskId, &ht.carol.ID, ht.alice.ID.String()))
+	assert.ErrorIs(t, ht.service.RemoveAssignee(ctx, taskId, ht.bob.ID.String(), ht.alice.ID.String()), dto.ErrRecordTaskHistory)
+	assert.ErrorIs(t, ht.service.Delete(ctx, taskId, ht.alice.ID.String()), dto.ErrRecordTaskHistory)
+
+	// Nobody hears about a change that was rolled back, and the task keeps
+	// its files.
+	assert.Len(t, ht.notifications.notifications, notified)
+	assert.Empty(t, ht.attachments.deleted)
+}
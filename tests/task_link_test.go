@@ -0,0 +1,294 @@
+package tests
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeTaskLinkRepository struct {
+	repository.TaskLinkRepository
+	tasks  *fakeTaskTreeRepository
+	nextID int
+	links  []entity.TaskLink
+	calls  []string
+}
+
+func (r *fakeTaskLinkRepository) LockLinks(ctx context.Context, tx *gorm.DB, teamId int) error {
+	r.calls = append(r.calls, "lock")
+	return nil
+}
+
+func (r *fakeTaskLinkRepository) CreateLink(ctx context.Context, tx *gorm.DB, link entity.TaskLink) (entity.TaskLink, error) {
+	r.calls = append(r.calls, "create")
+	r.nextID++
+	link.ID = r.nextID
+	r.links = append(r.links, link)
+	return link, nil
+}
+
+func (r *fakeTaskLinkRepository) GetLinkById(ctx context.Context, tx *gorm.DB, taskId int, linkId int) (entity.TaskLink, error) {
+	for _, link := range r.links {
+		if link.ID == linkId && (link.SourceTaskID == taskId || link.TargetTaskID == taskId) {
+			return link, nil
+		}
+	}
+	return entity.TaskLink{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeTaskLinkRepository) GetLinksByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.TaskLink, error) {
+	var links []entity.TaskLink
+	for _, link := range r.links {
+		if link.SourceTaskID == taskId || link.TargetTaskID == taskId {
+			link.SourceTask = r.tasks.tasks[link.SourceTaskID]
+			link.TargetTask = r.tasks.tasks[link.TargetTaskID]
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (r *fakeTaskLinkRepository) LinkExists(ctx context.Context, tx *gorm.DB, sourceTaskId int, targetTaskId int, linkType string) (bool, error) {
+	for _, link := range r.links {
+		if link.SourceTaskID == sourceTaskId && link.TargetTaskID == targetTaskId && link.Type == linkType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeTaskLinkRepository) DeleteLink(ctx context.Context, tx *gorm.DB, linkId int) error {
+	for i, link := range r.links {
+		if link.ID == linkId {
+			r.links = append(r.links[:i], r.links[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *fakeTaskLinkRepository) DeleteLinksByTaskId(ctx context.Context, tx *gorm.DB, taskId int) error {
+	var kept []entity.TaskLink
+	for _, link := range r.links {
+		if link.SourceTaskID != taskId && link.TargetTaskID != taskId {
+			kept = append(kept, link)
+		}
+	}
+	r.links = kept
+	return nil
+}
+
+func (r *fakeTaskLinkRepository) GetBlockedTaskIds(ctx context.Context, tx *gorm.DB, taskIds []int) ([]int, error) {
+	var ids []int
+	for _, link := range r.links {
+		for _, taskId := range taskIds {
+			if link.Type == constants.ENUM_TASK_LINK_BLOCKS && link.SourceTaskID == taskId {
+				ids = append(ids, link.TargetTaskID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeTaskLinkRepository) GetBlockers(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.Task, error) {
+	var tasks []entity.Task
+	for _, link := range r.links {
+		if link.Type == constants.ENUM_TASK_LINK_BLOCKS && link.TargetTaskID == taskId {
+			tasks = append(tasks, r.tasks.tasks[link.SourceTaskID])
+		}
+	}
+	return tasks, nil
+}
+
+type taskLinkTest struct {
+	subtaskTest
+	linkRepo *fakeTaskLinkRepository
+	links    service.TaskLinkService
+}
+
+func setUpTaskLinkTest() taskLinkTest {
+	nt := setUpNotificationTest()
+	taskRepo := newFakeTaskTreeRepository()
+	linkRepo := &fakeTaskLinkRepository{tasks: taskRepo}
+	workflowService := service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil)
+
+	return taskLinkTest{
+		subtaskTest: subtaskTest{
+			taskRepo:      taskRepo,
+			checklistRepo: newFakeTaskChecklistRepository(),
+			service:       service.NewTaskService(fakeTransactor{}, taskRepo, nil, nil, newFakeTaskChecklistRepository(), linkRepo, nt.assignees, newFakeLabelRepository(), memberAuthorization(nt), workflowService, nil, service.TaskServiceDeps{}),
+			actor:         nt.alice.ID.String(),
+		},
+		linkRepo: linkRepo,
+		links:    service.NewTaskLinkService(fakeTransactor{}, linkRepo, taskRepo, workflowService),
+	}
+}
+
+func (lt taskLinkTest) link(from int, linkType string, to int) (dto.TaskLinkResponse, error) {
+	return lt.links.Create(context.Background(), strconv.Itoa(from), lt.actor, dto.TaskLinkCreateRequest{Type: linkType, TaskID: to})
+}
+
+func (lt taskLinkTest) setStatus(taskId int, status string) error {
+	_, err := lt.service.Update(context.Background(), dto.TaskUpdateRequest{
+		Title:       "task",
+		Description: "description",
+		Status:      status,
+		DueDate:     "2030-01-01T00:00:00Z",
+	}, strconv.Itoa(taskId), lt.actor)
+	return err
+}
+
+func Test_TaskLink_InverseTypesAreStoredForward(t *testing.T) {
+	ctx := context.Background()
+	lt := setUpTaskLinkTest()
+
+	a := lt.create(t, 1, "To Do", nil)
+	b := lt.create(t, 1, "Done", nil)
+
+	created, err := lt.link(a.ID, constants.ENUM_TASK_LINK_BLOCKED_BY, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_TASK_LINK_BLOCKED_BY, created.Type)
+	assert.Equal(t, b.ID, created.Task.ID)
+	assert.True(t, created.Task.IsDone)
+
+	require.Len(t, lt.linkRepo.links, 1)
+	assert.Equal(t, b.ID, lt.linkRepo.links[0].SourceTaskID)
+	assert.Equal(t, constants.ENUM_TASK_LINK_BLOCKS, lt.linkRepo.links[0].Type)
+
+	fromB, err := lt.links.GetLinksByTaskId(ctx, strconv.Itoa(b.ID))
+	require.NoError(t, err)
+	require.Len(t, fromB, 1)
+	assert.Equal(t, constants.ENUM_TASK_LINK_BLOCKS, fromB[0].Type)
+	assert.Equal(t, a.ID, fromB[0].Task.ID)
+	assert.False(t, fromB[0].Task.IsDone)
+
+	require.NoError(t, lt.links.Delete(ctx, strconv.Itoa(a.ID), created.ID))
+	assert.Empty(t, lt.linkRepo.links)
+}
+
+func Test_TaskLink_RejectsInvalidLinks(t *testing.T) {
+	lt := setUpTaskLinkTest()
+
+	a := lt.create(t, 1, "To Do", nil)
+	b := lt.create(t, 1, "To Do", nil)
+	other := lt.create(t, 2, "To Do", nil)
+
+	_, err := lt.link(a.ID, constants.ENUM_TASK_LINK_RELATES_TO, a.ID)
+	assert.ErrorIs(t, err, dto.ErrTaskLinkSelf)
+	_, err = lt.link(a.ID, constants.ENUM_TASK_LINK_RELATES_TO, other.ID)
+	assert.ErrorIs(t, err, dto.ErrLinkedTaskOtherTeam)
+	_, err = lt.link(a.ID, constants.ENUM_TASK_LINK_RELATES_TO, 999)
+	assert.ErrorIs(t, err, dto.ErrLinkedTaskNotFound)
+	_, err = lt.link(a.ID, "parent_of", b.ID)
+	assert.ErrorIs(t, err, dto.ErrInvalidTaskLinkType)
+
+	_, err = lt.link(a.ID, constants.ENUM_TASK_LINK_RELATES_TO, b.ID)
+	require.NoError(t, err)
+	_, err = lt.link(b.ID, constants.ENUM_TASK_LINK_RELATES_TO, a.ID)
+	assert.ErrorIs(t, err, dto.ErrTaskLinkExists)
+
+	_, err = lt.link(a.ID, constants.ENUM_TASK_LINK_DUPLICATES, b.ID)
+	require.NoError(t, err)
+	_, err = lt.link(b.ID, constants.ENUM_TASK_LINK_DUPLICATED_BY, a.ID)
+	assert.ErrorIs(t, err, dto.ErrTaskLinkExists)
+}
+
+func Test_TaskLink_RejectsBlockingCycles(t *testing.T) {
+	lt := setUpTaskLinkTest()
+
+	a := lt.create(t, 1, "To Do", nil)
+	b := lt.create(t, 1, "To Do", nil)
+	c := lt.create(t, 1, "To Do", nil)
+
+	_, err := lt.link(a.ID, constants.ENUM_TASK_LINK_BLOCKS, b.ID)
+	require.NoError(t, err)
+	_, err = lt.link(b.ID, constants.ENUM_TASK_LINK_BLOCKS, c.ID)
+	require.NoError(t, err)
+
+	_, err = lt.link(c.ID, constants.ENUM_TASK_LINK_BLOCKS, a.ID)
+	assert.ErrorIs(t, err, dto.ErrTaskLinkCycle)
+	_, err = lt.link(a.ID, constants.ENUM_TASK_LINK_BLOCKED_BY, c.ID)
+	assert.ErrorIs(t, err, dto.ErrTaskLinkCycle)
+
+	// Only blocking links are ordered; others may point back.
+	_, err = lt.link(c.ID, constants.ENUM_TASK_LINK_RELATES_TO, a.ID)
+	assert.NoError(t, err)
+}
+
+func Test_TaskLink_CreatesUnderTeamLock(t *testing.T) {
+	lt := setUpTaskLinkTest()
+
+	a := lt.create(t, 1, "To Do", nil)
+	b := lt.create(t, 1, "To Do", nil)
+
+	_, err := lt.link(a.ID, constants.ENUM_TASK_LINK_BLOCKS, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lock", "create"}, lt.linkRepo.calls)
+
+	lt.linkRepo.calls = nil
+	_, err = lt.link(b.ID, constants.ENUM_TASK_LINK_BLOCKS, a.ID)
+	assert.ErrorIs(t, err, dto.ErrTaskLinkCycle)
+	assert.Equal(t, []string{"lock"}, lt.linkRepo.calls)
+}
+
+func Test_TaskLink_OpenBlockerPreventsDone(t *testing.T) {
+	lt := setUpTaskLinkTest()
+
+	blocker := lt.create(t, 1, "To Do", nil)
+	blocked := lt.create(t, 1, "To Do", nil)
+	_, err := lt.link(blocker.ID, constants.ENUM_TASK_LINK_BLOCKS, blocked.ID)
+	require.NoError(t, err)
+
+	err = lt.setStatus(blocked.ID, "Done")
+	assert.ErrorIs(t, err, dto.ErrTaskBlocked)
+	assert.Contains(t, err.Error(), "#"+strconv.Itoa(blocker.ID))
+	assert.Equal(t, "To Do", lt.taskRepo.tasks[blocked.ID].Status)
+
+	// Moving to a status that is not done is still allowed.
+	require.NoError(t, lt.setStatus(blocked.ID, "In Progress"))
+
+	require.NoError(t, lt.setStatus(blocker.ID, "Done"))
+	require.NoError(t, lt.setStatus(blocked.ID, "Done"))
+}
+
+func Test_TaskLink_BlockersSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql string
+	db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	})
+
+	_, err = repository.NewTaskLinkRepository(db).GetBlockers(context.Background(), nil, 7)
+	require.NoError(t, err)
+	assert.Contains(t, sql, "JOIN task_links ON task_links.source_task_id = tasks.id")
+	assert.Contains(t, sql, "task_links.target_task_id = ? AND task_links.type = ?")
+	assert.Contains(t, sql, "`tasks`.`deleted_at` IS NULL")
+}
+
+func Test_TaskLink_LockLinksSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}))
+
+	require.NoError(t, repository.NewTaskLinkRepository(db).LockLinks(context.Background(), nil, 1))
+
+	assert.Contains(t, sql, "FROM `teams` WHERE id = ?")
+	assert.True(t, strings.HasSuffix(sql, "FOR UPDATE"), sql)
+}
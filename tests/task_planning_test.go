@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func planningCreateRequest(priority string, estimate *int, remaining *int) dto.TaskCreateRequest {
+	return dto.TaskCreateRequest{
+		Title:           "task",
+		Description:     "description",
+		Status:          "To Do",
+		DueDate:         "2030-01-01T00:00:00Z",
+		TeamsID:         1,
+		Priority:        priority,
+		Estimate:        estimate,
+		RemainingEffort: remaining,
+	}
+}
+
+func planningUpdateRequest(priority string, estimate *int, remaining *int) dto.TaskUpdateRequest {
+	return dto.TaskUpdateRequest{
+		Title:           "task",
+		Description:     "description",
+		Status:          "To Do",
+		DueDate:         "2030-01-01T00:00:00Z",
+		Priority:        priority,
+		Estimate:        estimate,
+		RemainingEffort: remaining,
+	}
+}
+
+func effort(value int) *int {
+	return &value
+}
+
+func Test_TaskPlanning_CreateDefaults(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSubtaskTest()
+
+	task, err := st.service.Register(ctx, planningCreateRequest("", nil, nil), st.actor)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_TASK_PRIORITY_MEDIUM, task.Priority)
+	assert.Equal(t, 2, task.PriorityRank)
+	assert.Zero(t, task.Estimate)
+	assert.Zero(t, task.RemainingEffort)
+
+	task, err = st.service.Register(ctx, planningCreateRequest("Urgent", effort(5), nil), st.actor)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_TASK_PRIORITY_URGENT, task.Priority)
+	assert.Equal(t, 4, task.PriorityRank)
+	assert.Equal(t, 5, task.Estimate)
+	assert.Equal(t, 5, task.RemainingEffort)
+
+	_, err = st.service.Register(ctx, planningCreateRequest("critical", nil, nil), st.actor)
+	assert.ErrorIs(t, err, dto.ErrInvalidTaskPriority)
+	_, err = st.service.Register(ctx, planningCreateRequest("", effort(3), effort(-1)), st.actor)
+	assert.ErrorIs(t, err, dto.ErrInvalidTaskEstimate)
+}
+
+func Test_TaskPlanning_UpdateKeepsOmittedFields(t *testing.T) {
+	ctx := context.Background()
+	st := setUpSubtaskTest()
+
+	created, err := st.service.Register(ctx, planningCreateRequest(constants.ENUM_TASK_PRIORITY_HIGH, effort(8), effort(6)), st.actor)
+	require.NoError(t, err)
+	taskId := strconv.Itoa(created.ID)
+
+	updated, err := st.service.Update(ctx, planningUpdateRequest("", nil, nil), taskId, st.actor)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_TASK_PRIORITY_HIGH, updated.Priority)
+	assert.Equal(t, 8, updated.Estimate)
+	assert.Equal(t, 6, updated.RemainingEffort)
+
+	// Clearing the remaining effort must reach the repository.
+	updated, err = st.service.Update(ctx, planningUpdateRequest(constants.ENUM_TASK_PRIORITY_LOW, nil, effort(0)), taskId, st.actor)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_TASK_PRIORITY_LOW, updated.Priority)
+	assert.Zero(t, updated.RemainingEffort)
+
+	task, err := st.service.GetTaskById(ctx, taskId)
+	require.NoError(t, err)
+	assert.Equal(t, 1, task.PriorityRank)
+	assert.Equal(t, 8, task.Estimate)
+	assert.Zero(t, task.RemainingEffort)
+
+	_, err = st.service.Update(ctx, planningUpdateRequest("none", nil, nil), taskId, st.actor)
+	assert.ErrorIs(t, err, dto.ErrInvalidTaskPriority)
+}
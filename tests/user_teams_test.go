@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/migrations"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeUserTeamsRepository struct {
+	members map[uuid.UUID]entity.UserTeams
+}
+
+func newFakeUserTeamsRepository(members ...entity.UserTeams) *fakeUserTeamsRepository {
+	r := &fakeUserTeamsRepository{members: map[uuid.UUID]entity.UserTeams{}}
+	for _, m := range members {
+		r.members[m.UserID] = m
+	}
+	return r
+}
+
+func (r *fakeUserTeamsRepository) AssignUserToTeam(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint, role string) error {
+	r.members[userId] = entity.UserTeams{UserID: userId, TeamID: teamId, Role: role}
+	return nil
+}
+
+func (r *fakeUserTeamsRepository) RemoveUserFromTeam(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint) error {
+	delete(r.members, userId)
+	return nil
+}
+
+func (r *fakeUserTeamsRepository) GetUsersByTeamId(ctx context.Context, tx *gorm.DB, teamId uint) ([]entity.User, error) {
+	var users []entity.User
+	for id := range r.members {
+		users = append(users, entity.User{ID: id})
+	}
+	return users, nil
+}
+
+func (r *fakeUserTeamsRepository) GetMembership(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint) (entity.UserTeams, error) {
+	m, ok := r.members[userId]
+	if !ok {
+		return entity.UserTeams{}, gorm.ErrRecordNotFound
+	}
+	return m, nil
+}
+
+func (r *fakeUserTeamsRepository) UpdateRole(ctx context.Context, tx *gorm.DB, userId uuid.UUID, teamId uint, role string) error {
+	m := r.members[userId]
+	m.Role = role
+	r.members[userId] = m
+	return nil
+}
+
+func (r *fakeUserTeamsRepository) CountByRoleForUpdate(ctx context.Context, tx *gorm.DB, teamId uint, role string) (int64, error) {
+	var count int64
+	for _, m := range r.members {
+		if m.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func Test_ChangeRole_LastOwner(t *testing.T) {
+	owner := uuid.New()
+	member := uuid.New()
+	repo := newFakeUserTeamsRepository(
+		entity.UserTeams{UserID: owner, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_OWNER},
+		entity.UserTeams{UserID: member, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+	)
+	userTeamsService := service.NewUserTeamsService(fakeTransactor{}, repo, nil, nil)
+
+	err := userTeamsService.ChangeRole(owner, 1, constants.ENUM_TEAM_ROLE_MEMBER)
+	assert.ErrorIs(t, err, dto.ErrLastTeamOwner)
+
+	assert.NoError(t, userTeamsService.ChangeRole(member, 1, constants.ENUM_TEAM_ROLE_OWNER))
+	assert.NoError(t, userTeamsService.ChangeRole(owner, 1, constants.ENUM_TEAM_ROLE_MAINTAINER))
+	assert.Equal(t, constants.ENUM_TEAM_ROLE_MAINTAINER, repo.members[owner].Role)
+}
+
+func Test_ChangeRole_NotMember(t *testing.T) {
+	userTeamsService := service.NewUserTeamsService(fakeTransactor{}, newFakeUserTeamsRepository(), nil, nil)
+
+	err := userTeamsService.ChangeRole(uuid.New(), 1, constants.ENUM_TEAM_ROLE_VIEWER)
+	assert.ErrorIs(t, err, dto.ErrMembershipNotFound)
+}
+
+func Test_UserTeams_OwnerCheckLocksOwners(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}))
+
+	_, err = repository.NewUserTeamsRepository(db).CountByRoleForUpdate(context.Background(), nil, 1, constants.ENUM_TEAM_ROLE_OWNER)
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, "WHERE team_id = ? AND role = ?")
+	assert.True(t, strings.HasSuffix(sql, "FOR UPDATE"), sql)
+}
+
+func Test_UserTeams_OwnerBackfillSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var statements []string
+	require.NoError(t, db.Callback().Raw().After("gorm:raw").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+
+	require.NoError(t, migrations.Backfill(db))
+
+	require.Len(t, statements, 3)
+	assert.Contains(t, statements[1], "UPDATE user_teams SET role = ?")
+	assert.Contains(t, statements[1], "NOT EXISTS (SELECT 1 FROM user_teams o WHERE o.team_id = ut.team_id AND o.role = ?)")
+	assert.Contains(t, statements[1], "e.created_at < ut.created_at")
+}
+
+type teamLeaveTest struct {
+	notificationTest
+	members   *fakeUserTeamsRepository
+	taskRepo  *fakeTaskTreeRepository
+	tasks     service.TaskService
+	userTeams service.UserTeamsService
+}
+
+// setUpTeamLeaveTest puts alice, bob and carol in team 1, with alice as a
+// maintainer, and gives bob a task.
+func setUpTeamLeaveTest(t *testing.T) (teamLeaveTest, int) {
+	nt := setUpNotificationTest()
+	members := newFakeUserTeamsRepository(
+		entity.UserTeams{UserID: nt.alice.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MAINTAINER},
+		entity.UserTeams{UserID: nt.bob.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+		entity.UserTeams{UserID: nt.carol.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+	)
+	taskRepo := newFakeTaskTreeRepository()
+	auth := service.NewAuthorizationService(nt.userRepo, members, taskRepo)
+	tasks := service.NewTaskService(fakeTransactor{}, taskRepo, nt.userRepo, members, newFakeTaskChecklistRepository(), nil, nt.assignees, newFakeLabelRepository(), auth, service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil), nil, service.TaskServiceDeps{
+		History:       &fakeTaskEventRepository{},
+		Notifications: nt.service,
+	})
+
+	task, err := tasks.Register(context.Background(), dto.TaskCreateRequest{
+		Title:       "task",
+		Description: "description",
+		Status:      "To Do",
+		DueDate:     "2030-01-01T00:00:00Z",
+		TeamsID:     1,
+		UserID:      &nt.bob.ID,
+	}, nt.alice.ID.String())
+	require.NoError(t, err)
+
+	return teamLeaveTest{
+		notificationTest: nt,
+		members:          members,
+		taskRepo:         taskRepo,
+		tasks:            tasks,
+		userTeams:        service.NewUserTeamsService(fakeTransactor{}, members, tasks, auth),
+	}, task.ID
+}
+
+func Test_Task_NonMemberCannotBeAssigned(t *testing.T) {
+	ctx := context.Background()
+	lt, taskId := setUpTeamLeaveTest(t)
+	outsider := uuid.New()
+
+	err := lt.tasks.AssignUserToTask(ctx, strconv.Itoa(taskId), &outsider, lt.alice.ID.String())
+	assert.ErrorIs(t, err, dto.ErrAssigneeNotTeamMember)
+
+	_, err = lt.tasks.Update(ctx, dto.TaskUpdateRequest{
+		Title:       "task",
+		Description: "description",
+		Status:      "To Do",
+		DueDate:     "2030-01-01T00:00:00Z",
+		UserID:      &outsider,
+	}, strconv.Itoa(taskId), lt.alice.ID.String())
+	assert.ErrorIs(t, err, dto.ErrAssigneeNotTeamMember)
+	assert.Equal(t, lt.bob.ID, *lt.taskRepo.tasks[taskId].UserID)
+}
+
+func Test_RemoveUserFromTeam_ReassignsTasks(t *testing.T) {
+	lt, taskId := setUpTeamLeaveTest(t)
+	outsider := uuid.New()
+
+	// Handing tasks to someone outside the team keeps the user in it.
+	err := lt.userTeams.RemoveUserFromTeam(lt.bob.ID, 1, lt.alice.ID.String(), &outsider)
+	assert.ErrorIs(t, err, dto.ErrAssigneeNotTeamMember)
+	assert.Contains(t, lt.members.members, lt.bob.ID)
+	assert.Equal(t, lt.bob.ID, *lt.taskRepo.tasks[taskId].UserID)
+
+	require.NoError(t, lt.userTeams.RemoveUserFromTeam(lt.bob.ID, 1, lt.alice.ID.String(), &lt.carol.ID))
+	assert.NotContains(t, lt.members.members, lt.bob.ID)
+	assert.Equal(t, lt.carol.ID, *lt.taskRepo.tasks[taskId].UserID)
+
+	assignees, err := lt.tasks.GetAssignees(context.Background(), strconv.Itoa(taskId))
+	require.NoError(t, err)
+	require.Len(t, assignees, 1)
+	assert.Equal(t, lt.carol.ID.String(), assignees[0].User.ID)
+
+	told := map[uuid.UUID]string{}
+	for _, notification := range lt.notifications.notifications {
+		told[notification.UserID] = notification.Type
+	}
+	assert.Equal(t, constants.ENUM_NOTIFICATION_TASK_ASSIGNED, told[lt.carol.ID])
+	assert.Equal(t, constants.ENUM_NOTIFICATION_TASK_UNASSIGNED, told[lt.bob.ID])
+}
@@ -6,10 +6,13 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Caknoooo/go-gin-clean-starter/config"
 	"github.com/Caknoooo/go-gin-clean-starter/controller"
 	"github.com/Caknoooo/go-gin-clean-starter/entity"
 	"github.com/Caknoooo/go-gin-clean-starter/repository"
 	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/Caknoooo/go-gin-clean-starter/storage"
+	"github.com/Caknoooo/go-gin-clean-starter/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -23,8 +26,11 @@ func SetupControllerUser() controller.UserController {
 	var (
 		db             = SetUpDatabaseConnection()
 		userRepo       = repository.NewUserRepository(db)
-		jwtService     = service.NewJWTService()
-		userService    = service.NewUserService(userRepo, jwtService)
+		sessionRepo    = repository.NewSessionRepository(db)
+		keyService     = service.NewSigningKeyService(config.JWTConfig{Algorithm: config.JWT_ALGORITHM_HS256, Secret: "test"}, nil)
+		jwtService     = service.NewJWTService(keyService, "Template", sessionRepo)
+		emailService   = service.NewEmailService(repository.NewEmailOutboxRepository(db), utils.NewSMTPMailer(config.EmailConfig{}))
+		userService    = service.NewUserService(userRepo, jwtService, storage.NewLocalStorage("assets", "/assets", []byte("secret")), sessionRepo, emailService)
 		userController = controller.NewUserController(userService)
 	)
 
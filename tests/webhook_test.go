@@ -0,0 +1,544 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/config"
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeWebhookRepository struct {
+	mu         sync.Mutex
+	nextID     int
+	webhooks   map[int]entity.Webhook
+	deliveries []entity.WebhookDelivery
+}
+
+func newFakeWebhookRepository() *fakeWebhookRepository {
+	return &fakeWebhookRepository{webhooks: map[int]entity.Webhook{}}
+}
+
+func (r *fakeWebhookRepository) CreateWebhook(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	webhook.ID = r.nextID
+	webhook.CreatedAt = time.Now()
+	r.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (r *fakeWebhookRepository) GetWebhooksByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var webhooks []entity.Webhook
+	for _, webhook := range r.webhooks {
+		if webhook.TeamsID == teamsID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
+}
+
+func (r *fakeWebhookRepository) GetWebhookById(ctx context.Context, tx *gorm.DB, teamsID int, webhookId int) (entity.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	webhook, ok := r.webhooks[webhookId]
+	if !ok || webhook.TeamsID != teamsID {
+		return entity.Webhook{}, gorm.ErrRecordNotFound
+	}
+	return webhook, nil
+}
+
+func (r *fakeWebhookRepository) UpdateWebhook(ctx context.Context, tx *gorm.DB, webhook entity.Webhook) (entity.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (r *fakeWebhookRepository) DeleteWebhook(ctx context.Context, tx *gorm.DB, teamsID int, webhookId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.webhooks, webhookId)
+	return nil
+}
+
+func (r *fakeWebhookRepository) CreateDeliveries(ctx context.Context, tx *gorm.DB, deliveries []entity.WebhookDelivery) ([]entity.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range deliveries {
+		deliveries[i].ID = len(r.deliveries) + 1
+		deliveries[i].CreatedAt = time.Now()
+		r.deliveries = append(r.deliveries, deliveries[i])
+	}
+	return deliveries, nil
+}
+
+func (r *fakeWebhookRepository) GetDeliveriesWithPagination(ctx context.Context, tx *gorm.DB, webhookId int, req dto.PaginationRequest) (dto.GetAllWebhookDeliveryRepositoryResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deliveries []entity.WebhookDelivery
+	for i := len(r.deliveries) - 1; i >= 0; i-- {
+		if r.deliveries[i].WebhookID == webhookId {
+			deliveries = append(deliveries, r.deliveries[i])
+		}
+	}
+	return dto.GetAllWebhookDeliveryRepositoryResponse{
+		Deliveries:         deliveries,
+		PaginationResponse: dto.PaginationResponse{Page: 1, PerPage: 20, Count: int64(len(deliveries)), MaxPage: 1},
+	}, nil
+}
+
+func (r *fakeWebhookRepository) GetDeliveryById(ctx context.Context, tx *gorm.DB, webhookId int, deliveryId int) (entity.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if deliveryId < 1 || deliveryId > len(r.deliveries) || r.deliveries[deliveryId-1].WebhookID != webhookId {
+		return entity.WebhookDelivery{}, gorm.ErrRecordNotFound
+	}
+	return r.deliveries[deliveryId-1], nil
+}
+
+func (r *fakeWebhookRepository) ClaimDueDeliveries(ctx context.Context, tx *gorm.DB, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []entity.WebhookDelivery
+	for i := range r.deliveries {
+		delivery := &r.deliveries[i]
+		if len(claimed) == limit || delivery.Status != constants.ENUM_WEBHOOK_DELIVERY_PENDING || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		if delivery.LockedUntil != nil && delivery.LockedUntil.After(now) {
+			continue
+		}
+		lockedUntil := now.Add(lease)
+		delivery.LockedUntil = &lockedUntil
+		claim := *delivery
+		claim.Webhook = r.webhooks[delivery.WebhookID]
+		claimed = append(claimed, claim)
+	}
+	return claimed, nil
+}
+
+func (r *fakeWebhookRepository) UpdateDelivery(ctx context.Context, tx *gorm.DB, delivery entity.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery.LockedUntil = nil
+	delivery.Webhook = entity.Webhook{}
+	r.deliveries[delivery.ID-1] = delivery
+	return nil
+}
+
+func (r *fakeWebhookRepository) get(id int) entity.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.deliveries[id-1]
+}
+
+// webhookReceiver is a local HTTP endpoint that records what it was sent and
+// answers with status.
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func startWebhookReceiver(t *testing.T) *webhookReceiver {
+	receiver := &webhookReceiver{status: http.StatusOK}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, receivedWebhook{header: r.Header.Clone(), body: body})
+		w.WriteHeader(receiver.status)
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (r *webhookReceiver) respondWith(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+const webhookTestSecret = "0123456789abcdef0123"
+
+// webhookTestConfig lets deliveries reach the local receivers and skips
+// resolving the example hosts.
+var webhookTestConfig = config.WebhookConfig{AllowPrivateNetworks: true}
+
+func Test_Webhook_DeliversSignedPayload(t *testing.T) {
+	ctx := context.Background()
+	receiver := startWebhookReceiver(t)
+	webhookRepo := newFakeWebhookRepository()
+	webhookService := service.NewWebhookService(webhookRepo, webhookTestConfig)
+
+	created, err := webhookService.CreateWebhook(ctx, 1, dto.WebhookCreateRequest{URL: receiver.URL, Secret: webhookTestSecret})
+	require.NoError(t, err)
+	assert.Equal(t, webhookTestSecret, created.Secret)
+	assert.Empty(t, created.Events)
+
+	webhookService.Dispatch(ctx, 1, constants.ENUM_WEBHOOK_EVENT_TEAM_UPDATED, nil, dto.WebhookTeamData{Team: dto.WebhookTeam{ID: 1, Name: "Core"}})
+
+	claimed, err := webhookService.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+
+	requests := receiver.received()
+	require.Len(t, requests, 1)
+	request := requests[0]
+	assert.Equal(t, constants.ENUM_WEBHOOK_EVENT_TEAM_UPDATED, request.header.Get(service.WEBHOOK_HEADER_EVENT))
+	assert.Equal(t, "1", request.header.Get(service.WEBHOOK_HEADER_DELIVERY))
+	timestamp := request.header.Get(service.WEBHOOK_HEADER_TIMESTAMP)
+	assert.Equal(t, service.SignWebhookPayload(webhookTestSecret, timestamp, request.body), request.header.Get(service.WEBHOOK_HEADER_SIGNATURE))
+	assert.NotEqual(t, service.SignWebhookPayload("another secret", timestamp, request.body), request.header.Get(service.WEBHOOK_HEADER_SIGNATURE))
+
+	var payload struct {
+		Event  string              `json:"event"`
+		TeamID int                 `json:"team_id"`
+		Data   dto.WebhookTeamData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(request.body, &payload))
+	assert.Equal(t, constants.ENUM_WEBHOOK_EVENT_TEAM_UPDATED, payload.Event)
+	assert.Equal(t, 1, payload.TeamID)
+	assert.Equal(t, "Core", payload.Data.Team.Name)
+
+	delivery := webhookRepo.get(1)
+	assert.Equal(t, constants.ENUM_WEBHOOK_DELIVERY_DELIVERED, delivery.Status)
+	assert.Equal(t, http.StatusOK, delivery.ResponseStatus)
+	assert.NotNil(t, delivery.DeliveredAt)
+}
+
+func Test_Webhook_EventFilterAndInactive(t *testing.T) {
+	ctx := context.Background()
+	receiver := startWebhookReceiver(t)
+	webhookRepo := newFakeWebhookRepository()
+	webhookService := service.NewWebhookService(webhookRepo, webhookTestConfig)
+
+	_, err := webhookService.CreateWebhook(ctx, 1, dto.WebhookCreateRequest{URL: receiver.URL, Events: []string{constants.ENUM_WEBHOOK_EVENT_TASK_CREATED}})
+	require.NoError(t, err)
+	disabled, err := webhookService.CreateWebhook(ctx, 1, dto.WebhookCreateRequest{URL: receiver.URL})
+	require.NoError(t, err)
+	inactive := false
+	_, err = webhookService.UpdateWebhook(ctx, 1, disabled.ID, dto.WebhookUpdateRequest{IsActive: &inactive})
+	require.NoError(t, err)
+	_, err = webhookService.CreateWebhook(ctx, 2, dto.WebhookCreateRequest{URL: receiver.URL})
+	require.NoError(t, err)
+
+	webhookService.Dispatch(ctx, 1, constants.ENUM_WEBHOOK_EVENT_TASK_DELETED, nil, nil)
+	webhookService.Dispatch(ctx, 1, constants.ENUM_WEBHOOK_EVENT_TASK_CREATED, nil, nil)
+
+	require.Len(t, webhookRepo.deliveries, 1)
+	assert.Equal(t, 1, webhookRepo.deliveries[0].WebhookID)
+	assert.Equal(t, constants.ENUM_WEBHOOK_EVENT_TASK_CREATED, webhookRepo.deliveries[0].Event)
+}
+
+func Test_Webhook_RetryAndRedeliver(t *testing.T) {
+	ctx := context.Background()
+	receiver := startWebhookReceiver(t)
+	receiver.respondWith(http.StatusInternalServerError)
+	webhookRepo := newFakeWebhookRepository()
+	webhookService := service.NewWebhookService(webhookRepo, webhookTestConfig)
+
+	webhook, err := webhookService.CreateWebhook(ctx, 1, dto.WebhookCreateRequest{URL: receiver.URL})
+	require.NoError(t, err)
+	assert.Len(t, webhook.Secret, 64)
+
+	webhookService.Dispatch(ctx, 1, constants.ENUM_WEBHOOK_EVENT_TASK_UPDATED, nil, nil)
+
+	before := time.Now()
+	_, err = webhookService.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+
+	failed := webhookRepo.get(1)
+	assert.Equal(t, constants.ENUM_WEBHOOK_DELIVERY_PENDING, failed.Status)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, failed.ResponseStatus)
+	assert.Contains(t, failed.LastError, "500")
+	assert.False(t, failed.NextAttemptAt.Before(before.Add(service.WEBHOOK_RETRY_BASE)))
+
+	// Not due yet, so nothing is claimed.
+	claimed, err := webhookService.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, claimed)
+
+	failed.Attempts = service.WEBHOOK_MAX_ATTEMPTS - 1
+	failed.NextAttemptAt = time.Now().Add(-time.Second)
+	require.NoError(t, webhookRepo.UpdateDelivery(ctx, nil, failed))
+
+	_, err = webhookService.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_WEBHOOK_DELIVERY_FAILED, webhookRepo.get(1).Status)
+
+	// Once the receiver is fixed, the failed delivery can be sent again.
+	receiver.respondWith(http.StatusNoContent)
+	redelivery, err := webhookService.Redeliver(ctx, 1, webhook.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, redelivery.ID)
+
+	_, err = webhookService.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_WEBHOOK_DELIVERY_DELIVERED, webhookRepo.get(2).Status)
+	assert.Equal(t, constants.ENUM_WEBHOOK_DELIVERY_FAILED, webhookRepo.get(1).Status)
+
+	requests := receiver.received()
+	require.Len(t, requests, 3)
+	assert.Equal(t, requests[0].body, requests[2].body)
+	assert.Equal(t, "2", requests[2].header.Get(service.WEBHOOK_HEADER_DELIVERY))
+
+	log, err := webhookService.GetDeliveries(ctx, 1, webhook.ID, dto.PaginationRequest{})
+	require.NoError(t, err)
+	assert.Len(t, log.Data, 2)
+
+	// Deliveries of another team's webhook stay out of reach.
+	_, err = webhookService.Redeliver(ctx, 2, webhook.ID, 1)
+	assert.ErrorIs(t, err, dto.ErrWebhookNotFound)
+	_, err = webhookService.Redeliver(ctx, 1, webhook.ID, 42)
+	assert.ErrorIs(t, err, dto.ErrWebhookDeliveryNotFound)
+}
+
+func Test_Webhook_DeletedBeforeDelivery(t *testing.T) {
+	ctx := context.Background()
+	receiver := startWebhookReceiver(t)
+	webhookRepo := newFakeWebhookRepository()
+	webhookService := service.NewWebhookService(webhookRepo, webhookTestConfig)
+
+	webhook, err := webhookService.CreateWebhook(ctx, 1, dto.WebhookCreateRequest{URL: receiver.URL})
+	require.NoError(t, err)
+	webhookService.Dispatch(ctx, 1, constants.ENUM_WEBHOOK_EVENT_TASK_UPDATED, nil, nil)
+	require.NoError(t, webhookService.DeleteWebhook(ctx, 1, webhook.ID))
+
+	_, err = webhookService.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, constants.ENUM_WEBHOOK_DELIVERY_FAILED, webhookRepo.get(1).Status)
+	assert.Empty(t, receiver.received())
+}
+
+func Test_Webhook_RejectsNonHTTPURL(t *testing.T) {
+	webhookService := service.NewWebhookService(newFakeWebhookRepository(), webhookTestConfig)
+
+	_, err := webhookService.CreateWebhook(context.Background(), 1, dto.WebhookCreateRequest{URL: "ftp://example.com/hook"})
+	assert.ErrorIs(t, err, dto.ErrInvalidWebhookURL)
+}
+
+func Test_Webhook_RejectsInternalAddresses(t *testing.T) {
+	webhookService := service.NewWebhookService(newFakeWebhookRepository(), config.WebhookConfig{})
+
+	for _, raw := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8080/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	} {
+		_, err := webhookService.CreateWebhook(context.Background(), 1, dto.WebhookCreateRequest{URL: raw})
+		assert.ErrorIs(t, err, dto.ErrWebhookAddressBlocked, raw)
+	}
+}
+
+func Test_Webhook_RejectsReservedRanges(t *testing.T) {
+	webhookService := service.NewWebhookService(newFakeWebhookRepository(), config.WebhookConfig{})
+
+	for _, tc := range []struct {
+		network  string
+		blocked  string
+		boundary string
+	}{
+		{network: "0.0.0.0/8", blocked: "http://0.1.2.3/hook", boundary: "http://1.0.0.1/hook"},
+		{network: "100.64.0.0/10", blocked: "http://100.100.100.200/hook", boundary: "http://100.128.0.1/hook"},
+		{network: "198.18.0.0/15", blocked: "http://198.19.255.254/hook", boundary: "http://198.20.0.1/hook"},
+		{network: "64:ff9b::/96", blocked: "http://[64:ff9b::a9fe:a9fe]/hook", boundary: "http://[64:ff9b::1:a9fe:a9fe]/hook"},
+	} {
+		t.Run(tc.network, func(t *testing.T) {
+			_, err := webhookService.CreateWebhook(context.Background(), 1, dto.WebhookCreateRequest{URL: tc.blocked})
+			assert.ErrorIs(t, err, dto.ErrWebhookAddressBlocked)
+
+			_, err = webhookService.CreateWebhook(context.Background(), 1, dto.WebhookCreateRequest{URL: tc.boundary})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_Webhook_DeliveryRefusesInternalAddress(t *testing.T) {
+	ctx := context.Background()
+	receiver := startWebhookReceiver(t)
+	webhookRepo := newFakeWebhookRepository()
+
+	// The webhook was saved while its host resolved to a public address and
+	// now points at the local receiver, as after a DNS rebind.
+	_, err := service.NewWebhookService(webhookRepo, webhookTestConfig).CreateWebhook(ctx, 1, dto.WebhookCreateRequest{URL: receiver.URL})
+	require.NoError(t, err)
+
+	webhookService := service.NewWebhookService(webhookRepo, config.WebhookConfig{})
+	webhookService.Dispatch(ctx, 1, constants.ENUM_WEBHOOK_EVENT_TEAM_UPDATED, nil, nil)
+	_, err = webhookService.ProcessDeliveries(ctx)
+	require.NoError(t, err)
+
+	assert.Empty(t, receiver.received())
+	delivery := webhookRepo.get(1)
+	assert.Equal(t, constants.ENUM_WEBHOOK_DELIVERY_PENDING, delivery.Status)
+	assert.Equal(t, 0, delivery.ResponseStatus)
+	assert.Equal(t, dto.ErrWebhookAddressBlocked.Error(), delivery.LastError)
+}
+
+func Test_Webhook_RetryDelay(t *testing.T) {
+	assert.Equal(t, service.WEBHOOK_RETRY_BASE, service.WebhookRetryDelay(1))
+	assert.Equal(t, 2*service.WEBHOOK_RETRY_BASE, service.WebhookRetryDelay(2))
+	assert.Equal(t, service.WEBHOOK_RETRY_MAX, service.WebhookRetryDelay(20))
+}
+
+type fakeWebhookTeamRepository struct {
+	repository.TeamRepository
+	team entity.Team
+}
+
+func (r *fakeWebhookTeamRepository) GetTeamById(ctx context.Context, tx *gorm.DB, teamId string) (entity.Team, error) {
+	if teamId != strconv.Itoa(r.team.ID) {
+		return entity.Team{}, gorm.ErrRecordNotFound
+	}
+	return r.team, nil
+}
+
+func (r *fakeWebhookTeamRepository) UpdateTeam(ctx context.Context, tx *gorm.DB, team entity.Team) (entity.Team, error) {
+	return team, nil
+}
+
+func Test_Webhook_TeamUpdateIsDispatched(t *testing.T) {
+	ctx := context.Background()
+	webhookRepo := newFakeWebhookRepository()
+	webhookService := service.NewWebhookService(webhookRepo, webhookTestConfig)
+	teamRepo := &fakeWebhookTeamRepository{team: entity.Team{ID: 3, Name: "Core", Description: "Platform team"}}
+	teamService := service.NewTeamService(nil, teamRepo, nil, nil, nil, nil, webhookService)
+
+	_, err := webhookService.CreateWebhook(ctx, 3, dto.WebhookCreateRequest{URL: "https://hooks.example.com/core"})
+	require.NoError(t, err)
+
+	_, err = teamService.Update(ctx, dto.TeamUpdateRequest{Name: "Platform"}, "3")
+	require.NoError(t, err)
+
+	require.Len(t, webhookRepo.deliveries, 1)
+	var payload struct {
+		Data dto.WebhookTeamData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(webhookRepo.deliveries[0].Payload), &payload))
+	assert.Equal(t, "Platform", payload.Data.Team.Name)
+	assert.Equal(t, "Platform team", payload.Data.Team.Description)
+}
+
+type fakeWebhookTaskRepository struct {
+	repository.TaskRepository
+	task entity.Task
+}
+
+func (r *fakeWebhookTaskRepository) GetTaskById(ctx context.Context, tx *gorm.DB, taskId string) (entity.Task, error) {
+	return r.task, nil
+}
+
+func (r *fakeWebhookTaskRepository) RemoveUserFromTask(ctx context.Context, tx *gorm.DB, taskId string) error {
+	r.task.UserID = nil
+	return nil
+}
+
+// fakeTaskEventRepository keeps the events it is given, or fails with err.
+// tx is the transaction of the last write.
+type fakeTaskEventRepository struct {
+	repository.TaskEventRepository
+	events []entity.TaskEvent
+	tx     *gorm.DB
+	err    error
+}
+
+func (r *fakeTaskEventRepository) CreateEvents(ctx context.Context, tx *gorm.DB, events []entity.TaskEvent) error {
+	r.tx = tx
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func Test_Webhook_TaskUnassignIsDispatched(t *testing.T) {
+	ctx := context.Background()
+	nt := setUpNotificationTest()
+	webhookRepo := newFakeWebhookRepository()
+	webhookService := service.NewWebhookService(webhookRepo, webhookTestConfig)
+
+	task := nt.task
+	task.TeamsID = 5
+	nt.assignees.assignees = append(nt.assignees.assignees, entity.TaskAssignee{TaskID: task.ID, UserID: nt.bob.ID, Role: constants.ENUM_TASK_ROLE_ASSIGNEE})
+	taskRepo := &fakeWebhookTaskRepository{task: task}
+	taskService := service.NewTaskService(fakeTransactor{}, taskRepo, nil, nil, nil, nil, nt.assignees, newFakeLabelRepository(), nil, nil, nil, service.TaskServiceDeps{
+		Webhooks: webhookService,
+	})
+
+	_, err := webhookService.CreateWebhook(ctx, 5, dto.WebhookCreateRequest{URL: "https://hooks.example.com/tasks"})
+	require.NoError(t, err)
+
+	require.NoError(t, taskService.RemoveUserFromTask(ctx, strconv.Itoa(task.ID), nt.alice.ID.String()))
+
+	require.Len(t, webhookRepo.deliveries, 1)
+	assert.Equal(t, constants.ENUM_WEBHOOK_EVENT_TASK_UNASSIGNED, webhookRepo.deliveries[0].Event)
+
+	var payload struct {
+		ActorID *uuid.UUID          `json:"actor_id"`
+		Data    dto.WebhookTaskData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(webhookRepo.deliveries[0].Payload), &payload))
+	assert.Equal(t, nt.alice.ID, *payload.ActorID)
+	assert.Equal(t, task.ID, payload.Data.Task.ID)
+	assert.Nil(t, payload.Data.Task.UserID)
+	require.Len(t, payload.Data.Changes, 1)
+	assert.Equal(t, nt.bob.ID.String(), payload.Data.Changes[0].OldValue)
+}
+
+func Test_Webhook_ClaimDueDeliveriesSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	var sql string
+	db.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	})
+
+	_, err = repository.NewWebhookRepository(db).ClaimDueDeliveries(context.Background(), nil, time.Now(), time.Minute, 5)
+	require.NoError(t, err)
+	assert.Contains(t, sql, "UPDATE `webhook_deliveries` SET")
+	assert.Contains(t, sql, "ORDER BY next_attempt_at ASC LIMIT ?")
+}
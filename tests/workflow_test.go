@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeWorkflowRepository struct {
+	repository.WorkflowRepository
+	statuses    []entity.WorkflowStatus
+	transitions []entity.WorkflowTransition
+}
+
+func (r *fakeWorkflowRepository) GetStatusesByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.WorkflowStatus, error) {
+	return r.statuses, nil
+}
+
+func (r *fakeWorkflowRepository) GetTransitionsByTeamID(ctx context.Context, tx *gorm.DB, teamsID int) ([]entity.WorkflowTransition, error) {
+	return r.transitions, nil
+}
+
+func (r *fakeWorkflowRepository) GetStatusById(ctx context.Context, tx *gorm.DB, teamsID int, statusId int) (entity.WorkflowStatus, error) {
+	for _, status := range r.statuses {
+		if status.ID == statusId {
+			return status, nil
+		}
+	}
+	return entity.WorkflowStatus{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeWorkflowRepository) CreateStatus(ctx context.Context, tx *gorm.DB, status entity.WorkflowStatus) (entity.WorkflowStatus, error) {
+	status.ID = len(r.statuses) + 1
+	r.statuses = append(r.statuses, status)
+	return status, nil
+}
+
+func (r *fakeWorkflowRepository) UpdateStatus(ctx context.Context, tx *gorm.DB, status entity.WorkflowStatus) (entity.WorkflowStatus, error) {
+	for i := range r.statuses {
+		if r.statuses[i].ID == status.ID {
+			r.statuses[i] = status
+		}
+	}
+	return status, nil
+}
+
+func (r *fakeWorkflowRepository) CreateTransition(ctx context.Context, tx *gorm.DB, transition entity.WorkflowTransition) (entity.WorkflowTransition, error) {
+	transition.ID = len(r.transitions) + 1
+	r.transitions = append(r.transitions, transition)
+	return transition, nil
+}
+
+// fakeStatusTaskRepository remembers the transaction statuses were renamed
+// in, or fails the rename with err.
+type fakeStatusTaskRepository struct {
+	repository.TaskRepository
+	renamed map[string]string
+	tx      *gorm.DB
+	err     error
+}
+
+func (r *fakeStatusTaskRepository) RenameStatus(ctx context.Context, tx *gorm.DB, teamsID int, from string, to string) error {
+	r.tx = tx
+	if r.err != nil {
+		return r.err
+	}
+	r.renamed[from] = to
+	return nil
+}
+
+func Test_Workflow_DefaultStatuses(t *testing.T) {
+	workflowService := service.NewWorkflowService(fakeTransactor{}, &fakeWorkflowRepository{}, nil)
+
+	status, err := workflowService.ValidateStatus(context.Background(), 1, "done")
+	assert.NoError(t, err)
+	assert.Equal(t, "Done", status)
+
+	_, err = workflowService.ValidateStatus(context.Background(), 1, "Dnoe")
+	assert.ErrorIs(t, err, dto.ErrInvalidStatus)
+}
+
+func Test_Workflow_Transitions(t *testing.T) {
+	repo := &fakeWorkflowRepository{
+		statuses: []entity.WorkflowStatus{
+			{ID: 1, Name: "Backlog", Category: constants.ENUM_STATUS_CATEGORY_TODO},
+			{ID: 2, Name: "Doing", Category: constants.ENUM_STATUS_CATEGORY_IN_PROGRESS},
+			{ID: 3, Name: "Shipped", Category: constants.ENUM_STATUS_CATEGORY_DONE},
+		},
+		transitions: []entity.WorkflowTransition{
+			{ID: 1, FromStatusID: 1, ToStatusID: 2},
+			{ID: 2, FromStatusID: 2, ToStatusID: 3},
+		},
+	}
+	workflowService := service.NewWorkflowService(fakeTransactor{}, repo, nil)
+	ctx := context.Background()
+
+	status, err := workflowService.ValidateTransition(ctx, 1, "Backlog", "doing")
+	assert.NoError(t, err)
+	assert.Equal(t, "Doing", status)
+
+	_, err = workflowService.ValidateTransition(ctx, 1, "Backlog", "Shipped")
+	assert.ErrorIs(t, err, dto.ErrTransitionNotAllowed)
+
+	// tasks left on a status that was removed from the workflow can move anywhere
+	_, err = workflowService.ValidateTransition(ctx, 1, "Pending", "Shipped")
+	assert.NoError(t, err)
+}
+
+func Test_Workflow_FirstCustomizationCopiesDefaults(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeWorkflowRepository{}
+	workflowService := service.NewWorkflowService(fakeTransactor{}, repo, nil)
+
+	_, err := workflowService.CreateStatus(ctx, 1, dto.WorkflowStatusCreateRequest{Name: "done", Position: 3, Category: constants.ENUM_STATUS_CATEGORY_DONE})
+	assert.ErrorIs(t, err, dto.ErrWorkflowStatusExists)
+
+	review, err := workflowService.CreateStatus(ctx, 1, dto.WorkflowStatusCreateRequest{Name: "Review", Position: 3, Category: constants.ENUM_STATUS_CATEGORY_IN_PROGRESS})
+	require.NoError(t, err)
+
+	workflow, err := workflowService.GetWorkflow(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, workflow.IsDefault)
+	require.Len(t, workflow.Statuses, 4)
+	assert.Equal(t, "To Do", workflow.Statuses[0].Name)
+	assert.Equal(t, review.ID, workflow.Statuses[3].ID)
+
+	// Tasks keep moving between the default statuses, but the new status
+	// is only reachable once a transition leads to it.
+	_, err = workflowService.ValidateTransition(ctx, 1, "Done", "To Do")
+	assert.NoError(t, err)
+	_, err = workflowService.ValidateTransition(ctx, 1, "In Progress", "Review")
+	assert.ErrorIs(t, err, dto.ErrTransitionNotAllowed)
+}
+
+func Test_Workflow_CustomizedWithoutTransitionsDenies(t *testing.T) {
+	repo := &fakeWorkflowRepository{
+		statuses: []entity.WorkflowStatus{
+			{ID: 1, Name: "Backlog", Category: constants.ENUM_STATUS_CATEGORY_TODO},
+			{ID: 2, Name: "Shipped", Category: constants.ENUM_STATUS_CATEGORY_DONE},
+		},
+	}
+	workflowService := service.NewWorkflowService(fakeTransactor{}, repo, nil)
+
+	_, err := workflowService.ValidateTransition(context.Background(), 1, "Backlog", "Shipped")
+	assert.ErrorIs(t, err, dto.ErrTransitionNotAllowed)
+
+	status, err := workflowService.ValidateTransition(context.Background(), 1, "Backlog", "backlog")
+	assert.NoError(t, err)
+	assert.Equal(t, "Backlog", status)
+}
+
+func Test_Workflow_RenameInOneTransaction(t *testing.T) {
+	ctx := context.Background()
+	tx := &gorm.DB{}
+	repo := &fakeWorkflowRepository{statuses: []entity.WorkflowStatus{{ID: 1, Name: "Doing", Category: constants.ENUM_STATUS_CATEGORY_IN_PROGRESS}}}
+	tasks := &fakeStatusTaskRepository{renamed: map[string]string{}}
+	workflowService := service.NewWorkflowService(fakeTransactor{tx: tx}, repo, tasks)
+
+	updated, err := workflowService.UpdateStatus(ctx, 1, 1, dto.WorkflowStatusUpdateRequest{Name: "Working", Category: constants.ENUM_STATUS_CATEGORY_IN_PROGRESS})
+	require.NoError(t, err)
+	assert.Equal(t, "Working", updated.Name)
+	assert.Equal(t, map[string]string{"Doing": "Working"}, tasks.renamed)
+	assert.Same(t, tx, tasks.tx)
+
+	tasks.err = errors.New("lock wait timeout")
+	_, err = workflowService.UpdateStatus(ctx, 1, 1, dto.WorkflowStatusUpdateRequest{Name: "Busy", Category: constants.ENUM_STATUS_CATEGORY_IN_PROGRESS})
+	assert.ErrorIs(t, err, dto.ErrUpdateWorkflowStatus)
+	assert.Equal(t, "Working", repo.statuses[0].Name)
+}
@@ -0,0 +1,362 @@
+package tests
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Caknoooo/go-gin-clean-starter/constants"
+	"github.com/Caknoooo/go-gin-clean-starter/dto"
+	"github.com/Caknoooo/go-gin-clean-starter/entity"
+	"github.com/Caknoooo/go-gin-clean-starter/repository"
+	"github.com/Caknoooo/go-gin-clean-starter/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type fakeWorklogRepository struct {
+	repository.WorklogRepository
+	users    *fakeUserRepository
+	tasks    *fakeTaskTreeRepository
+	nextID   int
+	worklogs []entity.Worklog
+	// calls lists the lock, running timer lookups and creates in the order
+	// they were made, with the transaction each was made in.
+	calls []worklogCall
+}
+
+type worklogCall struct {
+	name string
+	tx   *gorm.DB
+}
+
+func (r *fakeWorklogRepository) LockTimers(ctx context.Context, tx *gorm.DB, userId uuid.UUID) error {
+	r.calls = append(r.calls, worklogCall{"lock", tx})
+	return nil
+}
+
+func (r *fakeWorklogRepository) CreateWorklog(ctx context.Context, tx *gorm.DB, worklog entity.Worklog) (entity.Worklog, error) {
+	r.calls = append(r.calls, worklogCall{"create", tx})
+	r.nextID++
+	worklog.ID = r.nextID
+	r.worklogs = append(r.worklogs, worklog)
+	return worklog, nil
+}
+
+func (r *fakeWorklogRepository) GetWorklogById(ctx context.Context, tx *gorm.DB, taskId int, worklogId int) (entity.Worklog, error) {
+	for _, worklog := range r.worklogs {
+		if worklog.ID == worklogId && worklog.TaskID == taskId {
+			worklog.User, _ = r.users.GetUserById(ctx, tx, worklog.UserID.String())
+			return worklog, nil
+		}
+	}
+	return entity.Worklog{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeWorklogRepository) GetWorklogsByTaskId(ctx context.Context, tx *gorm.DB, taskId int) ([]entity.Worklog, error) {
+	var worklogs []entity.Worklog
+	for _, worklog := range r.worklogs {
+		if worklog.TaskID == taskId {
+			worklogs = append(worklogs, worklog)
+		}
+	}
+	return worklogs, nil
+}
+
+func (r *fakeWorklogRepository) GetRunningWorklog(ctx context.Context, tx *gorm.DB, userId uuid.UUID) (entity.Worklog, error) {
+	r.calls = append(r.calls, worklogCall{"running", tx})
+	for _, worklog := range r.worklogs {
+		if worklog.UserID == userId && worklog.IsRunning {
+			return worklog, nil
+		}
+	}
+	return entity.Worklog{}, gorm.ErrRecordNotFound
+}
+
+func (r *fakeWorklogRepository) StopWorklog(ctx context.Context, tx *gorm.DB, worklogId int, duration int) error {
+	for i, worklog := range r.worklogs {
+		if worklog.ID == worklogId {
+			r.worklogs[i].Duration = duration
+			r.worklogs[i].IsRunning = false
+		}
+	}
+	return nil
+}
+
+func (r *fakeWorklogRepository) DeleteWorklog(ctx context.Context, tx *gorm.DB, worklogId int) error {
+	for i, worklog := range r.worklogs {
+		if worklog.ID == worklogId {
+			r.worklogs = append(r.worklogs[:i], r.worklogs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *fakeWorklogRepository) matching(filter dto.WorklogFilter) []entity.Worklog {
+	var worklogs []entity.Worklog
+	for _, worklog := range r.worklogs {
+		switch {
+		case worklog.IsRunning,
+			filter.TaskID != 0 && worklog.TaskID != filter.TaskID,
+			filter.TeamID != 0 && r.tasks.tasks[worklog.TaskID].TeamsID != filter.TeamID,
+			filter.UserID != nil && worklog.UserID != *filter.UserID,
+			filter.From != nil && worklog.StartedAt.Before(*filter.From),
+			filter.To != nil && !worklog.StartedAt.Before(*filter.To):
+			continue
+		}
+		worklogs = append(worklogs, worklog)
+	}
+	return worklogs
+}
+
+func (r *fakeWorklogRepository) SumByUser(ctx context.Context, tx *gorm.DB, filter dto.WorklogFilter) ([]dto.WorklogUserTotal, error) {
+	var totals []dto.WorklogUserTotal
+	index := map[uuid.UUID]int{}
+	for _, worklog := range r.matching(filter) {
+		i, ok := index[worklog.UserID]
+		if !ok {
+			user, _ := r.users.GetUserById(ctx, tx, worklog.UserID.String())
+			i = len(totals)
+			index[worklog.UserID] = i
+			totals = append(totals, dto.WorklogUserTotal{UserID: worklog.UserID, Name: user.Name})
+		}
+		totals[i].Duration += int64(worklog.Duration)
+	}
+	return totals, nil
+}
+
+func (r *fakeWorklogRepository) SumByTask(ctx context.Context, tx *gorm.DB, filter dto.WorklogFilter) ([]dto.WorklogTaskTotal, error) {
+	var totals []dto.WorklogTaskTotal
+	index := map[int]int{}
+	for _, worklog := range r.matching(filter) {
+		i, ok := index[worklog.TaskID]
+		if !ok {
+			i = len(totals)
+			index[worklog.TaskID] = i
+			totals = append(totals, dto.WorklogTaskTotal{TaskID: worklog.TaskID, Title: r.tasks.tasks[worklog.TaskID].Title})
+		}
+		totals[i].Duration += int64(worklog.Duration)
+	}
+	return totals, nil
+}
+
+type worklogTest struct {
+	subtaskTest
+	nt          notificationTest
+	tx          *gorm.DB
+	worklogRepo *fakeWorklogRepository
+	worklogs    service.WorklogService
+}
+
+// setUpWorklogTest makes alice a maintainer and bob and carol members of
+// team 1.
+func setUpWorklogTest() worklogTest {
+	nt := setUpNotificationTest()
+	st := setUpSubtaskTest()
+	tx := &gorm.DB{}
+	worklogRepo := &fakeWorklogRepository{users: nt.userRepo, tasks: st.taskRepo}
+	members := newFakeUserTeamsRepository(
+		entity.UserTeams{UserID: nt.alice.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MAINTAINER},
+		entity.UserTeams{UserID: nt.bob.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+		entity.UserTeams{UserID: nt.carol.ID, TeamID: 1, Role: constants.ENUM_TEAM_ROLE_MEMBER},
+	)
+
+	return worklogTest{
+		subtaskTest: st,
+		nt:          nt,
+		tx:          tx,
+		worklogRepo: worklogRepo,
+		worklogs:    service.NewWorklogService(fakeTransactor{tx: tx}, worklogRepo, st.taskRepo, service.NewAuthorizationService(nt.userRepo, members, st.taskRepo)),
+	}
+}
+
+func (wt worklogTest) log(t *testing.T, taskId int, user entity.User, startedAt time.Time, duration int) dto.WorklogResponse {
+	worklog, err := wt.worklogs.Create(context.Background(), strconv.Itoa(taskId), user.ID.String(), dto.WorklogCreateRequest{
+		StartedAt: startedAt.Format(time.RFC3339),
+		Duration:  duration,
+	})
+	require.NoError(t, err)
+	return worklog
+}
+
+func Test_Worklog_ManualEntry(t *testing.T) {
+	ctx := context.Background()
+	wt := setUpWorklogTest()
+	task := wt.create(t, 1, "To Do", nil)
+	taskId := strconv.Itoa(task.ID)
+
+	worklog := wt.log(t, task.ID, wt.nt.bob, time.Now().Add(-2*time.Hour), 3600)
+	assert.Equal(t, "Bob", worklog.User.Name)
+	assert.Equal(t, 3600, worklog.Duration)
+	assert.False(t, worklog.IsRunning)
+
+	_, err := wt.worklogs.Create(ctx, taskId, wt.nt.bob.ID.String(), dto.WorklogCreateRequest{
+		StartedAt: time.Now().Add(-time.Minute).Format(time.RFC3339),
+		Duration:  3600,
+	})
+	assert.ErrorIs(t, err, dto.ErrInvalidWorklog)
+
+	_, err = wt.worklogs.Create(ctx, taskId, wt.nt.bob.ID.String(), dto.WorklogCreateRequest{StartedAt: "yesterday", Duration: 60})
+	assert.ErrorIs(t, err, dto.ErrInvalidWorklog)
+
+	_, err = wt.worklogs.Create(ctx, "999", wt.nt.bob.ID.String(), dto.WorklogCreateRequest{StartedAt: time.Now().Format(time.RFC3339), Duration: 60})
+	assert.ErrorIs(t, err, dto.ErrTaskNotFound)
+}
+
+func Test_Worklog_Timer(t *testing.T) {
+	ctx := context.Background()
+	wt := setUpWorklogTest()
+	a := strconv.Itoa(wt.create(t, 1, "To Do", nil).ID)
+	b := strconv.Itoa(wt.create(t, 1, "To Do", nil).ID)
+	bob := wt.nt.bob.ID.String()
+
+	started, err := wt.worklogs.StartTimer(ctx, a, bob, dto.WorklogTimerRequest{Note: " pairing "})
+	require.NoError(t, err)
+	assert.True(t, started.IsRunning)
+	assert.Equal(t, "pairing", started.Note)
+
+	// A user runs one timer at a time.
+	_, err = wt.worklogs.StartTimer(ctx, b, bob, dto.WorklogTimerRequest{})
+	assert.ErrorIs(t, err, dto.ErrTimerRunning)
+	assert.Contains(t, err.Error(), "#"+a)
+	_, err = wt.worklogs.StopTimer(ctx, b, bob)
+	assert.ErrorIs(t, err, dto.ErrNoRunningTimer)
+
+	// Running timers are left out of summaries until stopped.
+	summary, err := wt.worklogs.GetTaskSummary(ctx, a, dto.WorklogSummaryRequest{})
+	require.NoError(t, err)
+	assert.Zero(t, summary.TotalDuration)
+
+	wt.worklogRepo.worklogs[0].StartedAt = time.Now().Add(-90 * time.Minute)
+	stopped, err := wt.worklogs.StopTimer(ctx, a, bob)
+	require.NoError(t, err)
+	assert.False(t, stopped.IsRunning)
+	assert.InDelta(t, 5400, stopped.Duration, 1)
+
+	_, err = wt.worklogs.StopTimer(ctx, a, bob)
+	assert.ErrorIs(t, err, dto.ErrNoRunningTimer)
+	_, err = wt.worklogs.StartTimer(ctx, b, bob, dto.WorklogTimerRequest{})
+	assert.NoError(t, err)
+}
+
+func Test_Worklog_TimerStartsUnderLock(t *testing.T) {
+	wt := setUpWorklogTest()
+	taskId := strconv.Itoa(wt.create(t, 1, "To Do", nil).ID)
+
+	_, err := wt.worklogs.StartTimer(context.Background(), taskId, wt.nt.bob.ID.String(), dto.WorklogTimerRequest{})
+	require.NoError(t, err)
+
+	// The running timer is looked up and the new one created while the user
+	// is locked, all in one transaction.
+	require.Len(t, wt.worklogRepo.calls, 3)
+	for i, name := range []string{"lock", "running", "create"} {
+		assert.Equal(t, name, wt.worklogRepo.calls[i].name)
+		assert.Same(t, wt.tx, wt.worklogRepo.calls[i].tx)
+	}
+}
+
+func Test_Worklog_LockTimersSQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}))
+
+	require.NoError(t, repository.NewWorklogRepository(db).LockTimers(context.Background(), nil, uuid.New()))
+
+	assert.Contains(t, sql, "FROM `users` WHERE id = ?")
+	assert.True(t, strings.HasSuffix(sql, "FOR UPDATE"), sql)
+}
+
+func Test_Worklog_DeleteOwnOrManage(t *testing.T) {
+	ctx := context.Background()
+	wt := setUpWorklogTest()
+	task := wt.create(t, 1, "To Do", nil)
+	taskId := strconv.Itoa(task.ID)
+
+	first := wt.log(t, task.ID, wt.nt.bob, time.Now().Add(-time.Hour), 600)
+	second := wt.log(t, task.ID, wt.nt.bob, time.Now().Add(-time.Hour), 600)
+
+	err := wt.worklogs.Delete(ctx, taskId, first.ID, wt.nt.carol.ID.String())
+	assert.ErrorIs(t, err, dto.ErrPermissionDenied)
+
+	require.NoError(t, wt.worklogs.Delete(ctx, taskId, first.ID, wt.nt.bob.ID.String()))
+	require.NoError(t, wt.worklogs.Delete(ctx, taskId, second.ID, wt.nt.alice.ID.String()))
+	assert.ErrorIs(t, wt.worklogs.Delete(ctx, taskId, second.ID, wt.nt.bob.ID.String()), dto.ErrWorklogNotFound)
+}
+
+func Test_Worklog_Summaries(t *testing.T) {
+	ctx := context.Background()
+	wt := setUpWorklogTest()
+	a := wt.create(t, 1, "To Do", nil)
+	b := wt.create(t, 1, "To Do", nil)
+	other := wt.create(t, 2, "To Do", nil)
+
+	june := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	wt.log(t, a.ID, wt.nt.bob, june, 3600)
+	wt.log(t, a.ID, wt.nt.carol, june, 1800)
+	wt.log(t, b.ID, wt.nt.bob, june, 600)
+	wt.log(t, other.ID, wt.nt.bob, june, 7200)
+	wt.log(t, a.ID, wt.nt.bob, june.AddDate(0, 1, 0), 900)
+
+	june2024 := dto.WorklogSummaryRequest{From: "2024-06-01", To: "2024-07-01"}
+
+	task, err := wt.worklogs.GetTaskSummary(ctx, strconv.Itoa(a.ID), june2024)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5400, task.TotalDuration)
+	assert.Len(t, task.Users, 2)
+	assert.Nil(t, task.Tasks)
+
+	team, err := wt.worklogs.GetTeamSummary(ctx, 1, june2024)
+	require.NoError(t, err)
+	assert.EqualValues(t, 6000, team.TotalDuration)
+	assert.Len(t, team.Users, 2)
+	assert.Len(t, team.Tasks, 2)
+
+	june2024.UserID = wt.nt.bob.ID.String()
+	team, err = wt.worklogs.GetTeamSummary(ctx, 1, june2024)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4200, team.TotalDuration)
+
+	mine, err := wt.worklogs.GetUserSummary(ctx, wt.nt.bob.ID.String(), dto.WorklogSummaryRequest{From: "2024-06-01"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 12300, mine.TotalDuration)
+	assert.Len(t, mine.Tasks, 3)
+	assert.Nil(t, mine.Users)
+
+	for _, req := range []dto.WorklogSummaryRequest{
+		{From: "June"},
+		{From: "2024-07-01", To: "2024-06-01"},
+	} {
+		_, err = wt.worklogs.GetTeamSummary(ctx, 1, req)
+		assert.ErrorIs(t, err, dto.ErrInvalidWorklogDate, req)
+	}
+}
+
+func Test_Worklog_SummarySQL(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	var sql string
+	db.Callback().Row().After("gorm:row").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	})
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	userId := uuid.New()
+
+	// Scan is not supported in dry run mode, so only the SQL is checked.
+	repository.NewWorklogRepository(db).SumByTask(context.Background(), nil, dto.WorklogFilter{TeamID: 3, UserID: &userId, From: &from})
+
+	assert.Contains(t, sql, "SELECT worklogs.task_id, tasks.title, SUM(worklogs.duration) AS duration FROM `worklogs` JOIN tasks ON tasks.id = worklogs.task_id")
+	assert.Contains(t, sql, "WHERE worklogs.is_running = ? AND worklogs.task_id IN (SELECT id FROM tasks WHERE teams_id = ?) AND worklogs.user_id = ? AND worklogs.started_at >= ? AND `worklogs`.`deleted_at` IS NULL")
+	assert.Contains(t, sql, "GROUP BY worklogs.task_id, tasks.title ORDER BY duration DESC")
+}
@@ -6,29 +6,43 @@ import (
 	"gopkg.in/gomail.v2"
 )
 
-func SendMail(toEmail string, subject string, body string) error {
-	emailConfig, err := config.NewEmailConfig()
-	if err != nil {
-		return err
+// Mailer delivers a single HTML email.
+type Mailer interface {
+	Send(toEmail string, subject string, body string) error
+}
+
+type smtpMailer struct {
+	config config.EmailConfig
+}
+
+func NewSMTPMailer(emailConfig config.EmailConfig) Mailer {
+	return &smtpMailer{
+		config: emailConfig,
 	}
+}
 
+func (m *smtpMailer) Send(toEmail string, subject string, body string) error {
 	mailer := gomail.NewMessage()
-	mailer.SetHeader("From", emailConfig.AuthEmail)
+	mailer.SetHeader("From", m.config.AuthEmail)
 	mailer.SetHeader("To", toEmail)
 	mailer.SetHeader("Subject", subject)
 	mailer.SetBody("text/html", body)
 
 	dialer := gomail.NewDialer(
-		emailConfig.Host,
-		emailConfig.Port,
-		emailConfig.AuthEmail,
-		emailConfig.AuthPassword,
+		m.config.Host,
+		m.config.Port,
+		m.config.AuthEmail,
+		m.config.AuthPassword,
 	)
 
-	err = dialer.DialAndSend(mailer)
+	return dialer.DialAndSend(mailer)
+}
+
+func SendMail(toEmail string, subject string, body string) error {
+	emailConfig, err := config.NewEmailConfig()
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return NewSMTPMailer(*emailConfig).Send(toEmail, subject, body)
 }
@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"embed"
+)
+
+//go:embed email-template/*.html
+var emailTemplates embed.FS
+
+// ReadEmailTemplate returns one of the templates in utils/email-template.
+// They are embedded so rendering does not depend on the working directory.
+func ReadEmailTemplate(name string) ([]byte, error) {
+	return emailTemplates.ReadFile("email-template/" + name)
+}